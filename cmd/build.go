@@ -16,13 +16,23 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"strings"
+	"time"
+
+	units "github.com/docker/go-units"
 
 	"github.com/okteto/okteto/cmd/utils"
 	"github.com/okteto/okteto/pkg/analytics"
 	"github.com/okteto/okteto/pkg/cmd/build"
 	"github.com/okteto/okteto/pkg/cmd/login"
+	"github.com/okteto/okteto/pkg/config"
+	k8Client "github.com/okteto/okteto/pkg/k8s/client"
 	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/registry"
 	"github.com/spf13/cobra"
 )
 
@@ -30,18 +40,102 @@ import (
 func Build(ctx context.Context) *cobra.Command {
 	var file string
 	var tag string
+	var output string
+	var builder string
+	var builderImage string
 	var target string
+	var platform string
 	var noCache bool
+	var skipIfExists bool
+	var compression string
 	var cacheFrom []string
 	var progress string
 	var buildArgs []string
+	var secrets []string
+	var labelList []string
+	var envFiles []string
+	var scan bool
+	var scanSeverity string
+	var sbomFormat string
+	var sbomOutput string
+	var sign bool
+	var signKey string
+	var frontendImage string
+	var frontendOpts []string
+	var metadataFile string
 
 	cmd := &cobra.Command{
-		Use:   "build [PATH]",
+		Use:   "build [PATH | URL]",
 		Short: "Build (and optionally push) a Docker image",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			log.Info("starting build command")
 
+			if err := build.ValidateProgress(progress); err != nil {
+				return err
+			}
+
+			if err := build.ValidateCompression(compression); err != nil {
+				return err
+			}
+
+			buildInfo := &model.BuildInfo{BuildInfoRaw: model.BuildInfoRaw{Builder: builder, BuilderImage: builderImage}}
+			if builder != model.DockerfileBuilder && builder != model.BuildpacksBuilder {
+				return fmt.Errorf("invalid builder '%s': must be %s or %s", builder, model.DockerfileBuilder, model.BuildpacksBuilder)
+			}
+			if builderImage != "" && !buildInfo.IsBuildpacks() {
+				return fmt.Errorf("the flag '--builder-image' requires '--builder=%s'", model.BuildpacksBuilder)
+			}
+			if buildInfo.IsBuildpacks() && (tag == "" || output != "") {
+				return fmt.Errorf("building with buildpacks requires the flag '-t' to push the image")
+			}
+
+			if output != "" {
+				if _, err := build.ParseOutput(output); err != nil {
+					return err
+				}
+				if scan || sign || sbomFormat != "" {
+					return fmt.Errorf("the flag '--output' can't be used with '--scan', '--sign' or '--sbom', they require pushing the image")
+				}
+			}
+
+			if skipIfExists {
+				if tag == "" || output != "" {
+					return fmt.Errorf("the flag '--skip-if-exists' requires the flag '-t' to push the image")
+				}
+				if noCache || buildInfo.IsBuildpacks() {
+					return fmt.Errorf("the flag '--skip-if-exists' can't be used with '--no-cache' or '--builder=%s'", model.BuildpacksBuilder)
+				}
+			}
+
+			if metadataFile != "" && buildInfo.IsBuildpacks() {
+				return fmt.Errorf("the flag '--metadata-file' can't be used with '--builder=%s'", model.BuildpacksBuilder)
+			}
+
+			if scan {
+				if tag == "" {
+					return fmt.Errorf("the vulnerability scan requires the flag '-t' to push the image")
+				}
+				if err := build.ValidateScanSeverity(scanSeverity); err != nil {
+					return err
+				}
+			}
+
+			if sign && tag == "" {
+				return fmt.Errorf("the image signature requires the flag '-t' to push the image")
+			}
+
+			if sbomFormat != "" {
+				if tag == "" {
+					return fmt.Errorf("the sbom generation requires the flag '-t' to push the image")
+				}
+				if err := build.ValidateSBOMFormat(sbomFormat); err != nil {
+					return err
+				}
+				if sbomOutput == "" {
+					sbomOutput = build.GetSBOMFilename(sbomFormat)
+				}
+			}
+
 			if err := login.WithEnvVarIfAvailable(ctx); err != nil {
 				return err
 			}
@@ -51,29 +145,117 @@ func Build(ctx context.Context) *cobra.Command {
 				path = args[0]
 			}
 
-			if err := utils.CheckIfDirectory(path); err != nil {
-				return fmt.Errorf("invalid build context: %s", err.Error())
+			if !model.IsGitBuildContext(path) && !buildInfo.IsBuildpacks() {
+				if err := utils.CheckIfDirectory(path); err != nil {
+					return fmt.Errorf("invalid build context: %s", err.Error())
+				}
+
+				if file == "" {
+					file = filepath.Join(path, "Dockerfile")
+				}
+
+				if err := utils.CheckIfRegularFile(file); err != nil {
+					return fmt.Errorf("invalid Dockerfile: %s", err.Error())
+				}
 			}
 
-			if file == "" {
-				file = filepath.Join(path, "Dockerfile")
+			buildKitHost, isOktetoCluster, err := build.GetBuildKitHost()
+			if err != nil {
+				return err
+			}
+			if progress != build.ProgressJSON && metadataFile != build.MetadataStdout {
+				log.Information("Running your build in %s...", buildKitHost)
 			}
 
-			if err := utils.CheckIfRegularFile(file); err != nil {
-				return fmt.Errorf("invalid Dockerfile: %s", err.Error())
+			frontend, err := build.GetFrontend(frontendImage, frontendOpts)
+			if err != nil {
+				return err
 			}
 
-			buildKitHost, isOktetoCluster, err := build.GetBuildKitHost()
+			labels, err := build.ParseLabels(labelList)
+			if err != nil {
+				return err
+			}
+
+			envArgs, err := build.GetBuildArgsFromEnvFiles(envFiles)
 			if err != nil {
 				return err
 			}
-			log.Information("Running your build in %s...", buildKitHost)
+			buildArgs = append(envArgs, buildArgs...)
+
+			// the standard output is reserved for the build metadata
+			out := io.Writer(os.Stdout)
+			if metadataFile == build.MetadataStdout {
+				out = os.Stderr
+			}
 
 			ctx := context.Background()
-			if err := build.Run(ctx, buildKitHost, isOktetoCluster, path, file, tag, target, noCache, cacheFrom, buildArgs, progress); err != nil {
+			var digest string
+			if buildInfo.IsBuildpacks() {
+				digest, err = build.RunBuildpacks(ctx, path, tag, buildInfo.GetBuilderImage(), noCache, buildArgs, os.Stdout)
+			} else {
+				digest, err = build.Run(ctx, build.Options{
+					BuildKitHost:    buildKitHost,
+					IsOktetoCluster: isOktetoCluster,
+					Path:            path,
+					Dockerfile:      file,
+					Tag:             tag,
+					Output:          output,
+					Target:          target,
+					Platform:        platform,
+					NoCache:         noCache,
+					SkipIfExists:    skipIfExists,
+					Compression:     compression,
+					CacheFrom:       cacheFrom,
+					BuildArgs:       buildArgs,
+					Secrets:         secrets,
+					Labels:          labels,
+					Frontend:        frontend,
+					MetadataFile:    metadataFile,
+					Progress:        progress,
+					Out:             out,
+				})
+			}
+			if err != nil {
 				analytics.TrackBuild(false)
 				return err
 			}
+			if digest != "" {
+				log.Infof("pushed image digest: %s", digest)
+			}
+
+			image := registry.GetImageWithDigest(strings.Split(tag, ",")[0], digest)
+			if sbomFormat != "" {
+				if err := build.GenerateSBOM(ctx, image, sbomFormat, sbomOutput); err != nil {
+					analytics.TrackBuild(false)
+					return err
+				}
+			}
+
+			if scan {
+				if err := build.Scan(ctx, image, scanSeverity); err != nil {
+					analytics.TrackBuild(false)
+					return err
+				}
+			}
+
+			if sign {
+				if err := build.Sign(ctx, image, signKey); err != nil {
+					analytics.TrackBuild(false)
+					return err
+				}
+			}
+
+			analytics.TrackBuild(true)
+			if progress == build.ProgressJSON || metadataFile == build.MetadataStdout {
+				return nil
+			}
+
+			if output != "" {
+				log.Success("Build succeeded")
+				log.Information("Your image was exported to '%s'.", output)
+				return nil
+			}
 
 			if tag == "" {
 				log.Success("Build succeeded")
@@ -81,18 +263,165 @@ func Build(ctx context.Context) *cobra.Command {
 			} else {
 				log.Success(fmt.Sprintf("Image '%s' successfully pushed", tag))
 			}
-
-			analytics.TrackBuild(true)
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&file, "file", "f", "", "name of the Dockerfile (Default is 'PATH/Dockerfile')")
 	cmd.Flags().StringVarP(&tag, "tag", "t", "", "name and optionally a tag in the 'name:tag' format (it is automatically pushed)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "export the image instead of pushing it (format: type=oci|docker|tar|local,dest=PATH)")
+	cmd.Flags().StringVarP(&builder, "builder", "", model.DockerfileBuilder, "builder of the image: dockerfile or buildpacks")
+	cmd.Flags().StringVarP(&builderImage, "builder-image", "", "", fmt.Sprintf("builder image of Cloud Native Buildpacks (Default is '%s')", model.DefaultBuildpacksBuilderImage))
 	cmd.Flags().StringVarP(&target, "target", "", "", "set the target build stage to build")
+	cmd.Flags().StringVarP(&platform, "platform", "", "", "set the target platforms of the build, separated by commas (e.g. 'linux/amd64,linux/arm64')")
 	cmd.Flags().BoolVarP(&noCache, "no-cache", "", false, "do not use cache when building the image")
+	cmd.Flags().BoolVarP(&skipIfExists, "skip-if-exists", "", false, "skip the build if the image was already pushed from the same build context")
 	cmd.Flags().StringArrayVar(&cacheFrom, "cache-from", nil, "cache source images")
-	cmd.Flags().StringVarP(&progress, "progress", "", "tty", "show plain/tty build output")
+	cmd.Flags().StringVarP(&compression, "compression", "", "", "compression of the pushed layers: gzip, uncompressed or estargz (lazily pullable by the cluster)")
+	cmd.Flags().StringVarP(&progress, "progress", "", build.ProgressTTY, "show tty, plain, json or quiet build output")
+	cmd.Flags().StringVarP(&metadataFile, "metadata-file", "", "", "write the reference, digest, duration, cache hit ratio and platforms of the build as json to a file ('-' for the standard output)")
 	cmd.Flags().StringArrayVar(&buildArgs, "build-arg", nil, "set build-time variables")
+	cmd.Flags().StringArrayVar(&labelList, "label", nil, "set metadata labels of the image, in addition to the standard OCI labels (format: key=value)")
+	cmd.Flags().StringArrayVar(&envFiles, "env-file", nil, "read build-time variables from a file, overridden by --build-arg")
+	cmd.Flags().BoolVarP(&scan, "scan", "", false, "scan the pushed image for vulnerabilities with trivy")
+	cmd.Flags().StringVarP(&scanSeverity, "scan-severity", "", build.DefaultScanSeverity, "lowest severity of the vulnerabilities that fail the scan (LOW, MEDIUM, HIGH or CRITICAL)")
+	cmd.Flags().StringVarP(&sbomFormat, "sbom", "", "", "generate the SBOM of the pushed image with syft in the spdx or cyclonedx format, and attach it with cosign")
+	cmd.Flags().Lookup("sbom").NoOptDefVal = build.SBOMFormatSPDX
+	cmd.Flags().StringVarP(&sbomOutput, "sbom-output", "", "", "path of the local copy of the SBOM (Default is 'sbom.<format>.json')")
+	cmd.Flags().BoolVarP(&sign, "sign", "", false, "sign the pushed image with cosign")
+	cmd.Flags().StringVarP(&signKey, "sign-key", "", "", "cosign key used to sign the image (Default is keyless signing)")
+	cmd.Flags().StringVarP(&frontendImage, "frontend", "", "", "image of the BuildKit frontend that builds the image (overrides the '# syntax=' directive)")
+	cmd.Flags().StringArrayVar(&frontendOpts, "frontend-opt", nil, "set attributes of the BuildKit frontend (format: key=value)")
+	cmd.Flags().StringArrayVar(&secrets, "secret", nil, "secret files exposed to the build with 'RUN --mount=type=secret' (format: id=mysecret,src=/local/secret)")
+	cmd.AddCommand(buildPrune(ctx))
+	cmd.AddCommand(buildWarm(ctx))
+	return cmd
+}
+
+func buildPrune(ctx context.Context) *cobra.Command {
+	var keepDuration time.Duration
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove your build cache",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log.Info("starting build prune command")
+
+			if err := login.WithEnvVarIfAvailable(ctx); err != nil {
+				return err
+			}
+
+			buildKitHost, isOktetoCluster, err := build.GetBuildKitHost()
+			if err != nil {
+				return err
+			}
+
+			reclaimed, err := build.Prune(ctx, buildKitHost, isOktetoCluster, keepDuration, all)
+			if err != nil {
+				return err
+			}
+
+			if isOktetoCluster {
+				log.Success("Your build cache was reset")
+				log.Information("Your next builds won't reuse your previous cache mounts.")
+				return nil
+			}
+
+			log.Success("Build cache pruned in %s: %s reclaimed", buildKitHost, units.HumanSize(float64(reclaimed)))
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVarP(&keepDuration, "keep-duration", "", config.GetBuildCacheTTL(), "keep the cache used more recently than this duration (Default is the value of OKTETO_BUILD_CACHE_TTL)")
+	cmd.Flags().BoolVarP(&all, "all", "", false, "remove all the build cache, not only the cache mounts")
+	return cmd
+}
+
+func buildWarm(ctx context.Context) *cobra.Command {
+	var devPath string
+	var namespace string
+	var k8sContext string
+	var target string
+	var progress string
+
+	cmd := &cobra.Command{
+		Use:   "warm",
+		Short: "Warm up your build cache by building the dependency stages of your dev image",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log.Info("starting build warm command")
+
+			if err := build.ValidateProgress(progress); err != nil {
+				return err
+			}
+
+			dev, err := utils.LoadDev(devPath)
+			if err != nil {
+				return err
+			}
+			dev.LoadContext(namespace, k8sContext)
+
+			if dev.Image.IsBuildpacks() {
+				return fmt.Errorf("'okteto build warm' requires the '%s' builder", model.DockerfileBuilder)
+			}
+
+			if target == "" {
+				target = dev.Image.WarmTarget
+			}
+			if target == "" {
+				log.Information("Set 'image.warmTarget' in your okteto manifest to only build your dependency stages.")
+			}
+
+			if dev.Namespace == "" && dev.Image.CacheScope != "" && dev.Image.CacheScope != model.CacheScopeUser {
+				_, _, dev.Namespace, err = k8Client.GetLocal(dev.Context)
+				if err != nil {
+					return err
+				}
+			}
+
+			if err := login.WithEnvVarIfAvailable(ctx); err != nil {
+				return err
+			}
+
+			buildKitHost, isOktetoCluster, err := build.GetBuildKitHost()
+			if err != nil {
+				return err
+			}
+			log.Information("Warming up your build cache in %s...", buildKitHost)
+
+			buildArgs := model.SerializeBuildArgs(dev.Image.Args)
+			opts := build.Options{
+				BuildKitHost:    buildKitHost,
+				IsOktetoCluster: isOktetoCluster,
+				Path:            dev.Image.Context,
+				Dockerfile:      dev.Image.Dockerfile,
+				Target:          target,
+				Platform:        dev.Image.Platform,
+				CacheFrom:       dev.Image.CacheFrom,
+				BuildArgs:       buildArgs,
+				Secrets:         model.SerializeBuildSecrets(dev.Image.Secrets),
+				Ignore:          dev.Image.Ignore,
+				CacheScope:      dev.Image.GetCacheScope(dev.Namespace, dev.Name),
+				Labels:          dev.Image.Labels,
+				Frontend:        dev.Image.Frontend,
+				Progress:        progress,
+				Out:             os.Stdout,
+			}
+			if _, err := build.Run(ctx, opts); err != nil {
+				return err
+			}
+
+			log.Success("Build cache warmed up")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&devPath, "file", "f", utils.DefaultDevManifest, "path to the manifest file")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "namespace of the cache mounts scoped by namespace")
+	cmd.Flags().StringVarP(&k8sContext, "context", "c", "", "context of the cache mounts scoped by namespace")
+	cmd.Flags().StringVarP(&target, "target", "", "", "build stage that warms up the cache (Default is 'image.warmTarget' of the manifest)")
+	cmd.Flags().StringVarP(&progress, "progress", "", build.ProgressTTY, "show tty, plain, json or quiet build output")
 	return cmd
 }
@@ -0,0 +1,111 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/okteto/okteto/cmd/utils"
+	"github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/ssh"
+	"github.com/spf13/cobra"
+)
+
+const remotePathPrefix = ":"
+
+//Cp copies files and directories between the local machine and the development container
+func Cp() *cobra.Command {
+	var devPath string
+	var namespace string
+	var k8sContext string
+
+	cmd := &cobra.Command{
+		Use:   "cp <src> <dst>",
+		Short: "Copy files and directories between your local machine and your development container",
+		Long: `Copy files and directories between your local machine and your development container.
+
+Paths in your development container are prefixed with ':'. For example:
+  okteto cp ./bin/app :/usr/local/bin/app
+  okteto cp :/tmp/core ./core`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			dev, err := utils.LoadDev(devPath)
+			if err != nil {
+				return err
+			}
+			dev.LoadContext(namespace, k8sContext)
+
+			return executeCp(ctx, dev, args[0], args[1])
+		},
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return fmt.Errorf("cp requires the SRC and DST arguments")
+			}
+
+			if isRemotePath(args[0]) == isRemotePath(args[1]) {
+				return fmt.Errorf("exactly one of SRC and DST must be a path in your development container, prefixed with '%s'", remotePathPrefix)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&devPath, "file", "f", utils.DefaultDevManifest, "path to the manifest file")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "namespace where the cp command is executed")
+	cmd.Flags().StringVarP(&k8sContext, "context", "c", "", "context where the cp command is executed")
+
+	return cmd
+}
+
+func executeCp(ctx context.Context, dev *model.Dev, src, dst string) error {
+	remotePort, err := ssh.GetPort(dev.Name)
+	if err != nil {
+		log.Infof("failed to get the SSH port for %s: %s", dev.Name, err)
+		return errors.UserError{
+			E:    fmt.Errorf("development mode is not enabled on your deployment"),
+			Hint: "Run 'okteto up' to enable it and try again",
+		}
+	}
+
+	if _, err := ssh.LoadIdentities(dev.SSHIdentities); err != nil {
+		return err
+	}
+
+	if isRemotePath(src) {
+		remote := strings.TrimPrefix(src, remotePathPrefix)
+		if err := ssh.Download(ctx, dev.Name, dev.Interface, remotePort, remote, dst); err != nil {
+			return fmt.Errorf("failed to copy %s from your development container: %s", remote, err)
+		}
+
+		log.Success("Copied %s to %s", remote, dst)
+		return nil
+	}
+
+	remote := strings.TrimPrefix(dst, remotePathPrefix)
+	if err := ssh.Upload(ctx, dev.Name, dev.Interface, remotePort, src, remote); err != nil {
+		return fmt.Errorf("failed to copy %s to your development container: %s", src, err)
+	}
+
+	log.Success("Copied %s to %s", src, remote)
+	return nil
+}
+
+func isRemotePath(p string) bool {
+	return strings.HasPrefix(p, remotePathPrefix)
+}
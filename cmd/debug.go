@@ -0,0 +1,134 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/okteto/okteto/cmd/utils"
+	k8Client "github.com/okteto/okteto/pkg/k8s/client"
+	"github.com/okteto/okteto/pkg/k8s/forward"
+	"github.com/okteto/okteto/pkg/k8s/pods"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/ssh"
+	"github.com/spf13/cobra"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+//Debug injects an ephemeral container with the okteto tooling in a pod and opens a shell in it
+func Debug() *cobra.Command {
+	var namespace string
+	var k8sContext string
+	var container string
+
+	cmd := &cobra.Command{
+		Use:   "debug <pod>",
+		Short: "Debug a running pod with an ephemeral container, without modifying its deployment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			return executeDebug(ctx, args[0], namespace, k8sContext, container)
+		},
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("debug requires the POD argument")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "namespace where the debug command is executed")
+	cmd.Flags().StringVarP(&k8sContext, "context", "c", "", "context where the debug command is executed")
+	cmd.Flags().StringVarP(&container, "container", "", "", "container whose processes are shared with the debug container (defaults to the first one)")
+
+	return cmd
+}
+
+func executeDebug(ctx context.Context, podName, namespace, k8sContext, container string) error {
+	client, restConfig, currentNamespace, err := k8Client.GetLocal(k8sContext)
+	if err != nil {
+		return err
+	}
+
+	if namespace == "" {
+		namespace = currentNamespace
+	}
+
+	pod, err := client.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get pod %s/%s: %s", namespace, podName, err)
+	}
+
+	if container == "" {
+		container = pod.Spec.Containers[0].Name
+	}
+
+	if !ssh.KeyExists() {
+		if err := ssh.GenerateKeys(); err != nil {
+			return err
+		}
+	}
+
+	authorizedKeys, err := ioutil.ReadFile(ssh.GetPublicKey())
+	if err != nil {
+		return fmt.Errorf("failed to read your SSH public key: %s", err)
+	}
+
+	pf, localPort, err := startDebugContainer(ctx, pod, container, string(authorizedKeys), restConfig, client)
+	if err != nil {
+		return err
+	}
+	defer pf.Stop()
+
+	log.Success("Debug container started in pod %s", pod.Name)
+	return ssh.Exec(ctx, pod.Name, model.Localhost, localPort, true, os.Stdin, os.Stdout, os.Stderr, []string{"sh"})
+}
+
+func startDebugContainer(ctx context.Context, pod *apiv1.Pod, container, authorizedKeys string, restConfig *rest.Config, client kubernetes.Interface) (*forward.PortForwardManager, int, error) {
+	spinner := utils.NewSpinner("Starting the debug container...")
+	spinner.Start()
+	defer spinner.Stop()
+
+	if err := pods.AddDebugContainer(ctx, pod, container, authorizedKeys, client); err != nil {
+		return nil, 0, err
+	}
+
+	if err := pods.WaitUntilDebugContainerRunning(ctx, pod, client); err != nil {
+		return nil, 0, err
+	}
+
+	localPort, err := model.GetAvailablePort(model.Localhost)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	pf := forward.NewPortForwardManager(ctx, model.Localhost, restConfig, client)
+	if err := pf.Add(model.Forward{Local: localPort, Remote: pods.DebugSSHPort}); err != nil {
+		return nil, 0, err
+	}
+
+	if err := pf.Start(pod.Name, pod.Namespace); err != nil {
+		return nil, 0, err
+	}
+
+	return pf, localPort, nil
+}
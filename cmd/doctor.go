@@ -15,6 +15,7 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/okteto/okteto/cmd/utils"
 	"github.com/okteto/okteto/pkg/analytics"
@@ -23,6 +24,7 @@ import (
 	k8Client "github.com/okteto/okteto/pkg/k8s/client"
 	"github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/okteto/okteto/pkg/ssh"
 	"github.com/spf13/cobra"
 )
 
@@ -31,6 +33,7 @@ func Doctor() *cobra.Command {
 	var devPath string
 	var namespace string
 	var k8sContext string
+	var resetHostKey bool
 	cmd := &cobra.Command{
 		Use:   "doctor",
 		Short: "Generates a zip file with the okteto logs",
@@ -47,6 +50,14 @@ func Doctor() *cobra.Command {
 			}
 			dev.LoadContext(namespace, k8sContext)
 
+			if resetHostKey {
+				if err := ssh.ResetHostKey(dev.Name); err != nil {
+					return fmt.Errorf("failed to reset the host key of your development container: %s", err)
+				}
+				log.Success("Host key of your development container reset")
+				return nil
+			}
+
 			c, _, namespace, err := k8Client.GetLocal(dev.Context)
 			if err != nil {
 				return err
@@ -68,5 +79,6 @@ func Doctor() *cobra.Command {
 	cmd.Flags().StringVarP(&devPath, "file", "f", utils.DefaultDevManifest, "path to the manifest file")
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "namespace where the up command was executing")
 	cmd.Flags().StringVarP(&k8sContext, "context", "c", "", "context where the up command was executing")
+	cmd.Flags().BoolVarP(&resetHostKey, "reset-hostkey", "", false, "forget the pinned SSH host key of your development container")
 	return cmd
 }
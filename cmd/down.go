@@ -15,19 +15,26 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/okteto/okteto/cmd/utils"
 	"github.com/okteto/okteto/pkg/analytics"
 	"github.com/okteto/okteto/pkg/cmd/down"
+	"github.com/okteto/okteto/pkg/cmd/hooks"
 	"github.com/okteto/okteto/pkg/errors"
 	k8Client "github.com/okteto/okteto/pkg/k8s/client"
 	"github.com/okteto/okteto/pkg/k8s/deployments"
+	"github.com/okteto/okteto/pkg/k8s/exec"
+	"github.com/okteto/okteto/pkg/k8s/pods"
 	"github.com/okteto/okteto/pkg/k8s/volumes"
 	"github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/model"
 	"github.com/okteto/okteto/pkg/syncthing"
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/dynamic"
 )
 
 //Down deactivates the development container
@@ -36,29 +43,54 @@ func Down() *cobra.Command {
 	var namespace string
 	var k8sContext string
 	var rm bool
+	var snapshot bool
+	var profiles []string
 
 	cmd := &cobra.Command{
 		Use:   "down",
 		Short: "Deactivates your development container",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if snapshot && !rm {
+				return fmt.Errorf("--snapshot can only be used with --volumes")
+			}
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			log.Info("starting down command")
 			ctx := context.Background()
-			dev, err := utils.LoadDev(devPath)
+			dev, err := utils.LoadDevWithProfiles(devPath, profiles)
 			if err != nil {
 				return err
 			}
 
 			dev.LoadContext(namespace, k8sContext)
 
+			if err := hooks.Run(hooks.PreDown, dev.Hooks.PreDown, dev.Hooks.Dir(), devContainerExec(dev)); err != nil {
+				return err
+			}
+
 			if err := runDown(ctx, dev); err != nil {
 				analytics.TrackDown(false)
 				return err
 			}
 
 			log.Success("Development container deactivated")
+
+			if err := hooks.Run(hooks.PostDown, dev.Hooks.PostDown, dev.Hooks.Dir(), nil); err != nil {
+				return err
+			}
 			log.Information("Run 'okteto push' to deploy your code changes to the cluster")
 
 			if rm {
+				if snapshot {
+					name, err := snapshotVolume(ctx, dev)
+					if err != nil {
+						return err
+					}
+					log.Success("Persistent volume snapshot '%s' created", name)
+					log.Information("Run 'okteto volume restore %s' to restore it", name)
+				}
+
 				if err := removeVolume(ctx, dev); err != nil {
 					analytics.TrackDownVolumes(false)
 					return err
@@ -83,7 +115,9 @@ func Down() *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&devPath, "file", "f", utils.DefaultDevManifest, "path to the manifest file")
+	cmd.Flags().StringSliceVarP(&profiles, "profile", "", nil, "profiles of the manifest applied by 'okteto up', to deactivate the services they add")
 	cmd.Flags().BoolVarP(&rm, "volumes", "v", false, "remove persistent volume")
+	cmd.Flags().BoolVarP(&snapshot, "snapshot", "", false, "take a snapshot of the persistent volume before removing it")
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "namespace where the down command is executed")
 	cmd.Flags().StringVarP(&k8sContext, "context", "c", "", "context where the down command is executed")
 	return cmd
@@ -120,6 +154,54 @@ func runDown(ctx context.Context, dev *model.Dev) error {
 	return nil
 }
 
+// devContainerExec returns the executor of the remote commands of the hooks in the development container of dev
+func devContainerExec(dev *model.Dev) hooks.RemoteExec {
+	return func(ctx context.Context, command []string, stdout, stderr io.Writer) error {
+		client, restConfig, namespace, err := k8Client.GetLocal(dev.Context)
+		if err != nil {
+			return err
+		}
+		if dev.Namespace == "" {
+			dev.Namespace = namespace
+		}
+
+		p, err := pods.GetDevPod(ctx, dev, client, false)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return fmt.Errorf("your development container is not running")
+		}
+
+		container := dev.Container
+		if container == "" {
+			container = p.Spec.Containers[0].Name
+		}
+		return exec.Exec(ctx, client, restConfig, dev.Namespace, p.Name, container, false, strings.NewReader(""), stdout, stderr, command)
+	}
+}
+
+func snapshotVolume(ctx context.Context, dev *model.Dev) (string, error) {
+	spinner := utils.NewSpinner("Taking a snapshot of the persistent volume...")
+	spinner.Start()
+	defer spinner.Stop()
+
+	_, restConfig, namespace, err := k8Client.GetLocal(dev.Context)
+	if err != nil {
+		return "", err
+	}
+	if dev.Namespace == "" {
+		dev.Namespace = namespace
+	}
+
+	dc, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return "", err
+	}
+
+	return volumes.Snapshot(ctx, dev, dc)
+}
+
 func removeVolume(ctx context.Context, dev *model.Dev) error {
 	spinner := utils.NewSpinner("Removing persistent volume...")
 	spinner.Start()
@@ -122,9 +122,14 @@ func executeExec(ctx context.Context, dev *model.Dev, args []string) error {
 			dev.RemotePort = p
 		}
 
-		dev.LoadRemote(ssh.GetPublicKey())
+		authorizedKeys, err := ssh.LoadIdentities(dev.SSHIdentities)
+		if err != nil {
+			return err
+		}
+
+		dev.LoadRemote(authorizedKeys)
 
-		return ssh.Exec(ctx, dev.Interface, dev.RemotePort, true, os.Stdin, os.Stdout, os.Stderr, wrapped)
+		return ssh.Exec(ctx, dev.Name, dev.Interface, dev.RemotePort, true, os.Stdin, os.Stdout, os.Stderr, wrapped)
 	}
 
 	return exec.Exec(ctx, client, cfg, dev.Namespace, p.Name, dev.Container, true, os.Stdin, os.Stdout, os.Stderr, wrapped)
@@ -0,0 +1,57 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forward
+
+import (
+	"github.com/okteto/okteto/cmd/utils"
+	"github.com/okteto/okteto/pkg/errors"
+	k8Client "github.com/okteto/okteto/pkg/k8s/client"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/spf13/cobra"
+)
+
+//Forward port forward management commands
+func Forward() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "forward",
+		Short: "Add or remove port forwards of a running 'okteto up'",
+	}
+	cmd.AddCommand(add())
+	cmd.AddCommand(remove())
+	return cmd
+}
+
+func loadDev(devPath, namespace, k8sContext string) (*model.Dev, error) {
+	if okteto.InDevContainer() {
+		return nil, errors.ErrNotInDevContainer
+	}
+
+	dev, err := utils.LoadDev(devPath)
+	if err != nil {
+		return nil, err
+	}
+	dev.LoadContext(namespace, k8sContext)
+
+	_, _, namespace, err = k8Client.GetLocal(dev.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	if dev.Namespace == "" {
+		dev.Namespace = namespace
+	}
+
+	return dev, nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forward
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/okteto/okteto/cmd/utils"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/ssh"
+	"github.com/spf13/cobra"
+)
+
+func remove() *cobra.Command {
+	var devPath string
+	var namespace string
+	var k8sContext string
+	cmd := &cobra.Command{
+		Use:   "remove <localPort>",
+		Short: "Removes a port forward from a running 'okteto up'",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("forward remove requires one argument")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log.Info("starting forward remove command")
+			port, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("'%s' is not a valid port", args[0])
+			}
+
+			dev, err := loadDev(devPath, namespace, k8sContext)
+			if err != nil {
+				return err
+			}
+
+			if err := ssh.RemoveForward(dev.Namespace, dev.Name, port); err != nil {
+				return fmt.Errorf("failed to remove port forward: %s", err)
+			}
+
+			log.Success("Stopped forwarding port %d", port)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&devPath, "file", "f", utils.DefaultDevManifest, "path to the manifest file")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "namespace where the up command is executing")
+	cmd.Flags().StringVarP(&k8sContext, "context", "c", "", "context where the up command is executing")
+	return cmd
+}
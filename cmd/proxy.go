@@ -0,0 +1,107 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/okteto/okteto/cmd/utils"
+	"github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/ssh"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+)
+
+//Proxy serves a local HTTP proxy to a port of your development container or a service of your namespace
+func Proxy() *cobra.Command {
+	var devPath string
+	var namespace string
+	var k8sContext string
+	var host string
+
+	cmd := &cobra.Command{
+		Use:   "proxy <localPort:[serviceName:]remotePort>",
+		Short: "Proxy HTTP requests from a local port to your development container, rewriting the Host header",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			f := model.Forward{}
+			if err := yaml.Unmarshal([]byte(args[0]), &f); err != nil {
+				return err
+			}
+
+			if f.Auto {
+				return fmt.Errorf("'auto' local ports are not supported by the proxy command")
+			}
+
+			dev, err := utils.LoadDev(devPath)
+			if err != nil {
+				return err
+			}
+			dev.LoadContext(namespace, k8sContext)
+
+			stop := make(chan os.Signal, 1)
+			signal.Notify(stop, os.Interrupt)
+			go func() {
+				<-stop
+				cancel()
+			}()
+
+			return executeProxy(ctx, dev, f, host)
+		},
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("proxy requires one argument")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&devPath, "file", "f", utils.DefaultDevManifest, "path to the manifest file")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "namespace where the proxy command is executed")
+	cmd.Flags().StringVarP(&k8sContext, "context", "c", "", "context where the proxy command is executed")
+	cmd.Flags().StringVarP(&host, "host", "", "", "Host header sent to your application (defaults to the service name)")
+
+	return cmd
+}
+
+func executeProxy(ctx context.Context, dev *model.Dev, f model.Forward, host string) error {
+	remotePort, err := ssh.GetPort(dev.Name)
+	if err != nil {
+		log.Infof("failed to get the SSH port for %s: %s", dev.Name, err)
+		return errors.UserError{
+			E:    fmt.Errorf("development mode is not enabled on your deployment"),
+			Hint: "Run 'okteto up' to enable it and try again",
+		}
+	}
+
+	if _, err := ssh.LoadIdentities(dev.SSHIdentities); err != nil {
+		return err
+	}
+
+	target, defaultHost := ssh.HTTPProxyTarget(f.ServiceName, f.Remote)
+	if host == "" {
+		host = defaultHost
+	}
+
+	localAddress := fmt.Sprintf("%s:%d", dev.Interface, f.Local)
+	log.Success("Proxying http://%s to %s with 'Host: %s'", localAddress, target, host)
+	return ssh.ServeHTTPProxy(ctx, dev.Name, dev.Interface, remotePort, localAddress, target, host)
+}
@@ -16,6 +16,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"github.com/okteto/okteto/cmd/utils"
 	"github.com/okteto/okteto/pkg/analytics"
@@ -45,6 +46,11 @@ func Push(ctx context.Context) *cobra.Command {
 	var progress string
 	var deploymentName string
 	var noCache bool
+	var skipIfExists bool
+	var scan bool
+	var scanSeverity string
+	var sign bool
+	var signKey string
 
 	cmd := &cobra.Command{
 		Use:   "push",
@@ -53,6 +59,16 @@ func Push(ctx context.Context) *cobra.Command {
 			log.Info("starting push command")
 			ctx := context.Background()
 
+			if err := build.ValidateProgress(progress); err != nil {
+				return err
+			}
+
+			if !scan {
+				scanSeverity = ""
+			} else if err := build.ValidateScanSeverity(scanSeverity); err != nil {
+				return err
+			}
+
 			dev, err := utils.LoadDevOrDefault(devPath, deploymentName)
 			if err != nil {
 				return err
@@ -88,7 +104,7 @@ func Push(ctx context.Context) *cobra.Command {
 				}
 			}
 
-			if err := runPush(ctx, dev, autoDeploy, imageTag, oktetoRegistryURL, progress, noCache, c); err != nil {
+			if err := runPush(ctx, dev, autoDeploy, imageTag, oktetoRegistryURL, progress, scanSeverity, noCache, skipIfExists, sign, signKey, c); err != nil {
 				analytics.TrackPush(false, oktetoRegistryURL)
 				return err
 			}
@@ -107,13 +123,18 @@ func Push(ctx context.Context) *cobra.Command {
 	cmd.Flags().StringVarP(&k8sContext, "context", "c", "", "context where the push command is executed")
 	cmd.Flags().StringVarP(&imageTag, "tag", "t", "", "image tag to build, push and redeploy")
 	cmd.Flags().BoolVarP(&autoDeploy, "deploy", "d", false, "create deployment when it doesn't exist in a namespace")
-	cmd.Flags().StringVarP(&progress, "progress", "", "tty", "show plain/tty build output")
+	cmd.Flags().StringVarP(&progress, "progress", "", build.ProgressTTY, "show tty, plain, json or quiet build output")
 	cmd.Flags().StringVar(&deploymentName, "name", "", "name of the deployment to push to")
 	cmd.Flags().BoolVarP(&noCache, "no-cache", "", false, "do not use cache when building the image")
+	cmd.Flags().BoolVarP(&skipIfExists, "skip-if-exists", "", false, "skip the build if the image was already pushed from the same build context")
+	cmd.Flags().BoolVarP(&scan, "scan", "", false, "scan the image for vulnerabilities with trivy before redeploying")
+	cmd.Flags().StringVarP(&scanSeverity, "scan-severity", "", build.DefaultScanSeverity, "lowest severity of the vulnerabilities that fail the scan (LOW, MEDIUM, HIGH or CRITICAL)")
+	cmd.Flags().BoolVarP(&sign, "sign", "", false, "sign the image with cosign before redeploying")
+	cmd.Flags().StringVarP(&signKey, "sign-key", "", "", "cosign key used to sign the image (Default is keyless signing)")
 	return cmd
 }
 
-func runPush(ctx context.Context, dev *model.Dev, autoDeploy bool, imageTag, oktetoRegistryURL, progress string, noCache bool, c *kubernetes.Clientset) error {
+func runPush(ctx context.Context, dev *model.Dev, autoDeploy bool, imageTag, oktetoRegistryURL, progress, scanSeverity string, noCache, skipIfExists, sign bool, signKey string, c *kubernetes.Clientset) error {
 	exists := true
 	d, err := deployments.Get(ctx, dev, dev.Namespace, c)
 
@@ -178,11 +199,23 @@ func runPush(ctx context.Context, dev *model.Dev, autoDeploy bool, imageTag, okt
 		return err
 	}
 
-	imageTag, err = buildImage(ctx, dev, imageTag, imageFromDeployment, oktetoRegistryURL, noCache, progress)
+	imageTag, err = buildImage(ctx, dev, imageTag, imageFromDeployment, oktetoRegistryURL, noCache, skipIfExists, progress)
 	if err != nil {
 		return err
 	}
 
+	if scanSeverity != "" {
+		if err := build.Scan(ctx, imageTag, scanSeverity); err != nil {
+			return err
+		}
+	}
+
+	if sign {
+		if err := build.Sign(ctx, imageTag, signKey); err != nil {
+			return err
+		}
+	}
+
 	spinner := utils.NewSpinner(fmt.Sprintf("Pushing source code to '%s'...", dev.Name))
 	spinner.Start()
 	defer spinner.Stop()
@@ -196,7 +229,7 @@ func runPush(ctx context.Context, dev *model.Dev, autoDeploy bool, imageTag, okt
 	if !exists {
 		d.Spec.Template.Spec.Containers[0].Image = imageTag
 		deployments.SetLastBuiltAnnotation(d)
-		return deployments.Deploy(ctx, d, true, c)
+		return deployments.Deploy(ctx, d, c)
 	}
 
 	for _, tr := range trList {
@@ -216,7 +249,7 @@ func runPush(ctx context.Context, dev *model.Dev, autoDeploy bool, imageTag, okt
 	return deployments.UpdateDeployments(ctx, trList, c)
 }
 
-func buildImage(ctx context.Context, dev *model.Dev, imageTag, imageFromDeployment, oktetoRegistryURL string, noCache bool, progress string) (string, error) {
+func buildImage(ctx context.Context, dev *model.Dev, imageTag, imageFromDeployment, oktetoRegistryURL string, noCache, skipIfExists bool, progress string) (string, error) {
 	buildKitHost, isOktetoCluster, err := build.GetBuildKitHost()
 	if err != nil {
 		return "", err
@@ -230,11 +263,39 @@ func buildImage(ctx context.Context, dev *model.Dev, imageTag, imageFromDeployme
 	log.Infof("pushing with image tag %s", buildTag)
 
 	buildArgs := model.SerializeBuildArgs(dev.Push.Args)
-	if err := build.Run(ctx, buildKitHost, isOktetoCluster, dev.Push.Context, dev.Push.Dockerfile, buildTag, dev.Push.Target, noCache, dev.Push.CacheFrom, buildArgs, progress); err != nil {
+	var digest string
+	if dev.Push.IsBuildpacks() {
+		digest, err = build.RunBuildpacks(ctx, dev.Push.Context, buildTag, dev.Push.GetBuilderImage(), noCache, buildArgs, os.Stdout)
+	} else {
+		digest, err = build.Run(ctx, build.Options{
+			BuildKitHost:    buildKitHost,
+			IsOktetoCluster: isOktetoCluster,
+			Path:            dev.Push.Context,
+			Dockerfile:      dev.Push.Dockerfile,
+			Tag:             buildTag,
+			Target:          dev.Push.Target,
+			Platform:        dev.Push.Platform,
+			NoCache:         noCache,
+			SkipIfExists:    skipIfExists,
+			Compression:     dev.Push.Compression,
+			CacheFrom:       dev.Push.CacheFrom,
+			BuildArgs:       buildArgs,
+			Secrets:         model.SerializeBuildSecrets(dev.Push.Secrets),
+			Ignore:          dev.Push.Ignore,
+			CacheScope:      dev.Push.GetCacheScope(dev.Namespace, dev.Name),
+			Labels:          dev.Push.Labels,
+			Frontend:        dev.Push.Frontend,
+			Progress:        progress,
+			Out:             os.Stdout,
+		})
+	}
+	if err != nil {
 		return "", fmt.Errorf("error building image '%s': %s", buildTag, err)
 	}
 
-	return buildTag, nil
+	image := registry.GetImageWithDigest(buildTag, digest)
+	log.Infof("deploying image %s", image)
+	return image, nil
 }
 
 func getImageFromDeployment(trList map[string]*model.Translation) (string, error) {
@@ -32,6 +32,7 @@ func Deploy(ctx context.Context) *cobra.Command {
 	var forceBuild bool
 	var wait bool
 	var noCache bool
+	var parallelism int
 
 	cmd := &cobra.Command{
 		Use:   "deploy <name>",
@@ -50,7 +51,7 @@ func Deploy(ctx context.Context) *cobra.Command {
 				return err
 			}
 
-			err = stack.Deploy(ctx, s, forceBuild, wait, noCache)
+			err = stack.Deploy(ctx, s, forceBuild, wait, noCache, parallelism)
 			analytics.TrackDeployStack(err == nil)
 			if err == nil {
 				log.Success("Successfully deployed stack '%s'", s.Name)
@@ -64,5 +65,6 @@ func Deploy(ctx context.Context) *cobra.Command {
 	cmd.Flags().BoolVarP(&forceBuild, "build", "", false, "build images before starting any Stack service")
 	cmd.Flags().BoolVarP(&wait, "wait", "", false, "wait until a minimum number of containers are in a ready state for every service")
 	cmd.Flags().BoolVarP(&noCache, "no-cache", "", false, "do not use cache when building the image")
+	cmd.Flags().IntVarP(&parallelism, "parallelism", "", 4, "maximum number of images built at the same time")
 	return cmd
 }
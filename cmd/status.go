@@ -25,6 +25,7 @@ import (
 	"github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/model"
 	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/okteto/okteto/pkg/ssh"
 	"github.com/okteto/okteto/pkg/syncthing"
 	"github.com/spf13/cobra"
 )
@@ -71,8 +72,11 @@ func Status() *cobra.Command {
 				log.Information("Remote syncthing url: http://%s", sy.RemoteGUIAddress)
 				log.Information("Syncthing username: okteto")
 				log.Information("Syncthing password: %s", sy.GUIPassword)
+				printSSHStats(dev)
 			}
 
+			warnSSHLatency(dev)
+
 			ctx := context.Background()
 			if watch {
 				err = runWithWatch(ctx, dev, sy)
@@ -126,3 +130,37 @@ func runWithoutWatch(ctx context.Context, dev *model.Dev, sy *syncthing.Syncthin
 	}
 	return nil
 }
+
+func printSSHStats(dev *model.Dev) {
+	stats, err := ssh.LoadStats(dev.Namespace, dev.Name)
+	if err != nil {
+		log.Infof("error accessing the ssh stats file: %s", err)
+		return
+	}
+
+	log.Information("SSH clients: %d (updated %s ago)", stats.Clients, time.Since(stats.Timestamp).Round(time.Second))
+	log.Information("SSH active channels: %d", stats.ActiveChannels)
+	log.Information("SSH reconnects: %d", stats.Reconnects)
+	log.Information("SSH missed keepalives: %d", stats.MissedKeepAlives)
+	log.Information("SSH dial latency: %s", stats.DialLatency)
+	log.Information("SSH keepalive latency: %s", stats.Health.Latency)
+	for _, f := range stats.Forwards {
+		log.Information("%s: %d bytes in, %d bytes out", f.Name, f.BytesIn, f.BytesOut)
+	}
+}
+
+func warnSSHLatency(dev *model.Dev) {
+	stats, err := ssh.LoadStats(dev.Namespace, dev.Name)
+	if err != nil {
+		return
+	}
+
+	if stats.IsStale() {
+		log.Infof("ignoring the ssh stats saved %s ago", time.Since(stats.Timestamp).Round(time.Second))
+		return
+	}
+
+	if stats.Health.Latency > 0 && !stats.Health.Healthy {
+		log.Yellow("The connection to your development container is slow (%s round-trip latency)", stats.Health.Latency.Round(time.Millisecond))
+	}
+}
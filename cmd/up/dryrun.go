@@ -0,0 +1,134 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package up
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/k8s/deployments"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/pmezard/go-difflib/difflib"
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// dryRun prints the changes that 'okteto up' would make to the deployments of the manifest, without applying them
+func (up *upContext) dryRun(ctx context.Context) error {
+	if err := up.initClient(ctx); err != nil {
+		return err
+	}
+
+	d, err := deployments.Get(ctx, up.Dev, up.Dev.Namespace, up.Client)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("couldn't get deployment %s/%s, please try again: %s", up.Dev.Namespace, up.Dev.Name, err)
+		}
+
+		log.Information("Deployment '%s' doesn't exist, it would be created", up.Dev.Name)
+		d = up.Dev.GevSandbox()
+	}
+
+	if err := up.setDevContainer(d); err != nil {
+		return err
+	}
+
+	trList, err := deployments.GetTranslations(ctx, up.Dev, d, up.Client)
+	if err != nil {
+		return err
+	}
+
+	originals := map[string]*appsv1.Deployment{}
+	for name, tr := range trList {
+		originals[name] = tr.Deployment.DeepCopy()
+	}
+
+	if err := deployments.TranslateDevMode(trList, up.Client, up.isOktetoNamespace); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(trList))
+	for name := range trList {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		diff, err := deploymentDiff(originals[name], trList[name].Deployment)
+		if err != nil {
+			return err
+		}
+
+		printDiff(color.Output, diff)
+	}
+
+	return nil
+}
+
+func deploymentDiff(original, translated *appsv1.Deployment) (string, error) {
+	before, err := deploymentYAML(original)
+	if err != nil {
+		return "", err
+	}
+
+	after, err := deploymentYAML(translated)
+	if err != nil {
+		return "", err
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(before),
+		B:        difflib.SplitLines(after),
+		FromFile: fmt.Sprintf("deployment/%s (current)", original.Name),
+		ToFile:   fmt.Sprintf("deployment/%s (development)", translated.Name),
+		Context:  3,
+	})
+}
+
+func deploymentYAML(d *appsv1.Deployment) (string, error) {
+	d = d.DeepCopy()
+	d.ManagedFields = nil
+	d.Status = appsv1.DeploymentStatus{}
+
+	b, err := yaml.Marshal(d)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize deployment %s: %w", d.Name, err)
+	}
+
+	return string(b), nil
+}
+
+func printDiff(w io.Writer, diff string) {
+	added := color.New(color.FgGreen).SprintFunc()
+	removed := color.New(color.FgHiRed).SprintFunc()
+	header := color.New(color.FgHiBlue).SprintFunc()
+
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"), strings.HasPrefix(line, "@@"):
+			fmt.Fprint(w, header(line))
+		case strings.HasPrefix(line, "+"):
+			fmt.Fprint(w, added(line))
+		case strings.HasPrefix(line, "-"):
+			fmt.Fprint(w, removed(line))
+		default:
+			fmt.Fprint(w, line)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package up
+
+import (
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_deploymentDiff(t *testing.T) {
+	original := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api"},
+		Spec: appsv1.DeploymentSpec{
+			Template: apiv1.PodTemplateSpec{
+				Spec: apiv1.PodSpec{
+					Containers: []apiv1.Container{{Name: "api", Image: "api:1.0"}},
+				},
+			},
+		},
+	}
+
+	translated := original.DeepCopy()
+	translated.Spec.Template.Spec.Containers[0].Image = "okteto/golang:1"
+
+	diff, err := deploymentDiff(original, translated)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(diff, "-      - image: api:1.0") {
+		t.Errorf("original image not removed in diff:\n%s", diff)
+	}
+
+	if !strings.Contains(diff, "+      - image: okteto/golang:1") {
+		t.Errorf("dev image not added in diff:\n%s", diff)
+	}
+
+	if !strings.Contains(diff, "deployment/api (development)") {
+		t.Errorf("missing diff header:\n%s", diff)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package up
+
+import (
+	"context"
+	"strings"
+
+	"github.com/okteto/okteto/pkg/k8s/events"
+	"github.com/okteto/okteto/pkg/log"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// streamEvents prints the warnings and image pulls of the development container until ctx is done
+func (up *upContext) streamEvents(ctx context.Context, deployment string) {
+	if err := events.Stream(ctx, up.Dev, deployment, up.Client, printEvent); err != nil {
+		log.Infof("failed to stream the events of %s: %s", deployment, err)
+	}
+}
+
+func printEvent(e *apiv1.Event) {
+	object := strings.ToLower(e.InvolvedObject.Kind) + "/" + e.InvolvedObject.Name
+	log.Infof("%s event %s: %s", object, e.Reason, e.Message)
+
+	switch {
+	case e.Type == apiv1.EventTypeWarning:
+		log.Yellow("%s %s: %s", e.Reason, object, e.Message)
+	case e.Reason == "Pulled":
+		log.Information("%s: %s", object, e.Message)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package up
+
+import (
+	"context"
+
+	"github.com/okteto/okteto/pkg/k8s/deployments"
+	"github.com/okteto/okteto/pkg/log"
+)
+
+// watchExternalChanges warns the user when another controller updates the deployment in development mode until ctx is done
+func (up *upContext) watchExternalChanges(ctx context.Context, deployment string) {
+	if err := deployments.WatchExternalChanges(ctx, up.Dev.Namespace, deployment, up.Client, printExternalChange); err != nil {
+		log.Infof("failed to watch the changes of %s: %s", deployment, err)
+	}
+}
+
+func printExternalChange(change *deployments.ExternalChange) {
+	log.Infof("deployment %s updated by %s", change.Deployment, change.Manager)
+	if change.Controller != "" {
+		log.Yellow("%s ('%s') updated the deployment '%s' in development mode and can revert your development container", change.Controller, change.Manager, change.Deployment)
+	} else {
+		log.Yellow("'%s' updated the deployment '%s' in development mode and can revert your development container", change.Manager, change.Deployment)
+	}
+	log.Yellow("%s", change.Hint)
+}
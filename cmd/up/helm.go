@@ -0,0 +1,38 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package up
+
+import (
+	"context"
+
+	"github.com/okteto/okteto/pkg/k8s/deployments"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+)
+
+// checkHelmReleases warns when the helm release of a deployment has a pending operation
+func (up *upContext) checkHelmReleases(ctx context.Context, trList map[string]*model.Translation) {
+	for _, tr := range trList {
+		status, err := deployments.GetPendingHelmOperation(ctx, tr.Deployment, up.Client)
+		if err != nil {
+			log.Infof("failed to check the helm release of '%s': %s", tr.Deployment.Name, err)
+			continue
+		}
+
+		if status != "" {
+			log.Yellow("The helm release of '%s' has a pending operation (%s).", tr.Deployment.Name, status)
+			log.Yellow("Wait for it to finish or your development container could be overwritten.")
+		}
+	}
+}
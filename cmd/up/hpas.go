@@ -0,0 +1,43 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package up
+
+import (
+	"context"
+	"strings"
+
+	"github.com/okteto/okteto/pkg/k8s/hpas"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// pinHPAs keeps the horizontal pod autoscalers from scaling up the deployments in development mode
+func (up *upContext) pinHPAs(ctx context.Context, trList map[string]*model.Translation) error {
+	ds := []*appsv1.Deployment{}
+	for _, tr := range trList {
+		ds = append(ds, tr.Deployment)
+	}
+
+	pinned, err := hpas.Pin(ctx, up.Dev, ds, up.Client)
+	if err != nil {
+		return err
+	}
+
+	if len(pinned) > 0 {
+		log.Yellow("Pinned horizontal pod autoscalers '%s' to one replica until you run 'okteto down'", strings.Join(pinned, "', '"))
+	}
+
+	return nil
+}
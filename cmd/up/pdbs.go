@@ -0,0 +1,48 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package up
+
+import (
+	"context"
+	"strings"
+
+	"github.com/okteto/okteto/pkg/k8s/pdbs"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// relaxPDBs relaxes the pod disruption budgets that would block the rollout of the development container
+func (up *upContext) relaxPDBs(ctx context.Context, trList map[string]*model.Translation) error {
+	if up.keepPDBs {
+		return nil
+	}
+
+	ds := []*appsv1.Deployment{}
+	for _, tr := range trList {
+		ds = append(ds, tr.Deployment)
+	}
+
+	relaxed, err := pdbs.Relax(ctx, up.Dev, ds, up.Client)
+	if err != nil {
+		return err
+	}
+
+	if len(relaxed) > 0 {
+		log.Yellow("Relaxed pod disruption budgets '%s' until you run 'okteto down'", strings.Join(relaxed, "', '"))
+		log.Yellow("Use the '--keep-pdbs' flag to leave them unchanged")
+	}
+
+	return nil
+}
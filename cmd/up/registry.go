@@ -0,0 +1,77 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package up
+
+import (
+	"context"
+
+	"github.com/okteto/okteto/pkg/k8s/secrets"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/okteto/okteto/pkg/registry"
+)
+
+// setRegistryPullSecret lets the development containers pull images from the okteto registry
+// in namespaces that are not managed by okteto
+func (up *upContext) setRegistryPullSecret(ctx context.Context, trList map[string]*model.Translation) error {
+	if up.isOktetoNamespace {
+		return nil
+	}
+
+	registryURL, err := okteto.GetRegistry()
+	if err != nil {
+		log.Infof("okteto registry not available: %s", err)
+		return nil
+	}
+
+	namespaces := []string{}
+	for _, tr := range trList {
+		needed := false
+		for _, rule := range tr.Rules {
+			if registry.IsOktetoRegistryImage(rule.Image, registryURL) {
+				rule.ImagePullSecret = secrets.RegistrySecretName
+				needed = true
+			}
+		}
+		if needed && !contains(namespaces, tr.Deployment.Namespace) {
+			namespaces = append(namespaces, tr.Deployment.Namespace)
+		}
+	}
+
+	if len(namespaces) == 0 {
+		return nil
+	}
+
+	token, err := okteto.GetToken()
+	if err != nil {
+		return err
+	}
+
+	for _, namespace := range namespaces {
+		if err := secrets.CreateRegistrySecret(ctx, namespace, registryURL, okteto.GetUserID(), token.Token, up.Client); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
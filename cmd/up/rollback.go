@@ -0,0 +1,51 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package up
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/okteto/okteto/pkg/cmd/down"
+	"github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// rollback restores the original deployments when the development container fails to start
+func (up *upContext) rollback(d *appsv1.Deployment, trList map[string]*model.Translation, failedErr *errors.DevPodFailedError) error {
+	log.Infof("development container failed to start, restoring the original deployments: %s", failedErr)
+	if err := down.Run(up.Dev, d, trList, false, up.Client); err != nil {
+		log.Infof("failed to restore the original deployments: %s", err)
+		return errors.UserError{
+			E:    fmt.Errorf("your development container failed to start: %s", failedErr),
+			Hint: "Run 'okteto down' to restore your original deployment",
+		}
+	}
+
+	return errors.UserError{
+		E:    fmt.Errorf("your development container failed to start: %s", failedErr),
+		Hint: rollbackHint(failedErr.Events),
+	}
+}
+
+func rollbackHint(events []string) string {
+	hint := "Your original deployment was restored"
+	if len(events) == 0 {
+		return hint
+	}
+
+	return fmt.Sprintf("%s. Latest events of your development container:\n      %s", hint, strings.Join(events, "\n      "))
+}
@@ -16,10 +16,13 @@ package up
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/okteto/okteto/pkg/config"
 	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
 )
 
 type upState string
@@ -34,6 +37,7 @@ const (
 	ready         upState = "ready"
 	failed        upState = "failed"
 	stateFile             = "okteto.state"
+	forwardsFile          = "okteto.forwards"
 )
 
 func (up *upContext) updateStateFile(state upState) {
@@ -60,3 +64,23 @@ func (up *upContext) updateStateFileWithMessage(state upState, message string) {
 		log.Infof("failed to update state file, %s", err)
 	}
 }
+
+// saveAutoForwards records the local ports selected for 'auto' forwards as environment variables, so tools can source them
+func (up *upContext) saveAutoForwards(forwards []model.Forward) {
+	s := filepath.Join(config.GetDeploymentHome(up.Dev.Namespace, up.Dev.Name), forwardsFile)
+	if len(forwards) == 0 {
+		if err := os.Remove(s); err != nil && !os.IsNotExist(err) {
+			log.Infof("failed to delete forwards file, %s", err)
+		}
+		return
+	}
+
+	var b strings.Builder
+	for _, f := range forwards {
+		fmt.Fprintf(&b, "%s=%d\n", f.EnvVar(), f.Local)
+	}
+
+	if err := ioutil.WriteFile(s, []byte(b.String()), 0644); err != nil {
+		log.Infof("failed to update forwards file, %s", err)
+	}
+}
@@ -41,6 +41,10 @@ type upContext struct {
 	cleaned           chan string
 	success           bool
 	resetSyncthing    bool
+	keepPDBs          bool
+	buildProgress     string
+	skipIfExists      bool
+	postUpCompleted   bool
 	inFd              uintptr
 	isTerm            bool
 	stateTerm         *term.State
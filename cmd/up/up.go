@@ -17,6 +17,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
 	"os/signal"
@@ -29,14 +30,18 @@ import (
 	"github.com/okteto/okteto/cmd/utils"
 	"github.com/okteto/okteto/pkg/analytics"
 	buildCMD "github.com/okteto/okteto/pkg/cmd/build"
+	"github.com/okteto/okteto/pkg/cmd/hooks"
 	"github.com/okteto/okteto/pkg/config"
 	"github.com/okteto/okteto/pkg/errors"
 	k8Client "github.com/okteto/okteto/pkg/k8s/client"
 	"github.com/okteto/okteto/pkg/k8s/deployments"
 	"github.com/okteto/okteto/pkg/k8s/exec"
+	"github.com/okteto/okteto/pkg/k8s/informers"
 	okLabels "github.com/okteto/okteto/pkg/k8s/labels"
+	"github.com/okteto/okteto/pkg/k8s/leases"
 	"github.com/okteto/okteto/pkg/k8s/namespaces"
 	"github.com/okteto/okteto/pkg/k8s/pods"
+	"github.com/okteto/okteto/pkg/k8s/quotas"
 	"github.com/okteto/okteto/pkg/k8s/secrets"
 	"github.com/okteto/okteto/pkg/k8s/services"
 	"github.com/okteto/okteto/pkg/k8s/volumes"
@@ -56,6 +61,9 @@ import (
 // ReconnectingMessage is the message shown when we are trying to reconnect
 const ReconnectingMessage = "Trying to reconnect to your cluster. File synchronization will automatically resume when the connection improves."
 
+// credentialsRefreshInterval is the minimum time between two refreshes of the cluster credentials
+const credentialsRefreshInterval = time.Minute
+
 var (
 	localClusters = []string{"127.", "172.", "192.", "169.", model.Localhost, "::1", "fe80::", "fc00::"}
 )
@@ -70,6 +78,12 @@ func Up() *cobra.Command {
 	var build bool
 	var forcePull bool
 	var resetSyncthing bool
+	var socks string
+	var dryRun bool
+	var keepPDBs bool
+	var progress string
+	var skipIfExists bool
+	var profiles []string
 	cmd := &cobra.Command{
 		Use:   "up",
 		Short: "Activates your development container",
@@ -80,6 +94,10 @@ func Up() *cobra.Command {
 				return errors.ErrNotInDevContainer
 			}
 
+			if err := buildCMD.ValidateProgress(progress); err != nil {
+				return err
+			}
+
 			u := upgradeAvailable()
 			if len(u) > 0 {
 				warningFolder := filepath.Join(config.GetOktetoHome(), ".warnings")
@@ -110,12 +128,12 @@ func Up() *cobra.Command {
 
 			checkLocalWatchesConfiguration()
 
-			dev, err := loadDevOrInit(namespace, k8sContext, devPath)
+			dev, err := loadDevOrInit(namespace, k8sContext, devPath, profiles)
 			if err != nil {
 				return err
 			}
 
-			if err := loadDevOverrides(dev, namespace, k8sContext, forcePull, remote); err != nil {
+			if err := loadDevOverrides(dev, namespace, k8sContext, forcePull, remote, socks); err != nil {
 				return err
 			}
 
@@ -131,7 +149,15 @@ func Up() *cobra.Command {
 				Dev:            dev,
 				Exit:           make(chan error, 1),
 				resetSyncthing: resetSyncthing,
+				keepPDBs:       keepPDBs,
+				buildProgress:  progress,
+				skipIfExists:   skipIfExists,
+			}
+
+			if dryRun {
+				return up.dryRun(context.Background())
 			}
+
 			up.inFd, up.isTerm = term.GetFdInfo(os.Stdin)
 			if up.isTerm {
 				var err error
@@ -149,18 +175,24 @@ func Up() *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&devPath, "file", "f", utils.DefaultDevManifest, "path to the manifest file")
+	cmd.Flags().StringSliceVarP(&profiles, "profile", "", nil, "profiles of the manifest to apply, in order (e.g. 'debug')")
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "namespace where the up command is executed")
 	cmd.Flags().StringVarP(&k8sContext, "context", "c", "", "context where the up command is executed")
 	cmd.Flags().IntVarP(&remote, "remote", "r", 0, "configures remote execution on the specified port")
 	cmd.Flags().BoolVarP(&autoDeploy, "deploy", "d", false, "create deployment when it doesn't exist in a namespace")
 	cmd.Flags().BoolVarP(&build, "build", "", false, "build on-the-fly the dev image using the info provided by the 'build' okteto manifest field")
+	cmd.Flags().BoolVarP(&skipIfExists, "skip-if-exists", "", false, "skip building the dev image if it was already pushed from the same build context")
 	cmd.Flags().BoolVarP(&forcePull, "pull", "", false, "force dev image pull")
 	cmd.Flags().BoolVarP(&resetSyncthing, "reset", "", false, "reset the file synchronization database")
+	cmd.Flags().StringVarP(&socks, "socks", "", "", "start a SOCKS5 proxy on the given address (e.g. localhost:1080) to reach the services of your namespace")
+	cmd.Flags().BoolVarP(&dryRun, "dry-run", "", false, "print the changes that would be made to your deployments without applying them")
+	cmd.Flags().StringVarP(&progress, "progress", "", buildCMD.ProgressTTY, "show tty, plain, json or quiet build output when building the dev image")
+	cmd.Flags().BoolVarP(&keepPDBs, "keep-pdbs", "", false, "don't relax the pod disruption budgets that block the rollout of your development container")
 	return cmd
 }
 
-func loadDevOrInit(namespace, k8sContext, devPath string) (*model.Dev, error) {
-	dev, err := utils.LoadDev(devPath)
+func loadDevOrInit(namespace, k8sContext, devPath string, profiles []string) (*model.Dev, error) {
+	dev, err := utils.LoadDevWithProfiles(devPath, profiles)
 
 	if err == nil {
 		return dev, nil
@@ -181,10 +213,10 @@ func loadDevOrInit(namespace, k8sContext, devPath string) (*model.Dev, error) {
 	}
 
 	log.Success(fmt.Sprintf("okteto manifest (%s) created", devPath))
-	return utils.LoadDev(devPath)
+	return utils.LoadDevWithProfiles(devPath, profiles)
 }
 
-func loadDevOverrides(dev *model.Dev, namespace, k8sContext string, forcePull bool, remote int) error {
+func loadDevOverrides(dev *model.Dev, namespace, k8sContext string, forcePull bool, remote int, socks string) error {
 
 	dev.LoadContext(namespace, k8sContext)
 
@@ -192,12 +224,21 @@ func loadDevOverrides(dev *model.Dev, namespace, k8sContext string, forcePull bo
 		dev.RemotePort = remote
 	}
 
+	if socks != "" {
+		dev.Proxy = socks
+	}
+
 	if dev.RemoteModeEnabled() {
 		if err := sshKeys(); err != nil {
 			return err
 		}
 
-		dev.LoadRemote(ssh.GetPublicKey())
+		authorizedKeys, err := ssh.LoadIdentities(dev.SSHIdentities)
+		if err != nil {
+			return err
+		}
+
+		dev.LoadRemote(authorizedKeys)
 	}
 
 	if forcePull {
@@ -208,33 +249,10 @@ func loadDevOverrides(dev *model.Dev, namespace, k8sContext string, forcePull bo
 }
 
 func (up *upContext) start(autoDeploy, build bool) error {
-
-	var namespace string
-	var err error
-	up.Client, up.RestConfig, namespace, err = k8Client.GetLocal(up.Dev.Context)
-	if err != nil {
-		kubecfg := config.GetKubeConfigFile()
-		log.Infof("failed to load local Kubeconfig: %s", err)
-		return fmt.Errorf("failed to load your local Kubeconfig: %q context not found in %q", up.Dev.Context, kubecfg)
-	}
-
-	if up.Dev.Namespace == "" {
-		up.Dev.Namespace = namespace
-	}
-
-	ctx := context.Background()
-	ns, err := namespaces.Get(ctx, up.Dev.Namespace, up.Client)
-	if err != nil {
-		log.Infof("failed to get namespace %s: %s", up.Dev.Namespace, err)
-		return fmt.Errorf("couldn't get namespace/%s, please try again", up.Dev.Namespace)
-	}
-
-	if !namespaces.IsOktetoAllowed(ns) {
-		return fmt.Errorf("'okteto up' is not allowed in the current namespace")
+	if err := up.initClient(context.Background()); err != nil {
+		return err
 	}
 
-	up.isOktetoNamespace = namespaces.IsOktetoNamespace(ns)
-
 	if err := createPIDFile(up.Dev.Namespace, up.Dev.Name); err != nil {
 		log.Infof("failed to create pid file for %s - %s: %s", up.Dev.Namespace, up.Dev.Name, err)
 		return fmt.Errorf("couldn't create pid file for %s - %s", up.Dev.Namespace, up.Dev.Name)
@@ -242,6 +260,10 @@ func (up *upContext) start(autoDeploy, build bool) error {
 
 	defer cleanPIDFile(up.Dev.Namespace, up.Dev.Name)
 
+	if err := hooks.Run(hooks.PreUp, up.Dev.Hooks.PreUp, up.Dev.Hooks.Dir(), nil); err != nil {
+		return err
+	}
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt)
 
@@ -263,10 +285,54 @@ func (up *upContext) start(autoDeploy, build bool) error {
 	return nil
 }
 
+// refreshClient rebuilds the kubernetes client so that expired cluster credentials are loaded again from the kubeconfig
+func (up *upContext) refreshClient() error {
+	log.Infof("refreshing the cluster credentials")
+	k8Client.Reset()
+	c, restConfig, _, err := k8Client.GetLocal(up.Dev.Context)
+	if err != nil {
+		return err
+	}
+
+	up.Client = c
+	up.RestConfig = restConfig
+	return nil
+}
+
+// initClient loads the kubernetes client of the context of the development container and checks that its namespace allows 'okteto up'
+func (up *upContext) initClient(ctx context.Context) error {
+	var namespace string
+	var err error
+	up.Client, up.RestConfig, namespace, err = k8Client.GetLocal(up.Dev.Context)
+	if err != nil {
+		kubecfg := config.GetKubeConfigFile()
+		log.Infof("failed to load local Kubeconfig: %s", err)
+		return fmt.Errorf("failed to load your local Kubeconfig: %q context not found in %q", up.Dev.Context, kubecfg)
+	}
+
+	if up.Dev.Namespace == "" {
+		up.Dev.Namespace = namespace
+	}
+
+	ns, err := namespaces.Get(ctx, up.Dev.Namespace, up.Client)
+	if err != nil {
+		log.Infof("failed to get namespace %s: %s", up.Dev.Namespace, err)
+		return fmt.Errorf("couldn't get namespace/%s, please try again", up.Dev.Namespace)
+	}
+
+	if !namespaces.IsOktetoAllowed(ns) {
+		return fmt.Errorf("'okteto up' is not allowed in the current namespace")
+	}
+
+	up.isOktetoNamespace = namespaces.IsOktetoNamespace(ns)
+	return nil
+}
+
 // activateLoop activates the development container in a retry loop
 func (up *upContext) activateLoop(autoDeploy, build bool) {
 	isRetry := false
 	isTransientError := false
+	var lastCredentialsRefresh time.Time
 	t := time.NewTicker(1 * time.Second)
 	iter := 0
 	defer t.Stop()
@@ -300,6 +366,16 @@ func (up *upContext) activateLoop(autoDeploy, build bool) {
 				continue
 			}
 
+			if errors.IsUnauthorized(err) && time.Since(lastCredentialsRefresh) > credentialsRefreshInterval {
+				lastCredentialsRefresh = time.Now()
+				if err := up.refreshClient(); err != nil {
+					log.Infof("failed to refresh the cluster credentials: %s", err)
+				} else {
+					isTransientError = true
+					continue
+				}
+			}
+
 			up.Exit <- err
 			return
 		}
@@ -322,6 +398,10 @@ func (up *upContext) activate(isRetry, autoDeploy, build bool) error {
 	up.CommandResult = make(chan error, 1)
 	up.cleaned = make(chan string, 1)
 
+	if err := informers.Start(ctx, up.Dev.Namespace, up.Client); err != nil {
+		log.Infof("failed to start the informer cache, reading from the API server: %s", err)
+	}
+
 	d, create, err := up.getCurrentDeployment(ctx, autoDeploy, isRetry)
 	if err != nil {
 		return err
@@ -362,15 +442,47 @@ func (up *upContext) activate(isRetry, autoDeploy, build bool) error {
 		return err
 	}
 
+	if err := quotas.Check(ctx, up.Dev, d, up.Client); err != nil {
+		return err
+	}
+
+	if err := leases.Acquire(ctx, up.Dev, up.Client); err != nil {
+		if err != leases.ErrNotAvailable {
+			return err
+		}
+		if !isRetry {
+			log.Yellow("Leases are not available in your namespace: other users won't be warned if they activate '%s' at the same time", up.Dev.Name)
+		}
+	} else {
+		go leases.Renew(ctx, up.Dev, up.Client)
+	}
+	eventsCtx, stopEvents := context.WithCancel(ctx)
+	defer stopEvents()
+	go up.streamEvents(eventsCtx, d.Name)
+
 	if err := up.devMode(ctx, d, create); err != nil {
+		if _, ok := err.(errors.UserError); ok {
+			return err
+		}
 		return fmt.Errorf("couldn't activate your development container (%s): %s", up.Dev.Container, err.Error())
 	}
 
 	log.Success("Development container activated")
+	go up.watchExternalChanges(ctx, d.Name)
 
 	if err := up.forwards(ctx); err != nil {
-		if err == errors.ErrSSHConnectError {
-			err := up.checkOktetoStartError(ctx, "Failed to connect to your development container")
+		if err == errors.ErrHostKeyMismatch {
+			return errors.UserError{
+				E:    err,
+				Hint: "If you recreated your development container, run 'okteto doctor --reset-hostkey' and try again",
+			}
+		}
+		if connectErr, ok := err.(*errors.SSHConnectError); ok {
+			msg := "Failed to connect to your development container"
+			if connectErr.Reachable {
+				msg = "Your development container rejected the SSH connection"
+			}
+			err := up.checkOktetoStartError(ctx, msg)
 			if err == errors.ErrLostSyncthing {
 				if err := pods.Destroy(ctx, up.Pod, up.Dev.Namespace, up.Client); err != nil {
 					return fmt.Errorf("error recreating development container: %s", err.Error())
@@ -381,6 +493,8 @@ func (up *upContext) activate(isRetry, autoDeploy, build bool) error {
 		return fmt.Errorf("couldn't connect to your development container: %s", err.Error())
 	}
 	log.Success("Connected to your development container")
+	up.warnRemappedPorts()
+	up.printAutoForwards()
 
 	go up.cleanCommand(ctx)
 
@@ -400,6 +514,13 @@ func (up *upContext) activate(isRetry, autoDeploy, build bool) error {
 	}
 	log.Success("Files synchronized")
 
+	if !up.postUpCompleted {
+		if err := hooks.Run(hooks.PostUp, up.Dev.Hooks.PostUp, up.Dev.Hooks.Dir(), up.remoteExec); err != nil {
+			return err
+		}
+		up.postUpCompleted = true
+	}
+
 	go func() {
 		output := <-up.cleaned
 		log.Debugf("clean command output: %s", output)
@@ -416,6 +537,8 @@ func (up *upContext) activate(isRetry, autoDeploy, build bool) error {
 			}
 		}
 
+		// events printed after this point would be written to the interactive terminal of the development container
+		stopEvents()
 		printDisplayContext(up.Dev)
 		up.CommandResult <- up.runCommand(ctx)
 	}()
@@ -496,8 +619,14 @@ func (up *upContext) waitUntilExitOrInterrupt() error {
 			return nil
 
 		case err := <-up.Disconnect:
-			if err == errors.ErrInsufficientSpace {
+			switch err {
+			case errors.ErrInsufficientSpace:
 				return up.getInsufficientSpaceError(err)
+			case errors.ErrSSHConnectionLost:
+				return errors.UserError{
+					E:    err,
+					Hint: fmt.Sprintf("Your development container stopped answering the last %d SSH keepalives.\n    Check your network connection or tune 'sshKeepalive' and 'sshMaxMissedKeepalives' in your okteto manifest", up.Dev.SSHMaxMissedKeepalives),
+				}
 			}
 			return err
 		}
@@ -532,20 +661,47 @@ func (up *upContext) buildDevImage(ctx context.Context, d *appsv1.Deployment, cr
 	}
 	log.Information("Running your build in %s...", buildKitHost)
 
-	imageTag := registry.GetImageTag(up.Dev.Image.Name, up.Dev.Name, up.Dev.Namespace, oktetoRegistryURL)
+	imageTag := registry.GetImageTag(up.Dev.Image.Name, up.Dev.Name, up.Dev.Namespace, oktetoRegistryURL, up.Dev.Image)
 	log.Infof("building dev image tag %s", imageTag)
 
 	buildArgs := model.SerializeBuildArgs(up.Dev.Image.Args)
-	if err := buildCMD.Run(ctx, buildKitHost, isOktetoCluster, up.Dev.Image.Context, up.Dev.Image.Dockerfile, imageTag, up.Dev.Image.Target, false, up.Dev.Image.CacheFrom, buildArgs, "tty"); err != nil {
+	var digest string
+	if up.Dev.Image.IsBuildpacks() {
+		digest, err = buildCMD.RunBuildpacks(ctx, up.Dev.Image.Context, imageTag, up.Dev.Image.GetBuilderImage(), false, buildArgs, os.Stdout)
+	} else {
+		digest, err = buildCMD.Run(ctx, buildCMD.Options{
+			BuildKitHost:    buildKitHost,
+			IsOktetoCluster: isOktetoCluster,
+			Path:            up.Dev.Image.Context,
+			Dockerfile:      up.Dev.Image.Dockerfile,
+			Tag:             imageTag,
+			Target:          buildCMD.GetDevTarget(up.Dev.Image),
+			Platform:        up.Dev.Image.Platform,
+			SkipIfExists:    up.skipIfExists,
+			Compression:     up.Dev.Image.Compression,
+			CacheFrom:       up.Dev.Image.CacheFrom,
+			BuildArgs:       buildArgs,
+			Secrets:         model.SerializeBuildSecrets(up.Dev.Image.Secrets),
+			Ignore:          up.Dev.Image.Ignore,
+			CacheScope:      up.Dev.Image.GetCacheScope(up.Dev.Namespace, up.Dev.Name),
+			Labels:          up.Dev.Image.Labels,
+			Frontend:        up.Dev.Image.Frontend,
+			Progress:        up.buildProgress,
+			Out:             os.Stdout,
+		})
+	}
+	if err != nil {
 		return fmt.Errorf("error building dev image '%s': %s", imageTag, err)
 	}
+	image := registry.GetImageWithDigest(imageTag, digest)
+	log.Infof("using dev image %s", image)
 	for _, s := range up.Dev.Services {
 		if s.Image.Name == up.Dev.Image.Name {
-			s.Image.Name = imageTag
+			s.Image.Name = image
 			s.SetLastBuiltAnnotation()
 		}
 	}
-	up.Dev.Image.Name = imageTag
+	up.Dev.Image.Name = image
 	up.Dev.SetLastBuiltAnnotation()
 	return nil
 }
@@ -589,19 +745,27 @@ func (up *upContext) devMode(ctx context.Context, d *appsv1.Deployment, create b
 		return err
 	}
 
+	up.checkHelmReleases(ctx, trList)
+
+	if err := up.setRegistryPullSecret(ctx, trList); err != nil {
+		return err
+	}
+
 	if err := deployments.TranslateDevMode(trList, up.Client, up.isOktetoNamespace); err != nil {
 		return err
 	}
 
+	if err := up.relaxPDBs(ctx, trList); err != nil {
+		return err
+	}
+
+	if err := up.pinHPAs(ctx, trList); err != nil {
+		return err
+	}
+
 	for name := range trList {
-		if name == d.Name {
-			if err := deployments.Deploy(ctx, trList[name].Deployment, create, up.Client); err != nil {
-				return err
-			}
-		} else {
-			if err := deployments.Deploy(ctx, trList[name].Deployment, false, up.Client); err != nil {
-				return err
-			}
+		if err := deployments.Deploy(ctx, trList[name].Deployment, up.Client); err != nil {
+			return err
 		}
 
 		if trList[name].Deployment.Annotations[okLabels.DeploymentAnnotation] == "" {
@@ -646,10 +810,14 @@ func (up *upContext) devMode(ctx context.Context, d *appsv1.Deployment, create b
 	}()
 
 	if err := pods.WaitUntilRunning(ctx, up.Dev, pod.Name, up.Client, reporter); err != nil {
+		if failedErr, ok := err.(*errors.DevPodFailedError); ok {
+			return up.rollback(d, trList, failedErr)
+		}
 		return err
 	}
 
 	up.Pod = pod.Name
+	ssh.SetDevPod(up.Dev.Name, string(pod.UID))
 	return nil
 }
 
@@ -684,13 +852,42 @@ func (up *upContext) forwards(ctx context.Context) error {
 
 func (up *upContext) sshForwards(ctx context.Context) error {
 	log.Infof("starting SSH port forwards")
-	f := forward.NewPortForwardManager(ctx, up.Dev.Interface, up.RestConfig, up.Client)
-	if err := f.Add(model.Forward{Local: up.Dev.RemotePort, Remote: up.Dev.SSHServerPort}); err != nil {
-		return err
+	var f *forward.PortForwardManager
+	sshAddr := fmt.Sprintf(":%d", up.Dev.RemotePort)
+	if up.Dev.JumpHost == nil {
+		f = forward.NewPortForwardManager(ctx, up.Dev.Interface, up.RestConfig, up.Client)
+		if err := f.Add(model.Forward{Local: up.Dev.RemotePort, Remote: up.Dev.SSHServerPort}); err != nil {
+			return err
+		}
+	} else {
+		// the jump host reaches the development container directly, without a kubernetes port-forward
+		ip, err := pods.GetIP(ctx, up.Pod, up.Dev.Namespace, up.Client)
+		if err != nil {
+			return err
+		}
+		sshAddr = fmt.Sprintf("%s:%d", ip, up.Dev.SSHServerPort)
+	}
+
+	fm := ssh.NewForwardManager(ctx, sshAddr, up.Dev.Interface, "0.0.0.0", f, ssh.PoolOptions{
+		Size:                up.Dev.SSHPoolSize,
+		KeepAlive:           up.Dev.SSHKeepalive,
+		MaxMissedKeepAlives: up.Dev.SSHMaxMissedKeepalives,
+		WebSocketURL:        up.Dev.SSHWebSocket,
+		Name:                up.Dev.Name,
+		JumpHost:            up.Dev.JumpHost,
+		ConnectTimeout:      up.Dev.Timeout.SSH,
+		TCP:                 up.Dev.TCP,
+		DrainTimeout:        up.Dev.Timeout.Drain,
+	})
+	up.Forwarder = fm
+
+	if up.Dev.JumpHost != nil {
+		// keeps the local SSH port used by 'okteto exec' and the ssh config entry working
+		if err := up.Forwarder.Add(model.Forward{Local: up.Dev.RemotePort, Remote: up.Dev.SSHServerPort}); err != nil {
+			return err
+		}
 	}
 
-	up.Forwarder = ssh.NewForwardManager(ctx, fmt.Sprintf(":%d", up.Dev.RemotePort), up.Dev.Interface, "0.0.0.0", f)
-
 	if err := up.Forwarder.Add(model.Forward{Local: up.Sy.RemotePort, Remote: syncthing.ClusterPort}); err != nil {
 		return err
 	}
@@ -711,12 +908,69 @@ func (up *upContext) sshForwards(ctx context.Context) error {
 		}
 	}
 
+	if up.Dev.Proxy != "" {
+		if err := fm.AddSocks(up.Dev.Proxy); err != nil {
+			return err
+		}
+	}
+
+	if up.Dev.SSHAudit {
+		if err := fm.EnableAudit(up.Dev.Namespace, up.Dev.Name); err != nil {
+			return fmt.Errorf("failed to enable the SSH audit log: %s", err)
+		}
+	}
+
 	if err := ssh.AddEntry(up.Dev.Name, up.Dev.Interface, up.Dev.RemotePort); err != nil {
 		log.Infof("failed to add entry to your SSH config file: %s", err)
 		return fmt.Errorf("failed to add entry to your SSH config file")
 	}
 
-	return up.Forwarder.Start(up.Pod, up.Dev.Namespace)
+	if err := up.Forwarder.Start(up.Pod, up.Dev.Namespace); err != nil {
+		return err
+	}
+
+	go fm.Monitor(ctx, up.Disconnect)
+	go fm.ReportStats(ctx, up.Dev.Namespace, up.Dev.Name)
+	go func() {
+		if err := fm.ServeControl(ctx, up.Dev.Namespace, up.Dev.Name); err != nil {
+			log.Infof("failed to start the forward control server: %s", err)
+		}
+	}()
+	return nil
+}
+
+func (up *upContext) warnRemappedPorts() {
+	fm, ok := up.Forwarder.(*ssh.ForwardManager)
+	if !ok {
+		return
+	}
+
+	for privileged, port := range fm.RemappedPorts() {
+		log.Yellow("Local port %d is privileged, forwarding it from %s:%d instead", privileged, up.Dev.Interface, port)
+		if hint := ssh.PrivilegedPortHint(up.Dev.Interface); hint != "" {
+			log.Hint("    %s to use port %d", hint, privileged)
+		}
+	}
+}
+
+func (up *upContext) printAutoForwards() {
+	fm, ok := up.Forwarder.(*ssh.ForwardManager)
+	if !ok {
+		return
+	}
+
+	forwards := fm.AutoForwards()
+	up.saveAutoForwards(forwards)
+	for _, f := range forwards {
+		for i := range up.Dev.Forward {
+			d := &up.Dev.Forward[i]
+			if d.Auto && d.Remote == f.Remote && d.ServiceName == f.ServiceName {
+				d.Local = f.Local
+			}
+		}
+
+		log.Information("Forwarding %s:%d to %s (%s=%d)", up.Dev.Interface, f.Local, f.String(), f.EnvVar(), f.Local)
+	}
 }
 
 func (up *upContext) initializeSyncthing() error {
@@ -902,7 +1156,7 @@ func (up *upContext) runCommand(ctx context.Context) error {
 	up.updateStateFile(ready)
 
 	if up.Dev.RemoteModeEnabled() {
-		return ssh.Exec(ctx, up.Dev.Interface, up.Dev.RemotePort, true, os.Stdin, os.Stdout, os.Stderr, up.Dev.Command.Values)
+		return ssh.Exec(ctx, up.Dev.Name, up.Dev.Interface, up.Dev.RemotePort, true, os.Stdin, os.Stdout, os.Stderr, up.Dev.Command.Values)
 	}
 
 	return exec.Exec(
@@ -920,6 +1174,27 @@ func (up *upContext) runCommand(ctx context.Context) error {
 	)
 }
 
+// remoteExec executes a non-interactive command in the development container
+func (up *upContext) remoteExec(ctx context.Context, command []string, stdout, stderr io.Writer) error {
+	if up.Dev.RemoteModeEnabled() {
+		return ssh.Exec(ctx, up.Dev.Name, up.Dev.Interface, up.Dev.RemotePort, false, strings.NewReader(""), stdout, stderr, command)
+	}
+
+	return exec.Exec(
+		ctx,
+		up.Client,
+		up.RestConfig,
+		up.Dev.Namespace,
+		up.Pod,
+		up.Dev.Container,
+		false,
+		strings.NewReader(""),
+		stdout,
+		stderr,
+		command,
+	)
+}
+
 func (up *upContext) checkOktetoStartError(ctx context.Context, msg string) error {
 	userID := pods.GetDevPodUserID(ctx, up.Dev, up.Client)
 	if up.Dev.PersistentVolumeEnabled() {
@@ -1034,6 +1309,8 @@ func (up *upContext) shutdown() {
 		up.Forwarder.Stop()
 	}
 
+	informers.Stop()
+
 	log.Info("completed shutdown sequence")
 	up.ShutdownCompleted <- true
 
@@ -1058,10 +1335,14 @@ func printDisplayContext(dev *model.Dev) {
 	}
 
 	if len(dev.Reverse) > 0 {
-		log.Println(fmt.Sprintf("    %s   %d <- %d", log.BlueString("Reverse:"), dev.Reverse[0].Local, dev.Reverse[0].Remote))
+		log.Println(fmt.Sprintf("    %s   %s <- %s", log.BlueString("Reverse:"), dev.Reverse[0].LocalRange(), dev.Reverse[0].RemoteRange()))
 		for i := 1; i < len(dev.Reverse); i++ {
-			log.Println(fmt.Sprintf("               %d <- %d", dev.Reverse[i].Local, dev.Reverse[i].Remote))
+			log.Println(fmt.Sprintf("               %s <- %s", dev.Reverse[i].LocalRange(), dev.Reverse[i].RemoteRange()))
 		}
 	}
+
+	if dev.Proxy != "" {
+		log.Println(fmt.Sprintf("    %s     %s", log.BlueString("Proxy:"), dev.Proxy))
+	}
 	fmt.Println()
 }
@@ -92,6 +92,14 @@ func Test_printDisplayContext(t *testing.T) {
 				Reverse:   []model.Reverse{{Local: 1000, Remote: 1000}, {Local: 2000, Remote: 2000}},
 			},
 		},
+		{
+			name: "range-reverse",
+			dev: &model.Dev{
+				Name:      "dev",
+				Namespace: "namespace",
+				Reverse:   []model.Reverse{{Local: 9000, Remote: 9000, Count: 10}},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -32,17 +32,22 @@ const (
 
 //LoadDev loads an okteto manifest checking "yml" and "yaml"
 func LoadDev(devPath string) (*model.Dev, error) {
+	return LoadDevWithProfiles(devPath, nil)
+}
+
+//LoadDevWithProfiles loads an okteto manifest checking "yml" and "yaml", with the given profiles of the manifest applied
+func LoadDevWithProfiles(devPath string, profiles []string) (*model.Dev, error) {
 	if !model.FileExists(devPath) {
 		if devPath == DefaultDevManifest {
 			if model.FileExists(secondaryDevManifest) {
-				return LoadDev(secondaryDevManifest)
+				return LoadDevWithProfiles(secondaryDevManifest, profiles)
 			}
 		}
 
 		return nil, fmt.Errorf("'%s' does not exist. Generate it by executing 'okteto init'", devPath)
 	}
 
-	return model.Get(devPath)
+	return model.GetWithProfiles(devPath, profiles)
 }
 
 //LoadDevOrDefault loads an okteto manifest or a default one if does not exist
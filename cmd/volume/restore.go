@@ -0,0 +1,109 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package volume
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/okteto/okteto/cmd/utils"
+	k8Client "github.com/okteto/okteto/pkg/k8s/client"
+	"github.com/okteto/okteto/pkg/k8s/deployments"
+	"github.com/okteto/okteto/pkg/k8s/volumes"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/dynamic"
+)
+
+//Restore restores the persistent volume of a development container from a snapshot
+func Restore(ctx context.Context) *cobra.Command {
+	var devPath string
+	var namespace string
+	var k8sContext string
+
+	cmd := &cobra.Command{
+		Use:   "restore [snapshot]",
+		Short: "Restores the persistent volume of your development container from a snapshot (defaults to the latest one)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log.Info("starting volume restore command")
+			dev, err := utils.LoadDev(devPath)
+			if err != nil {
+				return err
+			}
+			dev.LoadContext(namespace, k8sContext)
+
+			snapshot := ""
+			if len(args) > 0 {
+				snapshot = args[0]
+			}
+
+			snapshot, err = executeRestore(ctx, dev, snapshot)
+			if err != nil {
+				return err
+			}
+
+			log.Success("Persistent volume restored from snapshot '%s'", snapshot)
+			return nil
+		},
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return fmt.Errorf("restore accepts at most one snapshot name")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&devPath, "file", "f", utils.DefaultDevManifest, "path to the manifest file")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "namespace where the volume restore command is executed")
+	cmd.Flags().StringVarP(&k8sContext, "context", "c", "", "context where the volume restore command is executed")
+	return cmd
+}
+
+func executeRestore(ctx context.Context, dev *model.Dev, snapshot string) (string, error) {
+	spinner := utils.NewSpinner("Restoring the persistent volume...")
+	spinner.Start()
+	defer spinner.Stop()
+
+	client, restConfig, namespace, err := k8Client.GetLocal(dev.Context)
+	if err != nil {
+		return "", err
+	}
+	if dev.Namespace == "" {
+		dev.Namespace = namespace
+	}
+
+	if !dev.PersistentVolumeEnabled() {
+		return "", fmt.Errorf("persistent volumes are not enabled in your okteto manifest")
+	}
+
+	d, err := deployments.Get(ctx, dev, dev.Namespace, client)
+	if err == nil && deployments.IsDevModeOn(d) {
+		return "", fmt.Errorf("your development container is active. Run 'okteto down' and try again")
+	}
+
+	dc, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return "", err
+	}
+
+	if snapshot == "" {
+		snapshot, err = volumes.GetLatestSnapshot(ctx, dev, dc)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return snapshot, volumes.Restore(ctx, dev, snapshot, client, dc)
+}
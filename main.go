@@ -19,11 +19,14 @@ import (
 	"os"
 
 	"github.com/okteto/okteto/cmd"
+	"github.com/okteto/okteto/cmd/forward"
 	initCMD "github.com/okteto/okteto/cmd/init"
 	"github.com/okteto/okteto/cmd/namespace"
 	"github.com/okteto/okteto/cmd/pipeline"
 	"github.com/okteto/okteto/cmd/stack"
 	"github.com/okteto/okteto/cmd/up"
+	"github.com/okteto/okteto/cmd/volume"
+	"github.com/okteto/okteto/pkg/cmd/build"
 	"github.com/okteto/okteto/pkg/config"
 	"github.com/okteto/okteto/pkg/errors"
 	"github.com/okteto/okteto/pkg/log"
@@ -63,6 +66,8 @@ func main() {
 	log.Init(logrus.WarnLevel, config.GetOktetoHome(), config.VersionString)
 	log.Info("start")
 	var logLevel string
+	var kubeconfig string
+	var insecureRegistries []string
 
 	root := &cobra.Command{
 		Use:           fmt.Sprintf("%s COMMAND [ARG...]", config.GetBinaryName()),
@@ -70,11 +75,17 @@ func main() {
 		SilenceErrors: true,
 		PersistentPreRun: func(ccmd *cobra.Command, args []string) {
 			log.SetLevel(logLevel)
+			if kubeconfig != "" {
+				config.SetKubeConfigFile(kubeconfig)
+			}
+			config.SetInsecureRegistries(insecureRegistries)
 			ccmd.SilenceUsage = true
 		},
 	}
 
 	root.PersistentFlags().StringVarP(&logLevel, "loglevel", "l", "warn", "amount of information outputted (debug, info, warn, error)")
+	root.PersistentFlags().StringVarP(&kubeconfig, "kubeconfig", "", "", "path to the kubeconfig file (overrides KUBECONFIG)")
+	root.PersistentFlags().StringArrayVar(&insecureRegistries, "insecure-registry", nil, "registry host pushed to and queried without verifying its TLS certificate")
 	root.AddCommand(cmd.Analytics())
 	root.AddCommand(cmd.Version())
 	root.AddCommand(cmd.Login())
@@ -91,9 +102,15 @@ func main() {
 	root.AddCommand(cmd.Status())
 	root.AddCommand(cmd.Doctor())
 	root.AddCommand(cmd.Exec())
+	root.AddCommand(cmd.Cp())
+	root.AddCommand(cmd.Proxy())
+	root.AddCommand(cmd.Debug())
+	root.AddCommand(forward.Forward())
 	root.AddCommand(cmd.Restart())
+	root.AddCommand(volume.Volume(ctx))
 
 	err := root.Execute()
+	build.StopPodForwards()
 
 	if err != nil {
 		log.Fail(err.Error())
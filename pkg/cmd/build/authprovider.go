@@ -23,11 +23,17 @@ import (
 	"github.com/docker/cli/cli/config/types"
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/session/auth"
+	"github.com/okteto/okteto/pkg/registry"
 	"google.golang.org/grpc"
 )
 
 var oktetoRegistry = ""
 
+// newDockerAuthProvider returns the credentials of the docker config file and of the cloud registries
+func newDockerAuthProvider() session.Attachable {
+	return &authProvider{config: &configfile.ConfigFile{AuthConfigs: map[string]types.AuthConfig{}}}
+}
+
 func newDockerAndOktetoAuthProvider(registryURL, username, password string, stderr io.Writer) session.Attachable {
 	result := &authProvider{
 		config: config.LoadDefaultConfigFile(stderr),
@@ -63,20 +69,9 @@ func (ap *authProvider) Credentials(ctx context.Context, req *auth.CredentialsRe
 		return res, nil
 	}
 
+	// credentials are read on every request, so the short-lived tokens of cloud registries are refreshed during long builds
 	ap.mu.Lock()
 	defer ap.mu.Unlock()
-	if req.Host == "registry-1.docker.io" {
-		req.Host = "https://index.docker.io/v1/"
-	}
-	ac, err := ap.config.GetAuthConfig(req.Host)
-	if err != nil {
-		return nil, err
-	}
-	if ac.IdentityToken != "" {
-		res.Secret = ac.IdentityToken
-	} else {
-		res.Username = ac.Username
-		res.Secret = ac.Password
-	}
+	res.Username, res.Secret = registry.GetRegistryCredentials(req.Host)
 	return res, nil
 }
@@ -15,44 +15,189 @@ package build
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
+	okErrors "github.com/okteto/okteto/pkg/errors"
 	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
 	"github.com/okteto/okteto/pkg/registry"
 	"github.com/pkg/errors"
+	"github.com/subosito/gotenv"
 )
 
-// Run runs the build sequence
-func Run(ctx context.Context, buildKitHost string, isOktetoCluster bool, path, dockerFile, tag, target string, noCache bool, cacheFrom, buildArgs []string, progress string) error {
-	log.Infof("building your image on %s", buildKitHost)
-	buildkitClient, err := getBuildkitClient(ctx, isOktetoCluster, buildKitHost)
+// Options are the options of a build
+type Options struct {
+	BuildKitHost    string
+	IsOktetoCluster bool
+	Path            string
+	Dockerfile      string
+
+	// Tag can be a comma separated list of tags pushed at once
+	Tag string
+
+	// Output exports the image as described by ParseOutput instead of pushing it
+	Output   string
+	Target   string
+	Platform string
+	NoCache  bool
+
+	// SkipIfExists skips the build if the registry already stores an image of Tag built from the same build context
+	SkipIfExists bool
+
+	Compression string
+	CacheFrom   []string
+	BuildArgs   []string
+	Secrets     []string
+	Ignore      []string
+
+	// CacheScope narrows the namespace of the cache mount ids of the user, as returned by model.BuildInfo.GetCacheScope
+	CacheScope string
+
+	// Labels override the standard OCI labels of the image
+	Labels   map[string]string
+	Frontend model.BuildFrontend
+
+	// MetadataFile is the file where the metadata of the build is written, as described by WriteMetadata
+	MetadataFile string
+
+	Progress string
+	Out      io.Writer
+}
+
+// Run runs the build sequence and returns the digest of the pushed image.
+// The image is labeled with the standard OCI labels, overridden by opts.Labels, and with the content hash of the build context
+func Run(ctx context.Context, opts Options) (string, error) {
+	start := time.Now()
+	log.Infof("building your image on %s", opts.BuildKitHost)
+	buildkitClient, err := getBuildkitClient(ctx, opts.IsOktetoCluster, opts.BuildKitHost)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	processedDockerfile, err := registry.GetDockerfile(path, dockerFile, isOktetoCluster)
+	processedDockerfile := opts.Dockerfile
+	var excludes []string
+	contextHash := ""
+	if model.IsGitBuildContext(opts.Path) {
+		log.Infof("using the git repository %s as the build context", opts.Path)
+	} else {
+		processedDockerfile, err = registry.GetDockerfile(opts.Path, opts.Dockerfile, opts.CacheScope, opts.IsOktetoCluster)
+		if err != nil {
+			return "", err
+		}
+
+		if opts.IsOktetoCluster {
+			defer os.Remove(processedDockerfile)
+		}
+
+		excludes, err = getExcludePatterns(opts.Path, opts.Dockerfile, opts.Ignore)
+		if err != nil {
+			return "", err
+		}
+
+		contextHash, err = getContextHash(opts.Path, opts.Dockerfile, excludes, opts.Target, opts.Platform, opts.BuildArgs, opts.Frontend)
+		if err != nil {
+			log.Infof("failed to compute the hash of the build context: %s", err.Error())
+		}
+	}
+
+	tag, err := expandTags(ctx, opts.Tag)
 	if err != nil {
-		return err
+		return "", err
+	}
+	for i := range opts.CacheFrom {
+		opts.CacheFrom[i], err = registry.ExpandOktetoDevRegistry(ctx, opts.CacheFrom[i])
+		if err != nil {
+			return "", err
+		}
 	}
 
-	if isOktetoCluster {
-		defer os.Remove(processedDockerfile)
+	if opts.SkipIfExists && tag != "" && opts.Output == "" && contextHash != "" {
+		if digest, ok := getExistingImage(tag, contextHash); ok {
+			log.Information("Skipping the build: '%s' was already built from the same build context", tag)
+			if opts.MetadataFile != "" {
+				if err := WriteMetadata(opts.MetadataFile, getMetadata(tag, digest, opts.Platform, start, 1)); err != nil {
+					return "", err
+				}
+			}
+			return digest, nil
+		}
+	}
+
+	imageLabels := getImageLabels(opts.Path, opts.Labels, time.Now())
+	if contextHash != "" {
+		imageLabels[labelContextHash] = contextHash
+	}
+	opt, err := getSolveOpt(opts.Path, processedDockerfile, tag, opts.Output, opts.Target, opts.Platform, opts.NoCache, opts.Compression, opts.CacheFrom, opts.BuildArgs, opts.Secrets, excludes, imageLabels, opts.Frontend)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create build solver")
 	}
 
-	tag, err = registry.ExpandOktetoDevRegistry(ctx, tag)
+	digest, cacheHitRatio, err := solveWithPushRetries(ctx, buildkitClient, opt, opts.Progress, opts.Out)
 	if err != nil {
-		return err
+		if opts.Compression == CompressionEstargz && strings.Contains(err.Error(), "unsupported layer compression type") {
+			return "", okErrors.UserError{
+				E:    fmt.Errorf("%s doesn't support the eStargz format", opts.BuildKitHost),
+				Hint: "eStargz images require BuildKit v0.10 or newer. Build your image without the eStargz option",
+			}
+		}
+		return "", err
+	}
+
+	if opts.MetadataFile != "" {
+		if err := WriteMetadata(opts.MetadataFile, getMetadata(tag, digest, opts.Platform, start, cacheHitRatio)); err != nil {
+			return "", err
+		}
+	}
+	return digest, nil
+}
+
+// expandTags expands the okteto.dev registry of a comma separated list of tags
+func expandTags(ctx context.Context, tag string) (string, error) {
+	if tag == "" {
+		return tag, nil
 	}
-	for i := range cacheFrom {
-		cacheFrom[i], err = registry.ExpandOktetoDevRegistry(ctx, cacheFrom[i])
+
+	tags := strings.Split(tag, ",")
+	for i := range tags {
+		expanded, err := registry.ExpandOktetoDevRegistry(ctx, tags[i])
 		if err != nil {
-			return err
+			return "", err
 		}
+		tags[i] = expanded
 	}
-	opt, err := getSolveOpt(path, processedDockerfile, tag, target, noCache, cacheFrom, buildArgs)
-	if err != nil {
-		return errors.Wrap(err, "failed to create build solver")
+	return strings.Join(tags, ","), nil
+}
+
+// GetBuildArgsFromEnvFiles returns the variables defined in the env files as build args.
+// Variables of later files override the ones of earlier files.
+func GetBuildArgsFromEnvFiles(envFiles []string) ([]string, error) {
+	envMap := map[string]string{}
+	for _, filename := range envFiles {
+		f, err := os.Open(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		fileMap, err := gotenv.StrictParse(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing env file %s: %s", filename, err.Error())
+		}
+
+		for name, value := range fileMap {
+			envMap[name] = value
+		}
 	}
 
-	return solveBuild(ctx, buildkitClient, opt, progress)
+	result := []string{}
+	for name, value := range envMap {
+		result = append(result, fmt.Sprintf("%s=%s", name, value))
+	}
+	sort.Strings(result)
+	return result, nil
 }
@@ -16,6 +16,7 @@ package build
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -23,13 +24,16 @@ import (
 	"strings"
 
 	"github.com/containerd/console"
+	"github.com/containerd/containerd/platforms"
 	"github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/session"
-	"github.com/moby/buildkit/session/auth/authprovider"
+	"github.com/moby/buildkit/session/secrets/secretsprovider"
 	"github.com/moby/buildkit/util/progress/progressui"
 	okErrors "github.com/okteto/okteto/pkg/errors"
 	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
 	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/okteto/okteto/pkg/registry"
 	"github.com/pkg/errors"
 	"golang.org/x/oauth2"
 	"golang.org/x/sync/errgroup"
@@ -37,7 +41,19 @@ import (
 )
 
 const (
-	frontend = "dockerfile.v0"
+	dockerfileFrontend = "dockerfile.v0"
+
+	// imageDigestKey is the key of the exporter response with the digest of the pushed image
+	imageDigestKey = "containerimage.digest"
+
+	// CompressionGzip pushes the image layers compressed with gzip
+	CompressionGzip = "gzip"
+
+	// CompressionUncompressed pushes the image layers uncompressed
+	CompressionUncompressed = "uncompressed"
+
+	// CompressionEstargz pushes the image layers in the eStargz format, so the cluster can lazily pull them
+	CompressionEstargz = "estargz"
 )
 
 //GetBuildKitHost returns the buildkit url and if Okteto Build Service is configured, or an error
@@ -54,27 +70,48 @@ func GetBuildKitHost() (string, bool, error) {
 }
 
 //getSolveOpt returns the buildkit solve options
-func getSolveOpt(buildCtx, file, imageTag, target string, noCache bool, cacheFrom, buildArgs []string) (*client.SolveOpt, error) {
-	if file == "" {
-		file = filepath.Join(buildCtx, "Dockerfile")
-	}
-	if _, err := os.Stat(file); os.IsNotExist(err) {
-		return nil, fmt.Errorf("Dockerfile '%s' does not exist", file)
-	}
-	localDirs := map[string]string{
-		"context":    buildCtx,
-		"dockerfile": filepath.Dir(file),
-	}
+func getSolveOpt(buildCtx, file, imageTag, output, target, platform string, noCache bool, compression string, cacheFrom, buildArgs, secrets, excludes []string, labels map[string]string, frontend model.BuildFrontend) (*client.SolveOpt, error) {
+	attachable := []session.Attachable{}
+	frontendAttrs := map[string]string{}
+	frontendImage := ""
+	if model.IsGitBuildContext(buildCtx) {
+		remote, subdir := parseGitContext(buildCtx)
+		frontendAttrs["context"] = remote
+		if subdir != "" {
+			frontendAttrs["contextsubdir"] = subdir
+		}
+		frontendAttrs["filename"] = getGitDockerfile(subdir, file)
+		frontendImage = getFrontendImage(frontend, "")
+	} else {
+		if file == "" {
+			file = filepath.Join(buildCtx, "Dockerfile")
+		}
+		if _, err := os.Stat(file); os.IsNotExist(err) {
+			return nil, fmt.Errorf("Dockerfile '%s' does not exist", file)
+		}
 
-	frontendAttrs := map[string]string{
-		"filename": filepath.Base(file),
+		localDirs, err := getLocalDirsProvider(buildCtx, filepath.Dir(file), excludes)
+		if err != nil {
+			return nil, err
+		}
+		attachable = append(attachable, localDirs)
+		frontendAttrs["filename"] = filepath.Base(file)
+		frontendImage = getFrontendImage(frontend, file)
 	}
+
 	if target != "" {
 		frontendAttrs["target"] = target
 	}
 	if noCache {
 		frontendAttrs["no-cache"] = ""
 	}
+	if platform != "" {
+		platforms, err := parsePlatforms(platform)
+		if err != nil {
+			return nil, err
+		}
+		frontendAttrs["platform"] = platforms
+	}
 	for _, buildArg := range buildArgs {
 		kv := strings.SplitN(buildArg, "=", 2)
 		if len(kv) != 2 {
@@ -82,7 +119,9 @@ func getSolveOpt(buildCtx, file, imageTag, target string, noCache bool, cacheFro
 		}
 		frontendAttrs["build-arg:"+kv[0]] = kv[1]
 	}
-	attachable := []session.Attachable{}
+	for k, v := range labels {
+		frontendAttrs["label:"+k] = v
+	}
 	token, err := okteto.GetToken()
 	if err == nil {
 		registryURL, err := okteto.GetRegistry()
@@ -91,30 +130,48 @@ func getSolveOpt(buildCtx, file, imageTag, target string, noCache bool, cacheFro
 		}
 		attachable = append(attachable, newDockerAndOktetoAuthProvider(registryURL, okteto.GetUserID(), token.Token, os.Stderr))
 	} else {
-		attachable = append(attachable, authprovider.NewDockerAuthProvider(os.Stderr))
+		attachable = append(attachable, newDockerAuthProvider())
+	}
+	if len(secrets) > 0 {
+		secretProvider, err := getSecretProvider(secrets)
+		if err != nil {
+			return nil, err
+		}
+		attachable = append(attachable, secretProvider)
 	}
 	opt := &client.SolveOpt{
-		LocalDirs:     localDirs,
-		Frontend:      frontend,
+		Frontend:      setFrontend(frontendAttrs, frontend, frontendImage),
 		FrontendAttrs: frontendAttrs,
 		Session:       attachable,
 		CacheImports:  []client.CacheOptionsEntry{},
 	}
 
-	if imageTag != "" {
-		insecure_registry_str, insecure_registry_env_ok := os.LookupEnv("OKTETO_INSECURE_REGISTRY_ENABLED")
-		if !insecure_registry_env_ok {
-			insecure_registry_str = "false"
+	if output != "" {
+		export, err := ParseOutput(output)
+		if err != nil {
+			return nil, err
+		}
+		if imageTag != "" && export.Type != client.ExporterLocal && export.Type != client.ExporterTar {
+			export.Attrs["name"] = imageTag
+		}
+		opt.Exports = []client.ExportEntry{*export}
+	} else if imageTag != "" {
+		insecure := false
+		for _, tag := range strings.Split(imageTag, ",") {
+			if registry.IsInsecureImage(tag) {
+				insecure = true
+			}
 		}
-		insecure_registry_enabled, _ := strconv.ParseBool(insecure_registry_str)
+		attrs := map[string]string{
+			"name":              imageTag,
+			"push":              "true",
+			"registry.insecure": strconv.FormatBool(insecure),
+		}
+		setCompression(attrs, compression)
 		opt.Exports = []client.ExportEntry{
 			{
-				Type: "image",
-				Attrs: map[string]string{
-					"name":              imageTag,
-					"push":              "true",
-					"registry.insecure": strconv.FormatBool(insecure_registry_enabled),
-				},
+				Type:  "image",
+				Attrs: attrs,
 			},
 		}
 		opt.CacheExports = []client.CacheOptionsEntry{
@@ -136,6 +193,68 @@ func getSolveOpt(buildCtx, file, imageTag, target string, noCache bool, cacheFro
 	return opt, nil
 }
 
+// getSecretProvider returns the session attachable that serves the build secrets, in the 'id=ID,src=PATH' format
+func getSecretProvider(secrets []string) (session.Attachable, error) {
+	sources := []secretsprovider.FileSource{}
+	for _, secret := range secrets {
+		source := secretsprovider.FileSource{}
+		for _, field := range strings.Split(secret, ",") {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("invalid secret '%s': must follow the syntax 'id=ID,src=PATH'", secret)
+			}
+
+			switch kv[0] {
+			case "id":
+				source.ID = kv[1]
+			case "src", "source":
+				source.FilePath = kv[1]
+			default:
+				return nil, fmt.Errorf("invalid secret '%s': unexpected key '%s'", secret, kv[0])
+			}
+		}
+
+		if source.ID == "" {
+			return nil, fmt.Errorf("invalid secret '%s': must follow the syntax 'id=ID,src=PATH'", secret)
+		}
+		if source.FilePath == "" {
+			source.FilePath = source.ID
+		}
+		if _, err := os.Stat(source.FilePath); err != nil {
+			return nil, fmt.Errorf("invalid secret '%s': %s", source.ID, err)
+		}
+		sources = append(sources, source)
+	}
+
+	store, err := secretsprovider.NewFileStore(sources)
+	if err != nil {
+		return nil, err
+	}
+	return secretsprovider.NewSecretProvider(store), nil
+}
+
+// parsePlatforms validates a comma separated list of platforms and returns it normalized
+func parsePlatforms(value string) (string, error) {
+	result := []string{}
+	for _, p := range strings.Split(value, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+
+		parsed, err := platforms.Parse(p)
+		if err != nil {
+			return "", fmt.Errorf("invalid platform '%s': %s", p, err)
+		}
+		result = append(result, platforms.Format(parsed))
+	}
+
+	if len(result) == 0 {
+		return "", fmt.Errorf("invalid platform '%s'", value)
+	}
+	return strings.Join(result, ","), nil
+}
+
 func getBuildkitClient(ctx context.Context, isOktetoCluster bool, buildKitHost string) (*client.Client, error) {
 	if isOktetoCluster {
 		c, err := getClientForOktetoCluster(ctx, buildKitHost)
@@ -150,8 +269,20 @@ func getBuildkitClient(ctx context.Context, isOktetoCluster bool, buildKitHost s
 		return c, nil
 	}
 
-	c, err := client.New(ctx, buildKitHost, client.WithFailFast())
+	address := buildKitHost
+	if isPodBuildKitHost(buildKitHost) {
+		var err error
+		address, err = getPodBuildKitAddress(buildKitHost)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to connect to %s", buildKitHost)
+		}
+	}
+
+	c, err := client.New(ctx, address, client.WithFailFast())
 	if err != nil {
+		if isPodBuildKitHost(buildKitHost) {
+			dropPodForward(buildKitHost)
+		}
 		return nil, errors.Wrapf(err, "failed to create build client for %s", buildKitHost)
 	}
 	return c, nil
@@ -188,25 +319,77 @@ func getClientForOktetoCluster(ctx context.Context, buildKitHost string) (*clien
 	return c, nil
 }
 
-func solveBuild(ctx context.Context, c *client.Client, opt *client.SolveOpt, progress string) error {
+// solveBuild runs the build and returns the digest of the pushed image, if any, and the ratio of cached steps
+func solveBuild(ctx context.Context, c *client.Client, opt *client.SolveOpt, progress string, out io.Writer) (string, float64, error) {
+	solveCh := make(chan *client.SolveStatus)
 	ch := make(chan *client.SolveStatus)
 	eg, ctx := errgroup.WithContext(ctx)
+	digest := ""
 	eg.Go(func() error {
-		var err error
-		_, err = c.Solve(ctx, nil, *opt, ch)
-		return errors.Wrap(err, "build failed")
+		resp, err := c.Solve(ctx, nil, *opt, solveCh)
+		if err != nil {
+			return errors.Wrap(err, "build failed")
+		}
+		digest = resp.ExporterResponse[imageDigestKey]
+		return nil
+	})
+
+	stats := cacheStats{}
+	eg.Go(func() error {
+		defer close(ch)
+		for status := range solveCh {
+			stats.update(status)
+			ch <- status
+		}
+		return nil
 	})
 
 	eg.Go(func() error {
+		switch progress {
+		case ProgressJSON:
+			return displayJSONStatus(out, ch)
+		case ProgressQuiet:
+			for range ch {
+			}
+			return nil
+		}
+
 		var c console.Console
-		if progress == "tty" {
+		if progress == ProgressTTY {
 			if cn, err := console.ConsoleFromFile(os.Stderr); err == nil {
 				c = cn
 			}
 		}
 		// not using shared context to not disrupt display but let it finish reporting errors
-		return progressui.DisplaySolveStatus(context.TODO(), "", c, os.Stdout, ch)
+		return progressui.DisplaySolveStatus(context.TODO(), "", c, out, ch)
 	})
 
-	return eg.Wait()
+	if err := eg.Wait(); err != nil {
+		return "", 0, err
+	}
+	return digest, stats.ratio(), nil
+}
+
+// ValidateCompression checks that the compression of the pushed layers is supported
+func ValidateCompression(compression string) error {
+	switch compression {
+	case "", CompressionGzip, CompressionUncompressed, CompressionEstargz:
+		return nil
+	default:
+		return fmt.Errorf("invalid compression '%s': must be %s, %s or %s", compression, CompressionGzip, CompressionUncompressed, CompressionEstargz)
+	}
+}
+
+// setCompression sets the compression of the layers pushed by the image exporter.
+// eStargz layers are recompressed even if they are cached as gzip, and need the OCI media types to be lazily pulled
+func setCompression(attrs map[string]string, compression string) {
+	if compression == "" {
+		return
+	}
+
+	attrs["compression"] = compression
+	if compression == CompressionEstargz {
+		attrs["force-compression"] = "true"
+		attrs["oci-mediatypes"] = "true"
+	}
 }
@@ -0,0 +1,95 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	okErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/registry"
+)
+
+const packBinary = "pack"
+
+// packDigestRegex matches the digest of the image published by pack, like '*** Images (sha256:...):'
+var packDigestRegex = regexp.MustCompile(`Images \((sha256:[a-f0-9]{64})\)`)
+
+// RunBuildpacks builds the source code of path with the Cloud Native Buildpacks of builderImage using the pack CLI,
+// pushes it and returns the digest of the pushed image. Tag can be a comma separated list of tags pushed at once.
+// Build args are passed to the buildpacks as build-time environment variables
+func RunBuildpacks(ctx context.Context, path, tag, builderImage string, noCache bool, buildArgs []string, out io.Writer) (string, error) {
+	if tag == "" {
+		return "", fmt.Errorf("building with buildpacks requires an image tag to push the image")
+	}
+
+	pack, err := exec.LookPath(packBinary)
+	if err != nil {
+		return "", okErrors.UserError{
+			E:    fmt.Errorf("building with buildpacks requires '%s'", packBinary),
+			Hint: "Install it following the instructions at https://buildpacks.io/docs/tools/pack",
+		}
+	}
+
+	tag, err = expandTags(ctx, tag)
+	if err != nil {
+		return "", err
+	}
+
+	log.Infof("building %s with the builder %s", path, builderImage)
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, pack, getPackArgs(path, strings.Split(tag, ","), builderImage, noCache, buildArgs)...)
+	cmd.Stdout = io.MultiWriter(out, &stdout)
+	cmd.Stderr = io.MultiWriter(out, &stderr)
+	if err := cmd.Run(); err != nil {
+		log.Infof("pack failed: %s", stderr.String())
+		return "", fmt.Errorf("build failed: %s", err)
+	}
+
+	return getPackDigest(stdout.String()), nil
+}
+
+func getPackArgs(path string, tags []string, builderImage string, noCache bool, buildArgs []string) []string {
+	args := []string{"build", tags[0], "--path", path, "--builder", builderImage, "--publish"}
+	for _, t := range tags[1:] {
+		args = append(args, "--tag", t)
+	}
+	for _, t := range tags {
+		if registry.IsInsecureImage(t) {
+			args = append(args, "--insecure-registry", registry.GetRegistryHost(t))
+		}
+	}
+	if noCache {
+		args = append(args, "--clear-cache")
+	}
+	for _, arg := range buildArgs {
+		args = append(args, "--env", arg)
+	}
+	return args
+}
+
+func getPackDigest(output string) string {
+	m := packDigestRegex.FindStringSubmatch(output)
+	if m == nil {
+		log.Infof("the digest of the image wasn't found in the output of pack")
+		return ""
+	}
+	return m[1]
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+)
+
+const (
+	gatewayFrontend = "gateway.v0"
+)
+
+var syntaxDirective = regexp.MustCompile(`^#\s*syntax\s*=\s*(\S+)\s*$`)
+
+// GetFrontend returns the frontend defined by the --frontend and --frontend-opt flags
+func GetFrontend(image string, opts []string) (model.BuildFrontend, error) {
+	frontend := model.BuildFrontend{Image: image}
+	if image == "" {
+		if len(opts) > 0 {
+			return frontend, fmt.Errorf("the flag '--frontend-opt' requires '--frontend'")
+		}
+		return frontend, nil
+	}
+
+	frontend.Attrs = map[string]string{}
+	for _, opt := range opts {
+		kv := strings.SplitN(opt, "=", 2)
+		if len(kv) != 2 {
+			return frontend, fmt.Errorf("invalid frontend-opt value %s", opt)
+		}
+		frontend.Attrs[kv[0]] = kv[1]
+	}
+	return frontend, nil
+}
+
+// getFrontendImage returns the image of the frontend of the build, from the manifest or from the '# syntax=' directive of the Dockerfile
+func getFrontendImage(frontend model.BuildFrontend, dockerFile string) string {
+	if frontend.Image != "" {
+		return frontend.Image
+	}
+
+	if dockerFile == "" {
+		return ""
+	}
+
+	image, err := getSyntaxDirective(dockerFile)
+	if err != nil {
+		log.Infof("failed to read the syntax directive of %s: %s", dockerFile, err)
+		return ""
+	}
+	return image
+}
+
+// getSyntaxDirective returns the frontend image of the '# syntax=' directive, which must be in the comments at the beginning of the Dockerfile
+func getSyntaxDirective(dockerFile string) (string, error) {
+	f, err := os.Open(dockerFile)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "#") {
+			return "", nil
+		}
+		if m := syntaxDirective.FindStringSubmatch(line); m != nil {
+			return m[1], nil
+		}
+	}
+	return "", scanner.Err()
+}
+
+// setFrontend sets the frontend image and attributes of the solve request
+func setFrontend(frontendAttrs map[string]string, frontend model.BuildFrontend, image string) string {
+	if image == "" {
+		return dockerfileFrontend
+	}
+
+	log.Infof("building with the frontend %s", image)
+	for k, v := range frontend.Attrs {
+		frontendAttrs[k] = v
+	}
+	frontendAttrs["source"] = image
+	return gatewayFrontend
+}
@@ -0,0 +1,50 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"path"
+	"strings"
+)
+
+// parseGitContext splits a git build context in the 'URL#ref:subdir' format into the context sent to buildkit ('URL#ref') and the subdirectory of the repository
+func parseGitContext(buildCtx string) (string, string) {
+	parts := strings.SplitN(buildCtx, "#", 2)
+	if len(parts) == 1 {
+		return buildCtx, ""
+	}
+
+	fragment := strings.SplitN(parts[1], ":", 2)
+	remote := parts[0]
+	if fragment[0] != "" {
+		remote = remote + "#" + fragment[0]
+	}
+
+	if len(fragment) == 1 {
+		return remote, ""
+	}
+	subdir := strings.Trim(path.Clean(fragment[1]), "/")
+	if subdir == "." {
+		subdir = ""
+	}
+	return remote, subdir
+}
+
+// getGitDockerfile returns the path of the dockerfile in the git repository
+func getGitDockerfile(subdir, dockerFile string) string {
+	if dockerFile == "" {
+		dockerFile = "Dockerfile"
+	}
+	return path.Join(subdir, dockerFile)
+}
@@ -0,0 +1,142 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/pkg/fileutils"
+	okErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/registry"
+)
+
+const (
+	labelContextHash = "dev.okteto.com/context-hash"
+)
+
+// getContextHash returns a content hash of the build context, the Dockerfile and the build options that change the resulting image.
+// Files excluded from the build context are not part of the hash
+func getContextHash(buildCtx, dockerFile string, excludes []string, target, platform string, buildArgs []string, frontend model.BuildFrontend) (string, error) {
+	if dockerFile == "" {
+		dockerFile = filepath.Join(buildCtx, "Dockerfile")
+	}
+
+	h := sha256.New()
+	if err := hashFile(h, "Dockerfile", dockerFile); err != nil {
+		return "", err
+	}
+
+	pm, err := fileutils.NewPatternMatcher(excludes)
+	if err != nil {
+		return "", fmt.Errorf("error reading the ignore patterns: %s", err.Error())
+	}
+
+	err = filepath.Walk(buildCtx, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(buildCtx, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		excluded, err := pm.Matches(rel)
+		if err != nil {
+			return err
+		}
+		if excluded {
+			if info.IsDir() && !pm.Exclusions() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		fmt.Fprintf(h, "%s %s\n", rel, info.Mode())
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "%s\n", link)
+		case info.Mode().IsRegular():
+			return hashFile(h, rel, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("error computing the hash of the build context: %s", err.Error())
+	}
+
+	args := append([]string{}, buildArgs...)
+	sort.Strings(args)
+	fmt.Fprintf(h, "target=%s\nplatform=%s\nargs=%s\nfrontend=%s\n", target, platform, strings.Join(args, ","), frontend.Image)
+	attrs := make([]string, 0, len(frontend.Attrs))
+	for k, v := range frontend.Attrs {
+		attrs = append(attrs, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(attrs)
+	fmt.Fprintf(h, "attrs=%s\n", strings.Join(attrs, ","))
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashFile writes the name and the content of a file to h
+func hashFile(h hash.Hash, name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fmt.Fprintf(h, "%s\n", name)
+	_, err = io.Copy(h, f)
+	return err
+}
+
+// getExistingImage returns the digest of the image of tag if every tag already stores an image built from contextHash
+func getExistingImage(tag, contextHash string) (string, bool) {
+	digest := ""
+	for _, t := range strings.Split(tag, ",") {
+		d, hash, err := registry.GetImageLabel(t, labelContextHash)
+		if err != nil {
+			if err != okErrors.ErrNotFound {
+				log.Infof("failed to check if '%s' exists: %s", t, err.Error())
+			}
+			return "", false
+		}
+		if hash != contextHash {
+			log.Infof("'%s' was built from a different build context", t)
+			return "", false
+		}
+		if digest == "" {
+			digest = d
+		}
+	}
+	return digest, digest != ""
+}
@@ -0,0 +1,109 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/builder/dockerignore"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/filesync"
+	"github.com/okteto/okteto/pkg/log"
+	fstypes "github.com/tonistiigi/fsutil/types"
+)
+
+const (
+	dockerignoreFilename = ".dockerignore"
+)
+
+// getExcludePatterns returns the patterns excluded from the build context.
+// '<Dockerfile>.dockerignore' takes precedence over the '.dockerignore' file of the build context, and the ignore list of the manifest is applied last.
+func getExcludePatterns(buildCtx, dockerFile string, ignore []string) ([]string, error) {
+	if dockerFile == "" {
+		dockerFile = filepath.Join(buildCtx, "Dockerfile")
+	}
+
+	excludes, err := readDockerignore(dockerFile + dockerignoreFilename)
+	if err != nil {
+		return nil, err
+	}
+
+	if excludes == nil {
+		excludes, err = readDockerignore(filepath.Join(buildCtx, dockerignoreFilename))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(ignore) > 0 {
+		patterns, err := dockerignore.ReadAll(strings.NewReader(strings.Join(ignore, "\n")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid build ignore list: %s", err)
+		}
+		excludes = append(excludes, patterns...)
+	}
+
+	return excludes, nil
+}
+
+// readDockerignore returns the patterns of an ignore file, or nil if the file doesn't exist
+func readDockerignore(filename string) ([]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	excludes, err := dockerignore.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", filename, err)
+	}
+
+	log.Infof("excluding the patterns of %s from the build context", filename)
+	if excludes == nil {
+		excludes = []string{}
+	}
+	return excludes, nil
+}
+
+// getLocalDirsProvider returns the session attachable that sends the build context and the dockerfile to buildkit.
+// The exclude patterns are applied on the client side so they don't depend on the version of the buildkit frontend.
+func getLocalDirsProvider(buildCtx, dockerfileDir string, excludes []string) (session.Attachable, error) {
+	for _, dir := range []string{buildCtx, dockerfileDir} {
+		fi, err := os.Stat(dir)
+		if err != nil {
+			return nil, fmt.Errorf("could not find %s: %s", dir, err)
+		}
+		if !fi.IsDir() {
+			return nil, fmt.Errorf("%s is not a directory", dir)
+		}
+	}
+
+	resetUIDAndGID := func(p string, st *fstypes.Stat) bool {
+		st.Uid = 0
+		st.Gid = 0
+		return true
+	}
+
+	return filesync.NewFSSyncProvider([]filesync.SyncedDir{
+		{Name: "context", Dir: buildCtx, Excludes: excludes, Map: resetUIDAndGID},
+		{Name: "dockerfile", Dir: dockerfileDir, Map: resetUIDAndGID},
+	}), nil
+}
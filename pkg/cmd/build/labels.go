@@ -0,0 +1,119 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/okteto/okteto/pkg/config"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/okteto"
+)
+
+const (
+	labelCreated  = "org.opencontainers.image.created"
+	labelSource   = "org.opencontainers.image.source"
+	labelRevision = "org.opencontainers.image.revision"
+	labelAuthors  = "org.opencontainers.image.authors"
+
+	labelBuiltBy = "dev.okteto.com/built-by"
+	labelVersion = "dev.okteto.com/version"
+	labelUser    = "dev.okteto.com/user"
+)
+
+// getImageLabels returns the standard OCI and okteto labels of an image built from buildCtx, overridden by the custom labels
+func getImageLabels(buildCtx string, custom map[string]string, now time.Time) map[string]string {
+	labels := map[string]string{
+		labelCreated: now.UTC().Format(time.RFC3339),
+		labelBuiltBy: "okteto",
+	}
+	if config.VersionString != "" {
+		labels[labelVersion] = config.VersionString
+	}
+	if userID := okteto.GetUserID(); userID != "" {
+		labels[labelUser] = userID
+	}
+
+	if model.IsGitBuildContext(buildCtx) {
+		remote, _ := parseGitContext(buildCtx)
+		remote = strings.SplitN(remote, "#", 2)[0]
+		labels[labelSource] = sanitizeGitURL(remote)
+	} else {
+		for k, v := range getGitLabels(buildCtx) {
+			labels[k] = v
+		}
+	}
+
+	for k, v := range custom {
+		labels[k] = v
+	}
+	return labels
+}
+
+// getGitLabels returns the source, revision and authors labels from the git repository of a local build context
+func getGitLabels(buildCtx string) map[string]string {
+	labels := map[string]string{}
+	repo, err := git.PlainOpenWithOptions(buildCtx, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		log.Infof("build context '%s' isn't in a git repository: %s", buildCtx, err)
+		return labels
+	}
+
+	if origin, err := repo.Remote("origin"); err == nil && len(origin.Config().URLs) > 0 {
+		labels[labelSource] = sanitizeGitURL(origin.Config().URLs[0])
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		log.Infof("failed to get the git commit of '%s': %s", buildCtx, err)
+		return labels
+	}
+	labels[labelRevision] = head.Hash().String()
+
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		log.Infof("failed to get the git commit of '%s': %s", buildCtx, err)
+		return labels
+	}
+	labels[labelAuthors] = fmt.Sprintf("%s <%s>", commit.Author.Name, commit.Author.Email)
+	return labels
+}
+
+// sanitizeGitURL removes the credentials of a git remote url, so they aren't published in the image
+func sanitizeGitURL(remote string) string {
+	u, err := url.Parse(remote)
+	if err != nil || u.Scheme == "" || u.User == nil {
+		return remote
+	}
+	u.User = nil
+	return strings.TrimSuffix(u.String(), "/")
+}
+
+// ParseLabels parses a list of labels in the 'key=value' format
+func ParseLabels(labels []string) (map[string]string, error) {
+	result := map[string]string{}
+	for _, label := range labels {
+		kv := strings.SplitN(label, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid label '%s': must follow the syntax 'key=value'", label)
+		}
+		result[kv[0]] = kv[1]
+	}
+	return result, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/moby/buildkit/client"
+	"github.com/okteto/okteto/pkg/registry"
+)
+
+// MetadataStdout is the metadata file that writes the build metadata to the standard output
+const MetadataStdout = "-"
+
+// Metadata is the machine-readable result of a build, consumed by CI pipelines
+type Metadata struct {
+	Reference     string   `json:"reference,omitempty"`
+	Digest        string   `json:"digest,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+	DurationMs    int64    `json:"durationMs"`
+	CacheHitRatio float64  `json:"cacheHitRatio"`
+	Platforms     []string `json:"platforms,omitempty"`
+}
+
+// cacheStats tracks if the completed steps of a build were cached, by vertex digest
+type cacheStats map[string]bool
+
+// update records the steps completed in a status update. Internal steps, like loading the build context, are never cached and are ignored
+func (s cacheStats) update(status *client.SolveStatus) {
+	for _, v := range status.Vertexes {
+		if v.Completed == nil || v.Error != "" || strings.HasPrefix(v.Name, "[internal]") {
+			continue
+		}
+		s[v.Digest.String()] = v.Cached
+	}
+}
+
+// ratio returns the ratio of cached steps, between 0 and 1
+func (s cacheStats) ratio() float64 {
+	if len(s) == 0 {
+		return 0
+	}
+
+	cached := 0
+	for _, c := range s {
+		if c {
+			cached++
+		}
+	}
+	return float64(cached) / float64(len(s))
+}
+
+// getMetadata returns the metadata of a build of tag and platform that started at start
+func getMetadata(tag, digest, platform string, start time.Time, cacheHitRatio float64) *Metadata {
+	m := &Metadata{
+		Digest:        digest,
+		DurationMs:    time.Since(start).Milliseconds(),
+		CacheHitRatio: cacheHitRatio,
+	}
+	if tag != "" {
+		m.Tags = strings.Split(tag, ",")
+		m.Reference = registry.GetImageWithDigest(m.Tags[0], digest)
+	}
+	if platform != "" {
+		if normalized, err := parsePlatforms(platform); err == nil {
+			m.Platforms = strings.Split(normalized, ",")
+		}
+	}
+	return m
+}
+
+// WriteMetadata writes the build metadata as json to path, or to the standard output if path is MetadataStdout
+func WriteMetadata(path string, m *Metadata) error {
+	bytes, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if path == MetadataStdout {
+		fmt.Fprintln(os.Stdout, string(bytes))
+		return nil
+	}
+
+	if err := ioutil.WriteFile(path, append(bytes, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write the build metadata to '%s': %s", path, err)
+	}
+	return nil
+}
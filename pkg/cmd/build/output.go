@@ -0,0 +1,75 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/moby/buildkit/client"
+)
+
+// dockerArchiveExporter is the docker name of the exporter of tarballs loadable with 'docker load'
+const dockerArchiveExporter = "docker-archive"
+
+// ParseOutput parses an output in the 'type=TYPE,dest=PATH' format, or a local directory path.
+// Supported types are oci and docker (or docker-archive) tarballs, tar for the root filesystem and local directories
+func ParseOutput(output string) (*client.ExportEntry, error) {
+	if !strings.Contains(output, "=") {
+		return &client.ExportEntry{Type: client.ExporterLocal, OutputDir: output}, nil
+	}
+
+	export := &client.ExportEntry{Attrs: map[string]string{}}
+	dest := ""
+	for _, field := range strings.Split(output, ",") {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid output '%s': must follow the syntax 'type=TYPE,dest=PATH'", output)
+		}
+
+		switch kv[0] {
+		case "type":
+			export.Type = kv[1]
+		case "dest":
+			dest = kv[1]
+		default:
+			export.Attrs[kv[0]] = kv[1]
+		}
+	}
+
+	if dest == "" {
+		return nil, fmt.Errorf("invalid output '%s': 'dest' is required", output)
+	}
+
+	switch export.Type {
+	case client.ExporterLocal:
+		export.OutputDir = dest
+	case dockerArchiveExporter, client.ExporterDocker, client.ExporterOCI, client.ExporterTar:
+		if export.Type == dockerArchiveExporter {
+			export.Type = client.ExporterDocker
+		}
+		export.Output = getOutputFile(dest)
+	default:
+		return nil, fmt.Errorf("invalid output '%s': type must be %s, %s, %s or %s", output, client.ExporterOCI, client.ExporterDocker, client.ExporterTar, client.ExporterLocal)
+	}
+	return export, nil
+}
+
+func getOutputFile(dest string) func(map[string]string) (io.WriteCloser, error) {
+	return func(map[string]string) (io.WriteCloser, error) {
+		return os.Create(dest)
+	}
+}
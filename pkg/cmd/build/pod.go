@@ -0,0 +1,117 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	k8Client "github.com/okteto/okteto/pkg/k8s/client"
+	"github.com/okteto/okteto/pkg/k8s/forward"
+)
+
+const (
+	podBuildKitScheme = "pod://"
+
+	// defaultBuildKitPort is the port of the tcp address of buildkitd in the kubernetes examples of BuildKit
+	defaultBuildKitPort = 1234
+)
+
+var (
+	podForwards   = map[string]*podForward{}
+	podForwardsMu sync.Mutex
+)
+
+// podForward is a port forward to a buildkitd pod
+type podForward struct {
+	address string
+	stop    func()
+}
+
+// isPodBuildKitHost returns if the BuildKit host is a buildkitd pod reached through a port forward, like 'pod://namespace/name:port'
+func isPodBuildKitHost(buildKitHost string) bool {
+	return strings.HasPrefix(buildKitHost, podBuildKitScheme)
+}
+
+// parsePodBuildKitHost returns the namespace, name and port of a 'pod://namespace/name[:port]' BuildKit host
+func parsePodBuildKitHost(buildKitHost string) (string, string, int, error) {
+	address := strings.TrimPrefix(buildKitHost, podBuildKitScheme)
+	parts := strings.Split(address, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", 0, fmt.Errorf("invalid buildkit host '%s': must follow the syntax 'pod://namespace/name[:port]'", buildKitHost)
+	}
+
+	name := parts[1]
+	port := defaultBuildKitPort
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		p, err := strconv.Atoi(name[i+1:])
+		if err != nil || p <= 0 {
+			return "", "", 0, fmt.Errorf("invalid buildkit host '%s': wrong port '%s'", buildKitHost, name[i+1:])
+		}
+		name = name[:i]
+		port = p
+	}
+	return parts[0], name, port, nil
+}
+
+// getPodBuildKitAddress forwards a local port to a buildkitd pod and returns its local tcp address.
+// The port forward is shared by the builds of the command and stopped when it exits
+func getPodBuildKitAddress(buildKitHost string) (string, error) {
+	podForwardsMu.Lock()
+	defer podForwardsMu.Unlock()
+	if pf, ok := podForwards[buildKitHost]; ok {
+		return pf.address, nil
+	}
+
+	namespace, name, port, err := parsePodBuildKitHost(buildKitHost)
+	if err != nil {
+		return "", err
+	}
+
+	c, restConfig, _, err := k8Client.GetLocal("")
+	if err != nil {
+		return "", err
+	}
+
+	localPort, stop, err := forward.ForwardPodPort(restConfig, c, namespace, name, port)
+	if err != nil {
+		return "", err
+	}
+
+	address := fmt.Sprintf("tcp://127.0.0.1:%d", localPort)
+	podForwards[buildKitHost] = &podForward{address: address, stop: stop}
+	return address, nil
+}
+
+// dropPodForward stops the port forward to a buildkitd pod, so the next build forwards a new one
+func dropPodForward(buildKitHost string) {
+	podForwardsMu.Lock()
+	defer podForwardsMu.Unlock()
+	if pf, ok := podForwards[buildKitHost]; ok {
+		pf.stop()
+		delete(podForwards, buildKitHost)
+	}
+}
+
+// StopPodForwards stops the port forwards to buildkitd pods
+func StopPodForwards() {
+	podForwardsMu.Lock()
+	defer podForwardsMu.Unlock()
+	for buildKitHost, pf := range podForwards {
+		pf.stop()
+		delete(podForwards, buildKitHost)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/moby/buildkit/client"
+)
+
+const (
+	// ProgressTTY displays the build steps in place when the output is a terminal
+	ProgressTTY = "tty"
+
+	// ProgressPlain displays the build steps line by line
+	ProgressPlain = "plain"
+
+	// ProgressJSON displays the build steps as json events, one per line
+	ProgressJSON = "json"
+
+	// ProgressQuiet doesn't display the build steps
+	ProgressQuiet = "quiet"
+
+	vertexStartEvent    = "vertex.start"
+	vertexFinishEvent   = "vertex.finish"
+	vertexLogEvent      = "vertex.log"
+	vertexProgressEvent = "vertex.progress"
+	pushRetryEvent      = "push.retry"
+)
+
+// ValidateProgress returns an error if the build output mode isn't supported
+func ValidateProgress(progress string) error {
+	switch progress {
+	case ProgressTTY, ProgressPlain, ProgressJSON, ProgressQuiet:
+		return nil
+	default:
+		return fmt.Errorf("invalid progress '%s': must be one of %s, %s, %s or %s", progress, ProgressTTY, ProgressPlain, ProgressJSON, ProgressQuiet)
+	}
+}
+
+// buildEvent is a machine-readable step of the build displayed with the json progress
+type buildEvent struct {
+	Type       string    `json:"type"`
+	Vertex     string    `json:"vertex"`
+	Name       string    `json:"name,omitempty"`
+	Time       time.Time `json:"time"`
+	Cached     bool      `json:"cached,omitempty"`
+	DurationMs int64     `json:"durationMs,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Stream     int       `json:"stream,omitempty"`
+	Data       string    `json:"data,omitempty"`
+	ID         string    `json:"id,omitempty"`
+	Current    int64     `json:"current,omitempty"`
+	Total      int64     `json:"total,omitempty"`
+}
+
+// displayJSONStatus writes the build events of ch to w until ch is closed
+func displayJSONStatus(w io.Writer, ch chan *client.SolveStatus) error {
+	encoder := json.NewEncoder(w)
+	started := map[string]bool{}
+	completed := map[string]bool{}
+	for status := range ch {
+		for _, event := range getBuildEvents(status, started, completed) {
+			if err := encoder.Encode(event); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getBuildEvents returns the events of a status update, started and completed track the vertices already reported
+func getBuildEvents(status *client.SolveStatus, started, completed map[string]bool) []buildEvent {
+	events := []buildEvent{}
+	for _, v := range status.Vertexes {
+		id := v.Digest.String()
+		if v.Started != nil && !started[id] {
+			started[id] = true
+			events = append(events, buildEvent{Type: vertexStartEvent, Vertex: id, Name: v.Name, Time: *v.Started, Cached: v.Cached})
+		}
+
+		if v.Completed != nil && !completed[id] {
+			completed[id] = true
+			event := buildEvent{Type: vertexFinishEvent, Vertex: id, Name: v.Name, Time: *v.Completed, Cached: v.Cached, Error: v.Error}
+			if v.Started != nil {
+				event.DurationMs = v.Completed.Sub(*v.Started).Milliseconds()
+			}
+			events = append(events, event)
+		}
+	}
+
+	for _, s := range status.Statuses {
+		events = append(events, buildEvent{Type: vertexProgressEvent, Vertex: s.Vertex.String(), Name: s.Name, Time: s.Timestamp, ID: s.ID, Current: s.Current, Total: s.Total})
+	}
+
+	for _, l := range status.Logs {
+		events = append(events, buildEvent{Type: vertexLogEvent, Vertex: l.Vertex.String(), Time: l.Timestamp, Stream: l.Stream, Data: string(l.Data)})
+	}
+	return events
+}
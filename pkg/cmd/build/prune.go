@@ -0,0 +1,73 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"context"
+	"time"
+
+	"github.com/moby/buildkit/client"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/registry"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+// cacheMountFilter selects the records of the 'RUN --mount=type=cache' mounts
+const cacheMountFilter = "type==" + string(client.UsageRecordTypeCacheMount)
+
+// Prune reclaims the build cache and returns the number of bytes released.
+// The Okteto build service is shared, so its cache can't be pruned directly: a new generation of the cache mount ids
+// of the user is started instead, and the previous mounts are reclaimed by the garbage collector of the build service
+func Prune(ctx context.Context, buildKitHost string, isOktetoCluster bool, keepDuration time.Duration, all bool) (int64, error) {
+	if isOktetoCluster {
+		if err := registry.ResetBuildCache(); err != nil {
+			return 0, errors.Wrap(err, "failed to reset the build cache")
+		}
+		return 0, nil
+	}
+
+	c, err := getBuildkitClient(ctx, false, buildKitHost)
+	if err != nil {
+		return 0, err
+	}
+
+	ch := make(chan client.UsageInfo)
+	var reclaimed int64
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		defer close(ch)
+		return c.Prune(ctx, ch, getPruneOptions(keepDuration, all)...)
+	})
+	eg.Go(func() error {
+		for u := range ch {
+			log.Infof("pruned %s (%d bytes)", u.ID, u.Size)
+			reclaimed += u.Size
+		}
+		return nil
+	})
+
+	if err := eg.Wait(); err != nil {
+		return 0, errors.Wrap(err, "failed to prune the build cache")
+	}
+	return reclaimed, nil
+}
+
+func getPruneOptions(keepDuration time.Duration, all bool) []client.PruneOption {
+	opts := []client.PruneOption{client.WithKeepOpt(keepDuration, 0)}
+	if all {
+		return append(opts, client.PruneAll)
+	}
+	return append(opts, client.WithFilter([]string{cacheMountFilter}))
+}
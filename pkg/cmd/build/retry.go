@@ -0,0 +1,95 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/moby/buildkit/client"
+	"github.com/okteto/okteto/pkg/log"
+)
+
+const (
+	maxPushAttempts    = 4
+	initialPushBackoff = 2 * time.Second
+)
+
+// transientPushErrors are the errors of registries and networks that usually succeed when retried
+var transientPushErrors = []string{
+	"500 internal server error",
+	"502 bad gateway",
+	"503 service unavailable",
+	"504 gateway timeout",
+	"timeout",
+	"connection reset by peer",
+	"broken pipe",
+	"unexpected eof",
+}
+
+// solveWithPushRetries solves the build, retrying it with exponential backoff when the push fails with a transient error.
+// Retries reuse the build cache and the registry skips the layers already uploaded, so the push resumes from the failed layers.
+// The ratio of cached steps is the one of the last attempt
+func solveWithPushRetries(ctx context.Context, c *client.Client, opt *client.SolveOpt, progress string, out io.Writer) (string, float64, error) {
+	backoff := initialPushBackoff
+	for attempt := 1; ; attempt++ {
+		digest, cacheHitRatio, err := solveBuild(ctx, c, opt, progress, out)
+		if err == nil || !isPush(opt) || attempt == maxPushAttempts || !isTransientPushError(err) {
+			return digest, cacheHitRatio, err
+		}
+
+		log.Infof("push attempt %d failed: %s", attempt, err)
+		reportPushRetry(out, progress, err, attempt, backoff)
+		select {
+		case <-ctx.Done():
+			return "", 0, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func isTransientPushError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	if strings.Contains(msg, "executor failed running") {
+		return false
+	}
+
+	for _, e := range transientPushErrors {
+		if strings.Contains(msg, e) {
+			return true
+		}
+	}
+	return false
+}
+
+func reportPushRetry(out io.Writer, progress string, err error, attempt int, backoff time.Duration) {
+	switch progress {
+	case ProgressJSON:
+		event := buildEvent{Type: pushRetryEvent, Time: time.Now(), Error: err.Error(), Data: backoff.String()}
+		if err := json.NewEncoder(out).Encode(event); err != nil {
+			log.Infof("failed to write the retry event: %s", err)
+		}
+	case ProgressQuiet:
+	default:
+		log.Yellow("Failed to push the image, retrying in %s (%d/%d)...", backoff, attempt, maxPushAttempts-1)
+	}
+}
+
+func isPush(opt *client.SolveOpt) bool {
+	return len(opt.Exports) > 0 && opt.Exports[0].Type == client.ExporterImage
+}
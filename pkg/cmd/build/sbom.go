@@ -0,0 +1,104 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+
+	okErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/log"
+)
+
+const (
+	syftBinary   = "syft"
+	cosignBinary = "cosign"
+
+	// SBOMFormatSPDX generates the SBOM in the SPDX json format
+	SBOMFormatSPDX = "spdx"
+
+	// SBOMFormatCycloneDX generates the SBOM in the CycloneDX json format
+	SBOMFormatCycloneDX = "cyclonedx"
+)
+
+// sbomFormats maps the SBOM formats to the syft output and the cosign attachment type
+var sbomFormats = map[string][2]string{
+	SBOMFormatSPDX:      {"spdx-json", "spdx"},
+	SBOMFormatCycloneDX: {"cyclonedx-json", "cyclonedx"},
+}
+
+// ValidateSBOMFormat returns an error if the SBOM format isn't supported
+func ValidateSBOMFormat(format string) error {
+	if _, ok := sbomFormats[format]; !ok {
+		return fmt.Errorf("invalid sbom format '%s': must be %s or %s", format, SBOMFormatSPDX, SBOMFormatCycloneDX)
+	}
+	return nil
+}
+
+// GetSBOMFilename returns the default path of the local copy of the SBOM
+func GetSBOMFilename(format string) string {
+	return fmt.Sprintf("sbom.%s.json", format)
+}
+
+// GenerateSBOM generates the SBOM of a pushed image with syft, writes it to output and attaches it to the image in the registry with cosign
+func GenerateSBOM(ctx context.Context, image, format, output string) error {
+	syft, err := exec.LookPath(syftBinary)
+	if err != nil {
+		return okErrors.UserError{
+			E:    fmt.Errorf("the sbom generation requires '%s'", syftBinary),
+			Hint: "Install it following the instructions at https://github.com/anchore/syft",
+		}
+	}
+
+	log.Information("Generating the %s SBOM of '%s'...", format, image)
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, syft, "--quiet", image, "--output", sbomFormats[format][0])
+	cmd.Env = append(os.Environ(), getSyftEnv(image)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		log.Infof("syft failed: %s", stderr.String())
+		return fmt.Errorf("failed to generate the sbom of '%s': %s", image, err)
+	}
+
+	if err := ioutil.WriteFile(output, stdout.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write the sbom: %s", err)
+	}
+	log.Success("SBOM saved to '%s'", output)
+
+	cosign, err := exec.LookPath(cosignBinary)
+	if err != nil {
+		log.Yellow("Install '%s' to attach the SBOM to the image in the registry", cosignBinary)
+		return nil
+	}
+
+	stderr.Reset()
+	cmd = exec.CommandContext(ctx, cosign, "attach", "sbom", "--sbom", output, "--type", sbomFormats[format][1], image)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		log.Infof("cosign failed: %s", stderr.String())
+		return fmt.Errorf("failed to attach the sbom to '%s': %s", image, err)
+	}
+	log.Success("SBOM attached to '%s'", image)
+	return nil
+}
+
+// getSyftEnv returns the syft settings to pull the image from its registry
+func getSyftEnv(image string) []string {
+	return getRegistryEnv(image, "SYFT_REGISTRY_AUTH_USERNAME", "SYFT_REGISTRY_AUTH_PASSWORD", "SYFT_REGISTRY_INSECURE_SKIP_TLS_VERIFY")
+}
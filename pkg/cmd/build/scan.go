@@ -0,0 +1,224 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	okErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/okteto/okteto/pkg/registry"
+)
+
+const (
+	trivyBinary = "trivy"
+
+	// DefaultScanSeverity is the default severity that fails the vulnerability scan
+	DefaultScanSeverity = "HIGH"
+)
+
+// severities are sorted from the lowest to the highest
+var severities = []string{"UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+type trivyReport struct {
+	Results []trivyResult `json:"Results"`
+}
+
+type trivyResult struct {
+	Target          string               `json:"Target"`
+	Vulnerabilities []trivyVulnerability `json:"Vulnerabilities"`
+}
+
+type trivyVulnerability struct {
+	VulnerabilityID  string     `json:"VulnerabilityID"`
+	PkgName          string     `json:"PkgName"`
+	InstalledVersion string     `json:"InstalledVersion"`
+	FixedVersion     string     `json:"FixedVersion"`
+	Severity         string     `json:"Severity"`
+	Layer            trivyLayer `json:"Layer"`
+}
+
+type trivyLayer struct {
+	Digest string `json:"Digest"`
+	DiffID string `json:"DiffID"`
+}
+
+// ValidateScanSeverity returns an error if the severity threshold of the vulnerability scan isn't valid
+func ValidateScanSeverity(severity string) error {
+	if getSeverityLevel(severity) < 0 {
+		return fmt.Errorf("invalid scan severity '%s': must be one of %s", severity, strings.Join(severities, ", "))
+	}
+	return nil
+}
+
+// Scan scans a pushed image for vulnerabilities with trivy, prints a summary per layer and
+// returns an error if a vulnerability is at or above the severity threshold
+func Scan(ctx context.Context, image, severity string) error {
+	path, err := exec.LookPath(trivyBinary)
+	if err != nil {
+		return okErrors.UserError{
+			E:    fmt.Errorf("the vulnerability scan requires '%s'", trivyBinary),
+			Hint: "Install it following the instructions at https://aquasecurity.github.io/trivy",
+		}
+	}
+
+	log.Information("Scanning '%s' for vulnerabilities...", image)
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, "--quiet", "image", "--format", "json", "--no-progress", image)
+	cmd.Env = append(os.Environ(), getScanEnv(image)...)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		log.Infof("trivy failed: %s", stderr.String())
+		return fmt.Errorf("failed to scan '%s': %s", image, err)
+	}
+
+	vulnerabilities, err := parseTrivyReport(output)
+	if err != nil {
+		return fmt.Errorf("failed to read the scan of '%s': %s", image, err)
+	}
+
+	failed := printScanSummary(os.Stdout, vulnerabilities, severity)
+	if failed > 0 {
+		return okErrors.UserError{
+			E:    fmt.Errorf("'%s' has %d vulnerabilities with severity %s or higher", image, failed, strings.ToUpper(severity)),
+			Hint: "Update the affected packages or raise the threshold with '--scan-severity'",
+		}
+	}
+
+	log.Success("No vulnerabilities with severity %s or higher found", strings.ToUpper(severity))
+	return nil
+}
+
+// getScanEnv returns the trivy settings to pull the image from its registry
+func getScanEnv(image string) []string {
+	return getRegistryEnv(image, "TRIVY_USERNAME", "TRIVY_PASSWORD", "TRIVY_INSECURE")
+}
+
+// getRegistryEnv returns the env vars with the okteto registry credentials and the TLS verification of the registry of image, for external tools
+func getRegistryEnv(image, usernameVar, passwordVar, insecureVar string) []string {
+	env := []string{}
+	if registryURL, err := okteto.GetRegistry(); err == nil && registry.IsOktetoRegistryImage(image, registryURL) {
+		if token, err := okteto.GetToken(); err == nil {
+			env = append(env, fmt.Sprintf("%s=%s", usernameVar, okteto.GetUserID()), fmt.Sprintf("%s=%s", passwordVar, token.Token))
+		}
+	}
+	if registry.IsInsecureImage(image) {
+		env = append(env, fmt.Sprintf("%s=true", insecureVar))
+	}
+	return env
+}
+
+// parseTrivyReport returns the vulnerabilities of a trivy json report, older versions of trivy return the list of results
+func parseTrivyReport(data []byte) ([]trivyVulnerability, error) {
+	var results []trivyResult
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &results); err != nil {
+			return nil, err
+		}
+	} else {
+		var report trivyReport
+		if err := json.Unmarshal(data, &report); err != nil {
+			return nil, err
+		}
+		results = report.Results
+	}
+
+	vulnerabilities := []trivyVulnerability{}
+	for _, r := range results {
+		vulnerabilities = append(vulnerabilities, r.Vulnerabilities...)
+	}
+	return vulnerabilities, nil
+}
+
+// printScanSummary prints the vulnerabilities per layer and the ones at or above the severity threshold, and returns how many are
+func printScanSummary(w io.Writer, vulnerabilities []trivyVulnerability, severity string) int {
+	threshold := getSeverityLevel(severity)
+	layers := []string{}
+	counts := map[string]map[string]int{}
+	failed := []trivyVulnerability{}
+	for _, v := range vulnerabilities {
+		layer := v.Layer.DiffID
+		if layer == "" {
+			layer = v.Layer.Digest
+		}
+		if _, ok := counts[layer]; !ok {
+			layers = append(layers, layer)
+			counts[layer] = map[string]int{}
+		}
+		counts[layer][strings.ToUpper(v.Severity)]++
+
+		if getSeverityLevel(v.Severity) >= threshold {
+			failed = append(failed, v)
+		}
+	}
+
+	if len(vulnerabilities) == 0 {
+		return 0
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "LAYER\tCRITICAL\tHIGH\tMEDIUM\tLOW\tUNKNOWN")
+	for _, layer := range layers {
+		c := counts[layer]
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\n", shortLayer(layer), c["CRITICAL"], c["HIGH"], c["MEDIUM"], c["LOW"], c["UNKNOWN"])
+	}
+	tw.Flush()
+
+	if len(failed) == 0 {
+		return 0
+	}
+
+	sort.SliceStable(failed, func(i, j int) bool {
+		return getSeverityLevel(failed[i].Severity) > getSeverityLevel(failed[j].Severity)
+	})
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VULNERABILITY\tSEVERITY\tPACKAGE\tINSTALLED\tFIXED\tLAYER")
+	for _, v := range failed {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", v.VulnerabilityID, strings.ToUpper(v.Severity), v.PkgName, v.InstalledVersion, v.FixedVersion, shortLayer(v.Layer.DiffID))
+	}
+	tw.Flush()
+	return len(failed)
+}
+
+func getSeverityLevel(severity string) int {
+	for i, s := range severities {
+		if strings.EqualFold(s, severity) {
+			return i
+		}
+	}
+	return -1
+}
+
+func shortLayer(layer string) string {
+	layer = strings.TrimPrefix(layer, "sha256:")
+	if len(layer) > 12 {
+		return layer[:12]
+	}
+	if layer == "" {
+		return "-"
+	}
+	return layer
+}
@@ -0,0 +1,114 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/config"
+)
+
+const trivyReportJSON = `{
+  "SchemaVersion": 2,
+  "ArtifactName": "okteto/api@sha256:4a1c",
+  "Results": [
+    {
+      "Target": "okteto/api (alpine 3.12.0)",
+      "Vulnerabilities": [
+        {"VulnerabilityID": "CVE-2020-1967", "PkgName": "libssl1.1", "InstalledVersion": "1.1.1g-r0", "FixedVersion": "1.1.1i-r0", "Severity": "HIGH", "Layer": {"DiffID": "sha256:50644c29ef5a27c9a40c393a73ece2479de78325cae7d762ef3cdc19bf42dd0a"}},
+        {"VulnerabilityID": "CVE-2020-28928", "PkgName": "musl", "InstalledVersion": "1.1.24-r8", "FixedVersion": "1.1.24-r10", "Severity": "MEDIUM", "Layer": {"DiffID": "sha256:50644c29ef5a27c9a40c393a73ece2479de78325cae7d762ef3cdc19bf42dd0a"}}
+      ]
+    },
+    {
+      "Target": "app/package-lock.json",
+      "Vulnerabilities": [
+        {"VulnerabilityID": "CVE-2021-23337", "PkgName": "lodash", "InstalledVersion": "4.17.15", "FixedVersion": "4.17.21", "Severity": "CRITICAL", "Layer": {"DiffID": "sha256:9f1e3ad3b9dbb7d0fe1a5b4aa5f0b4e1cda7a0d1b3ab3a4f9b8f1ce3c2f0a111"}}
+      ]
+    }
+  ]
+}`
+
+func Test_parseTrivyReport(t *testing.T) {
+	vulnerabilities, err := parseTrivyReport([]byte(trivyReportJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vulnerabilities) != 3 {
+		t.Fatalf("expected 3 vulnerabilities, got %d", len(vulnerabilities))
+	}
+
+	legacy := `[{"Target": "okteto/api", "Vulnerabilities": [{"VulnerabilityID": "CVE-2020-1967", "Severity": "HIGH"}]}]`
+	vulnerabilities, err = parseTrivyReport([]byte(legacy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vulnerabilities) != 1 {
+		t.Fatalf("expected 1 vulnerability, got %d", len(vulnerabilities))
+	}
+}
+
+func Test_printScanSummary(t *testing.T) {
+	vulnerabilities, err := parseTrivyReport([]byte(trivyReportJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		severity string
+		failed   int
+	}{
+		{severity: "critical", failed: 1},
+		{severity: "HIGH", failed: 2},
+		{severity: "LOW", failed: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.severity, func(t *testing.T) {
+			var b bytes.Buffer
+			if failed := printScanSummary(&b, vulnerabilities, tt.severity); failed != tt.failed {
+				t.Errorf("expected %d failed vulnerabilities, got %d", tt.failed, failed)
+			}
+			if !strings.Contains(b.String(), "50644c29ef5a  0         1     1       0    0") {
+				t.Errorf("missing layer summary:\n%s", b.String())
+			}
+		})
+	}
+
+	if err := ValidateScanSeverity("severe"); err == nil {
+		t.Error("expected an error for an invalid severity")
+	}
+}
+
+func Test_getSyftEnv(t *testing.T) {
+	config.SetInsecureRegistries([]string{"registry.example.com"})
+	defer config.SetInsecureRegistries(nil)
+
+	env := getSyftEnv("registry.example.com/api@sha256:4a1c")
+	if !reflect.DeepEqual(env, []string{"SYFT_REGISTRY_INSECURE_SKIP_TLS_VERIFY=true"}) {
+		t.Errorf("unexpected env: %v", env)
+	}
+
+	if env := getSyftEnv("okteto/api@sha256:4a1c"); len(env) != 0 {
+		t.Errorf("unexpected env: %v", env)
+	}
+
+	if err := ValidateSBOMFormat(SBOMFormatCycloneDX); err != nil {
+		t.Error(err)
+	}
+	if err := ValidateSBOMFormat("syft"); err == nil {
+		t.Error("expected an error for an invalid sbom format")
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	okErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/registry"
+)
+
+// Sign signs a pushed image with cosign, using the key when it is set or keyless signing otherwise.
+// The image should include its digest, so the signature applies to the pushed image and not to a tag that might move
+func Sign(ctx context.Context, image, key string) error {
+	cosign, err := exec.LookPath(cosignBinary)
+	if err != nil {
+		return okErrors.UserError{
+			E:    fmt.Errorf("the image signature requires '%s'", cosignBinary),
+			Hint: "Install it following the instructions at https://github.com/sigstore/cosign",
+		}
+	}
+
+	if !strings.Contains(image, "@") {
+		log.Yellow("Signing '%s' by tag, the signature will follow the tag if it's pushed again", image)
+	}
+
+	log.Information("Signing '%s'...", image)
+	cmd := exec.CommandContext(ctx, cosign, getSignArgs(image, key)...)
+	cmd.Env = os.Environ()
+	if key == "" {
+		cmd.Env = append(cmd.Env, "COSIGN_EXPERIMENTAL=1")
+	}
+
+	// keyless signing opens the browser to authenticate and key files can ask for their password
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to sign '%s': %s", image, err)
+	}
+
+	log.Success("Image '%s' signed", image)
+	return nil
+}
+
+func getSignArgs(image, key string) []string {
+	args := []string{"sign"}
+	if key != "" {
+		args = append(args, "--key", key)
+	}
+	if registry.IsInsecureImage(image) {
+		args = append(args, "--allow-insecure-registry")
+	}
+	return append(args, image)
+}
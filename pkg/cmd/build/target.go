@@ -0,0 +1,78 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+)
+
+// GetDevTarget returns the target stage of the dev image of b.
+// If b doesn't define a target and its Dockerfile has a stage named model.DevTarget, that stage is built instead of the last one
+func GetDevTarget(b *model.BuildInfo) string {
+	if b.Target != "" || b.IsBuildpacks() || model.IsGitBuildContext(b.Context) {
+		return b.Target
+	}
+
+	dockerFile := b.Dockerfile
+	if dockerFile == "" {
+		dockerFile = filepath.Join(b.Context, "Dockerfile")
+	}
+
+	stages, err := getDockerfileStages(dockerFile)
+	if err != nil {
+		log.Infof("failed to read the stages of '%s': %s", dockerFile, err)
+		return ""
+	}
+
+	for _, stage := range stages {
+		if stage == model.DevTarget {
+			log.Infof("building the '%s' stage of '%s'", model.DevTarget, dockerFile)
+			return model.DevTarget
+		}
+	}
+	return ""
+}
+
+// getDockerfileStages returns the names of the stages of a Dockerfile, lowercased like BuildKit does
+func getDockerfileStages(dockerFile string) ([]string, error) {
+	f, err := os.Open(dockerFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	result, err := parser.Parse(f)
+	if err != nil {
+		return nil, err
+	}
+
+	stages := []string{}
+	for _, node := range result.AST.Children {
+		if !strings.EqualFold(node.Value, "from") || node.Next == nil || node.Next.Next == nil {
+			continue
+		}
+
+		as := node.Next.Next
+		if strings.EqualFold(as.Value, "as") && as.Next != nil {
+			stages = append(stages, strings.ToLower(as.Next.Value))
+		}
+	}
+	return stages, nil
+}
@@ -17,6 +17,9 @@ import (
 	"context"
 
 	"github.com/okteto/okteto/pkg/k8s/deployments"
+	"github.com/okteto/okteto/pkg/k8s/hpas"
+	"github.com/okteto/okteto/pkg/k8s/leases"
+	"github.com/okteto/okteto/pkg/k8s/pdbs"
 	"github.com/okteto/okteto/pkg/k8s/secrets"
 	"github.com/okteto/okteto/pkg/k8s/services"
 	"github.com/okteto/okteto/pkg/log"
@@ -52,12 +55,34 @@ func Run(dev *model.Dev, d *appsv1.Deployment, trList map[string]*model.Translat
 		return err
 	}
 
+	for _, namespace := range dev.GetNamespaces() {
+		if err := secrets.DestroyRegistrySecret(ctx, namespace, c); err != nil {
+			return err
+		}
+	}
+
+	if err := leases.Release(ctx, dev, c); err != nil {
+		return err
+	}
+
+	if err := pdbs.Restore(ctx, dev, c); err != nil {
+		return err
+	}
+
+	if err := hpas.Restore(ctx, dev, c); err != nil {
+		return err
+	}
+
 	stopSyncthing(dev)
 
 	if err := ssh.RemoveEntry(dev.Name); err != nil {
 		log.Infof("failed to remove ssh entry: %s", err)
 	}
 
+	if err := ssh.ResetHostKey(dev.Name); err != nil {
+		log.Infof("failed to reset ssh host key: %s", err)
+	}
+
 	if d == nil {
 		return nil
 	}
@@ -19,9 +19,12 @@ import (
 	"time"
 
 	"github.com/okteto/okteto/pkg/k8s/labels"
-	"github.com/okteto/okteto/pkg/k8s/pods"
 	"github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/model"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sLabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -45,25 +48,57 @@ func waitForDevPodsTermination(ctx context.Context, c kubernetes.Interface, d *m
 func _waitForDevPodsTermination(ctx context.Context, c kubernetes.Interface, namespace string, selector map[string]string, wg *sync.WaitGroup, t int) {
 	defer wg.Done()
 
-	tick := time.NewTicker(1 * time.Second)
-	for i := 0; i < t; i++ {
-		ps, err := pods.ListBySelector(ctx, namespace, selector, c)
-		if err != nil {
-			log.Infof("failed to get dev pods with selector %s, exiting: %s", selector, err)
-			return
+	opts := metav1.ListOptions{LabelSelector: k8sLabels.SelectorFromSet(selector).String()}
+	ps, err := c.CoreV1().Pods(namespace).List(ctx, opts)
+	if err != nil {
+		log.Infof("failed to get dev pods with selector %s, exiting: %s", selector, err)
+		return
+	}
+
+	pending := map[string]bool{}
+	for i := range ps.Items {
+		if ps.Items[i].GetDeletionTimestamp() == nil {
+			log.Infof("waiting for %s/%s to terminate", ps.Items[i].GetNamespace(), ps.Items[i].GetName())
+			pending[ps.Items[i].GetName()] = true
 		}
+	}
+
+	if len(pending) == 0 {
+		return
+	}
+
+	opts.ResourceVersion = ps.ResourceVersion
+	w, err := c.CoreV1().Pods(namespace).Watch(ctx, opts)
+	if err != nil {
+		log.Infof("failed to watch dev pods with selector %s, exiting: %s", selector, err)
+		return
+	}
+	defer w.Stop()
+
+	timeout := time.NewTimer(time.Duration(t) * time.Second)
+	defer timeout.Stop()
 
-		exit := true
-		for i := range ps {
-			log.Infof("waiting for %s/%s to terminate", ps[i].GetNamespace(), ps[i].GetName())
-			if pods.Exists(ctx, ps[i].GetName(), ps[i].GetNamespace(), c) {
-				exit = false
+	for len(pending) > 0 {
+		select {
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				log.Infof("dev pods watch with selector %s closed, exiting", selector)
+				return
 			}
-		}
 
-		if exit {
+			p, ok := event.Object.(*apiv1.Pod)
+			if !ok {
+				continue
+			}
+
+			if event.Type == watch.Deleted || p.GetDeletionTimestamp() != nil {
+				delete(pending, p.GetName())
+			}
+		case <-timeout.C:
+			log.Infof("dev pods with selector %s didn't terminate after %d seconds", selector, t)
+			return
+		case <-ctx.Done():
 			return
 		}
-		<-tick.C
 	}
 }
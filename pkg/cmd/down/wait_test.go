@@ -16,6 +16,7 @@ package down
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/okteto/okteto/pkg/k8s/labels"
 	"github.com/okteto/okteto/pkg/model"
@@ -75,3 +76,25 @@ func Test_waitForDevPodsTermination(t *testing.T) {
 	}
 
 }
+
+func Test_waitForDevPodsTerminationWatch(t *testing.T) {
+	ctx := context.Background()
+	pod := &v1.Pod{}
+	pod.SetName("dev-123")
+	pod.SetNamespace("ns")
+	pod.Labels = map[string]string{labels.InteractiveDevLabel: "dev"}
+
+	client := fake.NewSimpleClientset(pod)
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		if err := client.CoreV1().Pods("ns").Delete(ctx, "dev-123", metav1.DeleteOptions{}); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	start := time.Now()
+	waitForDevPodsTermination(ctx, client, &model.Dev{Name: "dev", Namespace: "ns"}, 5)
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Fatalf("didn't stop waiting when the pod was deleted, took %s", elapsed)
+	}
+}
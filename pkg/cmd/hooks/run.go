@@ -0,0 +1,111 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+)
+
+const (
+	//PreUp is the stage of the hooks executed before activating the development container
+	PreUp = "preUp"
+
+	//PostUp is the stage of the hooks executed once the development container is ready and the files are synchronized
+	PostUp = "postUp"
+
+	//PreDown is the stage of the hooks executed before deactivating the development container
+	PreDown = "preDown"
+
+	//PostDown is the stage of the hooks executed after deactivating the development container
+	PostDown = "postDown"
+)
+
+//RemoteExec executes a command in the development container
+type RemoteExec func(ctx context.Context, command []string, stdout, stderr io.Writer) error
+
+//Run executes the hooks of a stage in order. Local commands run in the folder of the manifest and remote commands in the development container.
+//A failed hook stops the sequence and returns its error, unless its failure policy is to continue
+func Run(stage string, hooks []model.Hook, dir string, remote RemoteExec) error {
+	for i, h := range hooks {
+		if err := runHook(stage, h, dir, remote); err != nil {
+			if h.ContinueOnFailure() {
+				log.Yellow("%s hook %d failed, continuing: %s", stage, i+1, err.Error())
+				continue
+			}
+			return errors.UserError{
+				E:    fmt.Errorf("%s hook %d failed: %s", stage, i+1, err.Error()),
+				Hint: fmt.Sprintf("Set 'onFailure: %s' in the hook to ignore its failures", model.HookFailurePolicyContinue),
+			}
+		}
+	}
+	return nil
+}
+
+func runHook(stage string, h model.Hook, dir string, remote RemoteExec) error {
+	ctx, cancel := context.WithTimeout(context.Background(), h.GetTimeout())
+	defer cancel()
+
+	if h.Local != "" {
+		log.Information("Running %s hook: %s", stage, h.Local)
+		if err := runLocal(ctx, h.Local, dir); err != nil {
+			return getHookError(ctx, h, err)
+		}
+	}
+
+	if h.Remote != "" {
+		if remote == nil {
+			return fmt.Errorf("remote commands are not supported in %s hooks", stage)
+		}
+		log.Information("Running %s hook in your development container: %s", stage, h.Remote)
+		err := remote(ctx, []string{"sh", "-c", h.Remote}, os.Stdout, os.Stderr)
+		// remote executions return no error when their context is done
+		if err != nil || ctx.Err() != nil {
+			return getHookError(ctx, h, err)
+		}
+	}
+	return nil
+}
+
+func runLocal(ctx context.Context, command, dir string) error {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+
+	c := exec.CommandContext(ctx, shell, flag, command)
+	c.Dir = dir
+	c.Env = os.Environ()
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
+
+func getHookError(ctx context.Context, h model.Hook, err error) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", h.GetTimeout())
+	}
+	if err == nil {
+		return ctx.Err()
+	}
+	return err
+}
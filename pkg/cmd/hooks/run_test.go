@@ -0,0 +1,96 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/okteto/okteto/pkg/model"
+)
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("local hooks of the test use sh")
+	}
+
+	remoteCommands := 0
+	remote := func(ctx context.Context, command []string, stdout, stderr io.Writer) error {
+		remoteCommands++
+		if command[2] == "fail" {
+			return fmt.Errorf("command failed")
+		}
+		if command[2] == "hang" {
+			<-ctx.Done()
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name    string
+		hooks   []model.Hook
+		remote  RemoteExec
+		want    int
+		wantErr bool
+	}{
+		{
+			name:   "ok",
+			hooks:  []model.Hook{{Local: "true", Remote: "ok"}, {Remote: "ok"}},
+			remote: remote,
+			want:   2,
+		},
+		{
+			name:    "local-failure",
+			hooks:   []model.Hook{{Local: "exit 1", Remote: "ok"}, {Remote: "ok"}},
+			remote:  remote,
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name:   "continue-on-failure",
+			hooks:  []model.Hook{{Remote: "fail", OnFailure: model.HookFailurePolicyContinue}, {Remote: "ok"}},
+			remote: remote,
+			want:   2,
+		},
+		{
+			name:    "remote-timeout",
+			hooks:   []model.Hook{{Remote: "hang", Timeout: 10 * time.Millisecond}, {Remote: "ok"}},
+			remote:  remote,
+			want:    1,
+			wantErr: true,
+		},
+		{
+			name:    "remote-not-supported",
+			hooks:   []model.Hook{{Remote: "ok"}},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remoteCommands = 0
+			err := Run(PostUp, tt.hooks, "", tt.remote)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if remoteCommands != tt.want {
+				t.Errorf("expected %d remote commands, got %d", tt.want, remoteCommands)
+			}
+		})
+	}
+}
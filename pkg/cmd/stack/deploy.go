@@ -34,13 +34,13 @@ import (
 )
 
 //Deploy deploys a stack
-func Deploy(ctx context.Context, s *model.Stack, forceBuild, wait, noCache bool) error {
+func Deploy(ctx context.Context, s *model.Stack, forceBuild, wait, noCache bool, parallelism int) error {
 	settings := cli.New()
 	if s.Namespace == "" {
 		s.Namespace = settings.Namespace()
 	}
 
-	if err := translate(ctx, s, forceBuild, noCache); err != nil {
+	if err := translate(ctx, s, forceBuild, noCache, parallelism); err != nil {
 		return err
 	}
 
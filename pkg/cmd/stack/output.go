@@ -0,0 +1,68 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// syncWriter serializes the writes of concurrent builds
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+func (s *syncWriter) withPrefix(prefix string) *prefixWriter {
+	return &prefixWriter{prefix: []byte(prefix), w: s}
+}
+
+// prefixWriter writes complete lines starting with prefix, so the output of concurrent builds isn't mixed within a line
+type prefixWriter struct {
+	prefix []byte
+	w      io.Writer
+	buf    bytes.Buffer
+}
+
+func (p *prefixWriter) Write(data []byte) (int, error) {
+	p.buf.Write(data)
+	for {
+		i := bytes.IndexByte(p.buf.Bytes(), '\n')
+		if i < 0 {
+			return len(data), nil
+		}
+
+		line := append(append([]byte{}, p.prefix...), p.buf.Next(i+1)...)
+		if _, err := p.w.Write(line); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Flush writes the last incomplete line
+func (p *prefixWriter) Flush() {
+	if p.buf.Len() == 0 {
+		return
+	}
+	line := append(append([]byte{}, p.prefix...), p.buf.Bytes()...)
+	_, _ = p.w.Write(append(line, '\n'))
+	p.buf.Reset()
+}
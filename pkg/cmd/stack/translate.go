@@ -16,6 +16,7 @@ package stack
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
@@ -29,6 +30,7 @@ import (
 	"github.com/okteto/okteto/pkg/okteto"
 	"github.com/okteto/okteto/pkg/registry"
 	"github.com/subosito/gotenv"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
@@ -39,12 +41,12 @@ const (
 	helmDriver            = "secrets"
 )
 
-func translate(ctx context.Context, s *model.Stack, forceBuild, noCache bool) error {
+func translate(ctx context.Context, s *model.Stack, forceBuild, noCache bool, parallelism int) error {
 	if err := translateEnvVars(s); err != nil {
 		return nil
 	}
 
-	if err := translateBuildImages(ctx, s, forceBuild, noCache); err != nil {
+	if err := translateBuildImages(ctx, s, forceBuild, noCache, parallelism); err != nil {
 		return err
 	}
 	return nil
@@ -102,7 +104,7 @@ func translateEnvFile(svc *model.Service, filename string) error {
 	return nil
 }
 
-func translateBuildImages(ctx context.Context, s *model.Stack, forceBuild, noCache bool) error {
+func translateBuildImages(ctx context.Context, s *model.Stack, forceBuild, noCache bool, parallelism int) error {
 	c, _, configNamespace, err := k8Client.GetLocal("")
 	if err != nil {
 		return err
@@ -126,8 +128,8 @@ func translateBuildImages(ctx context.Context, s *model.Stack, forceBuild, noCac
 	if err != nil {
 		return err
 	}
-	building := false
 
+	services := []string{}
 	for name, svc := range s.Services {
 		if svc.Build == nil {
 			continue
@@ -138,21 +140,124 @@ func translateBuildImages(ctx context.Context, s *model.Stack, forceBuild, noCac
 			}
 			log.Infof("image '%s' not found, building it", svc.Image)
 		}
-		if !building {
-			building = true
-			log.Information("Running your build in %s...", buildKitHost)
+		services = append(services, name)
+	}
+	sort.Strings(services)
+
+	builds := getServiceBuilds(s, services, oktetoRegistryURL)
+	if len(builds) == 0 {
+		return nil
+	}
+	log.Information("Running your build in %s...", buildKitHost)
+
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	sem := make(chan struct{}, parallelism)
+	parallel := parallelism > 1 && len(builds) > 1
+	output := &syncWriter{w: os.Stdout}
+	g, gCtx := errgroup.WithContext(ctx)
+	for _, b := range builds {
+		b := b
+		g.Go(func() error {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			names := strings.Join(b.services, ", ")
+			log.Information("Building image for service '%s'...", names)
+			progress := build.ProgressTTY
+			var out io.Writer = os.Stdout
+			if parallel {
+				w := output.withPrefix(fmt.Sprintf("[%s] ", names))
+				defer w.Flush()
+				progress = build.ProgressPlain
+				out = w
+			}
+
+			svc := s.Services[b.services[0]]
+			buildArgs := model.SerializeBuildArgs(svc.Build.Args)
+			var digest string
+			var err error
+			if svc.Build.IsBuildpacks() {
+				digest, err = build.RunBuildpacks(gCtx, svc.Build.Context, strings.Join(b.tags, ","), svc.Build.GetBuilderImage(), noCache, buildArgs, out)
+			} else {
+				digest, err = build.Run(gCtx, build.Options{
+					BuildKitHost:    buildKitHost,
+					IsOktetoCluster: isOktetoCluster,
+					Path:            svc.Build.Context,
+					Dockerfile:      svc.Build.Dockerfile,
+					Tag:             strings.Join(b.tags, ","),
+					Target:          svc.Build.Target,
+					Platform:        svc.Build.Platform,
+					NoCache:         noCache,
+					Compression:     svc.Build.Compression,
+					CacheFrom:       svc.Build.CacheFrom,
+					BuildArgs:       buildArgs,
+					Secrets:         model.SerializeBuildSecrets(svc.Build.Secrets),
+					Ignore:          svc.Build.Ignore,
+					CacheScope:      svc.Build.GetCacheScope(s.Namespace, b.services[0]),
+					Labels:          svc.Build.Labels,
+					Frontend:        svc.Build.Frontend,
+					Progress:        progress,
+					Out:             out,
+				})
+			}
+			if err != nil {
+				return fmt.Errorf("error building image for '%s': %s", names, err)
+			}
+			b.digest = digest
+			log.Success("Image for service '%s' successfully pushed", names)
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	for _, b := range builds {
+		for i, name := range b.services {
+			svc := s.Services[name]
+			svc.Image = registry.GetImageWithDigest(b.tags[i], b.digest)
+			svc.SetLastBuiltAnnotationtamp()
+			s.Services[name] = svc
+		}
+	}
+	return nil
+}
+
+// serviceBuild is an image build shared by the services with the same build definition
+type serviceBuild struct {
+	services []string
+	tags     []string
+	digest   string
+}
+
+// getServiceBuilds groups the services with identical build definitions so their image is built once and pushed with the tags of every service
+func getServiceBuilds(s *model.Stack, services []string, oktetoRegistryURL string) []*serviceBuild {
+	result := []*serviceBuild{}
+	byKey := map[string]*serviceBuild{}
+	for _, name := range services {
+		svc := s.Services[name]
+		key := getBuildKey(svc.Build)
+		if svc.Build.CacheScope == model.CacheScopeService {
+			key = fmt.Sprintf("%s|%s", key, name)
 		}
-		imageTag := registry.GetImageTag(svc.Image, name, s.Namespace, oktetoRegistryURL)
-		log.Information("Building image for service '%s'...", name)
-		buildArgs := model.SerializeBuildArgs(svc.Build.Args)
-		if err := build.Run(ctx, buildKitHost, isOktetoCluster, svc.Build.Context, svc.Build.Dockerfile, imageTag, svc.Build.Target, noCache, svc.Build.CacheFrom, buildArgs, "tty"); err != nil {
-			return fmt.Errorf("error building image for '%s': %s", name, err)
+		b, ok := byKey[key]
+		if !ok {
+			b = &serviceBuild{}
+			byKey[key] = b
+			result = append(result, b)
+		} else {
+			log.Infof("service '%s' has the same build as '%s'", name, b.services[0])
 		}
-		svc.Image = imageTag
-		svc.SetLastBuiltAnnotationtamp()
-		s.Services[name] = svc
-		log.Success("Image for service '%s' successfully pushed", name)
+		b.services = append(b.services, name)
+		b.tags = append(b.tags, registry.GetImageTag(svc.Image, name, s.Namespace, oktetoRegistryURL, svc.Build))
 	}
+	return result
+}
 
-	return nil
+// getBuildKey returns a key that is equal for identical build definitions
+func getBuildKey(b *model.BuildInfo) string {
+	return fmt.Sprintf("%s|%s|%s|%s|%v|%v|%v|%v|%v|%s|%s|%s|%s|%v", b.Context, b.Dockerfile, b.Target, b.Platform, model.SerializeBuildArgs(b.Args), b.CacheFrom, model.SerializeBuildSecrets(b.Secrets), b.Ignore, b.Frontend, b.Compression, b.Builder, b.BuilderImage, b.CacheScope, b.Labels)
 }
@@ -14,8 +14,10 @@
 package stack
 
 import (
+	"bytes"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"testing"
 
 	"github.com/okteto/okteto/pkg/model"
@@ -78,3 +80,65 @@ func Test_translateEnvVars(t *testing.T) {
 		}
 	}
 }
+
+func Test_getServiceBuilds(t *testing.T) {
+	s := &model.Stack{
+		Name:      "name",
+		Namespace: "cindy",
+		Services: map[string]model.Service{
+			"api":    {Build: &model.BuildInfo{BuildInfoRaw: model.BuildInfoRaw{Context: "/app", Dockerfile: "/app/Dockerfile"}}},
+			"worker": {Build: &model.BuildInfo{BuildInfoRaw: model.BuildInfoRaw{Context: "/app", Dockerfile: "/app/Dockerfile"}}},
+			"web":    {Build: &model.BuildInfo{BuildInfoRaw: model.BuildInfoRaw{Context: "/app", Dockerfile: "/app/Dockerfile", Target: "web"}}},
+			"arm":    {Build: &model.BuildInfo{BuildInfoRaw: model.BuildInfoRaw{Context: "/app", Dockerfile: "/app/Dockerfile", Platform: "linux/arm64"}}},
+		},
+	}
+
+	builds := getServiceBuilds(s, []string{"api", "arm", "web", "worker"}, "registry.okteto.dev")
+	if len(builds) != 3 {
+		t.Fatalf("expected 3 builds, got %d", len(builds))
+	}
+
+	expected := []serviceBuild{
+		{services: []string{"api", "worker"}, tags: []string{"registry.okteto.dev/cindy/api:okteto", "registry.okteto.dev/cindy/worker:okteto"}},
+		{services: []string{"arm"}, tags: []string{"registry.okteto.dev/cindy/arm:okteto"}},
+		{services: []string{"web"}, tags: []string{"registry.okteto.dev/cindy/web:okteto"}},
+	}
+	for i := range expected {
+		if !reflect.DeepEqual(*builds[i], expected[i]) {
+			t.Errorf("expected %+v, got %+v", expected[i], *builds[i])
+		}
+	}
+}
+
+func Test_prefixWriter(t *testing.T) {
+	var b bytes.Buffer
+	w := (&syncWriter{w: &b}).withPrefix("[api] ")
+	if _, err := w.Write([]byte("#1 load\n#2 ")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("build\n#3 push")); err != nil {
+		t.Fatal(err)
+	}
+	w.Flush()
+
+	expected := "[api] #1 load\n[api] #2 build\n[api] #3 push\n"
+	if b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+}
+
+func Test_getServiceBuildsWithServiceCacheScope(t *testing.T) {
+	s := &model.Stack{
+		Name:      "name",
+		Namespace: "cindy",
+		Services: map[string]model.Service{
+			"api":    {Build: &model.BuildInfo{BuildInfoRaw: model.BuildInfoRaw{Context: "/app", CacheScope: model.CacheScopeService}}},
+			"worker": {Build: &model.BuildInfo{BuildInfoRaw: model.BuildInfoRaw{Context: "/app", CacheScope: model.CacheScopeService}}},
+		},
+	}
+
+	builds := getServiceBuilds(s, []string{"api", "worker"}, "registry.okteto.dev")
+	if len(builds) != 2 {
+		t.Fatalf("expected 2 builds, got %d", len(builds))
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -36,6 +37,10 @@ var VersionString string
 var timeout time.Duration
 var tOnce sync.Once
 
+var kubeconfigFile string
+
+var insecureRegistries []string
+
 //GetBinaryName returns the name of the binary
 func GetBinaryName() string {
 	return filepath.Base(GetBinaryFullPath())
@@ -132,8 +137,22 @@ func homedirWindows() (string, error) {
 	return home, nil
 }
 
+// SetKubeConfigFile overrides the kubeconfig file used by the cli, ignoring the KUBECONFIG env var
+func SetKubeConfigFile(path string) {
+	kubeconfigFile = path
+}
+
+// IsKubeConfigFileOverridden returns true if the kubeconfig file was set with SetKubeConfigFile
+func IsKubeConfigFileOverridden() bool {
+	return kubeconfigFile != ""
+}
+
 // GetKubeConfigFile returns the path to the kubeconfig file, taking the KUBECONFIG env var into consideration
 func GetKubeConfigFile() string {
+	if kubeconfigFile != "" {
+		return kubeconfigFile
+	}
+
 	home := GetUserHomeDir()
 	kubeconfig := filepath.Join(home, ".kube", "config")
 	kubeconfigEnv := os.Getenv("KUBECONFIG")
@@ -150,6 +169,32 @@ func splitKubeConfigEnv(value string) string {
 	return strings.Split(value, ":")[0]
 }
 
+// SetInsecureRegistries sets the registries accessed without verifying their TLS certificate
+func SetInsecureRegistries(registries []string) {
+	insecureRegistries = registries
+}
+
+// GetInsecureRegistries returns the hosts of the registries accessed without verifying their TLS certificate,
+// set with SetInsecureRegistries or with the OKTETO_INSECURE_REGISTRIES env var as a comma separated list
+func GetInsecureRegistries() []string {
+	registries := append([]string{}, insecureRegistries...)
+	if v := os.Getenv("OKTETO_INSECURE_REGISTRIES"); v != "" {
+		registries = append(registries, strings.Split(v, ",")...)
+	}
+
+	result := []string{}
+	for _, r := range registries {
+		r = strings.TrimSpace(r)
+		r = strings.TrimPrefix(r, "https://")
+		r = strings.TrimPrefix(r, "http://")
+		r = strings.TrimSuffix(r, "/")
+		if r != "" {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
 // GetTimeout returns the per-action timeout
 func GetTimeout() time.Duration {
 	tOnce.Do(func() {
@@ -171,3 +216,55 @@ func GetTimeout() time.Duration {
 
 	return timeout
 }
+
+// GetKubernetesQPS returns the queries per second allowed to the API server, configured with OKTETO_K8S_QPS
+func GetKubernetesQPS(defaultQPS float32) float32 {
+	v, ok := os.LookupEnv("OKTETO_K8S_QPS")
+	if !ok {
+		return defaultQPS
+	}
+
+	parsed, err := strconv.ParseFloat(v, 32)
+	if err != nil || parsed <= 0 {
+		log.Infof("'%s' is not a valid QPS, ignoring", v)
+		return defaultQPS
+	}
+
+	log.Infof("OKTETO_K8S_QPS applied: '%s'", v)
+	return float32(parsed)
+}
+
+// GetKubernetesBurst returns the burst of queries allowed to the API server, configured with OKTETO_K8S_BURST
+func GetKubernetesBurst(defaultBurst int) int {
+	v, ok := os.LookupEnv("OKTETO_K8S_BURST")
+	if !ok {
+		return defaultBurst
+	}
+
+	parsed, err := strconv.Atoi(v)
+	if err != nil || parsed <= 0 {
+		log.Infof("'%s' is not a valid burst, ignoring", v)
+		return defaultBurst
+	}
+
+	log.Infof("OKTETO_K8S_BURST applied: '%d'", parsed)
+	return parsed
+}
+
+// GetBuildCacheTTL returns how long the build cache mounts of the user are reused before being reset,
+// configured with OKTETO_BUILD_CACHE_TTL. Zero means the cache is never reset automatically
+func GetBuildCacheTTL() time.Duration {
+	v, ok := os.LookupEnv("OKTETO_BUILD_CACHE_TTL")
+	if !ok {
+		return 0
+	}
+
+	parsed, err := time.ParseDuration(v)
+	if err != nil || parsed < 0 {
+		log.Infof("'%s' is not a valid cache TTL, ignoring", v)
+		return 0
+	}
+
+	log.Infof("OKTETO_BUILD_CACHE_TTL applied: '%s'", parsed.String())
+	return parsed
+}
@@ -146,3 +146,45 @@ func TestGetDeploymentHome(t *testing.T) {
 		t.Errorf("expected %s, got %s", expected, got)
 	}
 }
+
+func TestGetKubernetesQPS(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected float32
+	}{
+		{name: "unset", expected: 5},
+		{name: "valid", value: "50", expected: 50},
+		{name: "invalid", value: "fast", expected: 5},
+		{name: "negative", value: "-1", expected: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.value == "" {
+				os.Unsetenv("OKTETO_K8S_QPS")
+			} else {
+				os.Setenv("OKTETO_K8S_QPS", tt.value)
+			}
+			defer os.Unsetenv("OKTETO_K8S_QPS")
+
+			if got := GetKubernetesQPS(5); got != tt.expected {
+				t.Errorf("expected %f, got %f", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetKubernetesBurst(t *testing.T) {
+	os.Setenv("OKTETO_K8S_BURST", "100")
+	defer os.Unsetenv("OKTETO_K8S_BURST")
+
+	if got := GetKubernetesBurst(10); got != 100 {
+		t.Errorf("expected 100, got %d", got)
+	}
+
+	os.Setenv("OKTETO_K8S_BURST", "1.5")
+	if got := GetKubernetesBurst(10); got != 10 {
+		t.Errorf("expected the default burst, got %d", got)
+	}
+}
@@ -30,6 +30,45 @@ func (u UserError) Error() string {
 	return u.E.Error()
 }
 
+// SSHConnectError is returned when the SSH connection to the development container can't be established
+type SSHConnectError struct {
+	// Reachable is true if the SSH server sent its banner but the SSH handshake failed
+	Reachable bool
+	Attempts  int
+	Err       error
+}
+
+// Error returns the error message
+func (e *SSHConnectError) Error() string {
+	if e.Reachable {
+		return fmt.Sprintf("ssh handshake rejected after %d attempts: %s", e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("ssh server not reachable after %d attempts: %s", e.Attempts, e.Err)
+}
+
+// Unwrap returns the error of the last attempt
+func (e *SSHConnectError) Unwrap() error {
+	return e.Err
+}
+
+// DevPodFailedError is returned when the development container can't start or keeps failing
+type DevPodFailedError struct {
+	Err error
+
+	// Events are the latest events of the pod of the development container
+	Events []string
+}
+
+// Error returns the error message
+func (e *DevPodFailedError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the cause of the failure
+func (e *DevPodFailedError) Unwrap() error {
+	return e.Err
+}
+
 var (
 	// ErrNotDevDeployment is raised when we detect that the deployment was returned to production mode
 	ErrNotDevDeployment = errors.New("Deployment is no longer in developer mode")
@@ -49,8 +88,11 @@ var (
 	// ErrQuota is returned when there aren't enough resources to enable dev mode
 	ErrQuota = fmt.Errorf("Quota exceeded, please free some resources and try again")
 
-	// ErrSSHConnectError is returned when okteto cannot connect to ssh
-	ErrSSHConnectError = fmt.Errorf("ssh start error")
+	// ErrSSHConnectionLost is raised when the ssh connection is not responding to keepalives anymore
+	ErrSSHConnectionLost = fmt.Errorf("lost connection to your development container")
+
+	// ErrHostKeyMismatch is raised when the host key of the development container doesn't match the pinned one
+	ErrHostKeyMismatch = fmt.Errorf("the host key of your development container has changed")
 
 	// ErrNotInDevContainer is returned when an unsupported command is invoked from a dev container (e.g. okteto up)
 	ErrNotInDevContainer = fmt.Errorf("this command is not supported from inside an development container")
@@ -110,6 +152,31 @@ func IsTransient(err error) bool {
 	}
 }
 
+// IsUnauthorized returns true if err is caused by expired or invalid cluster credentials
+func IsUnauthorized(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	switch {
+	case strings.Contains(err.Error(), "Unauthorized"),
+		strings.Contains(err.Error(), "the server has asked for the client to provide credentials"),
+		strings.Contains(err.Error(), "getting credentials: exec"):
+		return true
+	default:
+		return false
+	}
+}
+
+// IsHostKeyMismatch returns true if the error is caused by a host key that doesn't match the pinned one
+func IsHostKeyMismatch(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return strings.Contains(err.Error(), ErrHostKeyMismatch.Error())
+}
+
 // IsClosedNetwork returns true if the error is caused by a closed network connection
 func IsClosedNetwork(err error) bool {
 	if err == nil {
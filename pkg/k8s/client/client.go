@@ -14,6 +14,10 @@
 package client
 
 import (
+	"fmt"
+
+	okConfig "github.com/okteto/okteto/pkg/config"
+	"github.com/okteto/okteto/pkg/okteto"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
@@ -25,13 +29,13 @@ var client *kubernetes.Clientset
 var config *rest.Config
 var namespace string
 
-//GetLocal returns a kubernetes client with the local configuration. It will detect if KUBECONFIG is defined.
+//GetLocal returns a kubernetes client with the local configuration. It will detect if KUBECONFIG is defined or if the kubeconfig file was overridden.
 func GetLocal(context string) (*kubernetes.Clientset, *rest.Config, string, error) {
 	if client == nil {
 		var err error
 
 		clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-			clientcmd.NewDefaultClientConfigLoadingRules(),
+			getLoadingRules(),
 			&clientcmd.ConfigOverrides{
 				CurrentContext: context,
 				ClusterInfo:    clientcmdapi.Cluster{Server: ""},
@@ -47,6 +51,10 @@ func GetLocal(context string) (*kubernetes.Clientset, *rest.Config, string, erro
 		if err != nil {
 			return nil, nil, "", err
 		}
+		config.UserAgent = getUserAgent(okConfig.VersionString, okteto.GetUserID())
+		config.QPS = okConfig.GetKubernetesQPS(rest.DefaultQPS)
+		config.Burst = okConfig.GetKubernetesBurst(rest.DefaultBurst)
+		config.RateLimiter = newThrottledRateLimiter(config.QPS, config.Burst)
 
 		client, err = kubernetes.NewForConfig(config)
 		if err != nil {
@@ -56,6 +64,27 @@ func GetLocal(context string) (*kubernetes.Clientset, *rest.Config, string, erro
 	return client, config, namespace, nil
 }
 
+func getLoadingRules() *clientcmd.ClientConfigLoadingRules {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if okConfig.IsKubeConfigFileOverridden() {
+		loadingRules.ExplicitPath = okConfig.GetKubeConfigFile()
+	}
+	return loadingRules
+}
+
+// getUserAgent identifies the okteto version and user in the audit logs of the cluster
+func getUserAgent(version, userID string) string {
+	if version == "" {
+		version = "dev"
+	}
+
+	userAgent := fmt.Sprintf("okteto-cli/%s", version)
+	if userID != "" {
+		userAgent = fmt.Sprintf("%s user:%s", userAgent, userID)
+	}
+	return userAgent
+}
+
 //Reset cleans the cached client
 func Reset() {
 	client = nil
@@ -16,6 +16,8 @@ package client
 import (
 	"os"
 	"testing"
+
+	okConfig "github.com/okteto/okteto/pkg/config"
 )
 
 func TestInCluster(t *testing.T) {
@@ -25,3 +27,37 @@ func TestInCluster(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func Test_getLoadingRules(t *testing.T) {
+	defer okConfig.SetKubeConfigFile("")
+
+	if rules := getLoadingRules(); rules.ExplicitPath != "" {
+		t.Fatalf("expected no explicit path, got %s", rules.ExplicitPath)
+	}
+
+	okConfig.SetKubeConfigFile("/tmp/kubeconfig")
+	if rules := getLoadingRules(); rules.ExplicitPath != "/tmp/kubeconfig" {
+		t.Fatalf("expected the overridden kubeconfig, got %s", rules.ExplicitPath)
+	}
+}
+
+func Test_getUserAgent(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  string
+		userID   string
+		expected string
+	}{
+		{name: "user", version: "1.10.0", userID: "cindy", expected: "okteto-cli/1.10.0 user:cindy"},
+		{name: "no-user", version: "1.10.0", expected: "okteto-cli/1.10.0"},
+		{name: "no-version", userID: "cindy", expected: "okteto-cli/dev user:cindy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getUserAgent(tt.version, tt.userID); got != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/okteto/okteto/pkg/log"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+const (
+	// throttleLogLatency is the wait after which a throttled request is logged
+	throttleLogLatency = 50 * time.Millisecond
+
+	// throttleWarningLatency is the wait after which the user is told that okteto is waiting on the rate limit
+	throttleWarningLatency = time.Second
+
+	// throttleWarningInterval is the minimum time between two warnings
+	throttleWarningInterval = time.Minute
+)
+
+// throttledRateLimiter reports the requests delayed by the client-side rate limit
+type throttledRateLimiter struct {
+	flowcontrol.RateLimiter
+
+	mu          sync.Mutex
+	lastWarning time.Time
+	burst       int
+}
+
+func newThrottledRateLimiter(qps float32, burst int) *throttledRateLimiter {
+	return &throttledRateLimiter{
+		RateLimiter: flowcontrol.NewTokenBucketRateLimiter(qps, burst),
+		burst:       burst,
+	}
+}
+
+// Accept returns once a token becomes available
+func (r *throttledRateLimiter) Accept() {
+	start := time.Now()
+	r.RateLimiter.Accept()
+	r.report(time.Since(start))
+}
+
+// Wait returns nil if a token is taken before the context is done
+func (r *throttledRateLimiter) Wait(ctx context.Context) error {
+	start := time.Now()
+	err := r.RateLimiter.Wait(ctx)
+	r.report(time.Since(start))
+	return err
+}
+
+func (r *throttledRateLimiter) report(waited time.Duration) {
+	if waited < throttleLogLatency {
+		return
+	}
+
+	log.Infof("request to the API server throttled for %s by the client rate limit (qps=%.0f burst=%d)", waited, r.QPS(), r.burst)
+	if !r.shouldWarn(waited) {
+		return
+	}
+
+	log.Yellow("Waiting on the API server rate limit. Set OKTETO_K8S_QPS and OKTETO_K8S_BURST to allow more requests per second")
+}
+
+func (r *throttledRateLimiter) shouldWarn(waited time.Duration) bool {
+	if waited < throttleWarningLatency {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if time.Since(r.lastWarning) < throttleWarningInterval {
+		return false
+	}
+
+	r.lastWarning = time.Now()
+	return true
+}
@@ -0,0 +1,40 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_throttledRateLimiterShouldWarn(t *testing.T) {
+	r := newThrottledRateLimiter(5, 10)
+
+	if r.shouldWarn(100 * time.Millisecond) {
+		t.Error("warned for a short wait")
+	}
+
+	if !r.shouldWarn(2 * time.Second) {
+		t.Error("didn't warn for a long wait")
+	}
+
+	if r.shouldWarn(2 * time.Second) {
+		t.Error("warned twice in the same interval")
+	}
+
+	r.lastWarning = time.Now().Add(-2 * throttleWarningInterval)
+	if !r.shouldWarn(2 * time.Second) {
+		t.Error("didn't warn after the interval")
+	}
+}
@@ -22,13 +22,18 @@ import (
 
 	"github.com/okteto/okteto/pkg/config"
 	"github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/k8s/informers"
 	"github.com/okteto/okteto/pkg/k8s/labels"
 	okLabels "github.com/okteto/okteto/pkg/k8s/labels"
 	"github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/model"
 	appsv1 "k8s.io/api/apps/v1"
 	apiv1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sLabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -51,32 +56,50 @@ func Get(ctx context.Context, dev *model.Dev, namespace string, c kubernetes.Int
 	var err error
 
 	if len(dev.Labels) == 0 {
+		if cached, ok := informers.GetDeployment(namespace, dev.Name); ok {
+			return cached, nil
+		}
+
 		d, err = c.AppsV1().Deployments(namespace).Get(ctx, dev.Name, metav1.GetOptions{})
 		if err != nil {
 			return nil, fmt.Errorf("failed to get deployment %s/%s: %w", namespace, dev.Name, err)
 		}
 	} else {
-		deploys, err := c.AppsV1().Deployments(namespace).List(
-			ctx,
-			metav1.ListOptions{
-				LabelSelector: dev.LabelsSelector(),
-			},
-		)
+		items, err := listBySelector(ctx, namespace, dev.LabelsSelector(), c)
 		if err != nil {
 			return nil, err
 		}
-		if len(deploys.Items) == 0 {
+		if len(items) == 0 {
 			return nil, fmt.Errorf("deployment for labels '%s' not found", dev.LabelsSelector())
 		}
-		if len(deploys.Items) > 1 {
-			return nil, fmt.Errorf("Found '%d' deployments for labels '%s' instead of 1", len(deploys.Items), dev.LabelsSelector())
+		if len(items) > 1 {
+			return nil, fmt.Errorf("Found '%d' deployments for labels '%s' instead of 1", len(items), dev.LabelsSelector())
 		}
-		d = &deploys.Items[0]
+		d = &items[0]
 	}
 
 	return d, nil
 }
 
+func listBySelector(ctx context.Context, namespace, selector string, c kubernetes.Interface) ([]appsv1.Deployment, error) {
+	if s, err := k8sLabels.Parse(selector); err == nil {
+		if cached, ok := informers.ListDeployments(namespace, s); ok {
+			return cached, nil
+		}
+	}
+
+	deploys, err := c.AppsV1().Deployments(namespace).List(
+		ctx,
+		metav1.ListOptions{
+			LabelSelector: selector,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return deploys.Items, nil
+}
+
 //GetRevisionAnnotatedDeploymentOrFailed returns a deployment object if it is healthy and annotated with its revision or an error
 func GetRevisionAnnotatedDeploymentOrFailed(ctx context.Context, dev *model.Dev, c *kubernetes.Clientset, waitUntilDeployed bool) (*appsv1.Deployment, error) {
 	d, err := Get(ctx, dev, dev.Namespace, c)
@@ -110,14 +133,21 @@ func GetTranslations(ctx context.Context, dev *model.Dev, d *appsv1.Deployment,
 	if d != nil {
 		rule := dev.ToTranslationRule(dev)
 		result[d.Name] = &model.Translation{
-			Interactive: true,
-			Name:        dev.Name,
-			Version:     model.TranslationVersion,
-			Deployment:  d,
-			Annotations: dev.Annotations,
-			Tolerations: dev.Tolerations,
-			Replicas:    *d.Spec.Replicas,
-			Rules:       []*model.TranslationRule{rule},
+			Interactive:       true,
+			Name:              dev.Name,
+			Version:           model.TranslationVersion,
+			Deployment:        d,
+			Annotations:       dev.Annotations,
+			Tolerations:       dev.Tolerations,
+			NodeSelector:      dev.NodeSelector,
+			Affinity:          (*apiv1.Affinity)(dev.Affinity),
+			ExcludeContainers: dev.ExcludeContainers,
+			PriorityClassName: dev.PriorityClassName,
+			ServiceAccount:    dev.ServiceAccount,
+			PodLabels:         dev.PodLabels,
+			SecurityPolicy:    dev.SecurityPolicy,
+			Replicas:          *d.Spec.Replicas,
+			Rules:             []*model.TranslationRule{rule},
 		}
 	}
 
@@ -130,27 +160,42 @@ func GetTranslations(ctx context.Context, dev *model.Dev, d *appsv1.Deployment,
 
 func loadServiceTranslations(ctx context.Context, dev *model.Dev, result map[string]*model.Translation, c kubernetes.Interface) error {
 	for _, s := range dev.Services {
-		d, err := Get(ctx, s, dev.Namespace, c)
+		namespace := s.GetServiceNamespace(dev)
+		if err := s.ValidateServiceNamespace(dev); err != nil {
+			return errors.UserError{E: err, Hint: "Check the 'namespace' of the services in your okteto manifest"}
+		}
+		d, err := Get(ctx, s, namespace, c)
 		if err != nil {
 			return err
 		}
 
 		rule := s.ToTranslationRule(dev)
 
-		if _, ok := result[d.Name]; ok {
-			result[d.Name].Rules = append(result[d.Name].Rules, rule)
+		key := d.Name
+		if namespace != dev.Namespace {
+			key = fmt.Sprintf("%s/%s", namespace, d.Name)
+		}
+
+		if _, ok := result[key]; ok {
+			result[key].Rules = append(result[key].Rules, rule)
 			continue
 		}
 
-		result[d.Name] = &model.Translation{
-			Name:        dev.Name,
-			Interactive: false,
-			Version:     model.TranslationVersion,
-			Deployment:  d,
-			Annotations: dev.Annotations,
-			Tolerations: dev.Tolerations,
-			Replicas:    *d.Spec.Replicas,
-			Rules:       []*model.TranslationRule{rule},
+		result[key] = &model.Translation{
+			Name:              dev.Name,
+			Interactive:       false,
+			Version:           model.TranslationVersion,
+			Deployment:        d,
+			Annotations:       dev.Annotations,
+			NodeSelector:      getServiceNodeSelector(s, dev),
+			Affinity:          getServiceAffinity(s, dev),
+			ExcludeContainers: dev.ExcludeContainers,
+			PriorityClassName: dev.PriorityClassName,
+			ServiceAccount:    dev.ServiceAccount,
+			PodLabels:         dev.PodLabels,
+			SecurityPolicy:    dev.SecurityPolicy,
+			Replicas:          *d.Spec.Replicas,
+			Rules:             []*model.TranslationRule{rule},
 		}
 
 	}
@@ -158,18 +203,25 @@ func loadServiceTranslations(ctx context.Context, dev *model.Dev, result map[str
 	return nil
 }
 
-//Deploy creates or updates a deployment
-func Deploy(ctx context.Context, d *appsv1.Deployment, forceCreate bool, client *kubernetes.Clientset) error {
-	if forceCreate {
-		if err := create(ctx, d, client); err != nil {
-			return err
-		}
-	} else {
-		if err := update(ctx, d, client); err != nil {
-			return err
-		}
+// getServiceNodeSelector returns the node selector of a service, or the one of the development container if the service doesn't define it
+func getServiceNodeSelector(s, dev *model.Dev) map[string]string {
+	if len(s.NodeSelector) > 0 {
+		return s.NodeSelector
 	}
-	return nil
+	return dev.NodeSelector
+}
+
+// getServiceAffinity returns the affinity of a service, or the one of the development container if the service doesn't define it
+func getServiceAffinity(s, dev *model.Dev) *apiv1.Affinity {
+	if s.Affinity != nil {
+		return (*apiv1.Affinity)(s.Affinity)
+	}
+	return (*apiv1.Affinity)(dev.Affinity)
+}
+
+//Deploy creates or updates a deployment
+func Deploy(ctx context.Context, d *appsv1.Deployment, client *kubernetes.Clientset) error {
+	return apply(ctx, d, client)
 }
 
 //UpdateOktetoRevision updates the okteto version annotation
@@ -186,7 +238,7 @@ func UpdateOktetoRevision(ctx context.Context, d *appsv1.Deployment, client *kub
 		revision := updated.Annotations[revisionAnnotation]
 		if revision != "" {
 			d.Annotations[okLabels.RevisionAnnotation] = revision
-			return update(ctx, d, client)
+			return apply(ctx, d, client)
 		}
 
 		if time.Now().After(timeout) {
@@ -247,7 +299,7 @@ func UpdateDeployments(ctx context.Context, trList map[string]*model.Translation
 		if tr.Deployment == nil {
 			continue
 		}
-		if err := update(ctx, tr.Deployment, c); err != nil {
+		if err := apply(ctx, tr.Deployment, c); err != nil {
 			return err
 		}
 	}
@@ -258,15 +310,14 @@ func UpdateDeployments(ctx context.Context, trList map[string]*model.Translation
 func TranslateDevModeOff(d *appsv1.Deployment) (*appsv1.Deployment, error) {
 	trRulesJSON := getAnnotation(d.Spec.Template.GetObjectMeta(), okLabels.TranslationAnnotation)
 	if trRulesJSON == "" {
-		dManifest := getAnnotation(d.GetObjectMeta(), oktetoDeploymentAnnotation)
-		if dManifest == "" {
+		dOrig, err := getOriginalDeployment(d)
+		if err != nil {
+			return nil, err
+		}
+		if dOrig == nil {
 			log.Infof("%s/%s is not a development container", d.Namespace, d.Name)
 			return d, nil
 		}
-		dOrig := &appsv1.Deployment{}
-		if err := json.Unmarshal([]byte(dManifest), dOrig); err != nil {
-			return nil, fmt.Errorf("malformed manifest: %s", err)
-		}
 		return dOrig, nil
 	}
 	trRules := &model.Translation{}
@@ -293,32 +344,293 @@ func TranslateDevModeOff(d *appsv1.Deployment) (*appsv1.Deployment, error) {
 	delete(labels, okLabels.InteractiveDevLabel)
 	delete(labels, okLabels.DetachedDevLabel)
 	d.Spec.Template.GetObjectMeta().SetLabels(labels)
+	for key, value := range trRules.StrippedLabels {
+		setLabel(d.Spec.Template.GetObjectMeta(), key, value)
+	}
+	if trRules.OriginalPriority != nil {
+		d.Spec.Template.Spec.PriorityClassName = trRules.OriginalPriority.PriorityClassName
+		d.Spec.Template.Spec.Priority = trRules.OriginalPriority.Priority
+	}
 	return d, nil
 }
 
-func create(ctx context.Context, d *appsv1.Deployment, c *kubernetes.Clientset) error {
-	_, err := c.AppsV1().Deployments(d.Namespace).Create(ctx, d, metav1.CreateOptions{})
+// apply creates d, or applies with server-side apply the fields of d changed by okteto.
+// The fields not changed by okteto are left to their managers, like the replicas of an autoscaler or the pod template of helm.
+// Okteto forces the ownership of the fields it changes, and removes with a strategic merge patch the fields it deletes
+func apply(ctx context.Context, d *appsv1.Deployment, c *kubernetes.Clientset) error {
+	d.ResourceVersion = ""
+	d.ManagedFields = nil
+	d.Status = appsv1.DeploymentStatus{}
+	d.TypeMeta = metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}
+
+	live, err := c.AppsV1().Deployments(d.Namespace).Get(ctx, d.Name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get deployment %s/%s: %w", d.Namespace, d.Name, err)
+	}
 	if err != nil {
+		live = nil
+	}
+
+	data, deletions, err := getApplyConfiguration(d, live)
+	if err != nil {
+		return fmt.Errorf("failed to serialize deployment %s/%s: %w", d.Namespace, d.Name, err)
+	}
+
+	force := true
+	applied, err := c.AppsV1().Deployments(d.Namespace).Patch(ctx, d.Name, types.ApplyPatchType, data, metav1.PatchOptions{FieldManager: okLabels.FieldManager, Force: &force})
+	if err != nil {
+		if apierrors.IsUnsupportedMediaType(err) {
+			log.Infof("server-side apply is not supported, updating deployment %s/%s: %s", d.Namespace, d.Name, err)
+			return update(ctx, d, live, c)
+		}
+		if apierrors.IsConflict(err) {
+			return errors.UserError{
+				E:    fmt.Errorf("deployment '%s' was modified while okteto was updating it", d.Name),
+				Hint: "Wait until the other changes to your deployment are done and try again",
+			}
+		}
 		return err
 	}
+
+	if deletions != nil {
+		applied, err = c.AppsV1().Deployments(d.Namespace).Patch(ctx, d.Name, types.StrategicMergePatchType, deletions, metav1.PatchOptions{FieldManager: okLabels.FieldManager})
+		if err != nil {
+			return fmt.Errorf("failed to remove the fields deleted by okteto from deployment %s/%s: %w", d.Namespace, d.Name, err)
+		}
+	}
+
+	informers.Invalidate(informers.Deployments, d.Namespace, d.Name, applied.ResourceVersion)
 	return nil
 }
 
-func update(ctx context.Context, d *appsv1.Deployment, c *kubernetes.Clientset) error {
-	d.ResourceVersion = ""
-	d.Status = appsv1.DeploymentStatus{}
-	_, err := c.AppsV1().Deployments(d.Namespace).Update(ctx, d, metav1.UpdateOptions{})
+// update replaces the deployment, or creates it if it doesn't exist, in clusters without server-side apply.
+// The update is rejected if the deployment changed since it was read.
+// The request body is serialized like in apply, so fields unknown to the client like the seccomp profile are kept
+func update(ctx context.Context, d, live *appsv1.Deployment, c *kubernetes.Clientset) error {
+	if live != nil {
+		d.ResourceVersion = live.ResourceVersion
+	}
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("failed to serialize deployment %s/%s: %w", d.Namespace, d.Name, err)
+	}
+	data, err = setSeccompProfile(d, data)
+	if err != nil {
+		return fmt.Errorf("failed to serialize deployment %s/%s: %w", d.Namespace, d.Name, err)
+	}
+
+	result := &appsv1.Deployment{}
+	if live != nil {
+		err = c.AppsV1().RESTClient().Put().
+			Namespace(d.Namespace).
+			Resource("deployments").
+			Name(d.Name).
+			Param("fieldManager", okLabels.FieldManager).
+			Body(data).
+			Do(ctx).
+			Into(result)
+	} else {
+		err = c.AppsV1().RESTClient().Post().
+			Namespace(d.Namespace).
+			Resource("deployments").
+			Param("fieldManager", okLabels.FieldManager).
+			Body(data).
+			Do(ctx).
+			Into(result)
+	}
 	if err != nil {
 		return err
 	}
+
+	informers.Invalidate(informers.Deployments, d.Namespace, d.Name, result.ResourceVersion)
 	return nil
 }
 
+// getApplyConfiguration returns the apply configuration of the fields of d changed by okteto, and a strategic merge patch
+// with the fields deleted by okteto, or nil if it doesn't delete any field.
+// Deployments in dev mode are compared with the deployment before its translation, so okteto keeps the ownership of all the fields
+// of its translation while the development container is active. Otherwise d is compared with the live deployment
+func getApplyConfiguration(d, live *appsv1.Deployment) ([]byte, []byte, error) {
+	if live == nil {
+		data, err := json.Marshal(d)
+		if err != nil {
+			return nil, nil, err
+		}
+		data, err = setSeccompProfile(d, data)
+		return data, nil, err
+	}
+
+	base := live.DeepCopy()
+	if IsDevModeOn(d) {
+		dOrig, err := TranslateDevModeOff(d.DeepCopy())
+		if err != nil {
+			log.Infof("failed to get the original deployment of %s/%s: %s", d.Namespace, d.Name, err)
+		} else {
+			base = dOrig
+		}
+	}
+	stripServerFields(base)
+	base.TypeMeta = d.TypeMeta
+
+	desired := d.DeepCopy()
+	stripServerFields(desired)
+	current, err := json.Marshal(desired)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	original, err := json.Marshal(base)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	patchMeta, err := strategicpatch.NewPatchMetaFromStruct(appsv1.Deployment{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	patchData, err := strategicpatch.CreateTwoWayMergePatchUsingLookupPatchMeta(original, current, patchMeta)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	patch := map[string]interface{}{}
+	if err := json.Unmarshal(patchData, &patch); err != nil {
+		return nil, nil, err
+	}
+
+	config, deletions, err := splitPatch(patch, patchMeta)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	metadata := getMap(config, "metadata")
+	metadata["name"] = d.Name
+	metadata["namespace"] = d.Namespace
+	config["apiVersion"] = d.APIVersion
+	config["kind"] = d.Kind
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err = setSeccompProfile(d, data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(deletions) == 0 {
+		return data, nil, nil
+	}
+	deletionsData, err := json.Marshal(deletions)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, deletionsData, nil
+}
+
+// splitPatch splits a strategic merge patch into the apply configuration of the fields it sets, and the patch of the fields it deletes.
+// The elements of merging lists keep their merge key in both
+func splitPatch(patch map[string]interface{}, meta strategicpatch.LookupPatchMeta) (map[string]interface{}, map[string]interface{}, error) {
+	config := map[string]interface{}{}
+	deletions := map[string]interface{}{}
+	for key, value := range patch {
+		if strings.HasPrefix(key, "$") {
+			// the order and the retained keys of the lists are not needed to apply or delete fields
+			if strings.HasPrefix(key, "$deleteFromPrimitiveList/") || key == "$patch" {
+				deletions[key] = value
+			}
+			continue
+		}
+
+		switch v := value.(type) {
+		case nil:
+			deletions[key] = nil
+		case map[string]interface{}:
+			subMeta, _, err := meta.LookupPatchMetadataForStruct(key)
+			if err != nil {
+				return nil, nil, err
+			}
+			c, d, err := splitPatch(v, subMeta)
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(c) > 0 {
+				config[key] = c
+			}
+			if len(d) > 0 {
+				deletions[key] = d
+			}
+		case []interface{}:
+			c, d, err := splitPatchList(key, v, meta)
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(c) > 0 {
+				config[key] = c
+			}
+			if len(d) > 0 {
+				deletions[key] = d
+			}
+		default:
+			config[key] = value
+		}
+	}
+	return config, deletions, nil
+}
+
+func splitPatchList(key string, list []interface{}, meta strategicpatch.LookupPatchMeta) ([]interface{}, []interface{}, error) {
+	if len(list) == 0 {
+		return list, nil, nil
+	}
+	if _, ok := list[0].(map[string]interface{}); !ok {
+		return list, nil, nil
+	}
+
+	subMeta, patchMeta, err := meta.LookupPatchMetadataForSlice(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	mergeKey := patchMeta.GetPatchMergeKey()
+	if mergeKey == "" {
+		// lists without merge key are replaced as a whole
+		return list, nil, nil
+	}
+
+	config := []interface{}{}
+	deletions := []interface{}{}
+	for _, item := range list {
+		element, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, nil, fmt.Errorf("unexpected element in '%s': %v", key, item)
+		}
+		if element["$patch"] == "delete" {
+			deletions = append(deletions, element)
+			continue
+		}
+		c, d, err := splitPatch(element, subMeta)
+		if err != nil {
+			return nil, nil, err
+		}
+		c[mergeKey] = element[mergeKey]
+		config = append(config, c)
+		if len(d) > 0 {
+			d[mergeKey] = element[mergeKey]
+			deletions = append(deletions, d)
+		}
+	}
+	return config, deletions, nil
+}
+
 func deleteUserAnnotations(annotations map[string]string, tr *model.Translation) error {
 	if tr.Annotations == nil {
 		return nil
 	}
 	for key := range tr.Annotations {
+		if isHelmKey(key) {
+			continue
+		}
 		delete(annotations, key)
 	}
 	return nil
@@ -335,6 +647,7 @@ func Destroy(ctx context.Context, dev *model.Dev, c *kubernetes.Clientset) error
 		}
 		return fmt.Errorf("error deleting kubernetes deployment: %s", err)
 	}
+	informers.Invalidate(informers.Deployments, dev.Namespace, dev.Name, "")
 	log.Infof("deployment '%s' deleted", dev.Name)
 	return nil
 }
@@ -0,0 +1,362 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func Test_loadServiceTranslationsInOtherNamespaces(t *testing.T) {
+	var replicas int32 = 1
+	newDeployment := func(name, namespace string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		}
+	}
+
+	c := fake.NewSimpleClientset(
+		newDeployment("api", "dev"),
+		newDeployment("worker", "dev"),
+		newDeployment("worker", "shared"),
+	)
+
+	dev, err := model.Read([]byte(`name: api
+namespace: dev
+sync:
+  - .:/app
+services:
+  - name: worker
+    sync:
+      - .:/app
+  - name: worker
+    namespace: shared`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := map[string]*model.Translation{}
+	if err := loadServiceTranslations(context.Background(), dev, result, c); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("expected 2 translations, got %d", len(result))
+	}
+
+	if tr, ok := result["worker"]; !ok || tr.Deployment.Namespace != "dev" {
+		t.Errorf("translation for the service in the dev namespace not found: %+v", result)
+	}
+
+	tr, ok := result["shared/worker"]
+	if !ok || tr.Deployment.Namespace != "shared" {
+		t.Fatalf("translation for the service in the shared namespace not found: %+v", result)
+	}
+
+	if mountsDevVolume(tr.Rules) {
+		t.Errorf("service in the shared namespace mounts the dev volume")
+	}
+}
+
+func Test_loadServiceTranslationsScheduling(t *testing.T) {
+	var replicas int32 = 1
+	newDeployment := func(name string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "dev"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		}
+	}
+
+	c := fake.NewSimpleClientset(newDeployment("worker"), newDeployment("db"))
+
+	dev, err := model.Read([]byte(`name: api
+namespace: dev
+sync:
+  - .:/app
+nodeSelector:
+  pool: dev
+services:
+  - name: worker
+    sync:
+      - .:/app
+  - name: db
+    sync:
+      - .:/data
+    nodeSelector:
+      pool: storage
+    affinity:
+      nodeAffinity:
+        requiredDuringSchedulingIgnoredDuringExecution:
+          nodeSelectorTerms:
+            - matchExpressions:
+                - key: disk
+                  operator: In
+                  values:
+                    - ssd`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := map[string]*model.Translation{}
+	if err := loadServiceTranslations(context.Background(), dev, result, c); err != nil {
+		t.Fatal(err)
+	}
+
+	if worker := result["worker"]; worker.NodeSelector["pool"] != "dev" || worker.Affinity != nil {
+		t.Errorf("service without scheduling fields didn't inherit the ones of the development container: %v %v", worker.NodeSelector, worker.Affinity)
+	}
+
+	db := result["db"]
+	if db.NodeSelector["pool"] != "storage" {
+		t.Errorf("node selector of the service wasn't used: %v", db.NodeSelector)
+	}
+	if db.Affinity == nil || db.Affinity.NodeAffinity == nil {
+		t.Errorf("affinity of the service wasn't used: %v", db.Affinity)
+	}
+}
+
+func Test_getApplyConfiguration(t *testing.T) {
+	var one, three int32 = 1, 3
+	live := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "api",
+			Namespace:       "dev",
+			UID:             "1234",
+			ResourceVersion: "10",
+			Labels:          map[string]string{"app": "api"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas:             &three,
+			RevisionHistoryLimit: &three,
+			Selector:             &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+			Template: apiv1.PodTemplateSpec{
+				Spec: apiv1.PodSpec{
+					Containers: []apiv1.Container{
+						{Name: "api", Image: "api:1.0", LivenessProbe: &apiv1.Probe{PeriodSeconds: 10}},
+						{Name: "proxy", Image: "proxy:1.0"},
+					},
+				},
+			},
+		},
+		Status: appsv1.DeploymentStatus{Replicas: 3},
+	}
+
+	d := live.DeepCopy()
+	d.Status = appsv1.DeploymentStatus{}
+	d.Spec.Replicas = &one
+	d.Spec.Template.Spec.Containers[0].Image = "api:2.0"
+	d.Spec.Template.Spec.Containers[0].LivenessProbe = nil
+
+	data, deletions, err := getApplyConfiguration(d, live)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &appsv1.Deployment{}
+	if err := json.Unmarshal(data, config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Kind != "Deployment" || config.Name != "api" || config.Namespace != "dev" || config.UID != "" {
+		t.Errorf("wrong metadata: %s", data)
+	}
+	if config.Spec.Replicas == nil || *config.Spec.Replicas != 1 {
+		t.Errorf("replicas changed by okteto weren't applied: %s", data)
+	}
+	if config.Spec.RevisionHistoryLimit != nil || config.Spec.Selector != nil || config.Labels != nil {
+		t.Errorf("fields not changed by okteto were applied: %s", data)
+	}
+	containers := config.Spec.Template.Spec.Containers
+	if len(containers) != 1 || containers[0].Name != "api" || containers[0].Image != "api:2.0" {
+		t.Errorf("wrong containers: %s", data)
+	}
+
+	expected := `{"spec":{"template":{"spec":{"containers":[{"livenessProbe":null,"name":"api"}]}}}}`
+	if string(deletions) != expected {
+		t.Errorf("wrong deletions: expected %s, got %s", expected, deletions)
+	}
+
+	d.Spec.Template.Spec.Containers[0].LivenessProbe = live.Spec.Template.Spec.Containers[0].LivenessProbe
+	if _, deletions, err := getApplyConfiguration(d, live); err != nil || deletions != nil {
+		t.Errorf("unexpected deletions: %s %v", deletions, err)
+	}
+
+	data, deletions, err = getApplyConfiguration(d, nil)
+	if err != nil || deletions != nil || !strings.Contains(string(data), "revisionHistoryLimit") {
+		t.Errorf("new deployments must be applied as a whole: %s %s %v", data, deletions, err)
+	}
+}
+
+func Test_getApplyConfigurationInDevMode(t *testing.T) {
+	dev, err := model.Read([]byte(`name: api
+container: api
+image: api:dev`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var replicas int32 = 3
+	d := dev.GevSandbox()
+	d.Spec.Replicas = &replicas
+	d.Spec.Template.Spec.Containers = []apiv1.Container{
+		{Name: "api", Image: "api:1.0"},
+		{Name: "proxy", Image: "proxy:1.0"},
+	}
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Deployment:  d,
+		Replicas:    replicas,
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// the live deployment is already translated, but okteto keeps applying all the fields of the translation
+	live := tr.Deployment.DeepCopy()
+	data, _, err := getApplyConfiguration(tr.Deployment, live)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &appsv1.Deployment{}
+	if err := json.Unmarshal(data, config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Annotations[oktetoDeploymentAnnotation] == "" || config.Annotations[oktetoTranslationPatchAnnotation] == "" {
+		t.Errorf("the annotations of the translation weren't applied: %s", data)
+	}
+	containers := config.Spec.Template.Spec.Containers
+	if len(containers) != 1 || containers[0].Name != "api" || containers[0].Image != "api:dev" || len(containers[0].VolumeMounts) == 0 {
+		t.Errorf("the development container wasn't applied: %s", data)
+	}
+	if len(config.Spec.Template.Spec.InitContainers) == 0 || len(config.Spec.Template.Spec.Volumes) == 0 {
+		t.Errorf("the okteto volumes weren't applied: %s", data)
+	}
+}
+
+// newApplyServer returns a fake API server that serves live and answers the apply requests with patchStatus
+func newApplyServer(t *testing.T, live *appsv1.Deployment, patchStatus int) (*kubernetes.Clientset, *[]string, func()) {
+	reasons := map[int]metav1.StatusReason{
+		http.StatusConflict:             metav1.StatusReasonConflict,
+		http.StatusUnsupportedMediaType: metav1.StatusReasonUnsupportedMediaType,
+	}
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, fmt.Sprintf("%s %s %s", r.Method, r.URL.RawQuery, body))
+		switch r.Method {
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(live)
+		case http.MethodPatch:
+			if patchStatus == http.StatusOK {
+				json.NewEncoder(w).Encode(live)
+				return
+			}
+			w.WriteHeader(patchStatus)
+			json.NewEncoder(w).Encode(metav1.Status{
+				TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+				Status:   metav1.StatusFailure,
+				Reason:   reasons[patchStatus],
+				Message:  http.StatusText(patchStatus),
+				Code:     int32(patchStatus),
+			})
+		case http.MethodPut:
+			w.Write(body)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+
+	c, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, &requests, server.Close
+}
+
+func Test_apply(t *testing.T) {
+	var replicas int32 = 1
+	live := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "test", ResourceVersion: "10"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+	}
+	d := live.DeepCopy()
+	d.Spec.Template.Annotations = map[string]string{securityPolicyAnnotation: model.SecurityPolicyRestricted}
+
+	t.Run("force", func(t *testing.T) {
+		c, requests, stop := newApplyServer(t, live, http.StatusOK)
+		defer stop()
+		if err := apply(context.Background(), d.DeepCopy(), c); err != nil {
+			t.Fatal(err)
+		}
+		last := (*requests)[len(*requests)-1]
+		if !strings.HasPrefix(last, http.MethodPatch) || !strings.Contains(last, "force=true") {
+			t.Errorf("the fields of okteto weren't force applied: %s", last)
+		}
+		if strings.Contains(last, "replicas") {
+			t.Errorf("fields not changed by okteto were applied: %s", last)
+		}
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		c, requests, stop := newApplyServer(t, live, http.StatusConflict)
+		defer stop()
+		err := apply(context.Background(), d.DeepCopy(), c)
+		if _, ok := err.(errors.UserError); !ok {
+			t.Fatalf("expected a user error, got %v", err)
+		}
+		for _, r := range *requests {
+			if strings.HasPrefix(r, http.MethodPut) {
+				t.Errorf("the deployment was replaced after a conflict: %s", r)
+			}
+		}
+	})
+
+	t.Run("unsupported-media-type", func(t *testing.T) {
+		c, requests, stop := newApplyServer(t, live, http.StatusUnsupportedMediaType)
+		defer stop()
+		if err := apply(context.Background(), d.DeepCopy(), c); err != nil {
+			t.Fatal(err)
+		}
+		last := (*requests)[len(*requests)-1]
+		if !strings.HasPrefix(last, http.MethodPut) {
+			t.Fatalf("the deployment wasn't updated: %s", last)
+		}
+		if !strings.Contains(last, `"resourceVersion":"10"`) {
+			t.Errorf("the update doesn't check the resource version: %s", last)
+		}
+		if !strings.Contains(last, `"seccompProfile":{"type":"RuntimeDefault"}`) {
+			t.Errorf("the seccomp profile was lost when updating the deployment: %s", last)
+		}
+	})
+}
@@ -0,0 +1,164 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	okLabels "github.com/okteto/okteto/pkg/k8s/labels"
+	"github.com/okteto/okteto/pkg/log"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	argoCDController = "Argo CD"
+	fluxController   = "Flux"
+
+	argoCDInstanceLabel      = "argocd.argoproj.io/instance"
+	argoCDTrackingAnnotation = "argocd.argoproj.io/tracking-id"
+	fluxKustomizationLabel   = "kustomize.toolkit.fluxcd.io/name"
+	fluxHelmReleaseLabel     = "helm.toolkit.fluxcd.io/name"
+	fluxReconcileAnnotation  = "kustomize.toolkit.fluxcd.io/reconcile"
+)
+
+// systemManagers update deployments in development mode as part of their normal operation
+var systemManagers = map[string]bool{
+	okLabels.FieldManager:     true,
+	"kube-controller-manager": true,
+}
+
+//ExternalChange represents an update of the spec of a deployment in development mode made by someone else than okteto
+type ExternalChange struct {
+	Deployment string
+	Manager    string
+	Controller string
+	Hint       string
+}
+
+//WatchExternalChanges calls handler every time the spec of deployment is updated by someone else than okteto, until ctx is done
+func WatchExternalChanges(ctx context.Context, namespace, deployment string, c kubernetes.Interface, handler func(*ExternalChange)) error {
+	dClient := c.AppsV1().Deployments(namespace)
+	d, err := dClient.Get(ctx, deployment, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get deployment %s/%s: %w", namespace, deployment, err)
+	}
+
+	w, err := dClient.Watch(ctx, metav1.ListOptions{
+		FieldSelector:   fmt.Sprintf("metadata.name=%s", deployment),
+		ResourceVersion: d.ResourceVersion,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch deployment %s/%s: %w", namespace, deployment, err)
+	}
+	defer w.Stop()
+
+	for {
+		select {
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return nil
+			}
+
+			updated, ok := event.Object.(*appsv1.Deployment)
+			if !ok || event.Type != watch.Modified {
+				continue
+			}
+
+			if change := getExternalChange(d, updated); change != nil {
+				handler(change)
+			}
+			d = updated
+		case <-ctx.Done():
+			log.Debug("call to deployments.WatchExternalChanges cancelled")
+			return nil
+		}
+	}
+}
+
+// getExternalChange returns the external change between two versions of a deployment, or nil if the spec wasn't changed by someone else
+func getExternalChange(previous, updated *appsv1.Deployment) *ExternalChange {
+	if updated.Generation <= previous.Generation {
+		return nil
+	}
+
+	manager := getLastManager(updated)
+	if manager == "" {
+		return nil
+	}
+
+	change := &ExternalChange{
+		Deployment: updated.Name,
+		Manager:    manager,
+		Controller: getGitOpsController(updated, manager),
+	}
+
+	switch change.Controller {
+	case argoCDController:
+		app := updated.Labels[argoCDInstanceLabel]
+		if app == "" {
+			app = strings.SplitN(updated.Annotations[argoCDTrackingAnnotation], ":", 2)[0]
+		}
+		change.Hint = fmt.Sprintf("Disable self-heal in the Argo CD application '%s' while you develop, or add the deployment to its 'ignoreDifferences'", app)
+	case fluxController:
+		change.Hint = fmt.Sprintf("Suspend its reconciliation while you develop: 'kubectl annotate deployment %s -n %s %s=disabled'", updated.Name, updated.Namespace, fluxReconcileAnnotation)
+	default:
+		change.Hint = fmt.Sprintf("Stop '%s' from updating the deployment while you develop", manager)
+	}
+
+	return change
+}
+
+// getLastManager returns the last manager that updated the deployment, ignoring okteto and the kubernetes controllers
+func getLastManager(d *appsv1.Deployment) string {
+	var last *metav1.ManagedFieldsEntry
+	for i := range d.ManagedFields {
+		entry := &d.ManagedFields[i]
+		if systemManagers[entry.Manager] || entry.Time == nil {
+			continue
+		}
+
+		if last == nil || last.Time.Before(entry.Time) {
+			last = entry
+		}
+	}
+
+	if last == nil {
+		return ""
+	}
+	return last.Manager
+}
+
+// getGitOpsController returns the gitops controller managing the deployment, or an empty string
+func getGitOpsController(d *appsv1.Deployment, manager string) string {
+	switch {
+	case strings.HasPrefix(manager, "argocd"),
+		d.Labels[argoCDInstanceLabel] != "",
+		d.Annotations[argoCDTrackingAnnotation] != "":
+		return argoCDController
+	case manager == "kustomize-controller",
+		manager == "helm-controller",
+		manager == "flux",
+		d.Labels[fluxKustomizationLabel] != "",
+		d.Labels[fluxHelmReleaseLabel] != "",
+		d.Annotations[okLabels.FluxAnnotation] != "":
+		return fluxController
+	default:
+		return ""
+	}
+}
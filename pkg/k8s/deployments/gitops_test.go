@@ -0,0 +1,108 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	okLabels "github.com/okteto/okteto/pkg/k8s/labels"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_getExternalChange(t *testing.T) {
+	now := time.Now()
+	managedFields := func(managers ...string) []metav1.ManagedFieldsEntry {
+		result := []metav1.ManagedFieldsEntry{}
+		for i, m := range managers {
+			updated := metav1.NewTime(now.Add(time.Duration(i) * time.Second))
+			result = append(result, metav1.ManagedFieldsEntry{Manager: m, Operation: metav1.ManagedFieldsOperationUpdate, Time: &updated})
+		}
+		return result
+	}
+
+	tests := []struct {
+		name       string
+		generation int64
+		labels     map[string]string
+		managers   []string
+		expected   *ExternalChange
+		hint       string
+	}{
+		{
+			name:       "argocd",
+			generation: 3,
+			labels:     map[string]string{argoCDInstanceLabel: "shop"},
+			managers:   []string{"argocd-application-controller", okLabels.FieldManager, "kube-controller-manager"},
+			expected:   &ExternalChange{Deployment: "web", Manager: "argocd-application-controller", Controller: argoCDController},
+			hint:       "'shop'",
+		},
+		{
+			name:       "flux",
+			generation: 3,
+			managers:   []string{okLabels.FieldManager, "kustomize-controller"},
+			expected:   &ExternalChange{Deployment: "web", Manager: "kustomize-controller", Controller: fluxController},
+			hint:       fluxReconcileAnnotation,
+		},
+		{
+			name:       "other",
+			generation: 3,
+			managers:   []string{"kubectl", okLabels.FieldManager},
+			expected:   &ExternalChange{Deployment: "web", Manager: "kubectl"},
+			hint:       "'kubectl'",
+		},
+		{
+			name:       "same-generation",
+			generation: 2,
+			managers:   []string{"argocd-application-controller"},
+		},
+		{
+			name:       "only-okteto",
+			generation: 3,
+			managers:   []string{okLabels.FieldManager, "kube-controller-manager"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "test", Generation: 2}}
+			updated := previous.DeepCopy()
+			updated.Generation = tt.generation
+			updated.Labels = tt.labels
+			updated.ManagedFields = managedFields(tt.managers...)
+
+			change := getExternalChange(previous, updated)
+			if tt.expected == nil {
+				if change != nil {
+					t.Fatalf("unexpected change: %+v", change)
+				}
+				return
+			}
+
+			if change == nil {
+				t.Fatal("change wasn't detected")
+			}
+
+			if change.Deployment != tt.expected.Deployment || change.Manager != tt.expected.Manager || change.Controller != tt.expected.Controller {
+				t.Errorf("expected %+v, got %+v", tt.expected, change)
+			}
+
+			if !strings.Contains(change.Hint, tt.hint) {
+				t.Errorf("hint '%s' doesn't include '%s'", change.Hint, tt.hint)
+			}
+		})
+	}
+}
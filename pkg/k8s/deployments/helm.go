@@ -0,0 +1,108 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	helmReleaseNameAnnotation      = "meta.helm.sh/release-name"
+	helmReleaseNamespaceAnnotation = "meta.helm.sh/release-namespace"
+	helmManagedByLabel             = "app.kubernetes.io/managed-by"
+	helmPendingStatusPrefix        = "pending-"
+)
+
+func isHelmKey(key string) bool {
+	return key == helmManagedByLabel || strings.HasPrefix(key, "helm.sh/") || strings.Contains(key, ".helm.sh/")
+}
+
+// preserveHelmMetadata sets the helm labels and annotations of the live object into the object that replaces it,
+// so restoring an older copy of a deployment doesn't break the next 'helm upgrade'
+func preserveHelmMetadata(live, o metav1.Object) {
+	o.SetLabels(mergeHelmKeys(live.GetLabels(), o.GetLabels()))
+	o.SetAnnotations(mergeHelmKeys(live.GetAnnotations(), o.GetAnnotations()))
+}
+
+func mergeHelmKeys(live, m map[string]string) map[string]string {
+	result := map[string]string{}
+	for key, value := range m {
+		if !isHelmKey(key) {
+			result[key] = value
+		}
+	}
+
+	for key, value := range live {
+		if isHelmKey(key) {
+			result[key] = value
+		}
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+
+	return result
+}
+
+//GetPendingHelmOperation returns the status of the helm release of a deployment if it has a pending operation
+func GetPendingHelmOperation(ctx context.Context, d *appsv1.Deployment, c kubernetes.Interface) (string, error) {
+	release := d.Annotations[helmReleaseNameAnnotation]
+	if release == "" {
+		return "", nil
+	}
+
+	namespace := d.Annotations[helmReleaseNamespaceAnnotation]
+	if namespace == "" {
+		namespace = d.Namespace
+	}
+
+	secrets, err := c.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("owner=helm,name=%s", release),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get the helm release '%s': %s", release, err)
+	}
+
+	if len(secrets.Items) == 0 {
+		return "", nil
+	}
+
+	sort.Slice(secrets.Items, func(i, j int) bool {
+		return getHelmRevision(secrets.Items[i].Labels) < getHelmRevision(secrets.Items[j].Labels)
+	})
+
+	status := secrets.Items[len(secrets.Items)-1].Labels["status"]
+	if strings.HasPrefix(status, helmPendingStatusPrefix) {
+		return status, nil
+	}
+
+	return "", nil
+}
+
+func getHelmRevision(labels map[string]string) int {
+	revision, err := strconv.Atoi(labels["version"])
+	if err != nil {
+		return 0
+	}
+	return revision
+}
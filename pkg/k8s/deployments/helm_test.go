@@ -0,0 +1,116 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_preserveHelmMetadataOnRestore(t *testing.T) {
+	original := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "api",
+			Labels:      map[string]string{"app": "api", "helm.sh/chart": "api-1.0.0"},
+			Annotations: map[string]string{helmReleaseNameAnnotation: "api", "team": "backend"},
+		},
+	}
+	manifest, err := json.Marshal(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	live := original.DeepCopy()
+	live.Labels["helm.sh/chart"] = "api-1.1.0"
+	live.Labels[helmManagedByLabel] = "Helm"
+	live.Annotations[oktetoDeploymentAnnotation] = string(manifest)
+
+	restored, err := TranslateDevModeOff(live)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if restored.Labels["helm.sh/chart"] != "api-1.1.0" || restored.Labels[helmManagedByLabel] != "Helm" {
+		t.Errorf("helm labels weren't preserved: %v", restored.Labels)
+	}
+
+	if restored.Annotations[helmReleaseNameAnnotation] != "api" || restored.Annotations["team"] != "backend" {
+		t.Errorf("wrong annotations: %v", restored.Annotations)
+	}
+
+	if _, ok := restored.Annotations[oktetoDeploymentAnnotation]; ok {
+		t.Errorf("okteto annotation wasn't removed: %v", restored.Annotations)
+	}
+}
+
+func newHelmSecret(name, release, version, status string) *apiv1.Secret {
+	return &apiv1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test",
+			Labels:    map[string]string{"owner": "helm", "name": release, "version": version, "status": status},
+		},
+	}
+}
+
+func TestGetPendingHelmOperation(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewSimpleClientset(
+		newHelmSecret("sh.helm.release.v1.api.v9", "api", "9", "superseded"),
+		newHelmSecret("sh.helm.release.v1.api.v10", "api", "10", "pending-upgrade"),
+		newHelmSecret("sh.helm.release.v1.web.v1", "web", "1", "deployed"),
+	)
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    string
+	}{
+		{
+			name:        "pending",
+			annotations: map[string]string{helmReleaseNameAnnotation: "api"},
+			expected:    "pending-upgrade",
+		},
+		{
+			name:        "deployed",
+			annotations: map[string]string{helmReleaseNameAnnotation: "web"},
+			expected:    "",
+		},
+		{
+			name:        "not-helm",
+			annotations: nil,
+			expected:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "d", Namespace: "test", Annotations: tt.annotations}}
+			status, err := GetPendingHelmOperation(ctx, d, c)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if status != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, status)
+			}
+		})
+	}
+}
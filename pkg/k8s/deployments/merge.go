@@ -0,0 +1,173 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"encoding/json"
+	"fmt"
+
+	okLabels "github.com/okteto/okteto/pkg/k8s/labels"
+	"github.com/okteto/okteto/pkg/log"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+const mergeSentinel = "dev.okteto.com/merge"
+
+// getOriginalDeployment returns the deployment saved before activating the development container.
+// Changes made by others to the live deployment since then are kept, only the fields translated by okteto are reverted.
+func getOriginalDeployment(d *appsv1.Deployment) (*appsv1.Deployment, error) {
+	manifest := getAnnotation(d.GetObjectMeta(), oktetoDeploymentAnnotation)
+	if manifest == "" {
+		return nil, nil
+	}
+
+	dOrig := &appsv1.Deployment{}
+	if err := json.Unmarshal([]byte(manifest), dOrig); err != nil {
+		return nil, fmt.Errorf("malformed manifest: %s", err)
+	}
+	preserveHelmMetadata(d.GetObjectMeta(), dOrig.GetObjectMeta())
+
+	patch := getAnnotation(d.GetObjectMeta(), oktetoTranslationPatchAnnotation)
+	if patch == "" {
+		return dOrig, nil
+	}
+
+	translated, err := strategicpatch.StrategicMergePatch([]byte(manifest), []byte(patch), appsv1.Deployment{})
+	if err != nil {
+		log.Infof("failed to recompute the translated deployment '%s', restoring the saved copy: %s", d.Name, err)
+		return dOrig, nil
+	}
+
+	merged, err := mergeOriginal(d, dOrig, translated)
+	if err != nil {
+		log.Infof("failed to merge the original deployment '%s', restoring the saved copy: %s", d.Name, err)
+		return dOrig, nil
+	}
+
+	return merged, nil
+}
+
+// mergeOriginal applies to the live deployment only the changes from the translated deployment back to the original one
+func mergeOriginal(live, dOrig *appsv1.Deployment, translated []byte) (*appsv1.Deployment, error) {
+	current, err := json.Marshal(live)
+	if err != nil {
+		return nil, err
+	}
+
+	// the sentinel keeps the label and annotation maps in the patch, so the ones added to the live deployment aren't dropped
+	dOrig = dOrig.DeepCopy()
+	setMergeSentinel(dOrig)
+	original, err := json.Marshal(dOrig)
+	if err != nil {
+		return nil, err
+	}
+
+	dTranslated := &appsv1.Deployment{}
+	if err := json.Unmarshal(translated, dTranslated); err != nil {
+		return nil, err
+	}
+	setMergeSentinel(dTranslated)
+	translated, err = json.Marshal(dTranslated)
+	if err != nil {
+		return nil, err
+	}
+
+	patchMeta, err := strategicpatch.NewPatchMetaFromStruct(appsv1.Deployment{})
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := strategicpatch.CreateTwoWayMergePatchUsingLookupPatchMeta(translated, original, patchMeta)
+	if err != nil {
+		return nil, err
+	}
+
+	mergedJSON, err := strategicpatch.StrategicMergePatchUsingLookupPatchMeta(current, patch, patchMeta)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &appsv1.Deployment{}
+	if err := json.Unmarshal(mergedJSON, merged); err != nil {
+		return nil, err
+	}
+
+	removeMergeSentinel(merged)
+	annotations := merged.GetObjectMeta().GetAnnotations()
+	delete(annotations, oktetoDeploymentAnnotation)
+	delete(annotations, oktetoTranslationPatchAnnotation)
+	delete(annotations, oktetoVersionAnnotation)
+	merged.GetObjectMeta().SetAnnotations(annotations)
+	labels := merged.GetObjectMeta().GetLabels()
+	delete(labels, okLabels.DevLabel)
+	merged.GetObjectMeta().SetLabels(labels)
+	return merged, nil
+}
+
+func setMergeSentinel(d *appsv1.Deployment) {
+	setLabel(d.GetObjectMeta(), mergeSentinel, "true")
+	setAnnotation(d.GetObjectMeta(), mergeSentinel, "true")
+	setLabel(d.Spec.Template.GetObjectMeta(), mergeSentinel, "true")
+	setAnnotation(d.Spec.Template.GetObjectMeta(), mergeSentinel, "true")
+}
+
+func removeMergeSentinel(d *appsv1.Deployment) {
+	for _, o := range []metav1.Object{d.GetObjectMeta(), d.Spec.Template.GetObjectMeta()} {
+		labels := o.GetLabels()
+		delete(labels, mergeSentinel)
+		o.SetLabels(labels)
+		annotations := o.GetAnnotations()
+		delete(annotations, mergeSentinel)
+		o.SetAnnotations(annotations)
+	}
+}
+
+// setTranslationPatchAnnotation saves the changes made by okteto to the original deployment, to restore only those fields.
+// The translated deployment is recomputed from the original deployment and the patch, which is much smaller than the deployment
+func setTranslationPatchAnnotation(d *appsv1.Deployment) error {
+	manifest := getAnnotation(d.GetObjectMeta(), oktetoDeploymentAnnotation)
+	translated := d.DeepCopy()
+	annotations := translated.GetObjectMeta().GetAnnotations()
+	delete(annotations, oktetoDeploymentAnnotation)
+	delete(annotations, oktetoTranslationPatchAnnotation)
+	translated.GetObjectMeta().SetAnnotations(annotations)
+
+	stripServerFields(translated)
+
+	bytes, err := json.Marshal(translated)
+	if err != nil {
+		return err
+	}
+
+	patch, err := strategicpatch.CreateTwoWayMergePatch([]byte(manifest), bytes, appsv1.Deployment{})
+	if err != nil {
+		return err
+	}
+
+	setAnnotation(d.GetObjectMeta(), oktetoTranslationPatchAnnotation, string(patch))
+	return nil
+}
+
+// stripServerFields removes the fields set by the API server from a deployment saved in an annotation, to keep it small
+func stripServerFields(d *appsv1.Deployment) {
+	d.ManagedFields = nil
+	d.ResourceVersion = ""
+	d.UID = ""
+	d.SelfLink = ""
+	d.Generation = 0
+	d.CreationTimestamp = metav1.Time{}
+	d.Status = appsv1.DeploymentStatus{}
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_restoreKeepsLiveChanges(t *testing.T) {
+	dev, err := model.Read([]byte(`name: web
+container: dev
+image: web:dev`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var replicas int32 = 3
+	d := dev.GevSandbox()
+	d.Spec.Replicas = &replicas
+	d.Spec.Template.Spec.Containers = []apiv1.Container{
+		{Name: "dev", Image: "web:1.0"},
+		{Name: "proxy", Image: "proxy:1.0"},
+	}
+	d.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply}}
+
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Deployment:  d,
+		Replicas:    replicas,
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{oktetoDeploymentAnnotation, oktetoTranslationPatchAnnotation} {
+		if strings.Contains(tr.Deployment.Annotations[key], "managedFields") {
+			t.Errorf("annotation '%s' has managed fields", key)
+		}
+	}
+
+	if strings.Contains(tr.Deployment.Annotations[oktetoTranslationPatchAnnotation], "proxy:1.0") {
+		t.Errorf("translation patch has fields not changed by okteto: %s", tr.Deployment.Annotations[oktetoTranslationPatchAnnotation])
+	}
+
+	live := tr.Deployment.DeepCopy()
+	live.Labels["argocd.argoproj.io/instance"] = "web"
+	live.Spec.Template.Spec.Containers[1].Image = "proxy:2.0"
+
+	restored, err := TranslateDevModeOff(live)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *restored.Spec.Replicas != replicas {
+		t.Errorf("replicas weren't restored: %d", *restored.Spec.Replicas)
+	}
+
+	containers := restored.Spec.Template.Spec.Containers
+	if len(containers) != 2 || containers[0].Image != "web:1.0" || containers[1].Image != "proxy:2.0" {
+		t.Errorf("wrong containers: %+v", containers)
+	}
+
+	if len(containers[0].VolumeMounts) != 0 || len(restored.Spec.Template.Spec.InitContainers) != 0 {
+		t.Errorf("okteto volumes weren't reverted: %+v", restored.Spec.Template.Spec)
+	}
+
+	if restored.Labels["argocd.argoproj.io/instance"] != "web" {
+		t.Errorf("live label was lost: %v", restored.Labels)
+	}
+
+	for _, key := range []string{oktetoDeploymentAnnotation, oktetoTranslationPatchAnnotation, oktetoVersionAnnotation} {
+		if _, ok := restored.Annotations[key]; ok {
+			t.Errorf("annotation '%s' wasn't removed", key)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"encoding/json"
+
+	"github.com/okteto/okteto/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+const (
+	securityPolicyAnnotation = "dev.okteto.com/security-policy"
+
+	netBindServiceCapability apiv1.Capability = "NET_BIND_SERVICE"
+	allCapabilities          apiv1.Capability = "ALL"
+)
+
+var (
+	trueBoolean = true
+
+	// oktetoBinUser runs the okteto init container, its image runs as root by default
+	oktetoBinUser int64 = 1000
+)
+
+//TranslateSecurityPolicy makes the pod template compliant with the security policy of the development container
+func TranslateSecurityPolicy(d *appsv1.Deployment, policy string) {
+	if policy != model.SecurityPolicyRestricted {
+		return
+	}
+
+	setAnnotation(d.Spec.Template.GetObjectMeta(), securityPolicyAnnotation, policy)
+	spec := &d.Spec.Template.Spec
+	if spec.SecurityContext == nil {
+		spec.SecurityContext = &apiv1.PodSecurityContext{}
+	}
+	spec.SecurityContext.RunAsNonRoot = &trueBoolean
+
+	for i := range spec.InitContainers {
+		translateRestrictedContainer(&spec.InitContainers[i])
+		if spec.InitContainers[i].Name == oktetoBinName && spec.InitContainers[i].SecurityContext.RunAsUser == nil {
+			spec.InitContainers[i].SecurityContext.RunAsUser = &oktetoBinUser
+		}
+	}
+	for i := range spec.Containers {
+		translateRestrictedContainer(&spec.Containers[i])
+	}
+}
+
+func translateRestrictedContainer(c *apiv1.Container) {
+	if c.SecurityContext == nil {
+		c.SecurityContext = &apiv1.SecurityContext{}
+	}
+
+	s := c.SecurityContext
+	s.AllowPrivilegeEscalation = &falseBoolean
+	if s.Privileged != nil && *s.Privileged {
+		s.Privileged = &falseBoolean
+	}
+	if s.RunAsNonRoot != nil && !*s.RunAsNonRoot {
+		s.RunAsNonRoot = nil
+	}
+
+	if s.Capabilities == nil {
+		s.Capabilities = &apiv1.Capabilities{}
+	}
+
+	add := []apiv1.Capability{}
+	for _, capability := range s.Capabilities.Add {
+		if capability == netBindServiceCapability {
+			add = append(add, capability)
+		}
+	}
+	s.Capabilities.Add = add
+
+	for _, capability := range s.Capabilities.Drop {
+		if capability == allCapabilities {
+			return
+		}
+	}
+	s.Capabilities.Drop = append(s.Capabilities.Drop, allCapabilities)
+}
+
+// setSeccompProfile adds the RuntimeDefault seccomp profile required by the restricted security policy to a serialized deployment.
+// The field isn't part of the kubernetes API types vendored by okteto, so it's added to the json sent to the API server.
+func setSeccompProfile(d *appsv1.Deployment, data []byte) ([]byte, error) {
+	if getAnnotation(d.Spec.Template.GetObjectMeta(), securityPolicyAnnotation) != model.SecurityPolicyRestricted {
+		return data, nil
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+
+	spec := getMap(getMap(getMap(obj, "spec"), "template"), "spec")
+	getMap(spec, "securityContext")["seccompProfile"] = map[string]interface{}{"type": "RuntimeDefault"}
+	return json.Marshal(obj)
+}
+
+func getMap(obj map[string]interface{}, key string) map[string]interface{} {
+	if m, ok := obj[key].(map[string]interface{}); ok {
+		return m
+	}
+
+	m := map[string]interface{}{}
+	obj[key] = m
+	return m
+}
@@ -17,6 +17,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	okLabels "github.com/okteto/okteto/pkg/k8s/labels"
 	"github.com/okteto/okteto/pkg/log"
@@ -30,10 +31,11 @@ import (
 )
 
 const (
-	oktetoDeploymentAnnotation = "dev.okteto.com/deployment"
-	oktetoVersionAnnotation    = "dev.okteto.com/version"
-	revisionAnnotation         = "deployment.kubernetes.io/revision"
-	oktetoBinName              = "okteto-bin"
+	oktetoDeploymentAnnotation       = "dev.okteto.com/deployment"
+	oktetoVersionAnnotation          = "dev.okteto.com/version"
+	oktetoTranslationPatchAnnotation = "dev.okteto.com/translation-patch"
+	revisionAnnotation               = "deployment.kubernetes.io/revision"
+	oktetoBinName                    = "okteto-bin"
 
 	//syncthing
 	oktetoSyncSecretVolume = "okteto-sync-secret" // skipcq GSC-G101  not a secret
@@ -45,23 +47,34 @@ var (
 	devReplicas                      int32 = 1
 	devTerminationGracePeriodSeconds int64
 	falseBoolean                     = false
+
+	// sidecarInjectionAnnotations are the annotations that disable the injection of sidecars by admission webhooks, by container name
+	sidecarInjectionAnnotations = map[string]struct{ key, value string }{
+		"istio-proxy":      {key: "sidecar.istio.io/inject", value: "false"},
+		"istio-init":       {key: "sidecar.istio.io/inject", value: "false"},
+		"linkerd-proxy":    {key: "linkerd.io/inject", value: "disabled"},
+		"linkerd-init":     {key: "linkerd.io/inject", value: "disabled"},
+		"vault-agent":      {key: "vault.hashicorp.com/agent-inject", value: "false"},
+		"vault-agent-init": {key: "vault.hashicorp.com/agent-inject", value: "false"},
+	}
 )
 
 func translate(t *model.Translation, c *kubernetes.Clientset, isOktetoNamespace bool) error {
+	devContainers := map[string]bool{}
 	for _, rule := range t.Rules {
 		devContainer := GetDevContainer(&t.Deployment.Spec.Template.Spec, rule.Container)
 		if devContainer == nil {
 			return fmt.Errorf("Container '%s' not found in deployment '%s'", rule.Container, t.Deployment.Name)
 		}
+		devContainers[devContainer.Name] = true
 		rule.Container = devContainer.Name
 	}
 
-	manifest := getAnnotation(t.Deployment.GetObjectMeta(), oktetoDeploymentAnnotation)
-	if manifest != "" {
-		dOrig := &appsv1.Deployment{}
-		if err := json.Unmarshal([]byte(manifest), dOrig); err != nil {
-			return err
-		}
+	dOrig, err := getOriginalDeployment(t.Deployment)
+	if err != nil {
+		return err
+	}
+	if dOrig != nil {
 		t.Deployment = dOrig
 	}
 	annotations := t.Deployment.GetObjectMeta().GetAnnotations()
@@ -71,6 +84,10 @@ func translate(t *model.Translation, c *kubernetes.Clientset, isOktetoNamespace
 	if c != nil && isOktetoNamespace {
 		c := os.Getenv("OKTETO_CLIENTSIDE_TRANSLATION")
 		if c == "" {
+			if fields := getServerSideIgnoredFields(t); len(fields) > 0 {
+				log.Yellow("The fields '%s' are not supported by the server-side translation of Okteto namespaces and will be ignored", strings.Join(fields, "', '"))
+				log.Yellow("Set the environment variable OKTETO_CLIENTSIDE_TRANSLATION=true to apply them")
+			}
 			commonTranslation(t)
 			return setTranslationAsAnnotation(t.Deployment.Spec.Template.GetObjectMeta(), t)
 		}
@@ -79,7 +96,9 @@ func translate(t *model.Translation, c *kubernetes.Clientset, isOktetoNamespace
 	}
 
 	t.Deployment.Status = appsv1.DeploymentStatus{}
-	manifestBytes, err := json.Marshal(t.Deployment)
+	saved := t.Deployment.DeepCopy()
+	stripServerFields(saved)
+	manifestBytes, err := json.Marshal(saved)
 	if err != nil {
 		return err
 	}
@@ -89,7 +108,12 @@ func translate(t *model.Translation, c *kubernetes.Clientset, isOktetoNamespace
 	setLabel(t.Deployment.Spec.Template.GetObjectMeta(), okLabels.DevLabel, "true")
 	TranslateDevAnnotations(t.Deployment.Spec.Template.GetObjectMeta(), t.Annotations)
 	TranslateDevTolerations(&t.Deployment.Spec.Template.Spec, t.Tolerations)
-	TranslatePodAffinity(&t.Deployment.Spec.Template.Spec, t.Name)
+	TranslateDevNodeSelector(&t.Deployment.Spec.Template.Spec, t.NodeSelector)
+	TranslateDevAffinity(&t.Deployment.Spec.Template.Spec, t.Affinity)
+	TranslateDevServiceAccount(&t.Deployment.Spec.Template.Spec, t.ServiceAccount)
+	if mountsDevVolume(t.Rules) {
+		TranslatePodAffinity(&t.Deployment.Spec.Template.Spec, t.Name)
+	}
 	t.Deployment.Spec.Template.Spec.TerminationGracePeriodSeconds = &devTerminationGracePeriodSeconds
 
 	if t.Interactive {
@@ -102,22 +126,66 @@ func translate(t *model.Translation, c *kubernetes.Clientset, isOktetoNamespace
 		}
 
 		TranslateDevContainer(devContainer, rule)
+		TranslateDevTolerations(&t.Deployment.Spec.Template.Spec, rule.Tolerations)
+		TranslateImagePullSecret(&t.Deployment.Spec.Template.Spec, rule.ImagePullSecret)
 		TranslateOktetoVolumes(&t.Deployment.Spec.Template.Spec, rule)
 		TranslatePodSecurityContext(&t.Deployment.Spec.Template.Spec, rule.SecurityContext)
 		TranslateOktetoDevSecret(&t.Deployment.Spec.Template.Spec, t.Name, rule.Secrets)
 		if rule.OktetoBinImageTag != "" {
 			TranslateOktetoBinVolumeMounts(devContainer)
-			TranslateOktetoInitBinContainer(rule.OktetoBinImageTag, &t.Deployment.Spec.Template.Spec)
+			TranslateOktetoInitBinContainer(rule.InitContainer, &t.Deployment.Spec.Template.Spec)
 			TranslateOktetoBinVolume(&t.Deployment.Spec.Template.Spec)
 		}
 	}
-	return nil
+
+	for _, name := range t.ExcludeContainers {
+		if devContainers[name] {
+			return fmt.Errorf("Container '%s' can't be excluded from deployment '%s' because it's a development container", name, t.Deployment.Name)
+		}
+	}
+	TranslateExcludedContainers(&t.Deployment.Spec.Template, t.ExcludeContainers)
+	TranslateSecurityPolicy(t.Deployment, t.SecurityPolicy)
+	return setTranslationPatchAnnotation(t.Deployment)
+}
+
+//getServerSideIgnoredFields returns the manifest fields the server-side translation doesn't apply
+func getServerSideIgnoredFields(t *model.Translation) []string {
+	fields := []string{}
+	if len(t.NodeSelector) > 0 {
+		fields = append(fields, "nodeSelector")
+	}
+	if t.Affinity != nil {
+		fields = append(fields, "affinity")
+	}
+	if t.ServiceAccount != "" {
+		fields = append(fields, "serviceAccount")
+	}
+	if len(t.ExcludeContainers) > 0 {
+		fields = append(fields, "excludeContainers")
+	}
+	if t.SecurityPolicy != "" {
+		fields = append(fields, "securityPolicy")
+	}
+	probes := false
+	lifecycle := false
+	for _, rule := range t.Rules {
+		probes = probes || rule.Probes != nil
+		lifecycle = lifecycle || rule.Lifecycle != nil
+	}
+	if probes {
+		fields = append(fields, "probes")
+	}
+	if lifecycle {
+		fields = append(fields, "lifecycle")
+	}
+	return fields
 }
 
 func commonTranslation(t *model.Translation) {
 	TranslateDevAnnotations(t.Deployment.GetObjectMeta(), t.Annotations)
 	setAnnotation(t.Deployment.GetObjectMeta(), oktetoVersionAnnotation, okLabels.Version)
 	setLabel(t.Deployment.GetObjectMeta(), okLabels.DevLabel, "true")
+	t.StrippedLabels = TranslatePodLabels(t.Deployment, t.PodLabels)
 
 	if t.Interactive {
 		setLabel(t.Deployment.Spec.Template.GetObjectMeta(), okLabels.InteractiveDevLabel, t.Name)
@@ -125,9 +193,57 @@ func commonTranslation(t *model.Translation) {
 		setLabel(t.Deployment.Spec.Template.GetObjectMeta(), okLabels.DetachedDevLabel, t.Name)
 	}
 
+	if t.PriorityClassName != "" {
+		t.OriginalPriority = &model.OriginalPriority{
+			PriorityClassName: t.Deployment.Spec.Template.Spec.PriorityClassName,
+			Priority:          t.Deployment.Spec.Template.Spec.Priority,
+		}
+		t.Deployment.Spec.Template.Spec.PriorityClassName = t.PriorityClassName
+		t.Deployment.Spec.Template.Spec.Priority = nil
+	}
+
 	t.Deployment.Spec.Replicas = &devReplicas
 }
 
+//TranslatePodLabels removes the labels stripped by the user from the pod template and returns them.
+//The labels used by the deployment selector are always kept.
+func TranslatePodLabels(d *appsv1.Deployment, podLabels *model.PodLabels) map[string]string {
+	if podLabels == nil {
+		return nil
+	}
+
+	labels := d.Spec.Template.GetObjectMeta().GetLabels()
+	stripped := map[string]string{}
+	for key, value := range labels {
+		if isSelectorLabel(d.Spec.Selector, key) || !podLabels.IsStripped(key) {
+			continue
+		}
+		stripped[key] = value
+		delete(labels, key)
+	}
+	d.Spec.Template.GetObjectMeta().SetLabels(labels)
+
+	if len(stripped) == 0 {
+		return nil
+	}
+	return stripped
+}
+
+func isSelectorLabel(selector *metav1.LabelSelector, key string) bool {
+	if selector == nil {
+		return false
+	}
+	if _, ok := selector.MatchLabels[key]; ok {
+		return true
+	}
+	for _, r := range selector.MatchExpressions {
+		if r.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
 //GetDevContainer returns the dev container of a given deployment
 func GetDevContainer(spec *apiv1.PodSpec, name string) *apiv1.Container {
 	if name == "" {
@@ -146,13 +262,129 @@ func GetDevContainer(spec *apiv1.PodSpec, name string) *apiv1.Container {
 //TranslateDevAnnotations sets the user provided annotations
 func TranslateDevAnnotations(o metav1.Object, annotations map[string]string) {
 	for key, value := range annotations {
+		if isHelmKey(key) {
+			log.Infof("skipping annotation '%s': helm metadata can't be overwritten", key)
+			continue
+		}
 		setAnnotation(o, key, value)
 	}
 }
 
 //TranslateDevTolerations sets the user provided toleretions
 func TranslateDevTolerations(spec *apiv1.PodSpec, tolerations []apiv1.Toleration) {
-	spec.Tolerations = append(spec.Tolerations, tolerations...)
+	for i := range tolerations {
+		if hasToleration(spec.Tolerations, &tolerations[i]) {
+			continue
+		}
+		spec.Tolerations = append(spec.Tolerations, tolerations[i])
+	}
+}
+
+func hasToleration(tolerations []apiv1.Toleration, t *apiv1.Toleration) bool {
+	for i := range tolerations {
+		if tolerations[i].MatchToleration(t) {
+			return true
+		}
+	}
+	return false
+}
+
+//TranslateDevNodeSelector sets the user provided node selector
+func TranslateDevNodeSelector(spec *apiv1.PodSpec, nodeSelector map[string]string) {
+	if len(nodeSelector) == 0 {
+		return
+	}
+	if spec.NodeSelector == nil {
+		spec.NodeSelector = map[string]string{}
+	}
+	for key, value := range nodeSelector {
+		spec.NodeSelector[key] = value
+	}
+}
+
+//TranslateDevAffinity replaces the affinity rules of the pod by the user provided ones
+func TranslateDevAffinity(spec *apiv1.PodSpec, affinity *apiv1.Affinity) {
+	if affinity == nil {
+		return
+	}
+	if spec.Affinity == nil {
+		spec.Affinity = &apiv1.Affinity{}
+	}
+	if affinity.NodeAffinity != nil {
+		spec.Affinity.NodeAffinity = affinity.NodeAffinity.DeepCopy()
+	}
+	if affinity.PodAffinity != nil {
+		spec.Affinity.PodAffinity = affinity.PodAffinity.DeepCopy()
+	}
+	if affinity.PodAntiAffinity != nil {
+		spec.Affinity.PodAntiAffinity = affinity.PodAntiAffinity.DeepCopy()
+	}
+}
+
+//TranslateDevServiceAccount sets the user provided service account
+func TranslateDevServiceAccount(spec *apiv1.PodSpec, serviceAccount string) {
+	if serviceAccount == "" {
+		return
+	}
+	spec.ServiceAccountName = serviceAccount
+	spec.DeprecatedServiceAccount = ""
+}
+
+//TranslateImagePullSecret adds an image pull secret to the pod
+func TranslateImagePullSecret(spec *apiv1.PodSpec, name string) {
+	if name == "" {
+		return
+	}
+	for _, s := range spec.ImagePullSecrets {
+		if s.Name == name {
+			return
+		}
+	}
+	spec.ImagePullSecrets = append(spec.ImagePullSecrets, apiv1.LocalObjectReference{Name: name})
+}
+
+//TranslateExcludedContainers removes the user provided containers from the pod.
+//Sidecars added by admission webhooks are not part of the pod template, so their injection is disabled with the annotations of the webhook
+func TranslateExcludedContainers(template *apiv1.PodTemplateSpec, excluded []string) {
+	if len(excluded) == 0 {
+		return
+	}
+	template.Spec.Containers = removeContainers(template.Spec.Containers, excluded)
+	template.Spec.InitContainers = removeContainers(template.Spec.InitContainers, excluded)
+
+	for _, name := range excluded {
+		if a, ok := sidecarInjectionAnnotations[name]; ok {
+			setAnnotation(template.GetObjectMeta(), a.key, a.value)
+		}
+	}
+}
+
+func removeContainers(containers []apiv1.Container, excluded []string) []apiv1.Container {
+	result := []apiv1.Container{}
+	for _, c := range containers {
+		isExcluded := false
+		for _, name := range excluded {
+			if c.Name == name {
+				isExcluded = true
+				break
+			}
+		}
+		if !isExcluded {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// mountsDevVolume returns if a deployment mounts the volume of the development container,
+// so its pods must run in the same node than the development container
+func mountsDevVolume(rules []*model.TranslationRule) bool {
+	for _, rule := range rules {
+		if len(rule.Volumes) > 0 {
+			return true
+		}
+	}
+	return false
 }
 
 //TranslatePodAffinity translates the affinity of pod to be all on the same node
@@ -196,18 +428,103 @@ func TranslateDevContainer(c *apiv1.Container, rule *model.TranslationRule) {
 		c.Args = rule.Args
 	}
 
-	if !rule.Healthchecks {
-		c.ReadinessProbe = nil
-		c.LivenessProbe = nil
-		c.StartupProbe = nil
-	}
-
+	TranslateProbes(c, rule)
+	TranslateLifecycle(c, rule.Lifecycle)
 	TranslateResources(c, rule.Resources)
 	TranslateEnvVars(c, rule)
 	TranslateVolumeMounts(c, rule)
 	TranslateContainerSecurityContext(c, rule.SecurityContext)
 }
 
+//TranslateProbes translates the probes of the dev container. Probes without an override are removed unless healthchecks are enabled
+func TranslateProbes(c *apiv1.Container, rule *model.TranslationRule) {
+	probes := rule.Probes
+	if probes == nil {
+		probes = &model.Probes{}
+	}
+	c.LivenessProbe = translateProbe(c.LivenessProbe, probes.Liveness, rule.Healthchecks)
+	c.ReadinessProbe = translateProbe(c.ReadinessProbe, probes.Readiness, rule.Healthchecks)
+	c.StartupProbe = translateProbe(c.StartupProbe, probes.Startup, rule.Healthchecks)
+}
+
+func translateProbe(probe *apiv1.Probe, o *model.ProbeOverride, healthchecks bool) *apiv1.Probe {
+	if o == nil {
+		if healthchecks {
+			return probe
+		}
+		return nil
+	}
+	if !o.Enabled {
+		return nil
+	}
+	if o.Probe == nil {
+		return probe
+	}
+
+	result := &apiv1.Probe{}
+	if probe != nil {
+		result = probe.DeepCopy()
+	}
+	if o.Probe.Exec != nil || o.Probe.HTTPGet != nil || o.Probe.TCPSocket != nil {
+		result.Handler = *o.Probe.Handler.DeepCopy()
+	}
+	if result.Exec == nil && result.HTTPGet == nil && result.TCPSocket == nil {
+		// there is no probe to relax
+		return nil
+	}
+
+	if o.Probe.InitialDelaySeconds != 0 {
+		result.InitialDelaySeconds = o.Probe.InitialDelaySeconds
+	}
+	if o.Probe.TimeoutSeconds != 0 {
+		result.TimeoutSeconds = o.Probe.TimeoutSeconds
+	}
+	if o.Probe.PeriodSeconds != 0 {
+		result.PeriodSeconds = o.Probe.PeriodSeconds
+	}
+	if o.Probe.SuccessThreshold != 0 {
+		result.SuccessThreshold = o.Probe.SuccessThreshold
+	}
+	if o.Probe.FailureThreshold != 0 {
+		result.FailureThreshold = o.Probe.FailureThreshold
+	}
+	return result
+}
+
+//TranslateLifecycle translates the lifecycle hooks of the dev container. Hooks without an override are kept
+func TranslateLifecycle(c *apiv1.Container, l *model.Lifecycle) {
+	if l == nil {
+		return
+	}
+
+	var postStart, preStop *apiv1.Handler
+	if c.Lifecycle != nil {
+		postStart = c.Lifecycle.PostStart
+		preStop = c.Lifecycle.PreStop
+	}
+	postStart = translateLifecycleHandler(postStart, l.PostStart)
+	preStop = translateLifecycleHandler(preStop, l.PreStop)
+
+	if postStart == nil && preStop == nil {
+		c.Lifecycle = nil
+		return
+	}
+	c.Lifecycle = &apiv1.Lifecycle{PostStart: postStart, PreStop: preStop}
+}
+
+func translateLifecycleHandler(h *apiv1.Handler, o *model.LifecycleOverride) *apiv1.Handler {
+	switch {
+	case o == nil:
+		return h
+	case !o.Enabled:
+		return nil
+	case o.Handler != nil:
+		return o.Handler.DeepCopy()
+	default:
+		return h
+	}
+}
+
 //TranslateResources translates the resources attached to a container
 func TranslateResources(c *apiv1.Container, r model.ResourceRequirements) {
 	if c.Resources.Requests == nil {
@@ -433,10 +750,10 @@ func TranslateContainerSecurityContext(c *apiv1.Container, s *model.SecurityCont
 }
 
 //TranslateOktetoInitBinContainer translates the bin init container of a pod
-func TranslateOktetoInitBinContainer(oktetoBinImageTag string, spec *apiv1.PodSpec) {
+func TranslateOktetoInitBinContainer(initContainer model.InitContainer, spec *apiv1.PodSpec) {
 	c := apiv1.Container{
 		Name:            oktetoBinName,
-		Image:           oktetoBinImageTag,
+		Image:           initContainer.Image,
 		ImagePullPolicy: apiv1.PullIfNotPresent,
 		Command:         []string{"sh", "-c", "cp /usr/local/bin/* /okteto/bin"},
 		VolumeMounts: []apiv1.VolumeMount{
@@ -446,6 +763,7 @@ func TranslateOktetoInitBinContainer(oktetoBinImageTag string, spec *apiv1.PodSp
 			},
 		},
 	}
+	TranslateResources(&c, initContainer.Resources)
 
 	if spec.InitContainers == nil {
 		spec.InitContainers = []apiv1.Container{}
@@ -14,12 +14,14 @@
 package deployments
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
 	"path"
 	"reflect"
+	"strings"
 	"testing"
 
 	okLabels "github.com/okteto/okteto/pkg/k8s/labels"
@@ -815,6 +817,518 @@ func Test_translateSecurityContext(t *testing.T) {
 	}
 }
 
+func Test_translateNodeSelectorAndAffinity(t *testing.T) {
+	manifest := []byte(`name: web
+container: dev
+image: web:latest
+nodeSelector:
+  accelerator: nvidia
+affinity:
+  nodeAffinity:
+    requiredDuringSchedulingIgnoredDuringExecution:
+      nodeSelectorTerms:
+      - matchExpressions:
+        - key: node-type
+          operator: In
+          values:
+          - gpu`)
+
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := dev.GevSandbox()
+	d.Spec.Template.Spec.NodeSelector = map[string]string{"pool": "default"}
+	tr := &model.Translation{
+		Interactive:  true,
+		Name:         dev.Name,
+		Deployment:   d,
+		NodeSelector: dev.NodeSelector,
+		Affinity:     (*apiv1.Affinity)(dev.Affinity),
+		Rules:        []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := tr.Deployment.Spec.Template.Spec
+	expectedNodeSelector := map[string]string{"pool": "default", "accelerator": "nvidia"}
+	if !reflect.DeepEqual(spec.NodeSelector, expectedNodeSelector) {
+		t.Errorf("wrong node selector: %v", spec.NodeSelector)
+	}
+
+	if spec.Affinity == nil || spec.Affinity.NodeAffinity == nil {
+		t.Fatal("node affinity wasn't translated")
+	}
+
+	terms := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) != 1 || terms[0].MatchExpressions[0].Key != "node-type" || terms[0].MatchExpressions[0].Values[0] != "gpu" {
+		t.Errorf("wrong node affinity: %+v", terms)
+	}
+
+	if spec.Affinity.PodAffinity == nil {
+		t.Error("okteto pod affinity was removed")
+	}
+}
+
+func Test_translateServiceTolerations(t *testing.T) {
+	manifest := []byte(`name: web
+container: dev
+image: web:latest
+tolerations:
+- key: dedicated
+  operator: Equal
+  value: dev
+services:
+  - name: worker
+    container: dev
+    image: worker:latest
+    tolerations:
+    - key: gpu
+      operator: Exists
+  - name: db
+    container: dev
+    image: db:latest`)
+
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		service  *model.Dev
+		expected []string
+	}{
+		{
+			name:     "own-tolerations",
+			service:  dev.Services[0],
+			expected: []string{"gpu"},
+		},
+		{
+			name:     "inherited-tolerations",
+			service:  dev.Services[1],
+			expected: []string{"dedicated"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &model.Translation{
+				Name:       dev.Name,
+				Deployment: tt.service.GevSandbox(),
+				Rules:      []*model.TranslationRule{tt.service.ToTranslationRule(dev)},
+			}
+
+			if err := translate(tr, nil, false); err != nil {
+				t.Fatal(err)
+			}
+
+			keys := []string{}
+			for _, toleration := range tr.Deployment.Spec.Template.Spec.Tolerations {
+				keys = append(keys, toleration.Key)
+			}
+
+			if !reflect.DeepEqual(keys, tt.expected) {
+				t.Errorf("wrong tolerations. Expected %v, got %v", tt.expected, keys)
+			}
+		})
+	}
+}
+
+func Test_translateInitContainer(t *testing.T) {
+	manifest := []byte(`name: web
+container: dev
+image: web:latest
+initContainer:
+  image: registry.local/okteto/bin:1.2.14
+  resources:
+    requests:
+      cpu: 10m
+      memory: 16Mi
+    limits:
+      cpu: 100m
+      memory: 64Mi`)
+
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Deployment:  dev.GevSandbox(),
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	initContainers := tr.Deployment.Spec.Template.Spec.InitContainers
+	if len(initContainers) != 1 {
+		t.Fatalf("expected 1 init container, got %d", len(initContainers))
+	}
+
+	c := initContainers[0]
+	if c.Image != "registry.local/okteto/bin:1.2.14" {
+		t.Errorf("wrong init container image: %s", c.Image)
+	}
+
+	if c.Resources.Requests.Cpu().String() != "10m" || c.Resources.Requests.Memory().String() != "16Mi" {
+		t.Errorf("wrong init container requests: %v", c.Resources.Requests)
+	}
+
+	if c.Resources.Limits.Cpu().String() != "100m" || c.Resources.Limits.Memory().String() != "64Mi" {
+		t.Errorf("wrong init container limits: %v", c.Resources.Limits)
+	}
+}
+
+func Test_translateExcludeContainers(t *testing.T) {
+	dev, err := model.Read([]byte(`name: web
+container: dev
+image: web:latest`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newTranslation := func(exclude []string) *model.Translation {
+		d := dev.GevSandbox()
+		d.Spec.Template.Spec.Containers = []apiv1.Container{
+			{Name: "dev", Image: "web:prod"},
+			{Name: "istio-proxy", Image: "istio/proxyv2"},
+		}
+		d.Spec.Template.Spec.InitContainers = []apiv1.Container{
+			{Name: "vault-agent-init", Image: "vault"},
+		}
+		return &model.Translation{
+			Name:              dev.Name,
+			Deployment:        d,
+			ExcludeContainers: exclude,
+			Rules:             []*model.TranslationRule{{Container: "dev"}},
+		}
+	}
+
+	tr := newTranslation([]string{"istio-proxy", "vault-agent-init"})
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := tr.Deployment.Spec.Template.Spec
+	if len(spec.Containers) != 1 || spec.Containers[0].Name != "dev" {
+		t.Errorf("sidecar wasn't removed: %+v", spec.Containers)
+	}
+
+	if len(spec.InitContainers) != 0 {
+		t.Errorf("init container wasn't removed: %+v", spec.InitContainers)
+	}
+
+	annotations := tr.Deployment.Spec.Template.Annotations
+	if annotations["sidecar.istio.io/inject"] != "false" || annotations["vault.hashicorp.com/agent-inject"] != "false" {
+		t.Errorf("sidecar injection wasn't disabled: %v", annotations)
+	}
+	if _, ok := annotations["linkerd.io/inject"]; ok {
+		t.Errorf("injection of a sidecar that wasn't excluded was disabled: %v", annotations)
+	}
+
+	tr = newTranslation([]string{"linkerd-proxy"})
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if tr.Deployment.Spec.Template.Annotations["linkerd.io/inject"] != "disabled" {
+		t.Errorf("linkerd injection wasn't disabled: %v", tr.Deployment.Spec.Template.Annotations)
+	}
+
+	tr = newTranslation([]string{"dev"})
+	if err := translate(tr, nil, false); err == nil {
+		t.Fatal("the development container was excluded")
+	}
+}
+
+func Test_translatePriorityClassName(t *testing.T) {
+	dev, err := model.Read([]byte(`name: web
+image: web:latest
+priorityClassName: high-priority`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var priority int32 = 10
+	d := dev.GevSandbox()
+	d.Spec.Template.Spec.PriorityClassName = "low-priority"
+	d.Spec.Template.Spec.Priority = &priority
+	tr := &model.Translation{
+		Interactive:       true,
+		Name:              dev.Name,
+		Deployment:        d,
+		PriorityClassName: dev.PriorityClassName,
+		Rules:             []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := tr.Deployment.Spec.Template.Spec
+	if spec.PriorityClassName != "high-priority" {
+		t.Errorf("wrong priority class name: %s", spec.PriorityClassName)
+	}
+
+	if spec.Priority != nil {
+		t.Errorf("priority wasn't cleared: %d", *spec.Priority)
+	}
+}
+
+func Test_translatePriorityClassNameDevModeOff(t *testing.T) {
+	var priority int32 = 10
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		Spec: appsv1.DeploymentSpec{
+			Template: apiv1.PodTemplateSpec{
+				Spec: apiv1.PodSpec{PriorityClassName: "low-priority", Priority: &priority},
+			},
+		},
+	}
+	tr := &model.Translation{Name: "web", Deployment: d, PriorityClassName: "high-priority", Replicas: 1}
+	commonTranslation(tr)
+	if err := setTranslationAsAnnotation(tr.Deployment.Spec.Template.GetObjectMeta(), tr); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := TranslateDevModeOff(tr.Deployment)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spec := restored.Spec.Template.Spec
+	if spec.PriorityClassName != "low-priority" {
+		t.Errorf("priority class name wasn't restored: %s", spec.PriorityClassName)
+	}
+
+	if spec.Priority == nil || *spec.Priority != priority {
+		t.Errorf("priority wasn't restored: %v", spec.Priority)
+	}
+}
+
+func Test_translateServiceAccount(t *testing.T) {
+	dev, err := model.Read([]byte(`name: web
+image: web:latest
+serviceAccount: debugger`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := dev.GevSandbox()
+	d.Spec.Template.Spec.ServiceAccountName = "web"
+	d.Spec.Template.Spec.DeprecatedServiceAccount = "web"
+	tr := &model.Translation{
+		Interactive:    true,
+		Name:           dev.Name,
+		Deployment:     d,
+		ServiceAccount: dev.ServiceAccount,
+		Rules:          []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := tr.Deployment.Spec.Template.Spec
+	if spec.ServiceAccountName != "debugger" {
+		t.Errorf("wrong service account: %s", spec.ServiceAccountName)
+	}
+
+	if spec.DeprecatedServiceAccount != "" {
+		t.Errorf("deprecated service account wasn't cleared: %s", spec.DeprecatedServiceAccount)
+	}
+}
+
+func Test_translatePodLabels(t *testing.T) {
+	tests := []struct {
+		name      string
+		podLabels *model.PodLabels
+		expected  map[string]string
+	}{
+		{
+			name:      "strip",
+			podLabels: &model.PodLabels{Strip: []string{"argocd.argoproj.io/", "flagger", "app"}},
+			expected:  map[string]string{"app": "web", "team": "backend"},
+		},
+		{
+			name:      "keep",
+			podLabels: &model.PodLabels{Keep: []string{"team"}},
+			expected:  map[string]string{"app": "web", "team": "backend"},
+		},
+		{
+			name:      "none",
+			podLabels: nil,
+			expected:  map[string]string{"app": "web", "team": "backend", "argocd.argoproj.io/instance": "web", "flagger": "true"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labels := map[string]string{"app": "web", "team": "backend", "argocd.argoproj.io/instance": "web", "flagger": "true"}
+			d := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "web"},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+					Template: apiv1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: labels}},
+				},
+			}
+			tr := &model.Translation{Name: "web", Deployment: d, PodLabels: tt.podLabels, Replicas: 1}
+			commonTranslation(tr)
+
+			result := tr.Deployment.Spec.Template.Labels
+			delete(result, okLabels.DetachedDevLabel)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Fatalf("wrong pod labels. Expected %v, got %v", tt.expected, result)
+			}
+
+			if err := setTranslationAsAnnotation(tr.Deployment.Spec.Template.GetObjectMeta(), tr); err != nil {
+				t.Fatal(err)
+			}
+
+			restored, err := TranslateDevModeOff(tr.Deployment)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			original := map[string]string{"app": "web", "team": "backend", "argocd.argoproj.io/instance": "web", "flagger": "true"}
+			if !reflect.DeepEqual(restored.Spec.Template.Labels, original) {
+				t.Errorf("pod labels weren't restored. Expected %v, got %v", original, restored.Spec.Template.Labels)
+			}
+		})
+	}
+}
+
+func Test_translateSecurityPolicy(t *testing.T) {
+	dev, err := model.Read([]byte(`name: web
+image: web:latest
+securityPolicy: restricted
+sync:
+  - .:/app`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privileged := true
+	d := dev.GevSandbox()
+	d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, apiv1.Container{
+		Name:  "proxy",
+		Image: "proxy:1.0",
+		SecurityContext: &apiv1.SecurityContext{
+			Privileged:   &privileged,
+			Capabilities: &apiv1.Capabilities{Add: []apiv1.Capability{"NET_ADMIN", "NET_BIND_SERVICE"}},
+		},
+	})
+	tr := &model.Translation{
+		Interactive:    true,
+		Name:           dev.Name,
+		Deployment:     d,
+		SecurityPolicy: dev.SecurityPolicy,
+		Rules:          []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := tr.Deployment.Spec.Template.Spec
+	if spec.SecurityContext == nil || spec.SecurityContext.RunAsNonRoot == nil || !*spec.SecurityContext.RunAsNonRoot {
+		t.Fatalf("pod isn't running as non root: %+v", spec.SecurityContext)
+	}
+
+	if len(spec.InitContainers) == 0 {
+		t.Fatal("the okteto init container wasn't added")
+	}
+
+	for _, c := range append(spec.InitContainers, spec.Containers...) {
+		s := c.SecurityContext
+		if s == nil || s.AllowPrivilegeEscalation == nil || *s.AllowPrivilegeEscalation {
+			t.Errorf("container '%s' allows privilege escalation", c.Name)
+			continue
+		}
+		if s.Privileged != nil && *s.Privileged {
+			t.Errorf("container '%s' is privileged", c.Name)
+		}
+		if s.RunAsUser != nil && *s.RunAsUser == 0 {
+			t.Errorf("container '%s' runs as root", c.Name)
+		}
+		if !reflect.DeepEqual(s.Capabilities.Drop, []apiv1.Capability{"ALL"}) {
+			t.Errorf("container '%s' doesn't drop all capabilities: %v", c.Name, s.Capabilities.Drop)
+		}
+		for _, capability := range s.Capabilities.Add {
+			if capability != "NET_BIND_SERVICE" {
+				t.Errorf("container '%s' adds capability %s", c.Name, capability)
+			}
+		}
+	}
+
+	data, err := json.Marshal(tr.Deployment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err = setSeccompProfile(tr.Deployment, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"seccompProfile":{"type":"RuntimeDefault"}`) {
+		t.Errorf("seccomp profile wasn't set: %s", string(data))
+	}
+}
+
+func Test_getServerSideIgnoredFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		tr       *model.Translation
+		expected []string
+	}{
+		{
+			name:     "none",
+			tr:       &model.Translation{},
+			expected: []string{},
+		},
+		{
+			name:     "security-policy",
+			tr:       &model.Translation{SecurityPolicy: model.SecurityPolicyRestricted},
+			expected: []string{"securityPolicy"},
+		},
+		{
+			name: "scheduling",
+			tr: &model.Translation{
+				NodeSelector:      map[string]string{"disktype": "ssd"},
+				Affinity:          &apiv1.Affinity{},
+				ServiceAccount:    "debugger",
+				ExcludeContainers: []string{"proxy"},
+			},
+			expected: []string{"nodeSelector", "affinity", "serviceAccount", "excludeContainers"},
+		},
+		{
+			name: "probes-and-lifecycle",
+			tr: &model.Translation{
+				Rules: []*model.TranslationRule{
+					{Probes: &model.Probes{Liveness: &model.ProbeOverride{}}},
+					{Lifecycle: &model.Lifecycle{PostStart: &model.LifecycleOverride{}}},
+				},
+			},
+			expected: []string{"probes", "lifecycle"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := getServerSideIgnoredFields(tt.tr)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("wrong ignored fields. Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
 func TestTranslateOktetoVolumes(t *testing.T) {
 	var tests = []struct {
 		name     string
@@ -897,3 +1411,98 @@ func TestTranslateOktetoVolumes(t *testing.T) {
 		})
 	}
 }
+
+func Test_translateProbesAndLifecycle(t *testing.T) {
+	dev, err := model.Read([]byte(`name: web
+image: web:latest
+probes:
+  liveness: false
+  readiness:
+    periodSeconds: 60
+    failureThreshold: 10
+  startup:
+    tcpSocket:
+      port: 8080
+lifecycle:
+  preStop: false
+  postStart:
+    exec:
+      command: ["sh", "-c", "echo started"]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	httpGet := apiv1.Handler{HTTPGet: &apiv1.HTTPGetAction{Path: "/healthz"}}
+	d := dev.GevSandbox()
+	c := &d.Spec.Template.Spec.Containers[0]
+	c.LivenessProbe = &apiv1.Probe{Handler: httpGet, PeriodSeconds: 5}
+	c.ReadinessProbe = &apiv1.Probe{Handler: httpGet, PeriodSeconds: 5, FailureThreshold: 3, TimeoutSeconds: 2}
+	c.Lifecycle = &apiv1.Lifecycle{
+		PostStart: &apiv1.Handler{Exec: &apiv1.ExecAction{Command: []string{"init"}}},
+		PreStop:   &apiv1.Handler{Exec: &apiv1.ExecAction{Command: []string{"drain"}}},
+	}
+	original := d.DeepCopy()
+
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Deployment:  d,
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	c = &tr.Deployment.Spec.Template.Spec.Containers[0]
+	if c.LivenessProbe != nil {
+		t.Errorf("liveness probe wasn't removed: %+v", c.LivenessProbe)
+	}
+	expectedReadiness := &apiv1.Probe{Handler: httpGet, PeriodSeconds: 60, FailureThreshold: 10, TimeoutSeconds: 2}
+	if !reflect.DeepEqual(c.ReadinessProbe, expectedReadiness) {
+		t.Errorf("readiness probe wasn't relaxed: %+v", c.ReadinessProbe)
+	}
+	if c.StartupProbe == nil || c.StartupProbe.TCPSocket == nil || c.StartupProbe.TCPSocket.Port.IntValue() != 8080 {
+		t.Errorf("startup probe wasn't replaced: %+v", c.StartupProbe)
+	}
+	if c.Lifecycle == nil || c.Lifecycle.PreStop != nil || c.Lifecycle.PostStart == nil || c.Lifecycle.PostStart.Exec.Command[2] != "echo started" {
+		t.Errorf("lifecycle hooks weren't translated: %+v", c.Lifecycle)
+	}
+
+	restored, err := TranslateDevModeOff(tr.Deployment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c = &restored.Spec.Template.Spec.Containers[0]
+	o := &original.Spec.Template.Spec.Containers[0]
+	if !reflect.DeepEqual(c.LivenessProbe, o.LivenessProbe) || !reflect.DeepEqual(c.ReadinessProbe, o.ReadinessProbe) || !reflect.DeepEqual(c.Lifecycle, o.Lifecycle) {
+		t.Errorf("probes and lifecycle hooks weren't restored: %+v %+v %+v", c.LivenessProbe, c.ReadinessProbe, c.Lifecycle)
+	}
+}
+
+func Test_translateProbe(t *testing.T) {
+	probe := &apiv1.Probe{Handler: apiv1.Handler{Exec: &apiv1.ExecAction{Command: []string{"check"}}}}
+	tests := []struct {
+		name         string
+		probe        *apiv1.Probe
+		override     *model.ProbeOverride
+		healthchecks bool
+		expected     *apiv1.Probe
+	}{
+		{name: "default", probe: probe, expected: nil},
+		{name: "healthchecks", probe: probe, healthchecks: true, expected: probe},
+		{name: "enabled", probe: probe, override: &model.ProbeOverride{Enabled: true}, expected: probe},
+		{name: "disabled", probe: probe, override: &model.ProbeOverride{}, healthchecks: true, expected: nil},
+		{
+			name:     "relax-missing-probe",
+			override: &model.ProbeOverride{Enabled: true, Probe: &apiv1.Probe{PeriodSeconds: 60}},
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := translateProbe(tt.probe, tt.override, tt.healthchecks); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/okteto/okteto/pkg/k8s/labels"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	oomKilledReason = "OOMKilled"
+
+	involvedObjectKind = "involvedObject.kind"
+	involvedObjectName = "involvedObject.name"
+)
+
+// Stream sends to handler the new events of the deployment, its replicasets and its pods until ctx is done.
+// Containers of the dev pods killed by running out of memory are reported as 'OOMKilled' warning events.
+func Stream(ctx context.Context, dev *model.Dev, deployment string, c kubernetes.Interface, handler func(*apiv1.Event)) error {
+	deploymentEvents, err := watchEvents(ctx, dev.Namespace, fields.Set{involvedObjectKind: "Deployment", involvedObjectName: deployment}, c)
+	if err != nil {
+		return err
+	}
+	defer deploymentEvents.Stop()
+
+	replicaSetEvents, err := watchEvents(ctx, dev.Namespace, fields.Set{involvedObjectKind: "ReplicaSet"}, c)
+	if err != nil {
+		return err
+	}
+	defer replicaSetEvents.Stop()
+
+	podEvents, err := watchEvents(ctx, dev.Namespace, fields.Set{involvedObjectKind: "Pod"}, c)
+	if err != nil {
+		return err
+	}
+	defer podEvents.Stop()
+
+	pClient := c.CoreV1().Pods(dev.Namespace)
+	selector := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", labels.InteractiveDevLabel, dev.Name)}
+	pList, err := pClient.List(ctx, selector)
+	if err != nil {
+		return fmt.Errorf("failed to list the dev pods of %s: %s", dev.Name, err)
+	}
+
+	restarts := map[string]int32{}
+	for i := range pList.Items {
+		for _, s := range pList.Items[i].Status.ContainerStatuses {
+			restarts[containerKey(&pList.Items[i], s.Name)] = s.RestartCount
+		}
+	}
+
+	selector.ResourceVersion = pList.ResourceVersion
+	watchPods, err := pClient.Watch(ctx, selector)
+	if err != nil {
+		return fmt.Errorf("failed to watch the dev pods of %s: %s", dev.Name, err)
+	}
+	defer watchPods.Stop()
+
+	r := newRelatedObjects(dev, deployment, c)
+	for {
+		var event watch.Event
+		var ok bool
+		select {
+		case event, ok = <-deploymentEvents.ResultChan():
+		case event, ok = <-replicaSetEvents.ResultChan():
+		case event, ok = <-podEvents.ResultChan():
+		case podEvent, podOK := <-watchPods.ResultChan():
+			if !podOK {
+				return nil
+			}
+
+			if p, isPod := podEvent.Object.(*apiv1.Pod); isPod {
+				for _, e := range getOOMKilledEvents(p, restarts) {
+					handler(e)
+				}
+			}
+			continue
+		case <-ctx.Done():
+			log.Debug("call to events.Stream cancelled")
+			return nil
+		}
+
+		if !ok {
+			return nil
+		}
+
+		e, isEvent := event.Object.(*apiv1.Event)
+		if !isEvent || event.Type != watch.Added {
+			continue
+		}
+
+		if r.isRelated(ctx, e.InvolvedObject) {
+			handler(e)
+		}
+	}
+}
+
+// watchEvents watches the new events of the involved objects that match selector
+func watchEvents(ctx context.Context, namespace string, selector fields.Set, c kubernetes.Interface) (watch.Interface, error) {
+	opts := metav1.ListOptions{FieldSelector: selector.AsSelector().String()}
+	eClient := c.CoreV1().Events(namespace)
+	eList, err := eClient.List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the events of namespace %s: %s", namespace, err)
+	}
+
+	opts.ResourceVersion = eList.ResourceVersion
+	w, err := eClient.Watch(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch the events of namespace %s: %s", namespace, err)
+	}
+	return w, nil
+}
+
+// relatedObjects tells if objects belong to the deployment of a development container, by their owners and labels
+type relatedObjects struct {
+	dev        string
+	deployment string
+	namespace  string
+	c          kubernetes.Interface
+	cache      map[types.UID]bool
+}
+
+func newRelatedObjects(dev *model.Dev, deployment string, c kubernetes.Interface) *relatedObjects {
+	return &relatedObjects{
+		dev:        dev.Name,
+		deployment: deployment,
+		namespace:  dev.Namespace,
+		c:          c,
+		cache:      map[types.UID]bool{},
+	}
+}
+
+// isRelated returns true if o is the deployment, one of its replicasets or one of its pods
+func (r *relatedObjects) isRelated(ctx context.Context, o apiv1.ObjectReference) bool {
+	switch o.Kind {
+	case "Deployment":
+		return o.Name == r.deployment
+	case "ReplicaSet", "Pod":
+	default:
+		return false
+	}
+
+	if related, ok := r.cache[o.UID]; ok && o.UID != "" {
+		return related
+	}
+
+	related := r.isOwned(ctx, o)
+	if o.UID != "" {
+		r.cache[o.UID] = related
+	}
+	return related
+}
+
+func (r *relatedObjects) isOwned(ctx context.Context, o apiv1.ObjectReference) bool {
+	if o.Kind == "ReplicaSet" {
+		rs, err := r.c.AppsV1().ReplicaSets(r.namespace).Get(ctx, o.Name, metav1.GetOptions{})
+		if err != nil {
+			log.Debugf("failed to get the owner of replicaset %s: %s", o.Name, err)
+			return false
+		}
+		for _, ref := range rs.OwnerReferences {
+			if ref.Kind == "Deployment" && ref.Name == r.deployment {
+				return true
+			}
+		}
+		return false
+	}
+
+	p, err := r.c.CoreV1().Pods(r.namespace).Get(ctx, o.Name, metav1.GetOptions{})
+	if err != nil {
+		log.Debugf("failed to get the owner of pod %s: %s", o.Name, err)
+		return false
+	}
+	if p.Labels[labels.InteractiveDevLabel] == r.dev {
+		return true
+	}
+	for _, ref := range p.OwnerReferences {
+		if ref.Kind == "ReplicaSet" {
+			return r.isRelated(ctx, apiv1.ObjectReference{Kind: ref.Kind, Name: ref.Name, UID: ref.UID})
+		}
+	}
+	return false
+}
+
+func getOOMKilledEvents(p *apiv1.Pod, restarts map[string]int32) []*apiv1.Event {
+	result := []*apiv1.Event{}
+	for _, s := range p.Status.ContainerStatuses {
+		key := containerKey(p, s.Name)
+		previous, seen := restarts[key]
+		restarts[key] = s.RestartCount
+		if !seen || s.RestartCount <= previous {
+			continue
+		}
+
+		if s.LastTerminationState.Terminated == nil || s.LastTerminationState.Terminated.Reason != oomKilledReason {
+			continue
+		}
+
+		result = append(result, &apiv1.Event{
+			InvolvedObject: apiv1.ObjectReference{Kind: "Pod", Name: p.Name, Namespace: p.Namespace},
+			Type:           apiv1.EventTypeWarning,
+			Reason:         oomKilledReason,
+			Message:        fmt.Sprintf("container '%s' was killed because it ran out of memory", s.Name),
+		})
+	}
+
+	return result
+}
+
+func containerKey(p *apiv1.Pod, container string) string {
+	return fmt.Sprintf("%s/%s", p.Name, container)
+}
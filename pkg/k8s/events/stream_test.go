@@ -0,0 +1,100 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/k8s/labels"
+	"github.com/okteto/okteto/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_isRelated(t *testing.T) {
+	owner := func(kind, name string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{Kind: kind, Name: name}}
+	}
+	c := fake.NewSimpleClientset(
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "api-5d8f7c", Namespace: "test", OwnerReferences: owner("Deployment", "api")}},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "api-gateway-5d8f7c", Namespace: "test", OwnerReferences: owner("Deployment", "api-gateway")}},
+		&apiv1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api-5d8f7c-x2x9z", Namespace: "test", OwnerReferences: owner("ReplicaSet", "api-5d8f7c")}},
+		&apiv1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api-gateway-5d8f7c-x2x9z", Namespace: "test", OwnerReferences: owner("ReplicaSet", "api-gateway-5d8f7c")}},
+		&apiv1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "dev-pod", Namespace: "test", Labels: map[string]string{labels.InteractiveDevLabel: "api"}}},
+	)
+	r := newRelatedObjects(&model.Dev{Name: "api", Namespace: "test"}, "api", c)
+
+	var tests = []struct {
+		kind     string
+		name     string
+		expected bool
+	}{
+		{kind: "Deployment", name: "api", expected: true},
+		{kind: "Deployment", name: "api-gateway", expected: false},
+		{kind: "ReplicaSet", name: "api-5d8f7c", expected: true},
+		{kind: "ReplicaSet", name: "api-gateway-5d8f7c", expected: false},
+		{kind: "Pod", name: "api-5d8f7c-x2x9z", expected: true},
+		{kind: "Pod", name: "api-gateway-5d8f7c-x2x9z", expected: false},
+		{kind: "Pod", name: "dev-pod", expected: true},
+		{kind: "Pod", name: "deleted", expected: false},
+		{kind: "Node", name: "api-node", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind+"/"+tt.name, func(t *testing.T) {
+			o := apiv1.ObjectReference{Kind: tt.kind, Name: tt.name}
+			if got := r.isRelated(context.Background(), o); got != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, got)
+			}
+		})
+	}
+}
+
+func Test_getOOMKilledEvents(t *testing.T) {
+	pod := &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-5d8f7c-x2x9z"},
+		Status: apiv1.PodStatus{
+			ContainerStatuses: []apiv1.ContainerStatus{{Name: "api"}},
+		},
+	}
+	restarts := map[string]int32{}
+
+	if e := getOOMKilledEvents(pod, restarts); len(e) != 0 {
+		t.Fatalf("unexpected events for a new pod: %v", e)
+	}
+
+	pod.Status.ContainerStatuses[0].RestartCount = 1
+	pod.Status.ContainerStatuses[0].LastTerminationState.Terminated = &apiv1.ContainerStateTerminated{Reason: "Error"}
+	if e := getOOMKilledEvents(pod, restarts); len(e) != 0 {
+		t.Fatalf("unexpected events for a container that failed: %v", e)
+	}
+
+	pod.Status.ContainerStatuses[0].RestartCount = 2
+	pod.Status.ContainerStatuses[0].LastTerminationState.Terminated = &apiv1.ContainerStateTerminated{Reason: oomKilledReason}
+	e := getOOMKilledEvents(pod, restarts)
+	if len(e) != 1 {
+		t.Fatalf("expected one event, got %d", len(e))
+	}
+
+	if e[0].Type != apiv1.EventTypeWarning || e[0].InvolvedObject.Name != pod.Name {
+		t.Errorf("wrong event: %+v", e[0])
+	}
+
+	if e := getOOMKilledEvents(pod, restarts); len(e) != 0 {
+		t.Fatalf("the same restart was reported twice: %v", e)
+	}
+}
@@ -92,6 +92,10 @@ func NewPortForwardManager(ctx context.Context, iface string, restConfig *rest.C
 
 // Add initializes a port forward
 func (p *PortForwardManager) Add(f model.Forward) error {
+	if f.Auto {
+		return fmt.Errorf("'auto' local ports are not supported when OKTETO_EXECUTE_SSH is set to false")
+	}
+
 	if _, ok := p.ports[f.Local]; ok {
 		return fmt.Errorf("port %d is listed multiple times, please check your configuration", f.Local)
 	}
@@ -0,0 +1,61 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forward
+
+import (
+	"fmt"
+
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// ForwardPodPort forwards a random local port to a port of a pod, and returns the local port and the function that stops the forward
+func ForwardPodPort(restConfig *rest.Config, c kubernetes.Interface, namespace, pod string, remotePort int) (int, func(), error) {
+	p := &PortForwardManager{
+		iface:      model.Localhost,
+		restConfig: restConfig,
+		client:     c,
+	}
+
+	a, pf, err := p.buildForwarder(pod, namespace, pod, []string{fmt.Sprintf("0:%d", remotePort)})
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to forward to pod '%s': %w", pod, err)
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- pf.ForwardPorts()
+	}()
+
+	select {
+	case <-a.readyChan:
+	case err := <-errChan:
+		if err == nil {
+			err = fmt.Errorf("port forward finished")
+		}
+		log.Infof("port forward to pod %s/%s failed: %s", namespace, pod, a.out.String())
+		return 0, nil, fmt.Errorf("failed to forward to pod '%s': %w", pod, err)
+	}
+
+	ports, err := pf.GetPorts()
+	if err != nil || len(ports) == 0 {
+		a.stop()
+		return 0, nil, fmt.Errorf("failed to get the local port forwarded to pod '%s': %v", pod, err)
+	}
+
+	log.Infof("forwarding localhost:%d to %s/%s:%d", ports[0].Local, namespace, pod, remotePort)
+	return int(ports[0].Local), a.stop, nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hpas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	okLabels "github.com/okteto/okteto/pkg/k8s/labels"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	pinnedByAnnotation = "dev.okteto.com/hpa-pinned-by"
+	originalAnnotation = "dev.okteto.com/hpa-original"
+)
+
+var devReplicas int32 = 1
+
+// original are the fields of the horizontal pod autoscaler changed by okteto
+type original struct {
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	MaxReplicas int32  `json:"maxReplicas"`
+}
+
+//Pin sets to one replica the horizontal pod autoscalers of the deployments while the development container is active.
+//It returns the names of the pinned horizontal pod autoscalers.
+func Pin(ctx context.Context, dev *model.Dev, deployments []*appsv1.Deployment, c kubernetes.Interface) ([]string, error) {
+	pinned := []string{}
+	for _, namespace := range model.GetDeploymentsNamespaces(deployments) {
+		hpaClient := c.AutoscalingV1().HorizontalPodAutoscalers(namespace)
+		list, err := hpaClient.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			log.Infof("failed to list the horizontal pod autoscalers of %s: %s", namespace, err)
+			continue
+		}
+
+		for i := range list.Items {
+			hpa := &list.Items[i]
+			if _, ok := hpa.Annotations[pinnedByAnnotation]; ok {
+				continue
+			}
+
+			if !targetsAny(hpa, deployments) {
+				continue
+			}
+
+			if err := pin(hpa, dev); err != nil {
+				return pinned, err
+			}
+
+			if _, err := hpaClient.Update(ctx, hpa, metav1.UpdateOptions{FieldManager: okLabels.FieldManager}); err != nil {
+				return pinned, fmt.Errorf("failed to pin the horizontal pod autoscaler '%s': %s", hpa.Name, err)
+			}
+
+			log.Infof("pinned horizontal pod autoscaler '%s/%s'", hpa.Namespace, hpa.Name)
+			pinned = append(pinned, getDisplayName(hpa, dev))
+		}
+	}
+
+	return pinned, nil
+}
+
+//Restore restores the horizontal pod autoscalers pinned by the development container
+func Restore(ctx context.Context, dev *model.Dev, c kubernetes.Interface) error {
+	for _, namespace := range dev.GetNamespaces() {
+		hpaClient := c.AutoscalingV1().HorizontalPodAutoscalers(namespace)
+		list, err := hpaClient.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			log.Infof("failed to list the horizontal pod autoscalers of %s: %s", namespace, err)
+			continue
+		}
+
+		for i := range list.Items {
+			hpa := &list.Items[i]
+			if hpa.Annotations[pinnedByAnnotation] != dev.Name {
+				continue
+			}
+
+			if err := restore(hpa); err != nil {
+				return err
+			}
+
+			if _, err := hpaClient.Update(ctx, hpa, metav1.UpdateOptions{FieldManager: okLabels.FieldManager}); err != nil {
+				return fmt.Errorf("failed to restore the horizontal pod autoscaler '%s': %s", hpa.Name, err)
+			}
+
+			log.Infof("restored horizontal pod autoscaler '%s/%s'", hpa.Namespace, hpa.Name)
+		}
+	}
+
+	return nil
+}
+
+func targetsAny(hpa *autoscalingv1.HorizontalPodAutoscaler, deployments []*appsv1.Deployment) bool {
+	if hpa.Spec.ScaleTargetRef.Kind != "Deployment" {
+		return false
+	}
+
+	for _, d := range deployments {
+		if hpa.Namespace == d.Namespace && hpa.Spec.ScaleTargetRef.Name == d.Name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getDisplayName returns the name of the horizontal pod autoscaler, qualified by its namespace
+// if it isn't the namespace of the development container
+func getDisplayName(hpa *autoscalingv1.HorizontalPodAutoscaler, dev *model.Dev) string {
+	if hpa.Namespace == dev.Namespace {
+		return hpa.Name
+	}
+	return fmt.Sprintf("%s/%s", hpa.Namespace, hpa.Name)
+}
+
+func pin(hpa *autoscalingv1.HorizontalPodAutoscaler, dev *model.Dev) error {
+	o, err := json.Marshal(original{MinReplicas: hpa.Spec.MinReplicas, MaxReplicas: hpa.Spec.MaxReplicas})
+	if err != nil {
+		return err
+	}
+
+	if hpa.Annotations == nil {
+		hpa.Annotations = map[string]string{}
+	}
+	hpa.Annotations[pinnedByAnnotation] = dev.Name
+	hpa.Annotations[originalAnnotation] = string(o)
+
+	min := devReplicas
+	hpa.Spec.MinReplicas = &min
+	hpa.Spec.MaxReplicas = devReplicas
+	return nil
+}
+
+func restore(hpa *autoscalingv1.HorizontalPodAutoscaler) error {
+	o := original{}
+	if err := json.Unmarshal([]byte(hpa.Annotations[originalAnnotation]), &o); err != nil {
+		return fmt.Errorf("malformed horizontal pod autoscaler annotation in '%s': %s", hpa.Name, err)
+	}
+
+	hpa.Spec.MinReplicas = o.MinReplicas
+	hpa.Spec.MaxReplicas = o.MaxReplicas
+	delete(hpa.Annotations, pinnedByAnnotation)
+	delete(hpa.Annotations, originalAnnotation)
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hpas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newHPA(name, kind, target string, min, max int32) *autoscalingv1.HorizontalPodAutoscaler {
+	return &autoscalingv1.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		Spec: autoscalingv1.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv1.CrossVersionObjectReference{Kind: kind, Name: target},
+			MinReplicas:    &min,
+			MaxReplicas:    max,
+		},
+	}
+}
+
+func TestPinAndRestore(t *testing.T) {
+	ctx := context.Background()
+	dev := &model.Dev{Name: "api", Namespace: "test"}
+	d := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "test"}}
+
+	c := fake.NewSimpleClientset(
+		newHPA("api", "Deployment", "api", 2, 10),
+		newHPA("db", "Deployment", "db", 1, 3),
+		newHPA("api-set", "StatefulSet", "api", 1, 3),
+	)
+
+	pinned, err := Pin(ctx, dev, []*appsv1.Deployment{d}, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pinned) != 1 || pinned[0] != "api" {
+		t.Fatalf("expected only 'api' to be pinned, got %v", pinned)
+	}
+
+	hpaClient := c.AutoscalingV1().HorizontalPodAutoscalers("test")
+	hpa, err := hpaClient.Get(ctx, "api", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *hpa.Spec.MinReplicas != 1 || hpa.Spec.MaxReplicas != 1 {
+		t.Errorf("hpa wasn't pinned: min=%d max=%d", *hpa.Spec.MinReplicas, hpa.Spec.MaxReplicas)
+	}
+
+	pinned, err = Pin(ctx, dev, []*appsv1.Deployment{d}, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pinned) != 0 {
+		t.Fatalf("hpa pinned twice: %v", pinned)
+	}
+
+	if err := Restore(ctx, dev, c); err != nil {
+		t.Fatal(err)
+	}
+
+	hpa, err = hpaClient.Get(ctx, "api", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *hpa.Spec.MinReplicas != 2 || hpa.Spec.MaxReplicas != 10 {
+		t.Errorf("hpa wasn't restored: min=%d max=%d", *hpa.Spec.MinReplicas, hpa.Spec.MaxReplicas)
+	}
+
+	if _, ok := hpa.Annotations[pinnedByAnnotation]; ok {
+		t.Errorf("pinned annotation wasn't removed: %v", hpa.Annotations)
+	}
+}
+
+func TestPinAndRestoreInOtherNamespaces(t *testing.T) {
+	ctx := context.Background()
+	dev := &model.Dev{
+		Name:      "api",
+		Namespace: "test",
+		Services:  []*model.Dev{{Name: "worker", Namespace: "shared"}},
+	}
+	ds := []*appsv1.Deployment{
+		{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "test"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "shared"}},
+	}
+
+	shared := newHPA("worker", "Deployment", "worker", 2, 10)
+	shared.Namespace = "shared"
+	sameName := newHPA("worker", "Deployment", "worker", 2, 10)
+	c := fake.NewSimpleClientset(shared, sameName)
+
+	pinned, err := Pin(ctx, dev, ds, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pinned) != 1 || pinned[0] != "shared/worker" {
+		t.Fatalf("expected only 'shared/worker' to be pinned, got %v", pinned)
+	}
+
+	hpa, err := c.AutoscalingV1().HorizontalPodAutoscalers("test").Get(ctx, "worker", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hpa.Spec.MaxReplicas != 10 {
+		t.Errorf("hpa of a deployment with the same name in another namespace was pinned")
+	}
+
+	if err := Restore(ctx, dev, c); err != nil {
+		t.Fatal(err)
+	}
+
+	hpa, err = c.AutoscalingV1().HorizontalPodAutoscalers("shared").Get(ctx, "worker", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *hpa.Spec.MinReplicas != 2 || hpa.Spec.MaxReplicas != 10 {
+		t.Errorf("hpa wasn't restored: min=%d max=%d", *hpa.Spec.MinReplicas, hpa.Spec.MaxReplicas)
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package informers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/okteto/okteto/pkg/log"
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8sInformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+)
+
+const (
+	//Deployments is the kind used to invalidate cached deployments
+	Deployments = "deployments"
+
+	//Pods is the kind used to invalidate cached pods
+	Pods = "pods"
+
+	syncTimeout = 10 * time.Second
+
+	// writeTimeout is the time after which a write not observed by the informers, most likely because the object
+	// was written again by someone else, no longer bypasses the cache
+	writeTimeout = 10 * time.Second
+)
+
+// namespaceCache keeps the deployments and pods of a namespace in sync with the API server while it is running
+type namespaceCache struct {
+	namespace   string
+	cancel      context.CancelFunc
+	deployments appslisters.DeploymentLister
+	pods        corelisters.PodLister
+
+	// written keeps the objects written by okteto that the informers haven't observed yet
+	mu      sync.Mutex
+	written map[cacheKey]write
+}
+
+type cacheKey struct {
+	kind string
+	name string
+}
+
+// write is a write to an object. An empty resource version means the object was deleted.
+type write struct {
+	resourceVersion string
+	at              time.Time
+}
+
+var (
+	mu      sync.RWMutex
+	current *namespaceCache
+)
+
+//Start starts the informers for the deployments and pods of namespace, replacing any previous cache.
+//Reads fall back to the API server if the informers don't sync in time.
+func Start(ctx context.Context, namespace string, c kubernetes.Interface) error {
+	Stop()
+
+	ctx, cancel := context.WithCancel(ctx)
+	factory := k8sInformers.NewSharedInformerFactoryWithOptions(c, 0, k8sInformers.WithNamespace(namespace))
+	deployments := factory.Apps().V1().Deployments()
+	pods := factory.Core().V1().Pods()
+	deployments.Informer()
+	pods.Informer()
+	factory.Start(ctx.Done())
+
+	syncCtx, syncCancel := context.WithTimeout(ctx, syncTimeout)
+	defer syncCancel()
+	for informer, ok := range factory.WaitForCacheSync(syncCtx.Done()) {
+		if !ok {
+			cancel()
+			return fmt.Errorf("failed to sync the %s cache of namespace %s", informer, namespace)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	current = &namespaceCache{
+		namespace:   namespace,
+		cancel:      cancel,
+		deployments: deployments.Lister(),
+		pods:        pods.Lister(),
+		written:     map[cacheKey]write{},
+	}
+	log.Infof("started the informer cache of namespace %s", namespace)
+	return nil
+}
+
+//Stop stops the informers of the current cache
+func Stop() {
+	mu.Lock()
+	defer mu.Unlock()
+	if current == nil {
+		return
+	}
+
+	current.cancel()
+	log.Infof("stopped the informer cache of namespace %s", current.namespace)
+	current = nil
+}
+
+//Invalidate records a write to an object, so it is read from the API server until the informers observe resourceVersion.
+//Use an empty resourceVersion for deleted objects.
+func Invalidate(kind, namespace, name, resourceVersion string) {
+	nc := get(namespace)
+	if nc == nil {
+		return
+	}
+
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	nc.written[cacheKey{kind: kind, name: name}] = write{resourceVersion: resourceVersion, at: time.Now()}
+}
+
+//GetDeployment returns a copy of the cached deployment. The second value is false if the deployment isn't cached or the cache can't serve the request.
+func GetDeployment(namespace, name string) (*appsv1.Deployment, bool) {
+	nc := get(namespace)
+	if nc == nil || !nc.isObserved(Deployments, name) {
+		return nil, false
+	}
+
+	d, err := nc.deployments.Deployments(namespace).Get(name)
+	if err != nil {
+		return nil, false
+	}
+
+	return d.DeepCopy(), true
+}
+
+//ListDeployments returns a copy of the cached deployments matching selector. The second value is false if the cache can't serve the request.
+func ListDeployments(namespace string, selector labels.Selector) ([]appsv1.Deployment, bool) {
+	nc := get(namespace)
+	if nc == nil || !nc.isKindObserved(Deployments) {
+		return nil, false
+	}
+
+	ds, err := nc.deployments.Deployments(namespace).List(selector)
+	if err != nil {
+		return nil, false
+	}
+
+	result := make([]appsv1.Deployment, 0, len(ds))
+	for _, d := range ds {
+		result = append(result, *d.DeepCopy())
+	}
+	return result, true
+}
+
+//ListPods returns a copy of the cached pods matching selector. The second value is false if the cache can't serve the request.
+func ListPods(namespace string, selector labels.Selector) ([]apiv1.Pod, bool) {
+	nc := get(namespace)
+	if nc == nil || !nc.isKindObserved(Pods) {
+		return nil, false
+	}
+
+	ps, err := nc.pods.Pods(namespace).List(selector)
+	if err != nil {
+		return nil, false
+	}
+
+	result := make([]apiv1.Pod, 0, len(ps))
+	for _, p := range ps {
+		result = append(result, *p.DeepCopy())
+	}
+	return result, true
+}
+
+func get(namespace string) *namespaceCache {
+	mu.RLock()
+	defer mu.RUnlock()
+	if current == nil || current.namespace != namespace {
+		return nil
+	}
+	return current
+}
+
+// isObserved returns true if the informers already observed the last write of okteto to the object
+func (nc *namespaceCache) isObserved(kind, name string) bool {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	return nc.isObservedLocked(kind, name)
+}
+
+// isKindObserved returns true if the informers already observed all the writes of okteto to objects of kind
+func (nc *namespaceCache) isKindObserved(kind string) bool {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	for k := range nc.written {
+		if k.kind == kind && !nc.isObservedLocked(kind, k.name) {
+			return false
+		}
+	}
+	return true
+}
+
+func (nc *namespaceCache) isObservedLocked(kind, name string) bool {
+	k := cacheKey{kind: kind, name: name}
+	w, ok := nc.written[k]
+	if !ok {
+		return true
+	}
+
+	if time.Since(w.at) < writeTimeout {
+		cachedVersion, exists := nc.cachedResourceVersion(kind, name)
+		if w.resourceVersion == "" && exists || w.resourceVersion != "" && cachedVersion != w.resourceVersion {
+			return false
+		}
+	}
+
+	delete(nc.written, k)
+	return true
+}
+
+func (nc *namespaceCache) cachedResourceVersion(kind, name string) (string, bool) {
+	var obj interface {
+		GetResourceVersion() string
+	}
+	var err error
+	switch kind {
+	case Deployments:
+		obj, err = nc.deployments.Deployments(nc.namespace).Get(name)
+	case Pods:
+		obj, err = nc.pods.Pods(nc.namespace).Get(name)
+	default:
+		return "", false
+	}
+
+	if err != nil {
+		return "", false
+	}
+	return obj.GetResourceVersion(), true
+}
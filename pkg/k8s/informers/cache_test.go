@@ -0,0 +1,105 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package informers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "test", ResourceVersion: "1"},
+	}
+	p := &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "test", Labels: map[string]string{"app": "api"}},
+	}
+	c := fake.NewSimpleClientset(d, p)
+
+	if _, ok := GetDeployment("test", "api"); ok {
+		t.Fatal("deployment served before the cache was started")
+	}
+
+	if err := Start(ctx, "test", c); err != nil {
+		t.Fatal(err)
+	}
+	defer Stop()
+
+	cached, ok := GetDeployment("test", "api")
+	if !ok || cached.Name != "api" {
+		t.Fatalf("deployment not served from the cache: %+v", cached)
+	}
+
+	if _, ok := GetDeployment("other", "api"); ok {
+		t.Fatal("deployment of another namespace served from the cache")
+	}
+
+	pods, ok := ListPods("test", labels.SelectorFromSet(map[string]string{"app": "api"}))
+	if !ok || len(pods) != 1 {
+		t.Fatalf("pods not served from the cache: %+v", pods)
+	}
+
+	updated := d.DeepCopy()
+	updated.ResourceVersion = "2"
+	Invalidate(Deployments, "test", "api", "2")
+	if _, ok := GetDeployment("test", "api"); ok {
+		t.Fatal("deployment served from the cache before observing the write")
+	}
+	if _, ok := ListDeployments("test", labels.Everything()); ok {
+		t.Fatal("deployments listed from the cache before observing the write")
+	}
+
+	if _, err := c.AppsV1().Deployments("test").Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	waitUntil(t, func() bool {
+		cached, ok := GetDeployment("test", "api")
+		return ok && cached.ResourceVersion == "2"
+	})
+
+	if err := c.CoreV1().Pods("test").Delete(ctx, "api-1", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	Invalidate(Pods, "test", "api-1", "")
+
+	waitUntil(t, func() bool {
+		pods, ok := ListPods("test", labels.Everything())
+		return ok && len(pods) == 0
+	})
+
+	Stop()
+	if _, ok := GetDeployment("test", "api"); ok {
+		t.Fatal("deployment served after the cache was stopped")
+	}
+}
+
+func waitUntil(t *testing.T, f func() bool) {
+	t.Helper()
+	timeout := time.Now().Add(5 * time.Second)
+	for !f() {
+		if time.Now().After(timeout) {
+			t.Fatal("the cache didn't observe the write")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -17,6 +17,10 @@ const (
 	//Version represents the current dev data version
 	Version = "1.0"
 
+	// FieldManager is the field manager of all the writes of okteto. It doesn't include the okteto version,
+	// so server-side apply keeps recognizing the fields set by previous okteto versions as its own
+	FieldManager = "okteto-cli"
+
 	// TimeFormat is the format to use when storing timestamps as a string
 	TimeFormat = "2006-01-02T15:04:05"
 
@@ -0,0 +1,242 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leases
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/k8s/labels"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	leaseDuration = 60 * time.Second
+	renewInterval = 20 * time.Second
+	sinceFormat   = "Jan 2 15:04"
+)
+
+//ErrNotAvailable is returned when the leases API is not available to the user in the namespace of the development container
+var ErrNotAvailable = fmt.Errorf("leases are not available in your namespace")
+
+//GetName returns the name of the lease of a development container
+func GetName(dev *model.Dev) string {
+	return fmt.Sprintf("okteto-%s", dev.Name)
+}
+
+//Acquire takes the lease of the development container and the leases of its services in other namespaces, failing if someone else holds them
+func Acquire(ctx context.Context, dev *model.Dev, c kubernetes.Interface) error {
+	holder := getHolderIdentity()
+	now := time.Now()
+	var result error
+	for _, l := range getLeaseHolders(dev) {
+		if err := acquire(ctx, l, holder, now, c); err != nil {
+			if err != ErrNotAvailable {
+				return err
+			}
+			result = err
+		}
+	}
+	return result
+}
+
+// getLeaseHolders returns the development container and its services in other namespaces.
+// Services in the namespace of the development container are covered by its lease,
+// services in other namespaces take the lease that 'okteto up' takes for their deployment in that namespace
+func getLeaseHolders(dev *model.Dev) []*model.Dev {
+	result := []*model.Dev{dev}
+	for _, s := range dev.Services {
+		namespace := s.GetServiceNamespace(dev)
+		if namespace == dev.Namespace {
+			continue
+		}
+		result = append(result, &model.Dev{Name: s.Name, Namespace: namespace})
+	}
+	return result
+}
+
+func acquire(ctx context.Context, dev *model.Dev, holder string, now time.Time, c kubernetes.Interface) error {
+	lClient := c.CoordinationV1().Leases(dev.Namespace)
+	renewTime := metav1.NewMicroTime(now)
+	duration := int32(leaseDuration.Seconds())
+
+	l, err := lClient.Get(ctx, GetName(dev), metav1.GetOptions{})
+	if err != nil {
+		if isNotAvailable(err) {
+			log.Infof("failed to get the lease of '%s': %s", dev.Name, err)
+			return ErrNotAvailable
+		}
+		if !k8sErrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get the lease of '%s': %s", dev.Name, err)
+		}
+
+		l = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   GetName(dev),
+				Labels: map[string]string{labels.DevLabel: "true"},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &holder,
+				LeaseDurationSeconds: &duration,
+				AcquireTime:          &renewTime,
+				RenewTime:            &renewTime,
+			},
+		}
+
+		if _, err := lClient.Create(ctx, l, metav1.CreateOptions{FieldManager: labels.FieldManager}); err != nil {
+			if k8sErrors.IsAlreadyExists(err) {
+				return acquire(ctx, dev, holder, now, c)
+			}
+			if isNotAvailable(err) {
+				log.Infof("failed to create the lease of '%s': %s", dev.Name, err)
+				return ErrNotAvailable
+			}
+			return fmt.Errorf("failed to create the lease of '%s': %s", dev.Name, err)
+		}
+
+		log.Infof("acquired lease '%s' as '%s'", l.Name, holder)
+		return nil
+	}
+
+	if isHeldByOther(l, holder, now) {
+		return errors.UserError{
+			E:    fmt.Errorf("Deployment '%s' is in use by %s since %s", dev.Name, *l.Spec.HolderIdentity, l.Spec.AcquireTime.Local().Format(sinceFormat)),
+			Hint: "Wait until they run 'okteto down' or use a different name for your development container",
+		}
+	}
+
+	if l.Spec.HolderIdentity == nil || *l.Spec.HolderIdentity != holder || l.Spec.AcquireTime == nil {
+		l.Spec.AcquireTime = &renewTime
+	}
+	l.Spec.HolderIdentity = &holder
+	l.Spec.LeaseDurationSeconds = &duration
+	l.Spec.RenewTime = &renewTime
+
+	if _, err := lClient.Update(ctx, l, metav1.UpdateOptions{FieldManager: labels.FieldManager}); err != nil {
+		if k8sErrors.IsConflict(err) {
+			return acquire(ctx, dev, holder, now, c)
+		}
+		if isNotAvailable(err) {
+			log.Infof("failed to update the lease of '%s': %s", dev.Name, err)
+			return ErrNotAvailable
+		}
+		return fmt.Errorf("failed to update the lease of '%s': %s", dev.Name, err)
+	}
+
+	return nil
+}
+
+//Renew keeps the lease of the development container until the context is cancelled
+func Renew(ctx context.Context, dev *model.Dev, c kubernetes.Interface) {
+	t := time.NewTicker(renewInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			if err := Acquire(ctx, dev, c); err != nil {
+				log.Infof("failed to renew the lease of '%s': %s", dev.Name, err)
+			}
+		case <-ctx.Done():
+			log.Debug("call to leases.Renew cancelled")
+			return
+		}
+	}
+}
+
+//Release deletes the leases of the development container and its services in other namespaces if they are held by this client
+func Release(ctx context.Context, dev *model.Dev, c kubernetes.Interface) error {
+	holder := getHolderIdentity()
+	for _, l := range getLeaseHolders(dev) {
+		if err := release(ctx, l, holder, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func release(ctx context.Context, dev *model.Dev, holder string, c kubernetes.Interface) error {
+	lClient := c.CoordinationV1().Leases(dev.Namespace)
+	l, err := lClient.Get(ctx, GetName(dev), metav1.GetOptions{})
+	if err != nil {
+		if k8sErrors.IsNotFound(err) || isNotAvailable(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get the lease of '%s': %s", dev.Name, err)
+	}
+
+	if l.Spec.HolderIdentity != nil && *l.Spec.HolderIdentity != holder {
+		log.Infof("the lease of '%s' is held by '%s', not releasing it", dev.Name, *l.Spec.HolderIdentity)
+		return nil
+	}
+
+	err = lClient.Delete(ctx, GetName(dev), metav1.DeleteOptions{Preconditions: &metav1.Preconditions{ResourceVersion: &l.ResourceVersion}})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		if k8sErrors.IsConflict(err) {
+			return release(ctx, dev, holder, c)
+		}
+		return fmt.Errorf("failed to delete the lease of '%s': %s", dev.Name, err)
+	}
+
+	return nil
+}
+
+// isNotAvailable returns true if err is returned because the user is not allowed to use leases,
+// or because the leases API is not served by the cluster
+func isNotAvailable(err error) bool {
+	if k8sErrors.IsForbidden(err) {
+		return true
+	}
+	if !k8sErrors.IsNotFound(err) {
+		return false
+	}
+	status, ok := err.(k8sErrors.APIStatus)
+	return ok && (status.Status().Details == nil || status.Status().Details.Name == "")
+}
+
+func isHeldByOther(l *coordinationv1.Lease, holder string, now time.Time) bool {
+	if l.Spec.HolderIdentity == nil || *l.Spec.HolderIdentity == "" || *l.Spec.HolderIdentity == holder {
+		return false
+	}
+
+	if l.Spec.RenewTime == nil || l.Spec.LeaseDurationSeconds == nil || l.Spec.AcquireTime == nil {
+		return false
+	}
+
+	expiration := l.Spec.RenewTime.Add(time.Duration(*l.Spec.LeaseDurationSeconds) * time.Second)
+	return now.Before(expiration)
+}
+
+func getHolderIdentity() string {
+	name := "unknown"
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		return name
+	}
+
+	return fmt.Sprintf("%s@%s", name, host)
+}
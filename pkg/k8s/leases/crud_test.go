@@ -0,0 +1,151 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/model"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+func TestAcquire(t *testing.T) {
+	ctx := context.Background()
+	dev := &model.Dev{Name: "api", Namespace: "test"}
+	c := fake.NewSimpleClientset()
+	now := time.Now()
+
+	if err := acquire(ctx, dev, "alice@laptop", now, c); err != nil {
+		t.Fatalf("failed to acquire a new lease: %s", err)
+	}
+
+	if err := acquire(ctx, dev, "alice@laptop", now.Add(10*time.Second), c); err != nil {
+		t.Fatalf("failed to renew the lease: %s", err)
+	}
+
+	err := acquire(ctx, dev, "bob@desktop", now.Add(30*time.Second), c)
+	if err == nil {
+		t.Fatal("lease taken over while it was held by someone else")
+	}
+
+	uErr, ok := err.(errors.UserError)
+	if !ok {
+		t.Fatalf("expected a user error, got %s", err)
+	}
+
+	if !strings.Contains(uErr.E.Error(), "in use by alice@laptop since") {
+		t.Errorf("wrong error message: %s", uErr.E)
+	}
+
+	if err := acquire(ctx, dev, "bob@desktop", now.Add(10*time.Second+leaseDuration+time.Second), c); err != nil {
+		t.Fatalf("failed to acquire an expired lease: %s", err)
+	}
+
+	l, err := c.CoordinationV1().Leases(dev.Namespace).Get(ctx, GetName(dev), metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *l.Spec.HolderIdentity != "bob@desktop" {
+		t.Errorf("expected the lease to be held by bob@desktop, got %s", *l.Spec.HolderIdentity)
+	}
+
+	if err := release(ctx, dev, "alice@laptop", c); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.CoordinationV1().Leases(dev.Namespace).Get(ctx, GetName(dev), metav1.GetOptions{}); err != nil {
+		t.Fatalf("lease held by someone else was released: %s", err)
+	}
+
+	if err := release(ctx, dev, "bob@desktop", c); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.CoordinationV1().Leases(dev.Namespace).Get(ctx, GetName(dev), metav1.GetOptions{}); !k8sErrors.IsNotFound(err) {
+		t.Fatalf("lease wasn't released: %v", err)
+	}
+
+	if err := release(ctx, dev, "bob@desktop", c); err != nil {
+		t.Fatalf("failed to release a missing lease: %s", err)
+	}
+}
+
+func TestAcquireNotAvailable(t *testing.T) {
+	ctx := context.Background()
+	dev := &model.Dev{Name: "api", Namespace: "test"}
+	gr := schema.GroupResource{Group: "coordination.k8s.io", Resource: "leases"}
+
+	forbidden := fake.NewSimpleClientset()
+	forbidden.PrependReactor("*", "leases", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		return true, nil, k8sErrors.NewForbidden(gr, GetName(dev), fmt.Errorf("no RBAC"))
+	})
+	if err := acquire(ctx, dev, "alice@laptop", time.Now(), forbidden); err != ErrNotAvailable {
+		t.Errorf("expected ErrNotAvailable for a forbidden lease, got %v", err)
+	}
+	if err := release(ctx, dev, "alice@laptop", forbidden); err != nil {
+		t.Errorf("failed to release a forbidden lease: %s", err)
+	}
+
+	notServed := fake.NewSimpleClientset()
+	notServed.PrependReactor("*", "leases", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		return true, nil, k8sErrors.NewNotFound(gr, "")
+	})
+	if err := acquire(ctx, dev, "alice@laptop", time.Now(), notServed); err != ErrNotAvailable {
+		t.Errorf("expected ErrNotAvailable when leases aren't served, got %v", err)
+	}
+}
+
+func TestAcquireServicesInOtherNamespaces(t *testing.T) {
+	ctx := context.Background()
+	dev := &model.Dev{
+		Name:      "api",
+		Namespace: "test",
+		Services: []*model.Dev{
+			{Name: "worker"},
+			{Name: "db", Namespace: "shared"},
+		},
+	}
+	c := fake.NewSimpleClientset()
+
+	if err := Acquire(ctx, dev, c); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.CoordinationV1().Leases("shared").Get(ctx, "okteto-db", metav1.GetOptions{}); err != nil {
+		t.Fatalf("lease of the service in the shared namespace wasn't acquired: %s", err)
+	}
+
+	if _, err := c.CoordinationV1().Leases("test").Get(ctx, "okteto-worker", metav1.GetOptions{}); !k8sErrors.IsNotFound(err) {
+		t.Fatalf("lease of the service in the namespace of the development container was acquired: %v", err)
+	}
+
+	if err := Release(ctx, dev, c); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.CoordinationV1().Leases("shared").Get(ctx, "okteto-db", metav1.GetOptions{}); !k8sErrors.IsNotFound(err) {
+		t.Fatalf("lease of the service in the shared namespace wasn't released: %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdbs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	okLabels "github.com/okteto/okteto/pkg/k8s/labels"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	relaxedByAnnotation = "dev.okteto.com/pdb-relaxed-by"
+	originalAnnotation  = "dev.okteto.com/pdb-original"
+)
+
+// original are the fields of the pod disruption budget changed by okteto
+type original struct {
+	MinAvailable   *intstr.IntOrString `json:"minAvailable,omitempty"`
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+//Relax allows the disruption of the pods of the deployments while the development container is active.
+//It only changes the pod disruption budgets that don't allow any disruption, and returns their names.
+func Relax(ctx context.Context, dev *model.Dev, deployments []*appsv1.Deployment, c kubernetes.Interface) ([]string, error) {
+	relaxed := []string{}
+	for _, namespace := range model.GetDeploymentsNamespaces(deployments) {
+		pdbClient := c.PolicyV1beta1().PodDisruptionBudgets(namespace)
+		list, err := pdbClient.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			log.Infof("failed to list the pod disruption budgets of %s: %s", namespace, err)
+			continue
+		}
+
+		for i := range list.Items {
+			pdb := &list.Items[i]
+			if !isBlocking(pdb) || !selectsAny(pdb, deployments) {
+				continue
+			}
+
+			if err := relax(pdb, dev); err != nil {
+				return relaxed, err
+			}
+
+			if _, err := pdbClient.Update(ctx, pdb, metav1.UpdateOptions{FieldManager: okLabels.FieldManager}); err != nil {
+				return relaxed, fmt.Errorf("failed to relax the pod disruption budget '%s': %s", pdb.Name, err)
+			}
+
+			log.Infof("relaxed pod disruption budget '%s/%s'", pdb.Namespace, pdb.Name)
+			relaxed = append(relaxed, getDisplayName(pdb, dev))
+		}
+	}
+
+	return relaxed, nil
+}
+
+//Restore restores the pod disruption budgets relaxed by the development container
+func Restore(ctx context.Context, dev *model.Dev, c kubernetes.Interface) error {
+	for _, namespace := range dev.GetNamespaces() {
+		pdbClient := c.PolicyV1beta1().PodDisruptionBudgets(namespace)
+		list, err := pdbClient.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			log.Infof("failed to list the pod disruption budgets of %s: %s", namespace, err)
+			continue
+		}
+
+		for i := range list.Items {
+			pdb := &list.Items[i]
+			if pdb.Annotations[relaxedByAnnotation] != dev.Name {
+				continue
+			}
+
+			if err := restore(pdb); err != nil {
+				return err
+			}
+
+			if _, err := pdbClient.Update(ctx, pdb, metav1.UpdateOptions{FieldManager: okLabels.FieldManager}); err != nil {
+				return fmt.Errorf("failed to restore the pod disruption budget '%s': %s", pdb.Name, err)
+			}
+
+			log.Infof("restored pod disruption budget '%s/%s'", pdb.Namespace, pdb.Name)
+		}
+	}
+
+	return nil
+}
+
+// getDisplayName returns the name of the pod disruption budget, qualified by its namespace
+// if it isn't the namespace of the development container
+func getDisplayName(pdb *policyv1beta1.PodDisruptionBudget, dev *model.Dev) string {
+	if pdb.Namespace == dev.Namespace {
+		return pdb.Name
+	}
+	return fmt.Sprintf("%s/%s", pdb.Namespace, pdb.Name)
+}
+
+func isBlocking(pdb *policyv1beta1.PodDisruptionBudget) bool {
+	if _, ok := pdb.Annotations[relaxedByAnnotation]; ok {
+		return false
+	}
+
+	return pdb.Status.DisruptionsAllowed == 0
+}
+
+func selectsAny(pdb *policyv1beta1.PodDisruptionBudget, deployments []*appsv1.Deployment) bool {
+	selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+	if err != nil || selector.Empty() {
+		return false
+	}
+
+	for _, d := range deployments {
+		if pdb.Namespace == d.Namespace && selector.Matches(labels.Set(d.Spec.Template.Labels)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func relax(pdb *policyv1beta1.PodDisruptionBudget, dev *model.Dev) error {
+	o, err := json.Marshal(original{MinAvailable: pdb.Spec.MinAvailable, MaxUnavailable: pdb.Spec.MaxUnavailable})
+	if err != nil {
+		return err
+	}
+
+	if pdb.Annotations == nil {
+		pdb.Annotations = map[string]string{}
+	}
+	pdb.Annotations[relaxedByAnnotation] = dev.Name
+	pdb.Annotations[originalAnnotation] = string(o)
+
+	zero := intstr.FromInt(0)
+	pdb.Spec.MinAvailable = &zero
+	pdb.Spec.MaxUnavailable = nil
+	return nil
+}
+
+func restore(pdb *policyv1beta1.PodDisruptionBudget) error {
+	o := original{}
+	if err := json.Unmarshal([]byte(pdb.Annotations[originalAnnotation]), &o); err != nil {
+		return fmt.Errorf("malformed pod disruption budget annotation in '%s': %s", pdb.Name, err)
+	}
+
+	pdb.Spec.MinAvailable = o.MinAvailable
+	pdb.Spec.MaxUnavailable = o.MaxUnavailable
+	delete(pdb.Annotations, relaxedByAnnotation)
+	delete(pdb.Annotations, originalAnnotation)
+	return nil
+}
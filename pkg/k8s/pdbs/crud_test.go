@@ -0,0 +1,107 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdbs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newPDB(name string, selector map[string]string, minAvailable int, allowed int32) *policyv1beta1.PodDisruptionBudget {
+	m := intstr.FromInt(minAvailable)
+	return &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MinAvailable: &m,
+			Selector:     &metav1.LabelSelector{MatchLabels: selector},
+		},
+		Status: policyv1beta1.PodDisruptionBudgetStatus{DisruptionsAllowed: allowed},
+	}
+}
+
+func TestRelaxAndRestore(t *testing.T) {
+	ctx := context.Background()
+	dev := &model.Dev{Name: "api", Namespace: "test"}
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "test"},
+		Spec: appsv1.DeploymentSpec{
+			Template: apiv1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "api"}},
+			},
+		},
+	}
+
+	c := fake.NewSimpleClientset(
+		newPDB("blocking", map[string]string{"app": "api"}, 1, 0),
+		newPDB("allowing", map[string]string{"app": "api"}, 1, 1),
+		newPDB("other", map[string]string{"app": "db"}, 1, 0),
+	)
+
+	relaxed, err := Relax(ctx, dev, []*appsv1.Deployment{d}, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(relaxed) != 1 || relaxed[0] != "blocking" {
+		t.Fatalf("expected only 'blocking' to be relaxed, got %v", relaxed)
+	}
+
+	pdbClient := c.PolicyV1beta1().PodDisruptionBudgets("test")
+	pdb, err := pdbClient.Get(ctx, "blocking", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pdb.Spec.MinAvailable.IntValue() != 0 {
+		t.Errorf("minAvailable wasn't relaxed: %s", pdb.Spec.MinAvailable.String())
+	}
+
+	if pdb.Annotations[relaxedByAnnotation] != dev.Name {
+		t.Errorf("relaxed annotation wasn't set: %v", pdb.Annotations)
+	}
+
+	relaxed, err = Relax(ctx, dev, []*appsv1.Deployment{d}, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(relaxed) != 0 {
+		t.Fatalf("pod disruption budgets relaxed twice: %v", relaxed)
+	}
+
+	if err := Restore(ctx, dev, c); err != nil {
+		t.Fatal(err)
+	}
+
+	pdb, err = pdbClient.Get(ctx, "blocking", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pdb.Spec.MinAvailable == nil || pdb.Spec.MinAvailable.IntValue() != 1 {
+		t.Errorf("minAvailable wasn't restored: %v", pdb.Spec.MinAvailable)
+	}
+
+	if _, ok := pdb.Annotations[relaxedByAnnotation]; ok {
+		t.Errorf("relaxed annotation wasn't removed: %v", pdb.Annotations)
+	}
+}
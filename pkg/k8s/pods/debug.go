@@ -0,0 +1,137 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pods
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/okteto/okteto/pkg/errors"
+	okLabels "github.com/okteto/okteto/pkg/k8s/labels"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	apiv1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	//DebugContainerName is the name of the ephemeral container injected by 'okteto debug'
+	DebugContainerName = "okteto-debug"
+
+	//DebugSSHPort is the port of the SSH server of the debug container
+	DebugSSHPort = 2223
+
+	debugAuthorizedKeysVariable = "OKTETO_AUTHORIZED_KEYS"
+	debugScript                 = `mkdir -p /var/okteto/remote && printf '%s\n' "$OKTETO_AUTHORIZED_KEYS" > /var/okteto/remote/authorized_keys && exec /usr/local/bin/remote`
+)
+
+//AddDebugContainer injects an ephemeral container running the okteto SSH server in the pod, sharing the process namespace of target
+func AddDebugContainer(ctx context.Context, pod *apiv1.Pod, target, authorizedKeys string, c kubernetes.Interface) error {
+	pClient := c.CoreV1().Pods(pod.Namespace)
+	ec, err := pClient.GetEphemeralContainers(ctx, pod.Name, metav1.GetOptions{})
+	if err != nil {
+		return getDebugError(err)
+	}
+
+	for i := range ec.EphemeralContainers {
+		if ec.EphemeralContainers[i].Name == DebugContainerName {
+			log.Infof("pod %s already has a debug container", pod.Name)
+			return nil
+		}
+	}
+
+	ec.EphemeralContainers = append(ec.EphemeralContainers, translateDebugContainer(target, authorizedKeys))
+	if _, err := pClient.UpdateEphemeralContainers(ctx, pod.Name, ec, metav1.UpdateOptions{FieldManager: okLabels.FieldManager}); err != nil {
+		return getDebugError(err)
+	}
+
+	log.Infof("added debug container to pod %s", pod.Name)
+	return nil
+}
+
+//WaitUntilDebugContainerRunning waits until the debug container of the pod is running
+func WaitUntilDebugContainerRunning(ctx context.Context, pod *apiv1.Pod, c kubernetes.Interface) error {
+	watchPod, err := c.CoreV1().Pods(pod.Namespace).Watch(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("metadata.name=%s", pod.Name),
+	})
+	if err != nil {
+		return err
+	}
+	defer watchPod.Stop()
+
+	for {
+		select {
+		case event, ok := <-watchPod.ResultChan():
+			if !ok {
+				return fmt.Errorf("failed to watch pod %s", pod.Name)
+			}
+
+			p, ok := event.Object.(*apiv1.Pod)
+			if !ok {
+				continue
+			}
+
+			for _, s := range p.Status.EphemeralContainerStatuses {
+				if s.Name != DebugContainerName {
+					continue
+				}
+
+				if s.State.Running != nil {
+					return nil
+				}
+
+				if s.State.Terminated != nil {
+					return fmt.Errorf("debug container terminated: %s", s.State.Terminated.Reason)
+				}
+
+				if s.State.Waiting != nil && failureReasons[s.State.Waiting.Reason] {
+					return fmt.Errorf("debug container failed to start: %s %s", s.State.Waiting.Reason, s.State.Waiting.Message)
+				}
+			}
+		case <-ctx.Done():
+			log.Debug("call to pods.WaitUntilDebugContainerRunning cancelled")
+			return ctx.Err()
+		}
+	}
+}
+
+func translateDebugContainer(target, authorizedKeys string) apiv1.EphemeralContainer {
+	return apiv1.EphemeralContainer{
+		EphemeralContainerCommon: apiv1.EphemeralContainerCommon{
+			Name:            DebugContainerName,
+			Image:           model.OktetoBinImageTag,
+			ImagePullPolicy: apiv1.PullIfNotPresent,
+			Command:         []string{"sh", "-c", debugScript},
+			Env: []apiv1.EnvVar{
+				{Name: debugAuthorizedKeysVariable, Value: authorizedKeys},
+				{Name: "OKTETO_REMOTE_PORT", Value: strconv.Itoa(DebugSSHPort)},
+			},
+		},
+		TargetContainerName: target,
+	}
+}
+
+func getDebugError(err error) error {
+	if k8sErrors.IsNotFound(err) || k8sErrors.IsMethodNotSupported(err) {
+		return errors.UserError{
+			E:    fmt.Errorf("ephemeral containers are not available in your cluster"),
+			Hint: "Enable the 'EphemeralContainers' feature gate of your cluster and try again",
+		}
+	}
+
+	return fmt.Errorf("failed to add the debug container: %s", err)
+}
@@ -0,0 +1,45 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pods
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+)
+
+func Test_translateDebugContainer(t *testing.T) {
+	c := translateDebugContainer("api", "ssh-rsa AAAA")
+	if c.Name != DebugContainerName || c.TargetContainerName != "api" {
+		t.Errorf("wrong debug container: %+v", c)
+	}
+
+	if c.Image != model.OktetoBinImageTag {
+		t.Errorf("wrong debug image: %s", c.Image)
+	}
+
+	env := map[string]string{}
+	for _, e := range c.Env {
+		env[e.Name] = e.Value
+	}
+
+	if env[debugAuthorizedKeysVariable] != "ssh-rsa AAAA" {
+		t.Errorf("authorized keys not set: %v", env)
+	}
+
+	if env["OKTETO_REMOTE_PORT"] != strconv.Itoa(DebugSSHPort) {
+		t.Errorf("remote port not set: %v", env)
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/okteto/okteto/pkg/errors"
 	"github.com/okteto/okteto/pkg/k8s/deployments"
 	"github.com/okteto/okteto/pkg/k8s/exec"
+	"github.com/okteto/okteto/pkg/k8s/informers"
 	okLabels "github.com/okteto/okteto/pkg/k8s/labels"
 	"github.com/okteto/okteto/pkg/k8s/replicasets"
 	"github.com/okteto/okteto/pkg/log"
@@ -35,6 +36,8 @@ import (
 	apiv1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
@@ -42,6 +45,8 @@ import (
 const (
 	deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
 	maxRetriesPodRunning         = 300 //1min pod is created
+	devPodResyncInterval         = 5 * time.Second
+	restartTimeout               = 60 * time.Second
 )
 
 var (
@@ -70,6 +75,10 @@ func ListBySelector(ctx context.Context, namespace string, selector map[string]s
 		return nil, fmt.Errorf("empty selector")
 	}
 
+	if cached, ok := informers.ListPods(namespace, labels.SelectorFromSet(selector)); ok {
+		return cached, nil
+	}
+
 	b := new(bytes.Buffer)
 	for key, value := range selector {
 		fmt.Fprintf(b, "%s=%s,", key, value)
@@ -91,13 +100,26 @@ func ListBySelector(ctx context.Context, namespace string, selector map[string]s
 	return p.Items, nil
 }
 
-// GetDevPodInLoop returns the dev pod for a deployment and loops until it success
+// GetDevPodInLoop returns the dev pod for a deployment, watching the dev pods until it success
 func GetDevPodInLoop(ctx context.Context, dev *model.Dev, c *kubernetes.Clientset, waitUntilDeployed bool) (*apiv1.Pod, error) {
-	ticker := time.NewTicker(500 * time.Millisecond)
-	to := 4 * config.GetTimeout() // 120 seconds
-	timeout := time.Now().Add(to)
+	ctx, cancel := context.WithTimeout(ctx, 4*config.GetTimeout()) // 120 seconds
+	defer cancel()
+
+	var events <-chan watch.Event
+	watchPods, err := c.CoreV1().Pods(dev.Namespace).Watch(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", okLabels.InteractiveDevLabel, dev.Name),
+	})
+	if err != nil {
+		log.Infof("failed to watch the dev pods, polling instead: %s", err)
+	} else {
+		defer watchPods.Stop()
+		events = watchPods.ResultChan()
+	}
 
-	for i := 0; ; i++ {
+	ticker := time.NewTicker(devPodResyncInterval)
+	defer ticker.Stop()
+
+	for {
 		pod, err := GetDevPod(ctx, dev, c, waitUntilDeployed)
 		if err != nil {
 			return nil, err
@@ -106,19 +128,35 @@ func GetDevPodInLoop(ctx context.Context, dev *model.Dev, c *kubernetes.Clientse
 			return pod, nil
 		}
 
-		if time.Now().After(timeout) {
-			return nil, fmt.Errorf("kubernetes is taking too long to create your development container. Please check for errors and try again")
-		}
-
 		select {
+		case event, ok := <-events:
+			if !ok {
+				log.Infof("dev pods watch closed, polling instead")
+				events = nil
+				continue
+			}
+
+			if p, ok := event.Object.(*apiv1.Pod); ok {
+				logPodSchedulingFailure(p)
+			}
 		case <-ticker.C:
 			continue
 		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return nil, fmt.Errorf("kubernetes is taking too long to create your development container. Please check for errors and try again")
+			}
 			log.Debug("call to pod.GetDevPodInLoop cancelled")
 			return nil, ctx.Err()
 		}
 	}
+}
 
+func logPodSchedulingFailure(p *apiv1.Pod) {
+	for _, c := range p.Status.Conditions {
+		if c.Type == apiv1.PodScheduled && c.Status == apiv1.ConditionFalse && c.Message != "" {
+			log.Infof("pod %s can't be scheduled: %s", p.Name, c.Message)
+		}
+	}
 }
 
 // GetDevPod returns the dev pod for a deployment
@@ -246,6 +284,10 @@ func WaitUntilRunning(ctx context.Context, dev *model.Dev, podName string, c *ku
 		return err
 	}
 
+	w := newFailureWatchdog(getFailureTimeout(dev))
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case event := <-watchPod.ResultChan():
@@ -255,6 +297,10 @@ func WaitUntilRunning(ctx context.Context, dev *model.Dev, podName string, c *ku
 				continue
 			}
 			log.Infof("dev pod %s updated to %s", pod.Name, pod.Status.Phase)
+			if reason := getContainerFailure(pod); reason != "" {
+				w.failing(reason)
+				continue
+			}
 			if pod.Status.Phase == apiv1.PodRunning {
 				return nil
 			}
@@ -269,20 +315,26 @@ func WaitUntilRunning(ctx context.Context, dev *model.Dev, podName string, c *ku
 			}
 
 			log.Infof("pod %s event: %s", podName, e.Message)
+			w.event(e)
 			switch e.Reason {
 			case "Failed", "FailedScheduling", "FailedCreatePodSandBox", "ErrImageNeverPull", "InspectFailed", "FailedCreatePodContainer":
 				if strings.Contains(e.Message, "pod has unbound immediate PersistentVolumeClaims") {
 					continue
 				}
 
-				return fmt.Errorf(e.Message)
+				return w.err(fmt.Errorf(e.Message))
 			case "FailedAttachVolume", "FailedMount":
+				w.failing(e.Message)
 				reporter <- fmt.Sprintf("%s: retrying", e.Message)
 			default:
 				if e.Reason == "Pulling" {
 					reporter <- strings.Replace(e.Message, "pulling", "Pulling", 1)
 				}
 			}
+		case <-ticker.C:
+			if w.expired() {
+				return w.err(fmt.Errorf("%s", w.reason))
+			}
 		case <-ctx.Done():
 			log.Debug("call to pods.WaitUntilRunning cancelled")
 			return ctx.Err()
@@ -299,6 +351,20 @@ func Exists(ctx context.Context, podName, namespace string, c kubernetes.Interfa
 	return pod.GetObjectMeta().GetDeletionTimestamp() == nil
 }
 
+//GetIP returns the IP of a pod
+func GetIP(ctx context.Context, podName, namespace string, c kubernetes.Interface) (string, error) {
+	pod, err := c.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	if pod.Status.PodIP == "" {
+		return "", fmt.Errorf("pod %s doesn't have an IP", podName)
+	}
+
+	return pod.Status.PodIP, nil
+}
+
 //Destroy destroys a pod by name
 func Destroy(ctx context.Context, podName, namespace string, c kubernetes.Interface) error {
 	err := c.CoreV1().Pods(namespace).Delete(
@@ -311,6 +377,7 @@ func Destroy(ctx context.Context, podName, namespace string, c kubernetes.Interf
 	if err != nil && !errors.IsNotFound(err) {
 		return err
 	}
+	informers.Invalidate(informers.Pods, namespace, podName, "")
 	return nil
 }
 
@@ -427,10 +494,12 @@ func Restart(ctx context.Context, dev *model.Dev, c *kubernetes.Clientset, sn st
 		err := c.CoreV1().Pods(dev.Namespace).Delete(ctx, pods.Items[i].Name, metav1.DeleteOptions{GracePeriodSeconds: &devTerminationGracePeriodSeconds})
 		if err != nil {
 			if strings.Contains(err.Error(), "not found") {
+				informers.Invalidate(informers.Pods, dev.Namespace, pods.Items[i].Name, "")
 				return nil
 			}
 			return fmt.Errorf("error deleting kubernetes service: %s", err)
 		}
+		informers.Invalidate(informers.Pods, dev.Namespace, pods.Items[i].Name, "")
 	}
 
 	if !found {
@@ -440,64 +509,80 @@ func Restart(ctx context.Context, dev *model.Dev, c *kubernetes.Clientset, sn st
 }
 
 func waitUntilRunning(ctx context.Context, namespace, selector string, c *kubernetes.Clientset) error {
-	t := time.NewTicker(1 * time.Second)
-	notready := map[string]bool{}
+	opts := metav1.ListOptions{LabelSelector: selector}
+	podList, err := c.CoreV1().Pods(namespace).List(ctx, opts)
+	if err != nil {
+		log.Infof("error listing pods to check status after restart: %s", err)
+		return fmt.Errorf("failed to retrieve development container information")
+	}
 
-	for i := 0; i < 60; i++ {
-		if i%5 == 0 {
-			log.Infof("checking if pods are ready")
+	notready := map[string]bool{}
+	for i := range podList.Items {
+		if !isRunning(&podList.Items[i]) {
+			notready[podList.Items[i].GetName()] = true
 		}
+	}
 
-		pods, err := c.CoreV1().Pods(namespace).List(
-			ctx,
-			metav1.ListOptions{
-				LabelSelector: selector,
-			},
-		)
+	if len(notready) == 0 {
+		log.Infof("pods are ready")
+		return nil
+	}
 
-		if err != nil {
-			log.Infof("error listing pods to check status after restart: %s", err)
-			return fmt.Errorf("failed to retrieve development container information")
-		}
+	opts.ResourceVersion = podList.ResourceVersion
+	watchPods, err := c.CoreV1().Pods(namespace).Watch(ctx, opts)
+	if err != nil {
+		log.Infof("error watching pods to check status after restart: %s", err)
+		return fmt.Errorf("failed to retrieve development container information")
+	}
+	defer watchPods.Stop()
 
-		allRunning := true
-		for i := range pods.Items {
-			switch pods.Items[i].Status.Phase {
-			case apiv1.PodPending:
-				allRunning = false
-				notready[pods.Items[i].GetName()] = true
-			case apiv1.PodFailed:
-				return fmt.Errorf("Pod %s failed to start", pods.Items[i].Name)
-			case apiv1.PodRunning:
-				if isRunning(&pods.Items[i]) {
-					if _, ok := notready[pods.Items[i].GetName()]; ok {
-						log.Infof("pod/%s is ready", pods.Items[i].GetName())
-						delete(notready, pods.Items[i].GetName())
-					}
-				} else {
-					allRunning = false
-					notready[pods.Items[i].GetName()] = true
-					if i%5 == 0 {
-						log.Infof("pod/%s is not ready", pods.Items[i].GetName())
-					}
-				}
+	timeout := time.NewTimer(restartTimeout)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case event, ok := <-watchPods.ResultChan():
+			if !ok {
+				return fmt.Errorf("failed to retrieve development container information")
 			}
-		}
 
-		if allRunning {
-			log.Infof("pods are ready")
-			return nil
-		}
+			pod, ok := event.Object.(*apiv1.Pod)
+			if !ok {
+				log.Infof("unknown event type: %s", event)
+				continue
+			}
 
-		<-t.C
-	}
+			switch {
+			case event.Type == watch.Deleted:
+				delete(notready, pod.GetName())
+			case pod.Status.Phase == apiv1.PodFailed:
+				return fmt.Errorf("Pod %s failed to start", pod.Name)
+			case isRunning(pod):
+				if _, ok := notready[pod.GetName()]; ok {
+					log.Infof("pod/%s is ready", pod.GetName())
+					delete(notready, pod.GetName())
+				}
+			default:
+				notready[pod.GetName()] = true
+				logPodSchedulingFailure(pod)
+			}
 
-	pods := make([]string, 0, len(notready))
-	for k := range notready {
-		pods = append(pods, k)
-	}
+			if len(notready) == 0 {
+				log.Infof("pods are ready")
+				return nil
+			}
+		case <-timeout.C:
+			pods := make([]string, 0, len(notready))
+			for k := range notready {
+				pods = append(pods, k)
+			}
 
-	return fmt.Errorf("Pod(s) %s didn't restart after 60 seconds", strings.Join(pods, ","))
+			return fmt.Errorf("Pod(s) %s didn't restart after %s", strings.Join(pods, ","), restartTimeout)
+		case <-ctx.Done():
+			log.Debug("call to pods.waitUntilRunning cancelled")
+			return ctx.Err()
+		}
+	}
 }
 
 func isRunning(p *apiv1.Pod) bool {
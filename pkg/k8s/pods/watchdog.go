@@ -0,0 +1,98 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pods
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/okteto/okteto/pkg/config"
+	"github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+const maxReportedEvents = 10
+
+var failureReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
+// failureWatchdog tracks how long the dev pod has been failing to start and its latest events
+type failureWatchdog struct {
+	timeout time.Duration
+	since   time.Time
+	reason  string
+	events  []string
+}
+
+func newFailureWatchdog(timeout time.Duration) *failureWatchdog {
+	return &failureWatchdog{timeout: timeout}
+}
+
+func getFailureTimeout(dev *model.Dev) time.Duration {
+	if dev.Timeout.Failure > 0 {
+		return dev.Timeout.Failure
+	}
+
+	return 2 * config.GetTimeout()
+}
+
+func (w *failureWatchdog) failing(reason string) {
+	if w.since.IsZero() {
+		log.Infof("dev pod is failing: %s", reason)
+		w.since = time.Now()
+	}
+	w.reason = reason
+}
+
+func (w *failureWatchdog) event(e *apiv1.Event) {
+	w.events = append(w.events, fmt.Sprintf("%s: %s", e.Reason, e.Message))
+	if len(w.events) > maxReportedEvents {
+		w.events = w.events[len(w.events)-maxReportedEvents:]
+	}
+}
+
+func (w *failureWatchdog) expired() bool {
+	return !w.since.IsZero() && time.Since(w.since) > w.timeout
+}
+
+func (w *failureWatchdog) err(err error) error {
+	return &errors.DevPodFailedError{Err: err, Events: w.events}
+}
+
+// getContainerFailure returns the reason why a container of the pod can't start, or an empty string
+func getContainerFailure(pod *apiv1.Pod) string {
+	statuses := append([]apiv1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, s := range statuses {
+		if s.State.Waiting == nil || !failureReasons[s.State.Waiting.Reason] {
+			continue
+		}
+
+		if s.State.Waiting.Message == "" {
+			return fmt.Sprintf("container '%s': %s", s.Name, s.State.Waiting.Reason)
+		}
+
+		return fmt.Sprintf("container '%s': %s: %s", s.Name, s.State.Waiting.Reason, s.State.Waiting.Message)
+	}
+
+	return ""
+}
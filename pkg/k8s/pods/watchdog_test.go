@@ -0,0 +1,97 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pods
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/okteto/okteto/pkg/errors"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+func Test_getContainerFailure(t *testing.T) {
+	var tests = []struct {
+		name     string
+		statuses []apiv1.ContainerStatus
+		expected string
+	}{
+		{
+			name:     "no-statuses",
+			expected: "",
+		},
+		{
+			name: "creating",
+			statuses: []apiv1.ContainerStatus{
+				{Name: "dev", State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "ContainerCreating"}}},
+			},
+			expected: "",
+		},
+		{
+			name: "crash-loop",
+			statuses: []apiv1.ContainerStatus{
+				{Name: "sidecar", State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+				{Name: "dev", State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off 10s"}}},
+			},
+			expected: "container 'dev': CrashLoopBackOff: back-off 10s",
+		},
+		{
+			name: "image-pull",
+			statuses: []apiv1.ContainerStatus{
+				{Name: "dev", State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+			},
+			expected: "container 'dev': ImagePullBackOff",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &apiv1.Pod{Status: apiv1.PodStatus{ContainerStatuses: tt.statuses}}
+			if got := getContainerFailure(pod); got != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
+
+func Test_failureWatchdog(t *testing.T) {
+	w := newFailureWatchdog(100 * time.Millisecond)
+	if w.expired() {
+		t.Fatal("watchdog expired without failures")
+	}
+
+	for i := 0; i < maxReportedEvents+5; i++ {
+		w.event(&apiv1.Event{Reason: "FailedMount", Message: fmt.Sprintf("attempt %d", i)})
+	}
+
+	w.failing("volume not found")
+	time.Sleep(150 * time.Millisecond)
+	if !w.expired() {
+		t.Fatal("watchdog didn't expire")
+	}
+
+	err, ok := w.err(fmt.Errorf(w.reason)).(*errors.DevPodFailedError)
+	if !ok {
+		t.Fatal("expected a DevPodFailedError")
+	}
+
+	if len(err.Events) != maxReportedEvents {
+		t.Fatalf("expected %d events, got %d", maxReportedEvents, len(err.Events))
+	}
+
+	if err.Events[0] != "FailedMount: attempt 5" {
+		t.Errorf("expected the oldest events to be dropped, got '%s'", err.Events[0])
+	}
+}
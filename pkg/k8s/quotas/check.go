@@ -0,0 +1,190 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quotas
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const quotaHint = "Reduce the 'resources' or the 'persistentVolume.size' of your okteto manifest, or ask your cluster administrator to increase the quota of the namespace"
+
+// quotaResources maps the resources of a quota to the resources requested by the development container
+var quotaResources = map[apiv1.ResourceName]apiv1.ResourceName{
+	apiv1.ResourceCPU:                    apiv1.ResourceRequestsCPU,
+	apiv1.ResourceMemory:                 apiv1.ResourceRequestsMemory,
+	apiv1.ResourceRequestsCPU:            apiv1.ResourceRequestsCPU,
+	apiv1.ResourceRequestsMemory:         apiv1.ResourceRequestsMemory,
+	apiv1.ResourceLimitsCPU:              apiv1.ResourceLimitsCPU,
+	apiv1.ResourceLimitsMemory:           apiv1.ResourceLimitsMemory,
+	apiv1.ResourceRequestsStorage:        apiv1.ResourceRequestsStorage,
+	apiv1.ResourcePersistentVolumeClaims: apiv1.ResourcePersistentVolumeClaims,
+}
+
+//Check validates that the resources of the development container and its persistent volume fit in the quotas and limit ranges of the namespace
+func Check(ctx context.Context, dev *model.Dev, d *appsv1.Deployment, c kubernetes.Interface) error {
+	needed, err := getNeededResources(ctx, dev, d, c)
+	if err != nil {
+		return err
+	}
+
+	quotas, err := c.CoreV1().ResourceQuotas(dev.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.Infof("failed to list the resource quotas of %s, skipping the check: %s", dev.Namespace, err)
+	} else if err := checkQuotas(quotas.Items, needed); err != nil {
+		return err
+	}
+
+	limitRanges, err := c.CoreV1().LimitRanges(dev.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.Infof("failed to list the limit ranges of %s, skipping the check: %s", dev.Namespace, err)
+		return nil
+	}
+
+	return checkLimitRanges(limitRanges.Items, dev)
+}
+
+// getNeededResources returns the resources that the development container adds to the namespace
+func getNeededResources(ctx context.Context, dev *model.Dev, d *appsv1.Deployment, c kubernetes.Interface) (apiv1.ResourceList, error) {
+	current := apiv1.ResourceRequirements{}
+	if d != nil {
+		for _, container := range d.Spec.Template.Spec.Containers {
+			if container.Name == dev.Container {
+				current = container.Resources
+			}
+		}
+	}
+
+	needed := apiv1.ResourceList{}
+	addIncrease(needed, apiv1.ResourceRequestsCPU, dev.Resources.Requests[apiv1.ResourceCPU], current.Requests[apiv1.ResourceCPU])
+	addIncrease(needed, apiv1.ResourceRequestsMemory, dev.Resources.Requests[apiv1.ResourceMemory], current.Requests[apiv1.ResourceMemory])
+	addIncrease(needed, apiv1.ResourceLimitsCPU, dev.Resources.Limits[apiv1.ResourceCPU], current.Limits[apiv1.ResourceCPU])
+	addIncrease(needed, apiv1.ResourceLimitsMemory, dev.Resources.Limits[apiv1.ResourceMemory], current.Limits[apiv1.ResourceMemory])
+
+	if !dev.PersistentVolumeEnabled() {
+		return needed, nil
+	}
+
+	_, err := c.CoreV1().PersistentVolumeClaims(dev.Namespace).Get(ctx, dev.GetVolumeName(), metav1.GetOptions{})
+	if err == nil {
+		return needed, nil
+	}
+
+	if !k8sErrors.IsNotFound(err) {
+		return nil, fmt.Errorf("error getting kubernetes volume claim: %s", err)
+	}
+
+	needed[apiv1.ResourceRequestsStorage] = resource.MustParse(dev.PersistentVolumeSize())
+	needed[apiv1.ResourcePersistentVolumeClaims] = *resource.NewQuantity(1, resource.DecimalSI)
+	return needed, nil
+}
+
+func addIncrease(needed apiv1.ResourceList, name apiv1.ResourceName, requested, current resource.Quantity) {
+	if requested.Cmp(current) <= 0 {
+		return
+	}
+
+	increase := requested.DeepCopy()
+	increase.Sub(current)
+	needed[name] = increase
+}
+
+func checkQuotas(quotas []apiv1.ResourceQuota, needed apiv1.ResourceList) error {
+	for _, q := range quotas {
+		if len(q.Spec.Scopes) > 0 || q.Spec.ScopeSelector != nil {
+			log.Infof("skipping scoped resource quota %s", q.Name)
+			continue
+		}
+
+		for name, hard := range q.Status.Hard {
+			requested, ok := needed[quotaResources[name]]
+			if !ok {
+				continue
+			}
+
+			remaining := hard.DeepCopy()
+			if used, ok := q.Status.Used[name]; ok {
+				remaining.Sub(used)
+			}
+
+			if requested.Cmp(remaining) > 0 {
+				if remaining.Sign() < 0 {
+					remaining = resource.Quantity{}
+				}
+				return errors.UserError{
+					E:    fmt.Errorf("Your development container requests %s of '%s', but only %s are left in the quota '%s'", requested.String(), name, remaining.String(), q.Name),
+					Hint: quotaHint,
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkLimitRanges(limitRanges []apiv1.LimitRange, dev *model.Dev) error {
+	for _, lr := range limitRanges {
+		for _, item := range lr.Spec.Limits {
+			switch item.Type {
+			case apiv1.LimitTypeContainer:
+				for _, resources := range []model.ResourceList{dev.Resources.Requests, dev.Resources.Limits} {
+					if err := checkLimitRange(lr.Name, item, resources, "container"); err != nil {
+						return err
+					}
+				}
+			case apiv1.LimitTypePersistentVolumeClaim:
+				if !dev.PersistentVolumeEnabled() {
+					continue
+				}
+
+				storage := model.ResourceList{apiv1.ResourceStorage: resource.MustParse(dev.PersistentVolumeSize())}
+				if err := checkLimitRange(lr.Name, item, storage, "persistent volume"); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkLimitRange(name string, item apiv1.LimitRangeItem, resources model.ResourceList, kind string) error {
+	for r, q := range resources {
+		if max, ok := item.Max[r]; ok && q.Cmp(max) > 0 {
+			return errors.UserError{
+				E:    fmt.Errorf("Your development container requests %s of '%s', but the limit range '%s' allows a maximum of %s per %s", q.String(), r, name, max.String(), kind),
+				Hint: quotaHint,
+			}
+		}
+
+		if min, ok := item.Min[r]; ok && q.Cmp(min) < 0 {
+			return errors.UserError{
+				E:    fmt.Errorf("Your development container requests %s of '%s', but the limit range '%s' requires a minimum of %s per %s", q.String(), r, name, min.String(), kind),
+				Hint: quotaHint,
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quotas
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCheck(t *testing.T) {
+	quota := &apiv1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: "test"},
+		Status: apiv1.ResourceQuotaStatus{
+			Hard: apiv1.ResourceList{
+				apiv1.ResourceRequestsMemory:  resource.MustParse("4Gi"),
+				apiv1.ResourceRequestsStorage: resource.MustParse("5Gi"),
+			},
+			Used: apiv1.ResourceList{
+				apiv1.ResourceRequestsMemory:  resource.MustParse("3Gi"),
+				apiv1.ResourceRequestsStorage: resource.MustParse("3Gi"),
+			},
+		},
+	}
+
+	limitRange := &apiv1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: "limits", Namespace: "test"},
+		Spec: apiv1.LimitRangeSpec{
+			Limits: []apiv1.LimitRangeItem{
+				{
+					Type: apiv1.LimitTypeContainer,
+					Max:  apiv1.ResourceList{apiv1.ResourceCPU: resource.MustParse("2")},
+				},
+			},
+		},
+	}
+
+	d := &appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{
+			Template: apiv1.PodTemplateSpec{
+				Spec: apiv1.PodSpec{
+					Containers: []apiv1.Container{
+						{
+							Name: "api",
+							Resources: apiv1.ResourceRequirements{
+								Requests: apiv1.ResourceList{apiv1.ResourceMemory: resource.MustParse("1Gi")},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var tests = []struct {
+		name      string
+		dev       *model.Dev
+		objects   []runtime.Object
+		expectErr string
+	}{
+		{
+			name: "fits",
+			dev: &model.Dev{
+				Resources: model.ResourceRequirements{
+					Requests: model.ResourceList{apiv1.ResourceMemory: resource.MustParse("2Gi")},
+				},
+			},
+		},
+		{
+			name: "memory-quota",
+			dev: &model.Dev{
+				Resources: model.ResourceRequirements{
+					Requests: model.ResourceList{apiv1.ResourceMemory: resource.MustParse("3Gi")},
+				},
+			},
+			expectErr: "requests 2Gi of 'requests.memory', but only 1Gi are left in the quota 'compute'",
+		},
+		{
+			name: "storage-quota",
+			dev: &model.Dev{
+				PersistentVolumeInfo: &model.PersistentVolumeInfo{Enabled: true, Size: "5Gi"},
+			},
+			expectErr: "requests 5Gi of 'requests.storage', but only 2Gi are left in the quota 'compute'",
+		},
+		{
+			name: "existing-volume",
+			dev: &model.Dev{
+				PersistentVolumeInfo: &model.PersistentVolumeInfo{Enabled: true, Size: "5Gi"},
+			},
+			objects: []runtime.Object{
+				&apiv1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "okteto-api", Namespace: "test"}},
+			},
+		},
+		{
+			name: "limit-range",
+			dev: &model.Dev{
+				Resources: model.ResourceRequirements{
+					Limits: model.ResourceList{apiv1.ResourceCPU: resource.MustParse("4")},
+				},
+			},
+			expectErr: "the limit range 'limits' allows a maximum of 2 per container",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.dev.Name = "api"
+			tt.dev.Namespace = "test"
+			tt.dev.Container = "api"
+			if tt.dev.PersistentVolumeInfo == nil {
+				tt.dev.PersistentVolumeInfo = &model.PersistentVolumeInfo{Enabled: false}
+			}
+
+			c := fake.NewSimpleClientset(append(tt.objects, quota, limitRange)...)
+			err := Check(context.Background(), tt.dev, d, c)
+			if tt.expectErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+
+			uErr, ok := err.(errors.UserError)
+			if !ok {
+				t.Fatalf("expected a user error, got %v", err)
+			}
+
+			if !strings.Contains(uErr.E.Error(), tt.expectErr) {
+				t.Errorf("expected error containing '%s', got '%s'", tt.expectErr, uErr.E)
+			}
+		})
+	}
+}
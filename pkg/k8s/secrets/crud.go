@@ -79,14 +79,14 @@ func Create(ctx context.Context, dev *model.Dev, c *kubernetes.Clientset, s *syn
 	}
 
 	if sct.Name == "" {
-		_, err := c.CoreV1().Secrets(dev.Namespace).Create(ctx, data, metav1.CreateOptions{})
+		_, err := c.CoreV1().Secrets(dev.Namespace).Create(ctx, data, metav1.CreateOptions{FieldManager: labels.FieldManager})
 		if err != nil {
 			return fmt.Errorf("error creating kubernetes sync secret: %s", err)
 		}
 
 		log.Infof("created okteto secret '%s'", secretName)
 	} else {
-		_, err := c.CoreV1().Secrets(dev.Namespace).Update(ctx, data, metav1.UpdateOptions{})
+		_, err := c.CoreV1().Secrets(dev.Namespace).Update(ctx, data, metav1.UpdateOptions{FieldManager: labels.FieldManager})
 		if err != nil {
 			return fmt.Errorf("error updating kubernetes okteto secret: %s", err)
 		}
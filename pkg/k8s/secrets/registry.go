@@ -0,0 +1,136 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/okteto/okteto/pkg/k8s/labels"
+	"github.com/okteto/okteto/pkg/log"
+	v1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	//RegistrySecretName is the name of the image pull secret for the okteto registry
+	RegistrySecretName = "okteto-registry"
+)
+
+type dockerConfig struct {
+	Auths map[string]dockerAuth `json:"auths"`
+}
+
+type dockerAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+}
+
+//CreateRegistrySecret creates or updates the image pull secret for the okteto registry
+func CreateRegistrySecret(ctx context.Context, namespace, registryURL, username, password string, c kubernetes.Interface) error {
+	config, err := getDockerConfigJSON(registryURL, username, password)
+	if err != nil {
+		return fmt.Errorf("error generating the okteto registry credentials: %s", err)
+	}
+
+	data := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: RegistrySecretName,
+			Labels: map[string]string{
+				labels.DevLabel: "true",
+			},
+		},
+		Type: v1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			v1.DockerConfigJsonKey: config,
+		},
+	}
+
+	sct, err := c.CoreV1().Secrets(namespace).Get(ctx, RegistrySecretName, metav1.GetOptions{})
+	if err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			return fmt.Errorf("error getting kubernetes secret: %s", err)
+		}
+
+		if _, err := c.CoreV1().Secrets(namespace).Create(ctx, data, metav1.CreateOptions{FieldManager: labels.FieldManager}); err != nil {
+			return fmt.Errorf("error creating kubernetes registry secret: %s", err)
+		}
+
+		log.Infof("created okteto registry secret '%s'", RegistrySecretName)
+		return nil
+	}
+
+	if string(sct.Data[v1.DockerConfigJsonKey]) == string(config) {
+		return nil
+	}
+
+	if _, err := c.CoreV1().Secrets(namespace).Update(ctx, data, metav1.UpdateOptions{FieldManager: labels.FieldManager}); err != nil {
+		return fmt.Errorf("error updating kubernetes registry secret: %s", err)
+	}
+
+	log.Infof("updated okteto registry secret '%s'", RegistrySecretName)
+	return nil
+}
+
+//DestroyRegistrySecret deletes the image pull secret for the okteto registry created by 'okteto up',
+//unless a development container of the namespace still uses it
+func DestroyRegistrySecret(ctx context.Context, namespace string, c kubernetes.Interface) error {
+	sct, err := c.CoreV1().Secrets(namespace).Get(ctx, RegistrySecretName, metav1.GetOptions{})
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("error getting kubernetes secret: %s", err)
+	}
+
+	if sct.Labels[labels.DevLabel] != "true" {
+		return nil
+	}
+
+	dList, err := c.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=true", labels.DevLabel)})
+	if err != nil {
+		return fmt.Errorf("error listing the development containers of %s: %s", namespace, err)
+	}
+
+	for i := range dList.Items {
+		for _, s := range dList.Items[i].Spec.Template.Spec.ImagePullSecrets {
+			if s.Name == RegistrySecretName {
+				log.Infof("okteto registry secret is used by '%s', not deleting it", dList.Items[i].Name)
+				return nil
+			}
+		}
+	}
+
+	err = c.CoreV1().Secrets(namespace).Delete(ctx, RegistrySecretName, metav1.DeleteOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return fmt.Errorf("error deleting kubernetes registry secret: %s", err)
+	}
+
+	log.Infof("deleted okteto registry secret '%s'", RegistrySecretName)
+	return nil
+}
+
+func getDockerConfigJSON(registryURL, username, password string) ([]byte, error) {
+	auth := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", username, password)))
+	return json.Marshal(dockerConfig{
+		Auths: map[string]dockerAuth{
+			registryURL: {Username: username, Password: password, Auth: auth},
+		},
+	})
+}
@@ -0,0 +1,117 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/k8s/labels"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCreateRegistrySecret(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewSimpleClientset()
+
+	if err := CreateRegistrySecret(ctx, "test", "registry.example.com", "alice", "token-1", c); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CreateRegistrySecret(ctx, "test", "registry.example.com", "alice", "token-2", c); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := c.CoreV1().Secrets("test").Get(ctx, RegistrySecretName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Type != v1.SecretTypeDockerConfigJson {
+		t.Errorf("wrong secret type: %s", s.Type)
+	}
+
+	config := dockerConfig{}
+	if err := json.Unmarshal(s.Data[v1.DockerConfigJsonKey], &config); err != nil {
+		t.Fatal(err)
+	}
+
+	auth, ok := config.Auths["registry.example.com"]
+	if !ok {
+		t.Fatalf("registry credentials not found: %+v", config)
+	}
+
+	if auth.Username != "alice" || auth.Password != "token-2" || auth.Auth != "YWxpY2U6dG9rZW4tMg==" {
+		t.Errorf("wrong registry credentials: %+v", auth)
+	}
+}
+
+func TestDestroyRegistrySecret(t *testing.T) {
+	ctx := context.Background()
+	inUse := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "test", Labels: map[string]string{labels.DevLabel: "true"}},
+		Spec: appsv1.DeploymentSpec{
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{ImagePullSecrets: []v1.LocalObjectReference{{Name: RegistrySecretName}}},
+			},
+		},
+	}
+	c := fake.NewSimpleClientset(inUse)
+
+	if err := CreateRegistrySecret(ctx, "test", "registry.example.com", "alice", "token", c); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := DestroyRegistrySecret(ctx, "test", c); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.CoreV1().Secrets("test").Get(ctx, RegistrySecretName, metav1.GetOptions{}); err != nil {
+		t.Fatalf("secret used by a development container was deleted: %s", err)
+	}
+
+	if err := c.AppsV1().Deployments("test").Delete(ctx, "api", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := DestroyRegistrySecret(ctx, "test", c); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.CoreV1().Secrets("test").Get(ctx, RegistrySecretName, metav1.GetOptions{}); !k8sErrors.IsNotFound(err) {
+		t.Fatalf("secret wasn't deleted: %v", err)
+	}
+
+	if err := DestroyRegistrySecret(ctx, "test", c); err != nil {
+		t.Fatalf("failed to delete a missing secret: %s", err)
+	}
+}
+
+func TestDestroyRegistrySecretNotCreatedByOkteto(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewSimpleClientset(&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: RegistrySecretName, Namespace: "test"}})
+
+	if err := DestroyRegistrySecret(ctx, "test", c); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.CoreV1().Secrets("test").Get(ctx, RegistrySecretName, metav1.GetOptions{}); err != nil {
+		t.Fatalf("secret not created by okteto was deleted: %s", err)
+	}
+}
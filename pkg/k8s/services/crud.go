@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"strings"
 
+	okLabels "github.com/okteto/okteto/pkg/k8s/labels"
 	"github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/model"
 	apiv1 "k8s.io/api/core/v1"
@@ -37,7 +38,7 @@ func CreateDev(ctx context.Context, dev *model.Dev, c *kubernetes.Clientset) err
 
 	if old.Name == "" {
 		log.Infof("creating service '%s'", s.Name)
-		_, err = sClient.Create(ctx, s, metav1.CreateOptions{})
+		_, err = sClient.Create(ctx, s, metav1.CreateOptions{FieldManager: okLabels.FieldManager})
 		if err != nil {
 			return fmt.Errorf("error creating kubernetes service: %s", err)
 		}
@@ -46,7 +47,7 @@ func CreateDev(ctx context.Context, dev *model.Dev, c *kubernetes.Clientset) err
 		log.Infof("updating service '%s'", s.Name)
 		old.Spec.Ports = s.Spec.Ports
 		old.Annotations = s.Annotations
-		_, err = sClient.Update(ctx, old, metav1.UpdateOptions{})
+		_, err = sClient.Update(ctx, old, metav1.UpdateOptions{FieldManager: okLabels.FieldManager})
 		if err != nil {
 			return fmt.Errorf("error updating kubernetes service: %s", err)
 		}
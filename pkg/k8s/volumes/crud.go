@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/okteto/okteto/pkg/config"
+	okLabels "github.com/okteto/okteto/pkg/k8s/labels"
 	"github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/model"
 
@@ -39,10 +40,15 @@ func Create(ctx context.Context, dev *model.Dev, c *kubernetes.Clientset) error
 		return fmt.Errorf("error getting kubernetes volume claim: %s", err)
 	}
 	if k8Volume.Name != "" {
+		if needsExpansion(k8Volume, dev) {
+			if err := expand(ctx, k8Volume, dev, c); err != nil {
+				return err
+			}
+		}
 		return checkPVCValues(k8Volume, dev)
 	}
 	log.Infof("creating volume claim '%s'", pvc.Name)
-	_, err = vClient.Create(ctx, pvc, metav1.CreateOptions{})
+	_, err = vClient.Create(ctx, pvc, metav1.CreateOptions{FieldManager: okLabels.FieldManager})
 	if err != nil {
 		return fmt.Errorf("error creating kubernetes volume claim: %s", err)
 	}
@@ -0,0 +1,89 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package volumes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/okteto/okteto/pkg/k8s/labels"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// needsExpansion returns if the manifest requests a bigger volume than the current one
+func needsExpansion(pvc *apiv1.PersistentVolumeClaim, dev *model.Dev) bool {
+	currentSize, ok := pvc.Spec.Resources.Requests[apiv1.ResourceStorage]
+	if !ok {
+		return false
+	}
+
+	return currentSize.Cmp(resource.MustParse(dev.PersistentVolumeSize())) < 0
+}
+
+// expand resizes the volume claim to the size of the manifest, if its storage class allows volume expansion
+func expand(ctx context.Context, pvc *apiv1.PersistentVolumeClaim, dev *model.Dev, c kubernetes.Interface) error {
+	currentSize := pvc.Spec.Resources.Requests[apiv1.ResourceStorage]
+	sc, err := getStorageClassName(ctx, pvc, c)
+	if err != nil {
+		return err
+	}
+
+	storageClass, err := c.StorageV1().StorageClasses().Get(ctx, sc, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error getting storage class '%s': %s", sc, err)
+	}
+
+	if storageClass.AllowVolumeExpansion == nil || !*storageClass.AllowVolumeExpansion {
+		return fmt.Errorf(
+			"current okteto volume size is '%s' instead of '%s' and the storage class '%s' doesn't allow volume expansion. Run 'okteto down -v' and try again",
+			currentSize.String(),
+			dev.PersistentVolumeSize(),
+			sc,
+		)
+	}
+
+	log.Infof("expanding volume claim '%s' from %s to %s", pvc.Name, currentSize.String(), dev.PersistentVolumeSize())
+	pvc.Spec.Resources.Requests[apiv1.ResourceStorage] = resource.MustParse(dev.PersistentVolumeSize())
+	updated, err := c.CoreV1().PersistentVolumeClaims(pvc.Namespace).Update(ctx, pvc, metav1.UpdateOptions{FieldManager: labels.FieldManager})
+	if err != nil {
+		return fmt.Errorf("error expanding kubernetes volume claim: %s", err)
+	}
+
+	*pvc = *updated
+	return nil
+}
+
+func getStorageClassName(ctx context.Context, pvc *apiv1.PersistentVolumeClaim, c kubernetes.Interface) (string, error) {
+	if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != "" {
+		return *pvc.Spec.StorageClassName, nil
+	}
+
+	scList, err := c.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("error getting storage classes: %s", err)
+	}
+
+	for i := range scList.Items {
+		if scList.Items[i].Annotations[labels.DefaultStorageClassAnnotation] == "true" {
+			return scList.Items[i].Name, nil
+		}
+	}
+
+	return "", fmt.Errorf("okteto volume '%s' has no storage class to expand it. Run 'okteto down -v' and try again", pvc.Name)
+}
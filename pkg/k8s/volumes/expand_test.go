@@ -0,0 +1,93 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package volumes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/k8s/labels"
+	"github.com/okteto/okteto/pkg/model"
+	apiv1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_expand(t *testing.T) {
+	allow := true
+	expandable := &storagev1.StorageClass{
+		ObjectMeta:           metav1.ObjectMeta{Name: "expandable", Annotations: map[string]string{labels.DefaultStorageClassAnnotation: "true"}},
+		AllowVolumeExpansion: &allow,
+	}
+	fixed := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "fixed"}}
+
+	var tests = []struct {
+		name         string
+		storageClass string
+		wantError    bool
+	}{
+		{name: "default-storage-class", storageClass: "", wantError: false},
+		{name: "expandable", storageClass: "expandable", wantError: false},
+		{name: "fixed", storageClass: "fixed", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pvc := &apiv1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "okteto-dev", Namespace: "test"},
+				Spec: apiv1.PersistentVolumeClaimSpec{
+					Resources: apiv1.ResourceRequirements{
+						Requests: apiv1.ResourceList{apiv1.ResourceStorage: resource.MustParse("2Gi")},
+					},
+				},
+			}
+			if tt.storageClass != "" {
+				pvc.Spec.StorageClassName = &tt.storageClass
+			}
+
+			dev := &model.Dev{
+				Name:                 "dev",
+				Namespace:            "test",
+				PersistentVolumeInfo: &model.PersistentVolumeInfo{Enabled: true, Size: "5Gi", StorageClass: tt.storageClass},
+			}
+
+			if !needsExpansion(pvc, dev) {
+				t.Fatal("volume should need an expansion")
+			}
+
+			c := fake.NewSimpleClientset(pvc.DeepCopy(), expandable, fixed)
+			err := expand(context.Background(), pvc, dev, c)
+			if tt.wantError {
+				if err == nil {
+					t.Fatal("expansion didn't fail")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expansion failed: %s", err)
+			}
+
+			if err := checkPVCValues(pvc, dev); err != nil {
+				t.Errorf("volume wasn't expanded: %s", err)
+			}
+
+			if needsExpansion(pvc, dev) {
+				t.Error("volume still needs an expansion")
+			}
+		})
+	}
+}
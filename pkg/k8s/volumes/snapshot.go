@@ -0,0 +1,189 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package volumes
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/okteto/okteto/pkg/config"
+	"github.com/okteto/okteto/pkg/k8s/labels"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	apiv1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	snapshotGroup      = "snapshot.storage.k8s.io"
+	snapshotKind       = "VolumeSnapshot"
+	snapshotLabel      = "volume.dev.okteto.com"
+	snapshotTimeFormat = "20060102150405"
+)
+
+var snapshotResource = schema.GroupVersionResource{Group: snapshotGroup, Version: "v1beta1", Resource: "volumesnapshots"}
+
+//Snapshot takes a snapshot of the volume of a development container, waits until it's ready and returns its name
+func Snapshot(ctx context.Context, dev *model.Dev, dc dynamic.Interface) (string, error) {
+	s := newSnapshot(dev, time.Now())
+	log.Infof("creating volume snapshot '%s'", s.GetName())
+	if _, err := dc.Resource(snapshotResource).Namespace(dev.Namespace).Create(ctx, s, metav1.CreateOptions{FieldManager: labels.FieldManager}); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return "", fmt.Errorf("volume snapshots are not available in your cluster")
+		}
+		return "", fmt.Errorf("error creating volume snapshot: %s", err)
+	}
+
+	return s.GetName(), waitUntilSnapshotReady(ctx, dev.Namespace, s.GetName(), dc)
+}
+
+//GetLatestSnapshot returns the name of the latest snapshot of the volume of a development container
+func GetLatestSnapshot(ctx context.Context, dev *model.Dev, dc dynamic.Interface) (string, error) {
+	list, err := dc.Resource(snapshotResource).Namespace(dev.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", snapshotLabel, dev.Name),
+	})
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return "", fmt.Errorf("volume snapshots are not available in your cluster")
+		}
+		return "", fmt.Errorf("error listing volume snapshots: %s", err)
+	}
+
+	name := latestSnapshot(list.Items)
+	if name == "" {
+		return "", fmt.Errorf("there are no snapshots of the volume of '%s'", dev.Name)
+	}
+
+	return name, nil
+}
+
+//Restore recreates the volume of a development container from a snapshot.
+//The volume is only destroyed if the snapshot exists and is ready to use
+func Restore(ctx context.Context, dev *model.Dev, snapshot string, c *kubernetes.Clientset, dc dynamic.Interface) error {
+	if err := checkSnapshotReady(ctx, dev.Namespace, snapshot, dc); err != nil {
+		return err
+	}
+
+	if err := Destroy(ctx, dev, c); err != nil {
+		return err
+	}
+
+	group := snapshotGroup
+	pvc := translate(dev)
+	pvc.Spec.DataSource = &apiv1.TypedLocalObjectReference{
+		APIGroup: &group,
+		Kind:     snapshotKind,
+		Name:     snapshot,
+	}
+
+	log.Infof("restoring volume claim '%s' from snapshot '%s'", pvc.Name, snapshot)
+	if _, err := c.CoreV1().PersistentVolumeClaims(dev.Namespace).Create(ctx, pvc, metav1.CreateOptions{FieldManager: labels.FieldManager}); err != nil {
+		return fmt.Errorf("error creating kubernetes volume claim: %s", err)
+	}
+
+	return nil
+}
+
+// checkSnapshotReady returns an error if a snapshot doesn't exist or isn't ready to use
+func checkSnapshotReady(ctx context.Context, namespace, name string, dc dynamic.Interface) error {
+	s, err := dc.Resource(snapshotResource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return fmt.Errorf("volume snapshot '%s' doesn't exist", name)
+		}
+		return fmt.Errorf("error getting volume snapshot '%s': %s", name, err)
+	}
+
+	if ready, found, _ := unstructured.NestedBool(s.Object, "status", "readyToUse"); !found || !ready {
+		return fmt.Errorf("volume snapshot '%s' is not ready to use", name)
+	}
+
+	return nil
+}
+
+func newSnapshot(dev *model.Dev, now time.Time) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": snapshotResource.GroupVersion().String(),
+			"kind":       snapshotKind,
+			"metadata": map[string]interface{}{
+				"name": fmt.Sprintf("%s-%s", dev.GetVolumeName(), now.UTC().Format(snapshotTimeFormat)),
+				"labels": map[string]interface{}{
+					labels.DevLabel: "true",
+					snapshotLabel:   dev.Name,
+				},
+			},
+			"spec": map[string]interface{}{
+				"source": map[string]interface{}{
+					"persistentVolumeClaimName": dev.GetVolumeName(),
+				},
+			},
+		},
+	}
+}
+
+func latestSnapshot(items []unstructured.Unstructured) string {
+	name := ""
+	var latest time.Time
+	for i := range items {
+		created := items[i].GetCreationTimestamp().Time
+		if name == "" || created.After(latest) {
+			name = items[i].GetName()
+			latest = created
+		}
+	}
+
+	return name
+}
+
+func waitUntilSnapshotReady(ctx context.Context, namespace, name string, dc dynamic.Interface) error {
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+	to := 3 * config.GetTimeout() // 90 seconds
+	timeout := time.Now().Add(to)
+
+	for {
+		s, err := dc.Resource(snapshotResource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("error getting volume snapshot '%s': %s", name, err)
+		}
+
+		if msg, found, _ := unstructured.NestedString(s.Object, "status", "error", "message"); found && msg != "" {
+			return fmt.Errorf("volume snapshot '%s' failed: %s", name, msg)
+		}
+
+		if ready, found, _ := unstructured.NestedBool(s.Object, "status", "readyToUse"); found && ready {
+			log.Infof("volume snapshot '%s' is ready", name)
+			return nil
+		}
+
+		if time.Now().After(timeout) {
+			return fmt.Errorf("volume snapshot '%s' wasn't ready after %s", name, to.String())
+		}
+
+		select {
+		case <-ticker.C:
+			continue
+		case <-ctx.Done():
+			log.Info("call to volumes.waitUntilSnapshotReady cancelled")
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package volumes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/okteto/okteto/pkg/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func Test_newSnapshot(t *testing.T) {
+	dev := &model.Dev{Name: "dev"}
+	now := time.Date(2020, 11, 3, 10, 2, 0, 0, time.UTC)
+	s := newSnapshot(dev, now)
+
+	if s.GetName() != "okteto-dev-20201103100200" {
+		t.Errorf("wrong snapshot name: %s", s.GetName())
+	}
+
+	if s.GetLabels()[snapshotLabel] != "dev" {
+		t.Errorf("wrong snapshot labels: %v", s.GetLabels())
+	}
+
+	source, _, _ := unstructured.NestedString(s.Object, "spec", "source", "persistentVolumeClaimName")
+	if source != dev.GetVolumeName() {
+		t.Errorf("wrong snapshot source: %s", source)
+	}
+}
+
+func Test_latestSnapshot(t *testing.T) {
+	if name := latestSnapshot(nil); name != "" {
+		t.Errorf("expected no snapshot, got %s", name)
+	}
+
+	now := time.Now()
+	items := []unstructured.Unstructured{}
+	for i, name := range []string{"old", "latest", "older"} {
+		s := unstructured.Unstructured{Object: map[string]interface{}{}}
+		s.SetName(name)
+		offset := map[int]time.Duration{0: -time.Hour, 1: 0, 2: -2 * time.Hour}[i]
+		s.SetCreationTimestamp(metav1.NewTime(now.Add(offset)))
+		items = append(items, s)
+	}
+
+	if name := latestSnapshot(items); name != "latest" {
+		t.Errorf("expected 'latest', got %s", name)
+	}
+}
+
+func Test_checkSnapshotReady(t *testing.T) {
+	dev := &model.Dev{Name: "dev", Namespace: "test"}
+	ready := newSnapshot(dev, time.Now())
+	ready.SetNamespace(dev.Namespace)
+	ready.SetName("ready")
+	if err := unstructured.SetNestedField(ready.Object, true, "status", "readyToUse"); err != nil {
+		t.Fatal(err)
+	}
+	pending := newSnapshot(dev, time.Now())
+	pending.SetNamespace(dev.Namespace)
+	pending.SetName("pending")
+
+	dc := fake.NewSimpleDynamicClient(runtime.NewScheme(), ready, pending)
+	ctx := context.Background()
+
+	if err := checkSnapshotReady(ctx, dev.Namespace, "ready", dc); err != nil {
+		t.Errorf("ready snapshot was rejected: %s", err)
+	}
+	if err := checkSnapshotReady(ctx, dev.Namespace, "pending", dc); err == nil {
+		t.Error("snapshot that isn't ready was accepted")
+	}
+	if err := checkSnapshotReady(ctx, dev.Namespace, "missing", dc); err == nil {
+		t.Error("missing snapshot was accepted")
+	}
+}
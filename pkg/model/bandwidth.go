@@ -0,0 +1,70 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Bandwidth is a transfer rate in bits per second
+type Bandwidth int64
+
+var bandwidthUnits = []struct {
+	suffix     string
+	multiplier int64
+}{
+	{suffix: "gbps", multiplier: 1000 * 1000 * 1000},
+	{suffix: "mbps", multiplier: 1000 * 1000},
+	{suffix: "kbps", multiplier: 1000},
+	{suffix: "bps", multiplier: 1},
+}
+
+// ParseBandwidth parses a transfer rate such as '5Mbps'. Supported units are bps, Kbps, Mbps and Gbps
+func ParseBandwidth(raw string) (Bandwidth, error) {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	for _, u := range bandwidthUnits {
+		if !strings.HasSuffix(value, u.suffix) {
+			continue
+		}
+
+		n, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(value, u.suffix)), 64)
+		if err != nil || n <= 0 {
+			break
+		}
+
+		return Bandwidth(n * float64(u.multiplier)), nil
+	}
+
+	return 0, fmt.Errorf("Wrong bandwidth '%s', must be a positive number followed by 'bps', 'Kbps', 'Mbps' or 'Gbps'", raw)
+}
+
+// BytesPerSecond returns the transfer rate in bytes per second
+func (b Bandwidth) BytesPerSecond() int64 {
+	return int64(b) / 8
+}
+
+func (b Bandwidth) String() string {
+	switch {
+	case b >= 1000*1000*1000 && b%(1000*1000*1000) == 0:
+		return fmt.Sprintf("%dGbps", b/(1000*1000*1000))
+	case b >= 1000*1000 && b%(1000*1000) == 0:
+		return fmt.Sprintf("%dMbps", b/(1000*1000))
+	case b >= 1000 && b%1000 == 0:
+		return fmt.Sprintf("%dKbps", b/1000)
+	default:
+		return fmt.Sprintf("%dbps", int64(b))
+	}
+}
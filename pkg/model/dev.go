@@ -17,6 +17,8 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -24,12 +26,14 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 
 	"github.com/a8m/envsubst"
 	"github.com/google/uuid"
 	"github.com/okteto/okteto/pkg/k8s/labels"
 	"github.com/okteto/okteto/pkg/log"
+	"github.com/subosito/gotenv"
 	yaml "gopkg.in/yaml.v2"
 	appsv1 "k8s.io/api/apps/v1"
 	apiv1 "k8s.io/api/core/v1"
@@ -42,6 +46,10 @@ const (
 	Localhost                   = "localhost"
 	oktetoSSHServerPortVariable = "OKTETO_REMOTE_PORT"
 	oktetoDefaultSSHServerPort  = 2222
+	oktetoDefaultSSHPoolSize    = 1
+	oktetoDefaultSSHKeepalive   = 30 * time.Second
+	//oktetoDefaultSSHMaxMissedKeepalives number of missed keepalives before the ssh connection is considered lost
+	oktetoDefaultSSHMaxMissedKeepalives = 3
 	//OktetoDefaultPVSize default volume size
 	OktetoDefaultPVSize = "2Gi"
 	//OktetoUpCmd up command
@@ -76,9 +84,29 @@ const (
 	//DefaultImage default image for sandboxes
 	DefaultImage = "okteto/dev:latest"
 
+	//DockerfileBuilder builds images from a Dockerfile with BuildKit
+	DockerfileBuilder = "dockerfile"
+	//BuildpacksBuilder builds images from source code with Cloud Native Buildpacks
+	BuildpacksBuilder = "buildpacks"
+	//DefaultBuildpacksBuilderImage default builder image of Cloud Native Buildpacks
+	DefaultBuildpacksBuilderImage = "paketobuildpacks/builder:base"
+
+	//DevTarget is the stage of a multi-stage Dockerfile built by okteto up when the manifest doesn't define a target
+	DevTarget = "dev"
+
+	//CacheScopeUser shares the build cache mounts between all the builds of the user
+	CacheScopeUser = "user"
+	//CacheScopeNamespace shares the build cache mounts between the builds of the user in the same namespace
+	CacheScopeNamespace = "namespace"
+	//CacheScopeService shares the build cache mounts between the builds of the user of the same service and namespace
+	CacheScopeService = "service"
+
 	//TranslationVersion version of the translation schema
 	TranslationVersion = "1.0"
 
+	//SecurityPolicyRestricted generates pods compliant with the restricted Pod Security Standard
+	SecurityPolicyRestricted = "restricted"
+
 	//ResourceAMDGPU amd.com/gpu resource
 	ResourceAMDGPU apiv1.ResourceName = "amd.com/gpu"
 	//ResourceNVIDIAGPU nvidia.com/gpu resource
@@ -99,8 +127,14 @@ var (
 	// ValidKubeNameRegex is the regex to validate a kubernetes resource name
 	ValidKubeNameRegex = regexp.MustCompile(`[^a-z0-9\-]+`)
 
+	// gitURLSuffix matches the http URLs of git repositories, optionally followed by '#ref:subdir'
+	gitURLSuffix = regexp.MustCompile(`\.git(?:#.+)?$`)
+
 	rootUser int64
 
+	// nonRootUser is the default user of the development containers when the restricted security policy is enabled
+	nonRootUser int64 = 1000
+
 	// DevReplicas is the number of dev replicas
 	DevReplicas int32 = 1
 
@@ -111,39 +145,63 @@ var (
 
 //Dev represents a development container
 type Dev struct {
-	Name                 string             `json:"name" yaml:"name"`
-	Labels               map[string]string  `json:"labels,omitempty" yaml:"labels,omitempty"`
-	Annotations          map[string]string  `json:"annotations,omitempty" yaml:"annotations,omitempty"`
-	Tolerations          []apiv1.Toleration `json:"tolerations,omitempty" yaml:"tolerations,omitempty"`
-	Context              string             `json:"context,omitempty" yaml:"context,omitempty"`
-	Namespace            string             `json:"namespace,omitempty" yaml:"namespace,omitempty"`
-	Container            string             `json:"container,omitempty" yaml:"container,omitempty"`
-	EmptyImage           bool
-	Image                *BuildInfo            `json:"image,omitempty" yaml:"image,omitempty"`
-	Push                 *BuildInfo            `json:"-" yaml:"push,omitempty"`
-	ImagePullPolicy      apiv1.PullPolicy      `json:"imagePullPolicy,omitempty" yaml:"imagePullPolicy,omitempty"`
-	Environment          []EnvVar              `json:"environment,omitempty" yaml:"environment,omitempty"`
-	Secrets              []Secret              `json:"secrets,omitempty" yaml:"secrets,omitempty"`
-	Command              Command               `json:"command,omitempty" yaml:"command,omitempty"`
-	Healthchecks         bool                  `json:"healthchecks,omitempty" yaml:"healthchecks,omitempty"`
-	WorkDir              string                `json:"workdir,omitempty" yaml:"workdir,omitempty"`
-	MountPath            string                `json:"mountpath,omitempty" yaml:"mountpath,omitempty"`
-	SubPath              string                `json:"subpath,omitempty" yaml:"subpath,omitempty"`
-	SecurityContext      *SecurityContext      `json:"securityContext,omitempty" yaml:"securityContext,omitempty"`
-	RemotePort           int                   `json:"remote,omitempty" yaml:"remote,omitempty"`
-	SSHServerPort        int                   `json:"sshServerPort,omitempty" yaml:"sshServerPort,omitempty"`
-	Volumes              []Volume              `json:"volumes,omitempty" yaml:"volumes,omitempty"`
-	ExternalVolumes      []ExternalVolume      `json:"externalVolumes,omitempty" yaml:"externalVolumes,omitempty"`
-	Syncs                []Sync                `json:"sync,omitempty" yaml:"sync,omitempty"`
-	parentSyncFolder     string                `json:"-" yaml:"-"`
-	Forward              []Forward             `json:"forward,omitempty" yaml:"forward,omitempty"`
-	Reverse              []Reverse             `json:"reverse,omitempty" yaml:"reverse,omitempty"`
-	Interface            string                `json:"interface,omitempty" yaml:"interface,omitempty"`
-	Resources            ResourceRequirements  `json:"resources,omitempty" yaml:"resources,omitempty"`
-	Services             []*Dev                `json:"services,omitempty" yaml:"services,omitempty"`
-	PersistentVolumeInfo *PersistentVolumeInfo `json:"persistentVolume,omitempty" yaml:"persistentVolume,omitempty"`
+	Name                   string             `json:"name" yaml:"name"`
+	Labels                 map[string]string  `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Annotations            map[string]string  `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Tolerations            []apiv1.Toleration `json:"tolerations,omitempty" yaml:"tolerations,omitempty"`
+	NodeSelector           map[string]string  `json:"nodeSelector,omitempty" yaml:"nodeSelector,omitempty"`
+	Affinity               *Affinity          `json:"affinity,omitempty" yaml:"affinity,omitempty"`
+	PriorityClassName      string             `json:"priorityClassName,omitempty" yaml:"priorityClassName,omitempty"`
+	ServiceAccount         string             `json:"serviceAccount,omitempty" yaml:"serviceAccount,omitempty"`
+	PodLabels              *PodLabels         `json:"podLabels,omitempty" yaml:"podLabels,omitempty"`
+	SecurityPolicy         string             `json:"securityPolicy,omitempty" yaml:"securityPolicy,omitempty"`
+	Context                string             `json:"context,omitempty" yaml:"context,omitempty"`
+	Namespace              string             `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Container              string             `json:"container,omitempty" yaml:"container,omitempty"`
+	ExcludeContainers      []string           `json:"excludeContainers,omitempty" yaml:"excludeContainers,omitempty"`
+	EmptyImage             bool
+	Image                  *BuildInfo            `json:"image,omitempty" yaml:"image,omitempty"`
+	Push                   *BuildInfo            `json:"-" yaml:"push,omitempty"`
+	ImagePullPolicy        apiv1.PullPolicy      `json:"imagePullPolicy,omitempty" yaml:"imagePullPolicy,omitempty"`
+	Environment            []EnvVar              `json:"environment,omitempty" yaml:"environment,omitempty"`
+	Secrets                []Secret              `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+	Command                Command               `json:"command,omitempty" yaml:"command,omitempty"`
+	Healthchecks           bool                  `json:"healthchecks,omitempty" yaml:"healthchecks,omitempty"`
+	Probes                 *Probes               `json:"probes,omitempty" yaml:"probes,omitempty"`
+	Lifecycle              *Lifecycle            `json:"lifecycle,omitempty" yaml:"lifecycle,omitempty"`
+	WorkDir                string                `json:"workdir,omitempty" yaml:"workdir,omitempty"`
+	MountPath              string                `json:"mountpath,omitempty" yaml:"mountpath,omitempty"`
+	SubPath                string                `json:"subpath,omitempty" yaml:"subpath,omitempty"`
+	SecurityContext        *SecurityContext      `json:"securityContext,omitempty" yaml:"securityContext,omitempty"`
+	RemotePort             int                   `json:"remote,omitempty" yaml:"remote,omitempty"`
+	SSHServerPort          int                   `json:"sshServerPort,omitempty" yaml:"sshServerPort,omitempty"`
+	SSHPoolSize            int                   `json:"sshPoolSize,omitempty" yaml:"sshPoolSize,omitempty"`
+	SSHKeepalive           time.Duration         `json:"sshKeepalive,omitempty" yaml:"sshKeepalive,omitempty"`
+	SSHMaxMissedKeepalives int                   `json:"sshMaxMissedKeepalives,omitempty" yaml:"sshMaxMissedKeepalives,omitempty"`
+	SSHWebSocket           string                `json:"sshWebSocket,omitempty" yaml:"sshWebSocket,omitempty"`
+	JumpHost               *JumpHost             `json:"jumpHost,omitempty" yaml:"jumpHost,omitempty"`
+	SSHIdentities          []string              `json:"sshIdentities,omitempty" yaml:"sshIdentities,omitempty"`
+	SSHAudit               bool                  `json:"sshAudit,omitempty" yaml:"sshAudit,omitempty"`
+	Timeout                Timeout               `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	TCP                    TCPOptions            `json:"tcp,omitempty" yaml:"tcp,omitempty"`
+	Volumes                []Volume              `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+	ExternalVolumes        []ExternalVolume      `json:"externalVolumes,omitempty" yaml:"externalVolumes,omitempty"`
+	Syncs                  []Sync                `json:"sync,omitempty" yaml:"sync,omitempty"`
+	parentSyncFolder       string                `json:"-" yaml:"-"`
+	Forward                []Forward             `json:"forward,omitempty" yaml:"forward,omitempty"`
+	Reverse                []Reverse             `json:"reverse,omitempty" yaml:"reverse,omitempty"`
+	Interface              string                `json:"interface,omitempty" yaml:"interface,omitempty"`
+	Proxy                  string                `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	Resources              ResourceRequirements  `json:"resources,omitempty" yaml:"resources,omitempty"`
+	Services               []*Dev                `json:"services,omitempty" yaml:"services,omitempty"`
+	PersistentVolumeInfo   *PersistentVolumeInfo `json:"persistentVolume,omitempty" yaml:"persistentVolume,omitempty"`
+	InitContainer          InitContainer         `json:"initContainer,omitempty" yaml:"initContainer,omitempty"`
+	Hooks                  Hooks                 `json:"hooks,omitempty" yaml:"hooks,omitempty"`
 }
 
+//Affinity represents the affinity rules of the development container pods
+type Affinity apiv1.Affinity
+
 //Command represents the start command of a development contaianer
 type Command struct {
 	Values []string
@@ -161,14 +219,44 @@ type BuildInfo struct {
 
 // BuildInfoRaw represents the build info for serialization
 type BuildInfoRaw struct {
-	Name       string   `yaml:"name,omitempty"`
-	Context    string   `yaml:"context,omitempty"`
-	Dockerfile string   `yaml:"dockerfile,omitempty"`
-	CacheFrom  []string `yaml:"cache_from,omitempty"`
-	Target     string   `yaml:"target,omitempty"`
-	Args       []EnvVar `yaml:"args,omitempty"`
+	Name       string            `yaml:"name,omitempty"`
+	Context    string            `yaml:"context,omitempty"`
+	Dockerfile string            `yaml:"dockerfile,omitempty"`
+	CacheFrom  []string          `yaml:"cache_from,omitempty"`
+	Target     string            `yaml:"target,omitempty"`
+	Platform   string            `yaml:"platform,omitempty"`
+	Args       BuildArgs         `yaml:"args,omitempty"`
+	Secrets    map[string]string `yaml:"secrets,omitempty"`
+	Ignore     []string          `yaml:"ignore,omitempty"`
+	Frontend   BuildFrontend     `yaml:"frontend,omitempty"`
+	// Compression is the compression of the pushed layers: gzip, uncompressed or estargz
+	Compression string `yaml:"compression,omitempty"`
+	// Builder is the builder of the image: dockerfile or buildpacks
+	Builder string `yaml:"builder,omitempty"`
+	// BuilderImage is the builder image of Cloud Native Buildpacks
+	BuilderImage string `yaml:"builderImage,omitempty"`
+	// CacheScope is the scope of the build cache mounts: user, namespace or service
+	CacheScope string `yaml:"cacheScope,omitempty"`
+	// TagTemplate is the template of the tag of the dev images, like '{{.Namespace}}-{{.GitSHA}}'
+	TagTemplate string `yaml:"tagTemplate,omitempty"`
+	// Labels are custom labels of the image, added to the standard OCI labels
+	Labels map[string]string `yaml:"labels,omitempty"`
+	// WarmTarget is the stage built by 'okteto build warm' to warm up the build cache
+	WarmTarget string `yaml:"warmTarget,omitempty"`
 }
 
+// BuildFrontend represents the BuildKit frontend image that builds an image, and the attributes passed to it
+type BuildFrontend struct {
+	Image string            `yaml:"image,omitempty"`
+	Attrs map[string]string `yaml:"attrs,omitempty"`
+}
+
+// BuildFrontendRaw represents the BuildKit frontend for serialization
+type BuildFrontendRaw BuildFrontend
+
+// BuildArgs represents the build args of an image, defined as a list of 'NAME=value' or as a map
+type BuildArgs []EnvVar
+
 // Volume represents a volume in the development container
 type Volume struct {
 	LocalPath  string
@@ -179,6 +267,15 @@ type Volume struct {
 type Sync struct {
 	LocalPath  string
 	RemotePath string
+	// Exclude are the glob patterns of the files of the folder that aren't synchronized, like '**/node_modules'
+	Exclude []string
+}
+
+// syncRaw represents a sync folder with exclusions for serialization
+type syncRaw struct {
+	LocalPath  string   `yaml:"localPath"`
+	RemotePath string   `yaml:"remotePath"`
+	Exclude    []string `yaml:"exclude,omitempty"`
 }
 
 // ExternalVolume represents a external volume in the development container
@@ -195,6 +292,18 @@ type PersistentVolumeInfo struct {
 	Size         string `json:"size,omitempty" yaml:"size,omitempty"`
 }
 
+// PodLabels represents the labels of the pod template propagated to the development container pods
+type PodLabels struct {
+	Strip []string `json:"strip,omitempty" yaml:"strip,omitempty"`
+	Keep  []string `json:"keep,omitempty" yaml:"keep,omitempty"`
+}
+
+// InitContainer represents the okteto init container of the development container pod
+type InitContainer struct {
+	Image     string               `json:"image,omitempty" yaml:"image,omitempty"`
+	Resources ResourceRequirements `json:"resources,omitempty" yaml:"resources,omitempty"`
+}
+
 // SecurityContext represents a pod security context
 type SecurityContext struct {
 	RunAsUser    *int64        `json:"runAsUser,omitempty" yaml:"runAsUser,omitempty"`
@@ -222,10 +331,25 @@ type Secret struct {
 	Mode       int32
 }
 
-// Reverse represents a remote forward port
+// JumpHost represents a bastion used to reach the SSH server of the development container
+type JumpHost struct {
+	Host       string `json:"host,omitempty" yaml:"host,omitempty"`
+	User       string `json:"user,omitempty" yaml:"user,omitempty"`
+	PrivateKey string `json:"privateKey,omitempty" yaml:"privateKey,omitempty"`
+}
+
+// Timeout represents the timeouts of the development container
+type Timeout struct {
+	SSH     time.Duration `json:"ssh,omitempty" yaml:"ssh,omitempty"`
+	Drain   time.Duration `json:"drain,omitempty" yaml:"drain,omitempty"`
+	Failure time.Duration `json:"failure,omitempty" yaml:"failure,omitempty"`
+}
+
+// Reverse represents a remote forward port or a range of consecutive ports
 type Reverse struct {
 	Remote int
 	Local  int
+	Count  int
 }
 
 // ResourceRequirements describes the compute resource requirements.
@@ -239,11 +363,20 @@ type ResourceList map[apiv1.ResourceName]resource.Quantity
 
 //Get returns a Dev object from a given file
 func Get(devPath string) (*Dev, error) {
-	b, err := ioutil.ReadFile(devPath)
+	return GetWithProfiles(devPath, nil)
+}
+
+//GetWithProfiles returns a Dev object from a given file with the given profiles of the manifest applied
+func GetWithProfiles(devPath string, profiles []string) (*Dev, error) {
+	b, err := readManifest(devPath, profiles)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := loadDotEnv(filepath.Dir(devPath)); err != nil {
+		return nil, err
+	}
+
 	dev, err := Read(b)
 	if err != nil {
 		return nil, err
@@ -330,10 +463,15 @@ func (dev *Dev) loadAbsPaths(devPath string) error {
 	if err != nil {
 		return err
 	}
-	dev.Image.Context = loadAbsPath(devDir, dev.Image.Context)
-	dev.Image.Dockerfile = loadAbsPath(devDir, dev.Image.Dockerfile)
-	dev.Push.Context = loadAbsPath(devDir, dev.Push.Context)
-	dev.Push.Dockerfile = loadAbsPath(devDir, dev.Push.Dockerfile)
+	dev.Image.loadAbsPaths(devDir)
+	dev.Push.loadAbsPaths(devDir)
+	dev.Hooks.dir = devDir
+	for id, src := range dev.Image.Secrets {
+		dev.Image.Secrets[id] = loadAbsPath(devDir, src)
+	}
+	for id, src := range dev.Push.Secrets {
+		dev.Push.Secrets[id] = loadAbsPath(devDir, src)
+	}
 	dev.loadVolumeAbsPaths(devDir)
 	for _, s := range dev.Services {
 		s.loadVolumeAbsPaths(devDir)
@@ -353,6 +491,25 @@ func (dev *Dev) loadVolumeAbsPaths(folder string) {
 	}
 }
 
+// loadAbsPaths makes the local paths of the build relative to folder, the dockerfile of a git build context is relative to the repository
+func (build *BuildInfo) loadAbsPaths(folder string) {
+	if IsGitBuildContext(build.Context) {
+		return
+	}
+	build.Context = loadAbsPath(folder, build.Context)
+	build.Dockerfile = loadAbsPath(folder, build.Dockerfile)
+}
+
+//IsGitBuildContext returns if the build context is a git repository URL instead of a local directory
+func IsGitBuildContext(context string) bool {
+	for _, prefix := range []string{"git://", "git@", "github.com/"} {
+		if strings.HasPrefix(context, prefix) {
+			return true
+		}
+	}
+	return (strings.HasPrefix(context, "http://") || strings.HasPrefix(context, "https://")) && gitURLSuffix.MatchString(context)
+}
+
 func loadAbsPath(folder, path string) string {
 	if filepath.IsAbs(path) {
 		return path
@@ -373,6 +530,15 @@ func (dev *Dev) expandEnvVars() error {
 	if err := dev.loadLabels(); err != nil {
 		return err
 	}
+	if err := dev.loadStrings(); err != nil {
+		return err
+	}
+	if err := dev.loadCommand(); err != nil {
+		return err
+	}
+	if err := dev.Hooks.loadCommands(); err != nil {
+		return err
+	}
 
 	return dev.loadImage()
 }
@@ -421,19 +587,97 @@ func (dev *Dev) loadLabels() error {
 	return nil
 }
 
+// loadStrings expands the environment variables of the string fields of the manifest that aren't expanded when they are parsed
+func (dev *Dev) loadStrings() error {
+	fields := []*string{&dev.Container, &dev.ServiceAccount, &dev.PriorityClassName, &dev.WorkDir, &dev.MountPath, &dev.SubPath, &dev.Interface, &dev.SSHWebSocket, &dev.InitContainer.Image}
+	for i := range dev.Syncs {
+		fields = append(fields, &dev.Syncs[i].RemotePath)
+	}
+	for i := range dev.Volumes {
+		fields = append(fields, &dev.Volumes[i].RemotePath)
+	}
+	for i := range dev.ExternalVolumes {
+		fields = append(fields, &dev.ExternalVolumes[i].Name, &dev.ExternalVolumes[i].SubPath, &dev.ExternalVolumes[i].MountPath)
+	}
+	if dev.PersistentVolumeInfo != nil {
+		fields = append(fields, &dev.PersistentVolumeInfo.StorageClass, &dev.PersistentVolumeInfo.Size)
+	}
+
+	var err error
+	for _, field := range fields {
+		if *field == "" {
+			continue
+		}
+		*field, err = ExpandEnv(*field)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, m := range []map[string]string{dev.Annotations, dev.NodeSelector} {
+		for k, v := range m {
+			m[k], err = ExpandEnv(v)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// loadCommand expands the environment variables of the command with the '${VAR}' notation.
+// Variables with the '$VAR' notation are left to the shell of the development container
+func (dev *Dev) loadCommand() error {
+	var err error
+	for i := range dev.Command.Values {
+		dev.Command.Values[i], err = expandBracedEnv(dev.Command.Values[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (dev *Dev) loadImage() error {
 	var err error
 	if dev.Image == nil {
 		dev.Image = &BuildInfo{}
 	}
-	if len(dev.Image.Name) > 0 {
-		dev.Image.Name, err = ExpandEnv(dev.Image.Name)
+	if err = dev.Image.expandEnvVars(); err != nil {
+		return err
+	}
+	if dev.Image.Name == "" {
+		dev.EmptyImage = true
+	}
+	if dev.Push != nil {
+		err = dev.Push.expandEnvVars()
+	}
+	return err
+}
+
+// expandEnvVars expands the environment variables of the image name and paths of a build
+func (b *BuildInfo) expandEnvVars() error {
+	var err error
+	for _, field := range []*string{&b.Name, &b.Context, &b.Dockerfile, &b.Target, &b.WarmTarget} {
+		if *field == "" {
+			continue
+		}
+		*field, err = ExpandEnv(*field)
 		if err != nil {
 			return err
 		}
 	}
-	if dev.Image.Name == "" {
-		dev.EmptyImage = true
+	for i := range b.CacheFrom {
+		b.CacheFrom[i], err = ExpandEnv(b.CacheFrom[i])
+		if err != nil {
+			return err
+		}
+	}
+	for k, v := range b.Labels {
+		b.Labels[k], err = ExpandEnv(v)
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -460,6 +704,37 @@ func (dev *Dev) setDefaults() error {
 	if dev.SSHServerPort == 0 {
 		dev.SSHServerPort = oktetoDefaultSSHServerPort
 	}
+	if dev.InitContainer.Image == "" {
+		dev.InitContainer.Image = OktetoBinImageTag
+	}
+	if dev.SSHPoolSize == 0 {
+		dev.SSHPoolSize = oktetoDefaultSSHPoolSize
+	}
+	if dev.SSHKeepalive == 0 {
+		dev.SSHKeepalive = oktetoDefaultSSHKeepalive
+	}
+	if dev.SSHMaxMissedKeepalives == 0 {
+		dev.SSHMaxMissedKeepalives = oktetoDefaultSSHMaxMissedKeepalives
+	}
+	if dev.JumpHost != nil {
+		if dev.JumpHost.Host != "" {
+			if _, _, err := net.SplitHostPort(dev.JumpHost.Host); err != nil {
+				dev.JumpHost.Host = net.JoinHostPort(dev.JumpHost.Host, "22")
+			}
+		}
+		privateKey, err := ExpandEnv(dev.JumpHost.PrivateKey)
+		if err != nil {
+			return err
+		}
+		dev.JumpHost.PrivateKey = privateKey
+	}
+	for i := range dev.SSHIdentities {
+		identity, err := ExpandEnv(dev.SSHIdentities[i])
+		if err != nil {
+			return err
+		}
+		dev.SSHIdentities[i] = identity
+	}
 	dev.setRunAsUserDefaults(dev)
 
 	for _, s := range dev.Services {
@@ -475,11 +750,13 @@ func (dev *Dev) setDefaults() error {
 		if s.Name != "" && len(s.Labels) > 0 {
 			return fmt.Errorf("'name' and 'labels' cannot be defined at the same time for service '%s'", s.Name)
 		}
-		s.Namespace = ""
 		s.Context = ""
 		s.setRunAsUserDefaults(dev)
 		s.Forward = make([]Forward, 0)
 		s.Reverse = make([]Reverse, 0)
+		s.Proxy = ""
+		s.JumpHost = nil
+		s.SSHIdentities = nil
 		s.Secrets = make([]Secret, 0)
 		s.Services = make([]*Dev, 0)
 	}
@@ -491,19 +768,27 @@ func setBuildDefaults(build *BuildInfo) {
 		build.Context = "."
 	}
 	if build.Dockerfile == "" {
-		build.Dockerfile = filepath.Join(build.Context, "Dockerfile")
+		if IsGitBuildContext(build.Context) {
+			build.Dockerfile = "Dockerfile"
+		} else {
+			build.Dockerfile = filepath.Join(build.Context, "Dockerfile")
+		}
 	}
 }
 
 func (dev *Dev) setRunAsUserDefaults(main *Dev) {
-	if !main.PersistentVolumeEnabled() {
+	if !main.PersistentVolumeEnabled() && main.SecurityPolicy != SecurityPolicyRestricted {
 		return
 	}
 	if dev.SecurityContext == nil {
 		dev.SecurityContext = &SecurityContext{}
 	}
 	if dev.SecurityContext.RunAsUser == nil {
-		dev.SecurityContext.RunAsUser = &rootUser
+		if main.SecurityPolicy == SecurityPolicyRestricted {
+			dev.SecurityContext.RunAsUser = &nonRootUser
+		} else {
+			dev.SecurityContext.RunAsUser = &rootUser
+		}
 	}
 	if dev.SecurityContext.RunAsGroup == nil {
 		dev.SecurityContext.RunAsGroup = dev.SecurityContext.RunAsUser
@@ -513,6 +798,35 @@ func (dev *Dev) setRunAsUserDefaults(main *Dev) {
 	}
 }
 
+// validateSecurityPolicy checks that the security context of dev can run with the security policy of main
+func (dev *Dev) validateSecurityPolicy(main *Dev) error {
+	switch main.SecurityPolicy {
+	case "":
+		return nil
+	case SecurityPolicyRestricted:
+	default:
+		return fmt.Errorf("'securityPolicy' must be empty or '%s'", SecurityPolicyRestricted)
+	}
+
+	if dev.SecurityContext == nil {
+		return nil
+	}
+
+	if dev.SecurityContext.RunAsUser != nil && *dev.SecurityContext.RunAsUser == 0 {
+		return fmt.Errorf("'securityContext.runAsUser' can't be 0 with the '%s' security policy", SecurityPolicyRestricted)
+	}
+
+	if dev.SecurityContext.Capabilities != nil {
+		for _, c := range dev.SecurityContext.Capabilities.Add {
+			if c != "NET_BIND_SERVICE" {
+				return fmt.Errorf("'securityContext.capabilities.add' can only include 'NET_BIND_SERVICE' with the '%s' security policy", SecurityPolicyRestricted)
+			}
+		}
+	}
+
+	return nil
+}
+
 func (dev *Dev) validate() error {
 	if dev.Name == "" {
 		return fmt.Errorf("Name cannot be empty")
@@ -538,6 +852,30 @@ func (dev *Dev) validate() error {
 		return err
 	}
 
+	if err := dev.Image.validateBuilder("image"); err != nil {
+		return err
+	}
+
+	if err := dev.Push.validateBuilder("push"); err != nil {
+		return err
+	}
+
+	if err := dev.Image.validateCacheScope("image"); err != nil {
+		return err
+	}
+
+	if err := dev.Push.validateCacheScope("push"); err != nil {
+		return err
+	}
+
+	if err := dev.Image.validateTagTemplate("image"); err != nil {
+		return err
+	}
+
+	if err := dev.Push.validateTagTemplate("push"); err != nil {
+		return err
+	}
+
 	if err := dev.validatePersistentVolume(); err != nil {
 		return err
 	}
@@ -558,11 +896,123 @@ func (dev *Dev) validate() error {
 		return fmt.Errorf("'sshServerPort' must be > 0")
 	}
 
+	if dev.SSHPoolSize <= 0 {
+		return fmt.Errorf("'sshPoolSize' must be > 0")
+	}
+
+	if dev.SSHKeepalive < time.Second {
+		return fmt.Errorf("'sshKeepalive' must be at least 1s")
+	}
+
+	if dev.SSHMaxMissedKeepalives <= 0 {
+		return fmt.Errorf("'sshMaxMissedKeepalives' must be > 0")
+	}
+
+	if dev.Timeout.SSH < 0 {
+		return fmt.Errorf("'timeout.ssh' must be >= 0")
+	}
+
+	if dev.Timeout.Drain < 0 {
+		return fmt.Errorf("'timeout.drain' must be >= 0")
+	}
+
+	if dev.Timeout.Failure < 0 {
+		return fmt.Errorf("'timeout.failure' must be >= 0")
+	}
+
+	if err := dev.TCP.validate("tcp"); err != nil {
+		return err
+	}
+
+	for _, f := range dev.Forward {
+		if err := f.TCP.validate(fmt.Sprintf("forward[%s].tcp", f.String())); err != nil {
+			return err
+		}
+	}
+
+	if dev.Proxy != "" {
+		if _, _, err := net.SplitHostPort(dev.Proxy); err != nil {
+			return fmt.Errorf("'proxy' must follow the syntax 'host:port': %s", err)
+		}
+	}
+
+	if dev.JumpHost != nil {
+		if dev.JumpHost.Host == "" {
+			return fmt.Errorf("'jumpHost.host' is required")
+		}
+		if dev.JumpHost.User == "" {
+			return fmt.Errorf("'jumpHost.user' is required")
+		}
+		if dev.JumpHost.PrivateKey != "" {
+			if err := checkFileAndNotDirectory(dev.JumpHost.PrivateKey); err != nil {
+				return fmt.Errorf("'jumpHost.privateKey' is not valid: %s", err)
+			}
+		}
+	}
+
+	for _, identity := range dev.SSHIdentities {
+		if err := checkFileAndNotDirectory(identity); err != nil {
+			return fmt.Errorf("'sshIdentities' is not valid: %s", err)
+		}
+	}
+
+	if dev.SSHWebSocket != "" {
+		u, err := url.Parse(dev.SSHWebSocket)
+		if err != nil || (u.Scheme != "ws" && u.Scheme != "wss") || u.Host == "" {
+			return fmt.Errorf("'sshWebSocket' must be a valid 'ws://' or 'wss://' url")
+		}
+	}
+
+	if err := dev.PodLabels.validate(); err != nil {
+		return err
+	}
+
+	if err := dev.validateSecurityPolicy(dev); err != nil {
+		return err
+	}
+
+	for _, c := range dev.ExcludeContainers {
+		if c == "" {
+			return fmt.Errorf("'excludeContainers' can't include empty names")
+		}
+		if c == dev.Container {
+			return fmt.Errorf("'excludeContainers' can't include your development container '%s'", c)
+		}
+	}
+
+	if err := dev.Hooks.validate(); err != nil {
+		return err
+	}
+
+	if err := dev.Probes.validate(); err != nil {
+		return err
+	}
+
+	if err := dev.Lifecycle.validate(); err != nil {
+		return err
+	}
+
 	for _, s := range dev.Services {
 		if err := validatePullPolicy(s.ImagePullPolicy); err != nil {
 			return err
 		}
-		if err := s.validateVolumes(dev); err != nil {
+		if !s.Hooks.isEmpty() {
+			return fmt.Errorf("'hooks' is not supported in services: service '%s' defines hooks", s.Name)
+		}
+		if err := s.validateSecurityPolicy(dev); err != nil {
+			return fmt.Errorf("service '%s': %s", s.Name, err)
+		}
+		if err := s.Probes.validate(); err != nil {
+			return fmt.Errorf("service '%s': %s", s.Name, err)
+		}
+		if err := s.Lifecycle.validate(); err != nil {
+			return fmt.Errorf("service '%s': %s", s.Name, err)
+		}
+		if s.Namespace != "" && dev.Namespace == "" {
+			// the namespace of the development container isn't known yet, it's validated by ValidateServiceNamespace
+			continue
+		}
+		if err := s.ValidateServiceNamespace(dev); err != nil {
 			return err
 		}
 	}
@@ -581,6 +1031,72 @@ func validatePullPolicy(pullPolicy apiv1.PullPolicy) error {
 	return nil
 }
 
+func (b *BuildInfo) validateBuilder(field string) error {
+	if b == nil {
+		return nil
+	}
+
+	switch b.Builder {
+	case "", DockerfileBuilder:
+		if b.BuilderImage != "" {
+			return fmt.Errorf("'%s.builderImage' is only supported with the '%s' builder", field, BuildpacksBuilder)
+		}
+	case BuildpacksBuilder:
+	default:
+		return fmt.Errorf("supported values for '%s.builder' are: '%s' or '%s'", field, DockerfileBuilder, BuildpacksBuilder)
+	}
+	return nil
+}
+
+func (b *BuildInfo) validateCacheScope(field string) error {
+	if b == nil {
+		return nil
+	}
+
+	switch b.CacheScope {
+	case "", CacheScopeUser, CacheScopeNamespace, CacheScopeService:
+		return nil
+	default:
+		return fmt.Errorf("supported values for '%s.cacheScope' are: '%s', '%s' or '%s'", field, CacheScopeUser, CacheScopeNamespace, CacheScopeService)
+	}
+}
+
+func (b *BuildInfo) validateTagTemplate(field string) error {
+	if b == nil || b.TagTemplate == "" {
+		return nil
+	}
+
+	if _, err := template.New("tag").Parse(b.TagTemplate); err != nil {
+		return fmt.Errorf("'%s.tagTemplate' is not a valid template: %s", field, err)
+	}
+	return nil
+}
+
+// GetCacheScope returns the suffix of the build cache mount ids for the cache scope of the build
+func (b *BuildInfo) GetCacheScope(namespace, service string) string {
+	switch b.CacheScope {
+	case CacheScopeNamespace:
+		return namespace
+	case CacheScopeService:
+		return fmt.Sprintf("%s-%s", namespace, service)
+	default:
+		return ""
+	}
+}
+
+// IsBuildpacks returns if the image is built with Cloud Native Buildpacks
+func (b *BuildInfo) IsBuildpacks() bool {
+	return b.Builder == BuildpacksBuilder
+}
+
+// GetBuilderImage returns the builder image of Cloud Native Buildpacks
+func (b *BuildInfo) GetBuilderImage() string {
+	if b.BuilderImage == "" {
+		return DefaultBuildpacksBuilderImage
+	}
+	return b.BuilderImage
+}
+
 func validateSecrets(secrets []Secret) error {
 	seen := map[string]bool{}
 	for _, s := range secrets {
@@ -662,6 +1178,16 @@ func SerializeBuildArgs(buildArgs []EnvVar) []string {
 	return result
 }
 
+//SerializeBuildSecrets returns the build secrets in the format of the --secret flag of the build command
+func SerializeBuildSecrets(secrets map[string]string) []string {
+	result := []string{}
+	for id, src := range secrets {
+		result = append(result, fmt.Sprintf("id=%s,src=%s", id, src))
+	}
+	sort.Strings(result)
+	return result
+}
+
 //SetLastBuiltAnnotation sets the dev timestacmp
 func (dev *Dev) SetLastBuiltAnnotation() {
 	if dev.Annotations == nil {
@@ -701,6 +1227,8 @@ func (dev *Dev) ToTranslationRule(main *Dev) *TranslationRule {
 		SecurityContext:  dev.SecurityContext,
 		Resources:        dev.Resources,
 		Healthchecks:     dev.Healthchecks,
+		Probes:           dev.Probes,
+		Lifecycle:        dev.Lifecycle,
 	}
 
 	if !dev.EmptyImage {
@@ -709,7 +1237,8 @@ func (dev *Dev) ToTranslationRule(main *Dev) *TranslationRule {
 
 	if main == dev {
 		rule.Marker = OktetoBinImageTag //for backward compatibility
-		rule.OktetoBinImageTag = OktetoBinImageTag
+		rule.OktetoBinImageTag = dev.InitContainer.Image
+		rule.InitContainer = dev.InitContainer
 		rule.Environment = append(
 			rule.Environment,
 			EnvVar{
@@ -768,6 +1297,13 @@ func (dev *Dev) ToTranslationRule(main *Dev) *TranslationRule {
 		rule.Args = []string{}
 	}
 
+	if main != dev {
+		rule.Tolerations = dev.Tolerations
+		if len(rule.Tolerations) == 0 {
+			rule.Tolerations = main.Tolerations
+		}
+	}
+
 	if main.PersistentVolumeEnabled() {
 		for _, v := range dev.Volumes {
 			rule.Volumes = append(
@@ -880,6 +1416,93 @@ func (dev *Dev) RemoteModeEnabled() bool {
 	return true
 }
 
+// GetServiceNamespace returns the namespace of a service of the development container
+func (dev *Dev) GetServiceNamespace(main *Dev) string {
+	if dev.Namespace == "" {
+		return main.Namespace
+	}
+	return dev.Namespace
+}
+
+// ValidateServiceNamespace validates the volumes of a service of the development container once the namespace of the development container is known.
+// Services in other namespaces can't mount the volume of the development container
+func (dev *Dev) ValidateServiceNamespace(main *Dev) error {
+	namespace := dev.GetServiceNamespace(main)
+	if namespace == main.Namespace {
+		return dev.validateVolumes(main)
+	}
+	if len(dev.Syncs) > 0 || len(dev.Volumes) > 0 {
+		return fmt.Errorf("'sync' and 'volumes' are not supported in services of other namespaces: service '%s' is in namespace '%s'", dev.Name, namespace)
+	}
+	return nil
+}
+
+// GetNamespaces returns the namespaces of the development container and its services
+func (dev *Dev) GetNamespaces() []string {
+	namespaces := []string{dev.Namespace}
+	for _, s := range dev.Services {
+		namespace := s.GetServiceNamespace(dev)
+		found := false
+		for _, n := range namespaces {
+			if n == namespace {
+				found = true
+				break
+			}
+		}
+		if !found {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
+// GetDeploymentsNamespaces returns the namespaces of the deployments, sorted
+func GetDeploymentsNamespaces(deployments []*appsv1.Deployment) []string {
+	found := map[string]bool{}
+	namespaces := []string{}
+	for _, d := range deployments {
+		if found[d.Namespace] {
+			continue
+		}
+		found[d.Namespace] = true
+		namespaces = append(namespaces, d.Namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// IsStripped returns if a label of the pod template isn't propagated to the development container pods
+func (p *PodLabels) IsStripped(key string) bool {
+	if p == nil {
+		return false
+	}
+	if len(p.Keep) > 0 && !hasLabelPrefix(key, p.Keep) {
+		return true
+	}
+	return hasLabelPrefix(key, p.Strip)
+}
+
+func (p *PodLabels) validate() error {
+	if p == nil {
+		return nil
+	}
+	for _, prefix := range append(p.Strip, p.Keep...) {
+		if prefix == "" {
+			return fmt.Errorf("'podLabels' can't include empty prefixes")
+		}
+	}
+	return nil
+}
+
+func hasLabelPrefix(key string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetKeyName returns the secret key name
 func (s *Secret) GetKeyName() string {
 	return fmt.Sprintf("dev-secret-%s", filepath.Base(s.RemotePath))
@@ -890,11 +1513,92 @@ func (s *Secret) GetFileName() string {
 	return filepath.Base(s.RemotePath)
 }
 
-//ExpandEnv expands the environments supporting the notation "${var:-$DEFAULT}"
+// loadDotEnv exports the variables of the .env file of a folder, so the manifest can reference them.
+// Variables already defined in the environment take precedence
+func loadDotEnv(folder string) error {
+	dotEnv := filepath.Join(folder, ".env")
+	if _, err := os.Stat(dotEnv); err != nil {
+		return nil
+	}
+
+	if err := gotenv.Load(dotEnv); err != nil {
+		return fmt.Errorf("error loading '%s': %s", dotEnv, err.Error())
+	}
+	return nil
+}
+
+//ExpandEnv expands the environments supporting the notation "${var:-$DEFAULT}".
+//When OKTETO_STRICT_ENV is true, variables that aren't set and don't have a default value are an error
 func ExpandEnv(value string) (string, error) {
-	result, err := envsubst.String(value)
+	result, err := envsubst.StringRestricted(value, isStrictEnv(), false)
 	if err != nil {
 		return "", fmt.Errorf("error expanding environment on '%s': %s", value, err.Error())
 	}
 	return result, nil
 }
+
+// expandBracedEnv expands only the variables of value with the notation "${var:-$DEFAULT}", "$${var}" escapes them.
+// The defaults can be nested, like "${var:-${DEFAULT}}", the nested variables are expanded first
+func expandBracedEnv(value string) (string, error) {
+	var result strings.Builder
+	for {
+		start := strings.Index(value, "${")
+		if start < 0 {
+			break
+		}
+		end := closingBraceIndex(value, start+1)
+		if end < 0 {
+			break
+		}
+
+		if start > 0 && value[start-1] == '$' {
+			result.WriteString(value[:start-1])
+			result.WriteString(value[start : end+1])
+		} else {
+			body, err := expandBracedEnv(value[start+2 : end])
+			if err != nil {
+				return "", err
+			}
+			expanded, err := ExpandEnv("${" + body + "}")
+			if err != nil {
+				return "", err
+			}
+			result.WriteString(value[:start])
+			result.WriteString(expanded)
+		}
+		value = value[end+1:]
+	}
+	result.WriteString(value)
+	return result.String(), nil
+}
+
+// closingBraceIndex returns the index of the brace closing the one at open, or -1 if it isn't closed
+func closingBraceIndex(value string, open int) int {
+	depth := 0
+	for i := open; i < len(value); i++ {
+		switch value[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isStrictEnv() bool {
+	v, ok := os.LookupEnv("OKTETO_STRICT_ENV")
+	if !ok {
+		return false
+	}
+
+	strict, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Infof("'%s' is not a valid value of OKTETO_STRICT_ENV, ignoring", v)
+		return false
+	}
+	return strict
+}
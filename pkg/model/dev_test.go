@@ -18,6 +18,7 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -814,6 +815,319 @@ func Test_validate(t *testing.T) {
       sshServerPort: -1`),
 			expectErr: true,
 		},
+		{
+			name: "valid-ssh-pool-size",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      sshPoolSize: 4`),
+			expectErr: false,
+		},
+		{
+			name: "invalid-ssh-pool-size",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      sshPoolSize: -1`),
+			expectErr: true,
+		},
+		{
+			name: "valid-proxy",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      proxy: localhost:1080`),
+			expectErr: false,
+		},
+		{
+			name: "invalid-proxy",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      proxy: 1080`),
+			expectErr: true,
+		},
+		{
+			name: "valid-ssh-keepalive",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      sshKeepalive: 5s
+      sshMaxMissedKeepalives: 2`),
+			expectErr: false,
+		},
+		{
+			name: "invalid-ssh-keepalive",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      sshKeepalive: 10ms`),
+			expectErr: true,
+		},
+		{
+			name: "invalid-ssh-max-missed-keepalives",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      sshMaxMissedKeepalives: -1`),
+			expectErr: true,
+		},
+		{
+			name: "valid-ssh-timeout",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      timeout:
+        ssh: 1m
+        drain: 10s`),
+			expectErr: false,
+		},
+		{
+			name: "invalid-ssh-timeout",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      timeout:
+        ssh: -5s`),
+			expectErr: true,
+		},
+		{
+			name: "invalid-drain-timeout",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      timeout:
+        drain: -5s`),
+			expectErr: true,
+		},
+		{
+			name: "invalid-failure-timeout",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      timeout:
+        failure: -1m`),
+			expectErr: true,
+		},
+		{
+			name: "service-in-other-namespace",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      services:
+        - name: db
+          namespace: shared`),
+			expectErr: false,
+		},
+		{
+			name: "service-in-other-namespace-with-sync",
+			manifest: []byte(`
+      name: deployment
+      namespace: dev
+      sync:
+        - .:/app
+      services:
+        - name: db
+          namespace: shared
+          sync:
+            - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "service-in-namespace-not-known-yet",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      services:
+        - name: db
+          namespace: shared
+          sync:
+            - .:/app`),
+			expectErr: false,
+		},
+		{
+			name: "exclude-sidecars",
+			manifest: []byte(`
+      name: deployment
+      container: api
+      excludeContainers:
+        - istio-proxy
+      sync:
+        - .:/app`),
+			expectErr: false,
+		},
+		{
+			name: "exclude-dev-container",
+			manifest: []byte(`
+      name: deployment
+      container: api
+      excludeContainers:
+        - api
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "valid-ssh-websocket",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      sshWebSocket: wss://ssh.example.com/tunnel`),
+			expectErr: false,
+		},
+		{
+			name: "invalid-ssh-websocket",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      sshWebSocket: https://ssh.example.com/tunnel`),
+			expectErr: true,
+		},
+		{
+			name: "valid-jump-host",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      jumpHost:
+        host: bastion.example.com
+        user: okteto`),
+			expectErr: false,
+		},
+		{
+			name: "jump-host-without-user",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      jumpHost:
+        host: bastion.example.com`),
+			expectErr: true,
+		},
+		{
+			name: "jump-host-with-missing-private-key",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      jumpHost:
+        host: bastion.example.com
+        user: okteto
+        privateKey: /does/not/exist`),
+			expectErr: true,
+		},
+		{
+			name: "valid-tcp-options",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      tcp:
+        noDelay: true
+        sendBuffer: 262144
+      forward:
+        - localPort: 5432
+          remotePort: 5432
+          tcp:
+            receiveBuffer: 1048576`),
+			expectErr: false,
+		},
+		{
+			name: "invalid-tcp-options",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      tcp:
+        sendBuffer: -1`),
+			expectErr: true,
+		},
+		{
+			name: "invalid-forward-tcp-options",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      forward:
+        - localPort: 5432
+          remotePort: 5432
+          tcp:
+            keepAlive: -1s`),
+			expectErr: true,
+		},
+		{
+			name: "ssh-identity-missing",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      sshIdentities:
+        - /does/not/exist`),
+			expectErr: true,
+		},
+		{
+			name: "restricted-security-policy",
+			manifest: []byte(`
+      name: deployment
+      securityPolicy: restricted
+      sync:
+        - .:/app
+      securityContext:
+        capabilities:
+          add:
+            - NET_BIND_SERVICE`),
+			expectErr: false,
+		},
+		{
+			name: "unknown-security-policy",
+			manifest: []byte(`
+      name: deployment
+      securityPolicy: baseline
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "restricted-security-policy-root",
+			manifest: []byte(`
+      name: deployment
+      securityPolicy: restricted
+      sync:
+        - .:/app
+      securityContext:
+        runAsUser: 0`),
+			expectErr: true,
+		},
+		{
+			name: "restricted-security-policy-service-capabilities",
+			manifest: []byte(`
+      name: deployment
+      securityPolicy: restricted
+      sync:
+        - .:/app
+      services:
+        - name: foo
+          securityContext:
+            capabilities:
+              add:
+                - SYS_PTRACE`),
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -921,3 +1235,203 @@ func Test_ExpandEnv(t *testing.T) {
 		})
 	}
 }
+
+func Test_ExpandEnvStrict(t *testing.T) {
+	os.Setenv("OKTETO_STRICT_ENV", "true")
+	defer os.Unsetenv("OKTETO_STRICT_ENV")
+
+	if _, err := ExpandEnv("value-${OKTETO_TEST_NOT_SET}"); err == nil {
+		t.Error("expected an error for a variable not set in strict mode")
+	}
+
+	result, err := ExpandEnv("value-${OKTETO_TEST_NOT_SET:-foo}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "value-foo" {
+		t.Errorf("expected 'value-foo', got '%s'", result)
+	}
+}
+
+func Test_expandBracedEnv(t *testing.T) {
+	os.Setenv("OKTETO_TEST_PORT", "8080")
+	defer os.Unsetenv("OKTETO_TEST_PORT")
+
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{
+			name:     "braced",
+			value:    "--port ${OKTETO_TEST_PORT} --home $HOME",
+			expected: "--port 8080 --home $HOME",
+		},
+		{
+			name:     "escaped",
+			value:    "--port $${OKTETO_TEST_PORT}",
+			expected: "--port ${OKTETO_TEST_PORT}",
+		},
+		{
+			name:     "nested-default",
+			value:    "--port ${OKTETO_TEST_UNSET_PORT:-${OKTETO_TEST_PORT}} --debug",
+			expected: "--port 8080 --debug",
+		},
+		{
+			name:     "escaped-nested-default",
+			value:    "--port $${OKTETO_TEST_UNSET_PORT:-${OKTETO_TEST_PORT}}",
+			expected: "--port ${OKTETO_TEST_UNSET_PORT:-${OKTETO_TEST_PORT}}",
+		},
+		{
+			name:     "unclosed",
+			value:    "--port ${OKTETO_TEST_PORT",
+			expected: "--port ${OKTETO_TEST_PORT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := expandBracedEnv(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestDevExpandEnv(t *testing.T) {
+	os.Setenv("OKTETO_TEST_PORT", "8080")
+	os.Setenv("OKTETO_TEST_WORKDIR", "/usr/src/app")
+	defer os.Unsetenv("OKTETO_TEST_PORT")
+	defer os.Unsetenv("OKTETO_TEST_WORKDIR")
+
+	manifest := []byte(`
+name: deployment
+command: ["sh", "-c", "go run main.go --port ${OKTETO_TEST_PORT} --home $HOME --escaped $${OKTETO_TEST_PORT}"]
+workdir: ${OKTETO_TEST_WORKDIR}
+sync:
+  - .:${OKTETO_TEST_WORKDIR}
+forward:
+  - ${OKTETO_TEST_PORT}:${OKTETO_TEST_REMOTE_PORT:-80}
+reverse:
+  - 9000:${OKTETO_TEST_PORT}
+annotations:
+  port: ${OKTETO_TEST_PORT}
+`)
+	dev, err := Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedCommand := []string{"sh", "-c", "go run main.go --port 8080 --home $HOME --escaped ${OKTETO_TEST_PORT}"}
+	if !reflect.DeepEqual(dev.Command.Values, expectedCommand) {
+		t.Errorf("expected command %v, got %v", expectedCommand, dev.Command.Values)
+	}
+	if dev.WorkDir != "/usr/src/app" {
+		t.Errorf("wrong workdir: %s", dev.WorkDir)
+	}
+	if dev.Syncs[0].RemotePath != "/usr/src/app" {
+		t.Errorf("wrong sync remote path: %s", dev.Syncs[0].RemotePath)
+	}
+	if dev.Forward[0].Local != 8080 || dev.Forward[0].Remote != 80 {
+		t.Errorf("wrong forward: %+v", dev.Forward[0])
+	}
+	if dev.Reverse[0].Local != 8080 {
+		t.Errorf("wrong reverse: %+v", dev.Reverse[0])
+	}
+	if dev.Annotations["port"] != "8080" {
+		t.Errorf("wrong annotation: %s", dev.Annotations["port"])
+	}
+}
+
+func TestIsGitBuildContext(t *testing.T) {
+	tests := []struct {
+		context  string
+		expected bool
+	}{
+		{context: ".", expected: false},
+		{context: "/home/okteto/api", expected: false},
+		{context: "https://github.com/okteto/movies.git", expected: true},
+		{context: "https://github.com/okteto/movies.git#main:api", expected: true},
+		{context: "https://okteto.com/context.tar.gz", expected: false},
+		{context: "git://github.com/okteto/movies", expected: true},
+		{context: "git@github.com:okteto/movies.git", expected: true},
+		{context: "github.com/okteto/movies", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.context, func(t *testing.T) {
+			if got := IsGitBuildContext(tt.context); got != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestBuildInfoExpandEnv(t *testing.T) {
+	os.Setenv("OKTETO_TEST_REGISTRY", "registry.example.com")
+	os.Setenv("OKTETO_TEST_DOCKERFILE", "Dockerfile.dev")
+	defer os.Unsetenv("OKTETO_TEST_REGISTRY")
+	defer os.Unsetenv("OKTETO_TEST_DOCKERFILE")
+
+	manifest := []byte(`
+name: deployment
+image:
+  name: ${OKTETO_TEST_REGISTRY}/api:dev
+  context: api
+  dockerfile: api/${OKTETO_TEST_DOCKERFILE}
+  cache_from:
+    - ${OKTETO_TEST_REGISTRY}/api:cache
+push:
+  name: ${OKTETO_TEST_REGISTRY}/api:${OKTETO_TEST_TAG:-latest}
+`)
+	dev, err := Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dev.Image.Name != "registry.example.com/api:dev" {
+		t.Errorf("wrong image name: %s", dev.Image.Name)
+	}
+	if dev.Image.Dockerfile != "api/Dockerfile.dev" {
+		t.Errorf("wrong dockerfile: %s", dev.Image.Dockerfile)
+	}
+	if dev.Image.CacheFrom[0] != "registry.example.com/api:cache" {
+		t.Errorf("wrong cache from: %s", dev.Image.CacheFrom[0])
+	}
+	if dev.Push.Name != "registry.example.com/api:latest" {
+		t.Errorf("wrong push name: %s", dev.Push.Name)
+	}
+}
+
+func Test_loadDotEnv(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, ".env"), []byte("OKTETO_TEST_DOTENV=file\nOKTETO_TEST_DOTENV_SET=file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("OKTETO_TEST_DOTENV_SET", "env")
+	defer os.Unsetenv("OKTETO_TEST_DOTENV")
+	defer os.Unsetenv("OKTETO_TEST_DOTENV_SET")
+
+	if err := loadDotEnv(dir); err != nil {
+		t.Fatal(err)
+	}
+	if v := os.Getenv("OKTETO_TEST_DOTENV"); v != "file" {
+		t.Errorf("variable of the .env file not loaded: '%s'", v)
+	}
+	if v := os.Getenv("OKTETO_TEST_DOTENV_SET"); v != "env" {
+		t.Errorf("variable of the environment overridden: '%s'", v)
+	}
+
+	if err := loadDotEnv(filepath.Join(dir, "missing")); err != nil {
+		t.Errorf("missing .env file failed: %s", err)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	extendsField = "extends"
+)
+
+// readManifest returns the content of a manifest merged with the manifests it extends with the 'extends' field, and with the selected profiles.
+// The base manifest is relative to the manifest that extends it and can extend other manifests.
+// Mappings are merged key by key, and lists and values of the extending manifest replace the ones of the base manifest.
+// Relative paths of the merged manifest, like the sync folders, are relative to the manifest that extends the base manifest
+func readManifest(devPath string, profiles []string) ([]byte, error) {
+	b, err := ioutil.ReadFile(devPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[interface{}]interface{}
+	if err := yaml.Unmarshal(b, &raw); err != nil || (raw[extendsField] == nil && raw[profilesField] == nil && len(profiles) == 0) {
+		// manifests without 'extends' or profiles are parsed as they are, so errors refer to their lines
+		return b, nil
+	}
+
+	merged, err := loadExtends(devPath, raw, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err = applyProfiles(devPath, merged, profiles)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(merged)
+}
+
+// loadExtends returns a manifest merged with the manifests it extends, visited are the manifests already loaded to detect cycles
+func loadExtends(devPath string, raw map[interface{}]interface{}, visited map[string]bool) (map[interface{}]interface{}, error) {
+	abs, err := filepath.Abs(devPath)
+	if err != nil {
+		return nil, err
+	}
+	if visited[abs] {
+		return nil, fmt.Errorf("'%s' extends itself", devPath)
+	}
+	visited[abs] = true
+
+	extends, ok := raw[extendsField]
+	if !ok {
+		return raw, nil
+	}
+	delete(raw, extendsField)
+
+	basePath, ok := extends.(string)
+	if !ok || basePath == "" {
+		return nil, fmt.Errorf("the field 'extends' of '%s' must be the path of a manifest", devPath)
+	}
+	if !filepath.IsAbs(basePath) {
+		basePath = filepath.Join(filepath.Dir(devPath), basePath)
+	}
+
+	b, err := ioutil.ReadFile(basePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the manifest extended by '%s': %s", devPath, err)
+	}
+
+	var base map[interface{}]interface{}
+	if err := yaml.Unmarshal(b, &base); err != nil {
+		return nil, fmt.Errorf("invalid manifest '%s': %s", basePath, err)
+	}
+	if base == nil {
+		base = map[interface{}]interface{}{}
+	}
+
+	base, err = loadExtends(basePath, base, visited)
+	if err != nil {
+		return nil, err
+	}
+	return mergeManifests(base, raw), nil
+}
+
+// mergeManifests merges the mappings of override into base recursively. Lists and values of override replace the ones of base
+func mergeManifests(base, override map[interface{}]interface{}) map[interface{}]interface{} {
+	for k, v := range override {
+		baseMap, isBaseMap := base[k].(map[interface{}]interface{})
+		overrideMap, isOverrideMap := v.(map[interface{}]interface{})
+		if isBaseMap && isOverrideMap {
+			base[k] = mergeManifests(baseMap, overrideMap)
+			continue
+		}
+		base[k] = v
+	}
+	return base
+}
@@ -0,0 +1,115 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestGetExtends(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.MkdirAll(filepath.Join(dir, "base"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "api"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	base := []byte(`
+image: okteto/golang:1
+command: bash
+sync:
+  - .:/usr/src/app
+environment:
+  - ENV=dev
+forward:
+  - 2345:2345
+resources:
+  limits:
+    cpu: 1
+    memory: 1Gi
+`)
+	if err := ioutil.WriteFile(filepath.Join(dir, "base", "okteto-base.yml"), base, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest := []byte(`
+extends: ../base/okteto-base.yml
+name: api
+image: okteto/golang:2
+forward:
+  - 8080:8080
+resources:
+  limits:
+    memory: 2Gi
+`)
+	devPath := filepath.Join(dir, "api", "okteto.yml")
+	if err := ioutil.WriteFile(devPath, manifest, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	dev, err := Get(devPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dev.Name != "api" || dev.Image.Name != "okteto/golang:2" {
+		t.Errorf("wrong name or image: %s %s", dev.Name, dev.Image.Name)
+	}
+	if !reflect.DeepEqual(dev.Command.Values, []string{"bash"}) {
+		t.Errorf("wrong command: %v", dev.Command.Values)
+	}
+	if len(dev.Syncs) != 1 || dev.Syncs[0].LocalPath != filepath.Join(dir, "api") {
+		t.Errorf("wrong sync: %+v", dev.Syncs)
+	}
+	if len(dev.Environment) != 1 || dev.Environment[0].Name != "ENV" {
+		t.Errorf("wrong environment: %+v", dev.Environment)
+	}
+	if len(dev.Forward) != 1 || dev.Forward[0].Local != 8080 {
+		t.Errorf("wrong forward: %+v", dev.Forward)
+	}
+	if !dev.Resources.Limits["cpu"].Equal(resource.MustParse("1")) || !dev.Resources.Limits["memory"].Equal(resource.MustParse("2Gi")) {
+		t.Errorf("wrong resources: %+v", dev.Resources.Limits)
+	}
+}
+
+func TestGetExtendsCycle(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "a.yml"), []byte("name: a\nextends: b.yml\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "b.yml"), []byte("extends: a.yml\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Get(filepath.Join(dir, "a.yml")); err == nil {
+		t.Error("expected an error for manifests that extend each other")
+	}
+}
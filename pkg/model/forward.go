@@ -19,38 +19,69 @@ import (
 	"strings"
 )
 
-const malformedPortForward = "Wrong port-forward syntax '%s', must be of the form 'localPort:remotePort' or 'localPort:serviceName:remotePort'"
+const (
+	malformedPortForward = "Wrong port-forward syntax '%s', must be of the form 'localPort:remotePort' or 'localPort:serviceName:remotePort'"
+
+	// autoLocalPort is used instead of the local port to let okteto select a free one
+	autoLocalPort = "auto"
+)
 
 // Forward represents a port forwarding definition
 type Forward struct {
-	Local       int
-	Remote      int
-	Service     bool   `json:"-" yaml:"-"`
-	ServiceName string `json:"-" yaml:"-"`
+	Local        int
+	Remote       int
+	Auto         bool       `json:"-" yaml:"-"`
+	Service      bool       `json:"-" yaml:"-"`
+	ServiceName  string     `json:"-" yaml:"-"`
+	MaxBandwidth Bandwidth  `json:"-" yaml:"-"`
+	TCP          TCPOptions `json:"-" yaml:"-"`
+}
+
+type forwardRaw struct {
+	LocalPort    int         `yaml:"localPort"`
+	RemotePort   int         `yaml:"remotePort"`
+	Name         string      `yaml:"name,omitempty"`
+	MaxBandwidth string      `yaml:"maxBandwidth,omitempty"`
+	TCP          *TCPOptions `yaml:"tcp,omitempty"`
 }
 
 // UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg for port forwards.
 // It supports the following options:
 // - int:int
 // - int:serviceName:int
+// - auto:int and auto:serviceName:int, to select a free local port
+// - the extended syntax with the 'localPort', 'remotePort', 'name', 'maxBandwidth' and 'tcp' keys
 // Anything else will result in an error
 func (f *Forward) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	var raw string
 	err := unmarshal(&raw)
 	if err != nil {
-		return err
+		var extended forwardRaw
+		if err := unmarshal(&extended); err != nil {
+			return err
+		}
+
+		return f.fromExtended(extended)
 	}
 
+	raw, err = ExpandEnv(raw)
+	if err != nil {
+		return err
+	}
 	parts := strings.Split(raw, ":")
 	if len(parts) < 2 || len(parts) > 3 {
 		return fmt.Errorf(malformedPortForward, raw)
 	}
 
-	localPort, err := strconv.Atoi(parts[0])
-	if err != nil {
-		return fmt.Errorf("Cannot convert local port '%s' in port-forward '%s'", parts[0], raw)
+	if parts[0] == autoLocalPort {
+		f.Auto = true
+	} else {
+		localPort, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return fmt.Errorf("Cannot convert local port '%s' in port-forward '%s'", parts[0], raw)
+		}
+		f.Local = localPort
 	}
-	f.Local = localPort
 
 	if len(parts) == 2 {
 		p, err := strconv.Atoi(parts[1])
@@ -73,17 +104,87 @@ func (f *Forward) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return nil
 }
 
+func (f *Forward) fromExtended(raw forwardRaw) error {
+	if raw.LocalPort <= 0 || raw.RemotePort <= 0 {
+		return fmt.Errorf("Wrong port-forward syntax, 'localPort' and 'remotePort' are required")
+	}
+
+	f.Local = raw.LocalPort
+	f.Remote = raw.RemotePort
+	if raw.Name != "" {
+		name, err := ExpandEnv(raw.Name)
+		if err != nil {
+			return err
+		}
+		f.Service = true
+		f.ServiceName = name
+	}
+
+	if raw.MaxBandwidth != "" {
+		b, err := ParseBandwidth(raw.MaxBandwidth)
+		if err != nil {
+			return err
+		}
+		f.MaxBandwidth = b
+	}
+
+	if raw.TCP != nil {
+		f.TCP = *raw.TCP
+	}
+
+	return nil
+}
+
 // MarshalYAML Implements the marshaler interface of the yaml pkg.
 func (f Forward) MarshalYAML() (interface{}, error) {
-	return f.String(), nil
+	if f.MaxBandwidth == 0 && f.TCP.IsZero() {
+		return f.String(), nil
+	}
+
+	raw := forwardRaw{
+		LocalPort:  f.Local,
+		RemotePort: f.Remote,
+		Name:       f.ServiceName,
+	}
+
+	if f.MaxBandwidth != 0 {
+		raw.MaxBandwidth = f.MaxBandwidth.String()
+	}
+
+	if !f.TCP.IsZero() {
+		tcp := f.TCP
+		raw.TCP = &tcp
+	}
+
+	return raw, nil
 }
 
 func (f Forward) String() string {
+	local := strconv.Itoa(f.Local)
+	if f.Auto {
+		local = autoLocalPort
+	}
+
+	if f.Service {
+		return fmt.Sprintf("%s:%s:%d", local, f.ServiceName, f.Remote)
+	}
+
+	return fmt.Sprintf("%s:%d", local, f.Remote)
+}
+
+// EnvVar returns the name of the environment variable that exposes the local port selected for an auto forward
+func (f Forward) EnvVar() string {
 	if f.Service {
-		return fmt.Sprintf("%d:%s:%d", f.Local, f.ServiceName, f.Remote)
+		service := strings.Map(func(r rune) rune {
+			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+				return r
+			}
+			return '_'
+		}, f.ServiceName)
+		return fmt.Sprintf("OKTETO_FORWARD_%s_%d", strings.ToUpper(service), f.Remote)
 	}
 
-	return fmt.Sprintf("%d:%d", f.Local, f.Remote)
+	return fmt.Sprintf("OKTETO_FORWARD_%d", f.Remote)
 }
 
 func (f *Forward) less(c *Forward) bool {
@@ -17,6 +17,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	yaml "gopkg.in/yaml.v2"
 )
@@ -37,6 +38,11 @@ func TestForward_MarshalYAML(t *testing.T) {
 			expected: "8080:svc:5214",
 			data:     Forward{Local: 8080, Remote: 5214, Service: true, ServiceName: "svc"},
 		},
+		{
+			name:     "max-bandwidth",
+			expected: "localPort: 8080\nremotePort: 9090\nmaxBandwidth: 5Mbps",
+			data:     Forward{Local: 8080, Remote: 9090, MaxBandwidth: 5000000},
+		},
 	}
 
 	for _, tt := range tests {
@@ -56,6 +62,7 @@ func TestForward_MarshalYAML(t *testing.T) {
 }
 
 func TestForward_UnmarshalYAML(t *testing.T) {
+	noDelay := false
 	tests := []struct {
 		name      string
 		data      string
@@ -78,6 +85,16 @@ func TestForward_UnmarshalYAML(t *testing.T) {
 			expectErr: false,
 			expected:  Forward{Local: 8080, Remote: 5214, Service: true, ServiceName: "svc"},
 		},
+		{
+			name:     "auto",
+			data:     "auto:8080",
+			expected: Forward{Auto: true, Remote: 8080},
+		},
+		{
+			name:     "auto-service",
+			data:     "auto:svc:5214",
+			expected: Forward{Auto: true, Remote: 5214, Service: true, ServiceName: "svc"},
+		},
 		{
 			name:      "bad-local-port",
 			data:      "local:8080",
@@ -108,6 +125,31 @@ func TestForward_UnmarshalYAML(t *testing.T) {
 			data:      "8080:svc",
 			expectErr: true,
 		},
+		{
+			name:     "extended",
+			data:     "localPort: 8080\nremotePort: 9090\nmaxBandwidth: 500Kbps",
+			expected: Forward{Local: 8080, Remote: 9090, MaxBandwidth: 500000},
+		},
+		{
+			name:     "extended-with-service-and-bandwidth",
+			data:     "localPort: 8080\nremotePort: 5214\nname: svc\nmaxBandwidth: 2Mbps",
+			expected: Forward{Local: 8080, Remote: 5214, Service: true, ServiceName: "svc", MaxBandwidth: 2000000},
+		},
+		{
+			name:     "extended-with-tcp-options",
+			data:     "localPort: 5432\nremotePort: 5432\ntcp:\n  noDelay: false\n  receiveBuffer: 1048576\n  keepAlive: 10s",
+			expected: Forward{Local: 5432, Remote: 5432, TCP: TCPOptions{NoDelay: &noDelay, ReceiveBuffer: 1048576, KeepAlive: 10 * time.Second}},
+		},
+		{
+			name:      "extended-without-remote-port",
+			data:      "localPort: 8080",
+			expectErr: true,
+		},
+		{
+			name:      "extended-with-bad-bandwidth",
+			data:      "localPort: 8080\nremotePort: 9090\nmaxBandwidth: 5MB",
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -144,6 +186,33 @@ func TestForward_UnmarshalYAML(t *testing.T) {
 	}
 }
 
+func TestForward_EnvVar(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     Forward
+		expected string
+	}{
+		{
+			name:     "basic",
+			data:     Forward{Auto: true, Remote: 8080},
+			expected: "OKTETO_FORWARD_8080",
+		},
+		{
+			name:     "service",
+			data:     Forward{Auto: true, Remote: 5432, Service: true, ServiceName: "my-db"},
+			expected: "OKTETO_FORWARD_MY_DB_5432",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.data.EnvVar(); got != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
+
 func TestForward_less(t *testing.T) {
 	tests := []struct {
 		name string
@@ -190,3 +259,41 @@ func TestForward_less(t *testing.T) {
 		})
 	}
 }
+
+func TestParseBandwidth(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  Bandwidth
+		expectErr bool
+	}{
+		{value: "100bps", expected: 100},
+		{value: "5Kbps", expected: 5000},
+		{value: "5Mbps", expected: 5000000},
+		{value: "0.5mbps", expected: 500000},
+		{value: "1Gbps", expected: 1000000000},
+		{value: "5", expectErr: true},
+		{value: "Mbps", expectErr: true},
+		{value: "-5Mbps", expectErr: true},
+		{value: "5MB", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			b, err := ParseBandwidth(tt.value)
+			if err != nil {
+				if !tt.expectErr {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			if tt.expectErr {
+				t.Fatal("didn't got expected error")
+			}
+
+			if b != tt.expected {
+				t.Errorf("got %d, expected %d", b, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	//HookFailurePolicyFail stops 'okteto up' or 'okteto down' when a hook fails
+	HookFailurePolicyFail = "fail"
+
+	//HookFailurePolicyContinue logs the failure of a hook and continues
+	HookFailurePolicyContinue = "continue"
+
+	defaultHookTimeout = 5 * time.Minute
+)
+
+// Hooks represents the commands executed before and after activating and deactivating the development container
+type Hooks struct {
+	PreUp    []Hook `json:"preUp,omitempty" yaml:"preUp,omitempty"`
+	PostUp   []Hook `json:"postUp,omitempty" yaml:"postUp,omitempty"`
+	PreDown  []Hook `json:"preDown,omitempty" yaml:"preDown,omitempty"`
+	PostDown []Hook `json:"postDown,omitempty" yaml:"postDown,omitempty"`
+	dir      string
+}
+
+// Hook represents a command executed in the local machine and/or in the development container
+type Hook struct {
+	Local     string        `json:"local,omitempty" yaml:"local,omitempty"`
+	Remote    string        `json:"remote,omitempty" yaml:"remote,omitempty"`
+	Timeout   time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	OnFailure string        `json:"onFailure,omitempty" yaml:"onFailure,omitempty"`
+}
+
+//GetTimeout returns the timeout of the hook, 5 minutes by default
+func (h Hook) GetTimeout() time.Duration {
+	if h.Timeout == 0 {
+		return defaultHookTimeout
+	}
+	return h.Timeout
+}
+
+//ContinueOnFailure returns true if a failure of the hook doesn't stop the command that runs it
+func (h Hook) ContinueOnFailure() bool {
+	return h.OnFailure == HookFailurePolicyContinue
+}
+
+//Dir returns the folder of the manifest, where local commands run
+func (h Hooks) Dir() string {
+	return h.dir
+}
+
+func (h Hooks) isEmpty() bool {
+	return len(h.PreUp) == 0 && len(h.PostUp) == 0 && len(h.PreDown) == 0 && len(h.PostDown) == 0
+}
+
+func (h *Hooks) loadCommands() error {
+	for _, hooks := range [][]Hook{h.PreUp, h.PostUp, h.PreDown, h.PostDown} {
+		for i := range hooks {
+			var err error
+			if hooks[i].Local, err = expandBracedEnv(hooks[i].Local); err != nil {
+				return err
+			}
+			if hooks[i].Remote, err = expandBracedEnv(hooks[i].Remote); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (h *Hooks) validate() error {
+	stages := []struct {
+		name        string
+		hooks       []Hook
+		allowRemote bool
+	}{
+		{name: "preUp", hooks: h.PreUp},
+		{name: "postUp", hooks: h.PostUp, allowRemote: true},
+		{name: "preDown", hooks: h.PreDown, allowRemote: true},
+		{name: "postDown", hooks: h.PostDown},
+	}
+
+	for _, s := range stages {
+		for i, hook := range s.hooks {
+			field := fmt.Sprintf("hooks.%s[%d]", s.name, i)
+			if hook.Local == "" && hook.Remote == "" {
+				return fmt.Errorf("'%s' must define a 'local' or a 'remote' command", field)
+			}
+			if hook.Remote != "" && !s.allowRemote {
+				return fmt.Errorf("'%s.remote' is not supported: the development container is not running during '%s' hooks", field, s.name)
+			}
+			if hook.Timeout < 0 {
+				return fmt.Errorf("'%s.timeout' must be >= 0", field)
+			}
+			switch hook.OnFailure {
+			case "", HookFailurePolicyFail, HookFailurePolicyContinue:
+			default:
+				return fmt.Errorf("'%s.onFailure' must be '%s' or '%s'", field, HookFailurePolicyFail, HookFailurePolicyContinue)
+			}
+		}
+	}
+	return nil
+}
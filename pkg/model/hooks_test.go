@@ -0,0 +1,120 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestReadHooks(t *testing.T) {
+	os.Setenv("OKTETO_TEST_DB", "postgres")
+	defer os.Unsetenv("OKTETO_TEST_DB")
+
+	manifest := []byte(`
+name: api
+image: okteto/golang:1
+sync:
+  - .:/usr/src/app
+hooks:
+  preUp:
+    - local: docker-compose stop
+  postUp:
+    - remote: make migrate DB=${OKTETO_TEST_DB} HOME=$HOME
+      timeout: 1m
+  preDown:
+    - local: echo bye
+      remote: make dump
+      onFailure: continue
+`)
+	dev, err := Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dev.Hooks.PreUp) != 1 || dev.Hooks.PreUp[0].Local != "docker-compose stop" {
+		t.Errorf("wrong preUp hooks: %+v", dev.Hooks.PreUp)
+	}
+	if dev.Hooks.PreUp[0].GetTimeout() != defaultHookTimeout || dev.Hooks.PreUp[0].ContinueOnFailure() {
+		t.Errorf("wrong preUp defaults: %+v", dev.Hooks.PreUp[0])
+	}
+
+	if len(dev.Hooks.PostUp) != 1 {
+		t.Fatalf("wrong postUp hooks: %+v", dev.Hooks.PostUp)
+	}
+	if dev.Hooks.PostUp[0].Remote != "make migrate DB=postgres HOME=$HOME" {
+		t.Errorf("wrong postUp command: '%s'", dev.Hooks.PostUp[0].Remote)
+	}
+	if dev.Hooks.PostUp[0].GetTimeout() != time.Minute {
+		t.Errorf("wrong postUp timeout: %s", dev.Hooks.PostUp[0].GetTimeout())
+	}
+
+	if len(dev.Hooks.PreDown) != 1 || !dev.Hooks.PreDown[0].ContinueOnFailure() {
+		t.Errorf("wrong preDown hooks: %+v", dev.Hooks.PreDown)
+	}
+	if len(dev.Hooks.PostDown) != 0 {
+		t.Errorf("wrong postDown hooks: %+v", dev.Hooks.PostDown)
+	}
+}
+
+func TestHooks_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		hooks   Hooks
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			hooks: Hooks{
+				PreUp:    []Hook{{Local: "docker-compose stop"}},
+				PostUp:   []Hook{{Local: "echo ready", Remote: "make migrate", Timeout: time.Minute}},
+				PreDown:  []Hook{{Remote: "make dump", OnFailure: HookFailurePolicyContinue}},
+				PostDown: []Hook{{Local: "docker-compose start", OnFailure: HookFailurePolicyFail}},
+			},
+		},
+		{
+			name:    "empty-hook",
+			hooks:   Hooks{PostUp: []Hook{{Timeout: time.Minute}}},
+			wantErr: true,
+		},
+		{
+			name:    "remote-pre-up",
+			hooks:   Hooks{PreUp: []Hook{{Remote: "make migrate"}}},
+			wantErr: true,
+		},
+		{
+			name:    "remote-post-down",
+			hooks:   Hooks{PostDown: []Hook{{Remote: "make clean"}}},
+			wantErr: true,
+		},
+		{
+			name:    "negative-timeout",
+			hooks:   Hooks{PreDown: []Hook{{Local: "echo bye", Timeout: -time.Second}}},
+			wantErr: true,
+		},
+		{
+			name:    "wrong-failure-policy",
+			hooks:   Hooks{PostUp: []Hook{{Local: "echo ready", OnFailure: "retry"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.hooks.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Hooks.validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// Probes represents the overrides of the probes of the development container.
+// Probes without an override are removed, unless 'healthchecks' is enabled
+type Probes struct {
+	Liveness  *ProbeOverride `json:"liveness,omitempty" yaml:"liveness,omitempty"`
+	Readiness *ProbeOverride `json:"readiness,omitempty" yaml:"readiness,omitempty"`
+	Startup   *ProbeOverride `json:"startup,omitempty" yaml:"startup,omitempty"`
+}
+
+// ProbeOverride represents how a probe of the development container is translated.
+// A disabled probe is removed, an enabled probe without fields is kept as it is,
+// and the fields of Probe replace the ones of the probe of the container
+type ProbeOverride struct {
+	Enabled bool         `json:"enabled"`
+	Probe   *apiv1.Probe `json:"probe,omitempty"`
+}
+
+// Lifecycle represents the overrides of the lifecycle hooks of the development container.
+// Lifecycle hooks without an override are kept as they are
+type Lifecycle struct {
+	PostStart *LifecycleOverride `json:"postStart,omitempty" yaml:"postStart,omitempty"`
+	PreStop   *LifecycleOverride `json:"preStop,omitempty" yaml:"preStop,omitempty"`
+}
+
+// LifecycleOverride represents how a lifecycle hook of the development container is translated.
+// A disabled hook is removed, an enabled hook without handler is kept as it is, and Handler replaces the hook of the container
+type LifecycleOverride struct {
+	Enabled bool           `json:"enabled"`
+	Handler *apiv1.Handler `json:"handler,omitempty"`
+}
+
+func (p *Probes) validate() error {
+	if p == nil {
+		return nil
+	}
+
+	overrides := []struct {
+		name     string
+		override *ProbeOverride
+	}{
+		{name: "liveness", override: p.Liveness},
+		{name: "readiness", override: p.Readiness},
+		{name: "startup", override: p.Startup},
+	}
+	for _, o := range overrides {
+		if o.override == nil || o.override.Probe == nil {
+			continue
+		}
+		probe := o.override.Probe
+		field := fmt.Sprintf("probes.%s", o.name)
+		if countHandlers(&probe.Handler) > 1 {
+			return fmt.Errorf("'%s' can only define one of 'exec', 'httpGet' or 'tcpSocket'", field)
+		}
+		if probe.InitialDelaySeconds < 0 || probe.TimeoutSeconds < 0 || probe.PeriodSeconds < 0 || probe.SuccessThreshold < 0 || probe.FailureThreshold < 0 {
+			return fmt.Errorf("the seconds and thresholds of '%s' must be >= 0", field)
+		}
+		if o.name != "readiness" && probe.SuccessThreshold > 1 {
+			return fmt.Errorf("'%s.successThreshold' must be 1", field)
+		}
+	}
+	return nil
+}
+
+func (l *Lifecycle) validate() error {
+	if l == nil {
+		return nil
+	}
+
+	overrides := []struct {
+		name     string
+		override *LifecycleOverride
+	}{
+		{name: "postStart", override: l.PostStart},
+		{name: "preStop", override: l.PreStop},
+	}
+	for _, o := range overrides {
+		if o.override == nil || o.override.Handler == nil {
+			continue
+		}
+		if countHandlers(o.override.Handler) != 1 {
+			return fmt.Errorf("'lifecycle.%s' must define one of 'exec', 'httpGet' or 'tcpSocket'", o.name)
+		}
+	}
+	return nil
+}
+
+func countHandlers(h *apiv1.Handler) int {
+	handlers := 0
+	if h.Exec != nil {
+		handlers++
+	}
+	if h.HTTPGet != nil {
+		handlers++
+	}
+	if h.TCPSocket != nil {
+		handlers++
+	}
+	return handlers
+}
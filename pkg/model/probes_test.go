@@ -0,0 +1,91 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	apiv1 "k8s.io/api/core/v1"
+)
+
+func TestProbes_validate(t *testing.T) {
+	exec := &apiv1.ExecAction{Command: []string{"check"}}
+	tcp := &apiv1.TCPSocketAction{}
+	tests := []struct {
+		name    string
+		probes  *Probes
+		wantErr bool
+	}{
+		{name: "nil", probes: nil},
+		{
+			name: "valid",
+			probes: &Probes{
+				Liveness:  &ProbeOverride{},
+				Readiness: &ProbeOverride{Enabled: true, Probe: &apiv1.Probe{PeriodSeconds: 60, SuccessThreshold: 2}},
+				Startup:   &ProbeOverride{Enabled: true, Probe: &apiv1.Probe{Handler: apiv1.Handler{Exec: exec}}},
+			},
+		},
+		{
+			name:    "two-handlers",
+			probes:  &Probes{Startup: &ProbeOverride{Enabled: true, Probe: &apiv1.Probe{Handler: apiv1.Handler{Exec: exec, TCPSocket: tcp}}}},
+			wantErr: true,
+		},
+		{
+			name:    "negative-period",
+			probes:  &Probes{Readiness: &ProbeOverride{Enabled: true, Probe: &apiv1.Probe{PeriodSeconds: -1}}},
+			wantErr: true,
+		},
+		{
+			name:    "liveness-success-threshold",
+			probes:  &Probes{Liveness: &ProbeOverride{Enabled: true, Probe: &apiv1.Probe{SuccessThreshold: 2}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.probes.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Probes.validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLifecycle_validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		lifecycle *Lifecycle
+		wantErr   bool
+	}{
+		{name: "nil", lifecycle: nil},
+		{
+			name: "valid",
+			lifecycle: &Lifecycle{
+				PostStart: &LifecycleOverride{Enabled: true, Handler: &apiv1.Handler{Exec: &apiv1.ExecAction{Command: []string{"true"}}}},
+				PreStop:   &LifecycleOverride{},
+			},
+		},
+		{
+			name:      "no-handler",
+			lifecycle: &Lifecycle{PreStop: &LifecycleOverride{Enabled: true, Handler: &apiv1.Handler{}}},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.lifecycle.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Lifecycle.validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	profilesField = "profiles"
+)
+
+// applyProfiles removes the profiles of a manifest and applies the selected ones, in order.
+// Lists of a profile, like forward, environment or services, are appended to the ones of the manifest,
+// mappings like resources are merged key by key, and values replace the ones of the manifest
+func applyProfiles(devPath string, manifest map[interface{}]interface{}, selected []string) (map[interface{}]interface{}, error) {
+	defined := map[interface{}]interface{}{}
+	if raw, ok := manifest[profilesField]; ok && raw != nil {
+		defined, ok = raw.(map[interface{}]interface{})
+		if !ok {
+			return nil, fmt.Errorf("the field 'profiles' of '%s' must be a mapping of profile names to manifest fields", devPath)
+		}
+	}
+	delete(manifest, profilesField)
+
+	for _, name := range selected {
+		raw, ok := defined[name]
+		if !ok {
+			return nil, fmt.Errorf("the profile '%s' is not defined in '%s'%s", name, devPath, getProfilesHint(defined))
+		}
+		if raw == nil {
+			continue
+		}
+
+		profile, ok := raw.(map[interface{}]interface{})
+		if !ok {
+			return nil, fmt.Errorf("the profile '%s' of '%s' must be a mapping of manifest fields", name, devPath)
+		}
+		if _, ok := profile[extendsField]; ok {
+			return nil, fmt.Errorf("the profile '%s' of '%s' can't use the field 'extends'", name, devPath)
+		}
+		manifest = mergeProfile(manifest, profile)
+	}
+	return manifest, nil
+}
+
+// mergeProfile merges a profile into a manifest, appending its lists and merging its mappings recursively
+func mergeProfile(manifest, profile map[interface{}]interface{}) map[interface{}]interface{} {
+	for k, v := range profile {
+		switch value := v.(type) {
+		case map[interface{}]interface{}:
+			if current, ok := manifest[k].(map[interface{}]interface{}); ok {
+				manifest[k] = mergeProfile(current, value)
+				continue
+			}
+		case []interface{}:
+			if current, ok := manifest[k].([]interface{}); ok {
+				manifest[k] = append(current, value...)
+				continue
+			}
+		}
+		manifest[k] = v
+	}
+	return manifest
+}
+
+func getProfilesHint(defined map[interface{}]interface{}) string {
+	if len(defined) == 0 {
+		return ""
+	}
+
+	names := []string{}
+	for name := range defined {
+		names = append(names, fmt.Sprintf("%v", name))
+	}
+	sort.Strings(names)
+	return fmt.Sprintf(": must be one of %s", strings.Join(names, ", "))
+}
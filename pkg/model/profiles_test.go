@@ -0,0 +1,94 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestGetWithProfiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	manifest := []byte(`
+name: api
+image: okteto/golang:1
+sync:
+  - .:/usr/src/app
+forward:
+  - 8080:8080
+environment:
+  - ENV=dev
+resources:
+  limits:
+    cpu: 1
+    memory: 1Gi
+profiles:
+  debug:
+    forward:
+      - 2345:2345
+    environment:
+      - DEBUG=true
+  full:
+    resources:
+      limits:
+        memory: 4Gi
+    services:
+      - name: worker
+        sync:
+          - .:/usr/src/app
+  light:
+`)
+	devPath := filepath.Join(dir, "okteto.yml")
+	if err := ioutil.WriteFile(devPath, manifest, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	dev, err := Get(devPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dev.Forward) != 1 || len(dev.Environment) != 1 || len(dev.Services) != 0 {
+		t.Errorf("expected no profile to be applied, got %+v %+v %+v", dev.Forward, dev.Environment, dev.Services)
+	}
+
+	dev, err = GetWithProfiles(devPath, []string{"debug", "full", "light"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dev.Forward) != 2 {
+		t.Errorf("expected the forwards of the debug profile to be appended, got %+v", dev.Forward)
+	}
+	if len(dev.Environment) != 2 || dev.Environment[1].Name != "DEBUG" {
+		t.Errorf("expected the environment of the debug profile to be appended, got %+v", dev.Environment)
+	}
+	if len(dev.Services) != 1 || dev.Services[0].Name != "worker" {
+		t.Errorf("expected the services of the full profile, got %+v", dev.Services)
+	}
+	if !dev.Resources.Limits["cpu"].Equal(resource.MustParse("1")) || !dev.Resources.Limits["memory"].Equal(resource.MustParse("4Gi")) {
+		t.Errorf("wrong resources: %+v", dev.Resources.Limits)
+	}
+
+	if _, err := GetWithProfiles(devPath, []string{"missing"}); err == nil {
+		t.Error("expected an error for a profile that isn't defined")
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import "fmt"
+
+// Expand returns one reverse forward per port of the range
+func (f Reverse) Expand() []Reverse {
+	if f.Count <= 1 {
+		return []Reverse{{Remote: f.Remote, Local: f.Local}}
+	}
+
+	result := make([]Reverse, 0, f.Count)
+	for i := 0; i < f.Count; i++ {
+		result = append(result, Reverse{Remote: f.Remote + i, Local: f.Local + i})
+	}
+
+	return result
+}
+
+// LocalRange returns the local port or port range of the reverse forward
+func (f Reverse) LocalRange() string {
+	return portRangeString(f.Local, f.Count)
+}
+
+// RemoteRange returns the remote port or port range of the reverse forward
+func (f Reverse) RemoteRange() string {
+	return portRangeString(f.Remote, f.Count)
+}
+
+func portRangeString(start, count int) string {
+	if count <= 1 {
+		return fmt.Sprintf("%d", start)
+	}
+
+	return fmt.Sprintf("%d-%d", start, start+count-1)
+}
@@ -16,12 +16,16 @@ package model
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/okteto/okteto/pkg/log"
+	yaml "gopkg.in/yaml.v2"
 	apiv1 "k8s.io/api/core/v1"
 	resource "k8s.io/apimachinery/pkg/api/resource"
+	k8syaml "sigs.k8s.io/yaml"
 )
 
 // UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
@@ -51,6 +55,61 @@ func (e EnvVar) MarshalYAML() (interface{}, error) {
 	return e.Name + "=" + e.Value, nil
 }
 
+// UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
+func (a *BuildArgs) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var list []EnvVar
+	if err := unmarshal(&list); err == nil {
+		*a = list
+		return nil
+	}
+
+	var rawMap map[string]string
+	if err := unmarshal(&rawMap); err != nil {
+		return err
+	}
+
+	names := []string{}
+	for name := range rawMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := BuildArgs{}
+	for _, name := range names {
+		value, err := ExpandEnv(rawMap[name])
+		if err != nil {
+			return err
+		}
+		result = append(result, EnvVar{Name: name, Value: value})
+	}
+	*a = result
+	return nil
+}
+
+// UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
+func (f *BuildFrontend) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var image string
+	if err := unmarshal(&image); err == nil {
+		f.Image = image
+		return nil
+	}
+
+	var raw BuildFrontendRaw
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	*f = BuildFrontend(raw)
+	return nil
+}
+
+// MarshalYAML Implements the marshaler interface of the yaml pkg.
+func (f BuildFrontend) MarshalYAML() (interface{}, error) {
+	if len(f.Attrs) == 0 {
+		return f.Image, nil
+	}
+	return BuildFrontendRaw(f), nil
+}
+
 // UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
 func (c *Command) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	var multi []string
@@ -121,7 +180,33 @@ func (buildInfo *BuildInfo) UnmarshalYAML(unmarshal func(interface{}) error) err
 	buildInfo.Context = rawBuildInfo.Context
 	buildInfo.Dockerfile = rawBuildInfo.Dockerfile
 	buildInfo.Target = rawBuildInfo.Target
+	buildInfo.CacheFrom = rawBuildInfo.CacheFrom
+	buildInfo.Platform = rawBuildInfo.Platform
 	buildInfo.Args = rawBuildInfo.Args
+	buildInfo.Ignore = rawBuildInfo.Ignore
+	buildInfo.Frontend = rawBuildInfo.Frontend
+	buildInfo.Compression = rawBuildInfo.Compression
+	buildInfo.Builder = rawBuildInfo.Builder
+	buildInfo.BuilderImage = rawBuildInfo.BuilderImage
+	buildInfo.CacheScope = rawBuildInfo.CacheScope
+	buildInfo.TagTemplate = rawBuildInfo.TagTemplate
+	buildInfo.Labels = rawBuildInfo.Labels
+	buildInfo.WarmTarget = rawBuildInfo.WarmTarget
+	if len(rawBuildInfo.Secrets) > 0 {
+		buildInfo.Secrets = map[string]string{}
+		for id, src := range rawBuildInfo.Secrets {
+			expanded, err := ExpandEnv(src)
+			if err != nil {
+				return err
+			}
+			if strings.HasPrefix(expanded, "~/") {
+				if home, err := os.UserHomeDir(); err == nil {
+					expanded = filepath.Join(home, expanded[2:])
+				}
+			}
+			buildInfo.Secrets[id] = expanded
+		}
+	}
 	return nil
 }
 
@@ -136,9 +221,33 @@ func (buildInfo BuildInfo) MarshalYAML() (interface{}, error) {
 	if buildInfo.Target != "" {
 		return buildInfo.BuildInfoRaw, nil
 	}
+	if buildInfo.Platform != "" {
+		return buildInfo.BuildInfoRaw, nil
+	}
 	if buildInfo.Args != nil && len(buildInfo.Args) != 0 {
 		return buildInfo.BuildInfoRaw, nil
 	}
+	if len(buildInfo.Secrets) != 0 {
+		return buildInfo.BuildInfoRaw, nil
+	}
+	if len(buildInfo.Ignore) != 0 {
+		return buildInfo.BuildInfoRaw, nil
+	}
+	if buildInfo.Frontend.Image != "" {
+		return buildInfo.BuildInfoRaw, nil
+	}
+	if buildInfo.Compression != "" {
+		return buildInfo.BuildInfoRaw, nil
+	}
+	if buildInfo.Builder != "" || buildInfo.BuilderImage != "" {
+		return buildInfo.BuildInfoRaw, nil
+	}
+	if buildInfo.CacheScope != "" || buildInfo.TagTemplate != "" {
+		return buildInfo.BuildInfoRaw, nil
+	}
+	if len(buildInfo.Labels) != 0 || buildInfo.WarmTarget != "" {
+		return buildInfo.BuildInfoRaw, nil
+	}
 	return buildInfo.Name, nil
 }
 
@@ -187,6 +296,7 @@ func (s Secret) MarshalYAML() (interface{}, error) {
 }
 
 // UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
+// It supports single ports (remote:local) and port ranges of the same size (remoteStart-remoteEnd:localStart-localEnd)
 func (f *Reverse) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	var raw string
 	err := unmarshal(&raw)
@@ -194,28 +304,87 @@ func (f *Reverse) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		return err
 	}
 
+	raw, err = ExpandEnv(raw)
+	if err != nil {
+		return err
+	}
 	parts := strings.SplitN(raw, ":", 2)
 	if len(parts) != 2 {
 		return fmt.Errorf("Wrong port-forward syntax '%s', must be of the form 'localPort:RemotePort'", raw)
 	}
-	remotePort, err := strconv.Atoi(parts[0])
+	remotePort, remoteCount, err := parsePortRange(parts[0])
 	if err != nil {
-		return fmt.Errorf("Cannot convert remote port '%s' in reverse '%s'", parts[0], raw)
+		return fmt.Errorf("Cannot convert remote port '%s' in reverse '%s': %s", parts[0], raw, err)
 	}
 
-	localPort, err := strconv.Atoi(parts[1])
+	localPort, localCount, err := parsePortRange(parts[1])
 	if err != nil {
-		return fmt.Errorf("Cannot convert local port '%s' in reverse '%s'", parts[1], raw)
+		return fmt.Errorf("Cannot convert local port '%s' in reverse '%s': %s", parts[1], raw, err)
+	}
+
+	if remoteCount != localCount {
+		return fmt.Errorf("Remote and local port ranges in reverse '%s' must have the same size", raw)
 	}
 
 	f.Local = localPort
 	f.Remote = remotePort
+	if localCount > 1 {
+		f.Count = localCount
+	}
 	return nil
 }
 
 // MarshalYAML Implements the marshaler interface of the yaml pkg.
 func (f Reverse) MarshalYAML() (interface{}, error) {
-	return fmt.Sprintf("%d:%d", f.Remote, f.Local), nil
+	return fmt.Sprintf("%s:%s", f.RemoteRange(), f.LocalRange()), nil
+}
+
+const (
+	maxPort = 65535
+
+	// maxPortRangeSize is the maximum number of ports of a port range
+	maxPortRangeSize = 100
+)
+
+func parsePortRange(raw string) (int, int, error) {
+	parts := strings.SplitN(raw, "-", 2)
+	start, err := parsePort(parts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if len(parts) == 1 {
+		return start, 1, nil
+	}
+
+	end, err := parsePort(parts[1])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if end < start {
+		return 0, 0, fmt.Errorf("invalid port range '%s'", raw)
+	}
+
+	count := end - start + 1
+	if count > maxPortRangeSize {
+		return 0, 0, fmt.Errorf("port range '%s' has %d ports, the maximum is %d", raw, count, maxPortRangeSize)
+	}
+
+	return start, count, nil
+}
+
+func parsePort(raw string) (int, error) {
+	port, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is not a port number", raw)
+	}
+
+	if port < 1 || port > maxPort {
+		return 0, fmt.Errorf("port %d must be between 1 and %d", port, maxPort)
+	}
+
+	return port, nil
 }
 
 // UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
@@ -280,11 +449,16 @@ func (v Volume) MarshalYAML() (interface{}, error) {
 }
 
 // UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
+// Sync folders are defined as 'localPath:remotePath', or as a mapping with their localPath, remotePath and exclude patterns
 func (s *Sync) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	var raw string
 	err := unmarshal(&raw)
 	if err != nil {
-		return err
+		var extended syncRaw
+		if err := unmarshal(&extended); err != nil {
+			return err
+		}
+		return s.fromExtended(extended)
 	}
 
 	parts := strings.SplitN(raw, ":", 2)
@@ -300,8 +474,32 @@ func (s *Sync) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return fmt.Errorf("each element in the 'sync' field must follow the syntax 'localPath:remotePath'")
 }
 
+func (s *Sync) fromExtended(raw syncRaw) error {
+	if raw.LocalPath == "" || raw.RemotePath == "" {
+		return fmt.Errorf("each element in the 'sync' field must define 'localPath' and 'remotePath'")
+	}
+
+	var err error
+	s.LocalPath, err = ExpandEnv(raw.LocalPath)
+	if err != nil {
+		return err
+	}
+	s.RemotePath = raw.RemotePath
+	for _, pattern := range raw.Exclude {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		s.Exclude = append(s.Exclude, filepath.ToSlash(pattern))
+	}
+	return nil
+}
+
 // MarshalYAML Implements the marshaler interface of the yaml pkg.
 func (s Sync) MarshalYAML() (interface{}, error) {
+	if len(s.Exclude) > 0 {
+		return syncRaw{LocalPath: s.LocalPath, RemotePath: s.RemotePath, Exclude: s.Exclude}, nil
+	}
 	return s.LocalPath + ":" + s.RemotePath, nil
 }
 
@@ -346,3 +544,97 @@ func checkFileAndNotDirectory(path string) error {
 	}
 	return fmt.Errorf("Secret '%s' is not a regular file", path)
 }
+
+// UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
+func (a *Affinity) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	affinity := apiv1.Affinity{}
+	if err := unmarshalK8sYAML(unmarshal, &affinity); err != nil {
+		return fmt.Errorf("invalid affinity: %s", err)
+	}
+
+	*a = Affinity(affinity)
+	return nil
+}
+
+// MarshalYAML Implements the marshaler interface of the yaml pkg.
+func (a *Affinity) MarshalYAML() (interface{}, error) {
+	return marshalK8sYAML(apiv1.Affinity(*a))
+}
+
+// UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
+func (p *ProbeOverride) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var enabled bool
+	if err := unmarshal(&enabled); err == nil {
+		p.Enabled = enabled
+		return nil
+	}
+
+	probe := &apiv1.Probe{}
+	if err := unmarshalK8sYAML(unmarshal, probe); err != nil {
+		return fmt.Errorf("invalid probe: %s", err)
+	}
+	p.Enabled = true
+	p.Probe = probe
+	return nil
+}
+
+// MarshalYAML Implements the marshaler interface of the yaml pkg.
+func (p ProbeOverride) MarshalYAML() (interface{}, error) {
+	if p.Probe == nil {
+		return p.Enabled, nil
+	}
+	return marshalK8sYAML(p.Probe)
+}
+
+// UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
+func (l *LifecycleOverride) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var enabled bool
+	if err := unmarshal(&enabled); err == nil {
+		l.Enabled = enabled
+		return nil
+	}
+
+	handler := &apiv1.Handler{}
+	if err := unmarshalK8sYAML(unmarshal, handler); err != nil {
+		return fmt.Errorf("invalid lifecycle hook: %s", err)
+	}
+	l.Enabled = true
+	l.Handler = handler
+	return nil
+}
+
+// MarshalYAML Implements the marshaler interface of the yaml pkg.
+func (l LifecycleOverride) MarshalYAML() (interface{}, error) {
+	if l.Handler == nil {
+		return l.Enabled, nil
+	}
+	return marshalK8sYAML(l.Handler)
+}
+
+// unmarshalK8sYAML unmarshals a mapping into a kubernetes object, using the json names of its fields
+func unmarshalK8sYAML(unmarshal func(interface{}) error, obj interface{}) error {
+	var raw map[string]interface{}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	bytes, err := yaml.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return k8syaml.UnmarshalStrict(bytes, obj)
+}
+
+// marshalK8sYAML marshals a kubernetes object into a mapping, using the json names of its fields
+func marshalK8sYAML(obj interface{}) (interface{}, error) {
+	bytes, err := k8syaml.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(bytes, &raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
@@ -18,6 +18,7 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -42,6 +43,41 @@ func TestReverseMashalling(t *testing.T) {
 			data:     "8080:8080",
 			expected: Reverse{Local: 8080, Remote: 8080},
 		},
+		{
+			name:     "range",
+			data:     "9000-9010:8000-8010",
+			expected: Reverse{Local: 8000, Remote: 9000, Count: 11},
+		},
+		{
+			name:      "range-different-size",
+			data:      "9000-9010:8000-8005",
+			expectErr: true,
+		},
+		{
+			name:      "range-inverted",
+			data:      "9010-9000:9010-9000",
+			expectErr: true,
+		},
+		{
+			name:      "range-too-big",
+			data:      "1000-60000:1000-60000",
+			expectErr: true,
+		},
+		{
+			name:      "port-zero",
+			data:      "0:8080",
+			expectErr: true,
+		},
+		{
+			name:      "port-too-high",
+			data:      "8080:70000",
+			expectErr: true,
+		},
+		{
+			name:      "range-out-of-bounds",
+			data:      "65530-65540:65530-65540",
+			expectErr: true,
+		},
 		{
 			name:      "missing-part",
 			data:      "8080",
@@ -252,6 +288,11 @@ func TestImageMashalling(t *testing.T) {
 			image:    BuildInfo{BuildInfoRaw{Name: "image-name", Context: "path"}},
 			expected: "name: image-name\ncontext: path\n",
 		},
+		{
+			name:     "secrets",
+			image:    BuildInfo{BuildInfoRaw{Name: "image-name", Secrets: map[string]string{"npmrc": "/home/.npmrc"}}},
+			expected: "name: image-name\nsecrets:\n  npmrc: /home/.npmrc\n",
+		},
 	}
 
 	for _, tt := range tests {
@@ -268,6 +309,70 @@ func TestImageMashalling(t *testing.T) {
 	}
 }
 
+func TestBuildArgsUnmashalling(t *testing.T) {
+	os.Setenv("NODE_VERSION", "14")
+	defer os.Unsetenv("NODE_VERSION")
+
+	tests := []struct {
+		name     string
+		data     []byte
+		expected BuildArgs
+	}{
+		{
+			name:     "list",
+			data:     []byte("- NODE=${NODE_VERSION}\n- ENV=dev\n"),
+			expected: BuildArgs{{Name: "NODE", Value: "14"}, {Name: "ENV", Value: "dev"}},
+		},
+		{
+			name:     "map",
+			data:     []byte("NODE: ${NODE_VERSION}\nENV: dev\n"),
+			expected: BuildArgs{{Name: "ENV", Value: "dev"}, {Name: "NODE", Value: "14"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args BuildArgs
+			if err := yaml.Unmarshal(tt.data, &args); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(args, tt.expected) {
+				t.Errorf("didn't unmarshal correctly. Actual %+v, Expected %+v", args, tt.expected)
+			}
+		})
+	}
+}
+
+func TestImageSecretsUnmashalling(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("NPM_CONFIG", "/etc/npmrc")
+	defer os.Unsetenv("NPM_CONFIG")
+
+	var image BuildInfo
+	manifest := []byte(`name: image-name
+secrets:
+  npmrc: ~/.npmrc
+  global: ${NPM_CONFIG}`)
+	if err := yaml.Unmarshal(manifest, &image); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{"npmrc": filepath.Join(home, ".npmrc"), "global": "/etc/npmrc"}
+	if !reflect.DeepEqual(image.Secrets, expected) {
+		t.Errorf("didn't unmarshal correctly. Actual %v, Expected %v", image.Secrets, expected)
+	}
+
+	serialized := SerializeBuildSecrets(image.Secrets)
+	expectedSerialized := []string{"id=global,src=/etc/npmrc", fmt.Sprintf("id=npmrc,src=%s", filepath.Join(home, ".npmrc"))}
+	if !reflect.DeepEqual(serialized, expectedSerialized) {
+		t.Errorf("didn't serialize correctly. Actual %v, Expected %v", serialized, expectedSerialized)
+	}
+}
+
 func TestSecretMashalling(t *testing.T) {
 	file, err := ioutil.TempFile("/tmp", "okteto-secret-test")
 	if err != nil {
@@ -401,3 +506,314 @@ func TestVolumeMashalling(t *testing.T) {
 		})
 	}
 }
+
+func TestAffinityMashalling(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []byte
+		expectErr bool
+	}{
+		{
+			"node-affinity",
+			[]byte(`nodeAffinity:
+  preferredDuringSchedulingIgnoredDuringExecution:
+  - weight: 1
+    preference:
+      matchExpressions:
+      - key: disktype
+        operator: In
+        values:
+        - ssd`),
+			false,
+		},
+		{
+			"unknown-field",
+			[]byte(`nodeAfinity: {}`),
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a Affinity
+			err := yaml.Unmarshal(tt.data, &a)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if a.NodeAffinity == nil || a.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].Weight != 1 {
+				t.Fatalf("didn't unmarshal correctly: %+v", a)
+			}
+
+			out, err := yaml.Marshal(&a)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var b Affinity
+			if err := yaml.Unmarshal(out, &b); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(a, b) {
+				t.Errorf("didn't marshal correctly. Actual %+v, Expected %+v", b, a)
+			}
+		})
+	}
+}
+
+func TestBuildFrontendSerialization(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected BuildFrontend
+	}{
+		{
+			name:     "image",
+			data:     "frontend: docker/dockerfile:1.2\n",
+			expected: BuildFrontend{Image: "docker/dockerfile:1.2"},
+		},
+		{
+			name:     "image-and-attrs",
+			data:     "frontend:\n  image: okteto/nixpacks-frontend\n  attrs:\n    provider: node\n",
+			expected: BuildFrontend{Image: "okteto/nixpacks-frontend", Attrs: map[string]string{"provider": "node"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b BuildInfo
+			if err := yaml.Unmarshal([]byte("context: api\n"+tt.data), &b); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(b.Frontend, tt.expected) {
+				t.Errorf("didn't unmarshal correctly. Actual %+v, Expected %+v", b.Frontend, tt.expected)
+			}
+
+			marshalled, err := yaml.Marshal(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(marshalled) != "context: api\n"+tt.data {
+				t.Errorf("didn't marshal correctly. Actual %s, Expected %s", marshalled, "context: api\n"+tt.data)
+			}
+		})
+	}
+
+	marshalled, err := yaml.Marshal(BuildInfo{BuildInfoRaw: BuildInfoRaw{Context: "api"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(marshalled) != "context: api\n" {
+		t.Errorf("empty frontend wasn't omitted: %s", marshalled)
+	}
+}
+
+func TestBuildPlatformSerialization(t *testing.T) {
+	var b BuildInfo
+	if err := yaml.Unmarshal([]byte("context: api\nplatform: linux/arm64\n"), &b); err != nil {
+		t.Fatal(err)
+	}
+	if b.Platform != "linux/arm64" {
+		t.Errorf("didn't unmarshal the platform: %+v", b)
+	}
+
+	marshalled, err := yaml.Marshal(BuildInfo{BuildInfoRaw: BuildInfoRaw{Name: "okteto/api", Platform: "linux/arm64"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(marshalled) != "name: okteto/api\nplatform: linux/arm64\n" {
+		t.Errorf("didn't marshal the platform: %s", marshalled)
+	}
+}
+
+func TestBuildCompressionSerialization(t *testing.T) {
+	var b BuildInfo
+	if err := yaml.Unmarshal([]byte("context: api\ncompression: estargz\n"), &b); err != nil {
+		t.Fatal(err)
+	}
+	if b.Compression != "estargz" {
+		t.Errorf("didn't unmarshal the compression: %+v", b)
+	}
+
+	marshalled, err := yaml.Marshal(BuildInfo{BuildInfoRaw: BuildInfoRaw{Name: "okteto/api", Compression: "estargz"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(marshalled) != "name: okteto/api\ncompression: estargz\n" {
+		t.Errorf("didn't marshal the compression: %s", marshalled)
+	}
+}
+
+func TestBuildBuilderSerialization(t *testing.T) {
+	var b BuildInfo
+	if err := yaml.Unmarshal([]byte("context: api\nbuilder: buildpacks\nbuilderImage: gcr.io/buildpacks/builder\n"), &b); err != nil {
+		t.Fatal(err)
+	}
+	if !b.IsBuildpacks() || b.GetBuilderImage() != "gcr.io/buildpacks/builder" {
+		t.Errorf("didn't unmarshal the builder: %+v", b)
+	}
+	if err := b.validateBuilder("image"); err != nil {
+		t.Error(err)
+	}
+
+	b = BuildInfo{BuildInfoRaw: BuildInfoRaw{Builder: BuildpacksBuilder}}
+	if b.GetBuilderImage() != DefaultBuildpacksBuilderImage {
+		t.Errorf("wrong default builder image: %s", b.GetBuilderImage())
+	}
+
+	b = BuildInfo{BuildInfoRaw: BuildInfoRaw{Builder: "kaniko"}}
+	if err := b.validateBuilder("image"); err == nil {
+		t.Error("unsupported builder didn't fail")
+	}
+
+	b = BuildInfo{BuildInfoRaw: BuildInfoRaw{BuilderImage: "gcr.io/buildpacks/builder"}}
+	if err := b.validateBuilder("image"); err == nil {
+		t.Error("builder image without buildpacks didn't fail")
+	}
+}
+
+func TestBuildCacheScope(t *testing.T) {
+	var b BuildInfo
+	if err := yaml.Unmarshal([]byte("context: api\ncacheScope: service\n"), &b); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.validateCacheScope("image"); err != nil {
+		t.Error(err)
+	}
+	if scope := b.GetCacheScope("cindy", "api"); scope != "cindy-api" {
+		t.Errorf("wrong service cache scope: %s", scope)
+	}
+
+	b.CacheScope = CacheScopeNamespace
+	if scope := b.GetCacheScope("cindy", "api"); scope != "cindy" {
+		t.Errorf("wrong namespace cache scope: %s", scope)
+	}
+
+	b.CacheScope = ""
+	if scope := b.GetCacheScope("cindy", "api"); scope != "" {
+		t.Errorf("wrong user cache scope: %s", scope)
+	}
+
+	b.CacheScope = "cluster"
+	if err := b.validateCacheScope("image"); err == nil {
+		t.Error("unsupported cache scope didn't fail")
+	}
+}
+
+func TestBuildWarmTargetSerialization(t *testing.T) {
+	var b BuildInfo
+	if err := yaml.Unmarshal([]byte("context: api\nwarmTarget: deps\n"), &b); err != nil {
+		t.Fatal(err)
+	}
+	if b.WarmTarget != "deps" {
+		t.Errorf("didn't unmarshal the warm target: %+v", b)
+	}
+
+	marshalled, err := yaml.Marshal(BuildInfo{BuildInfoRaw: BuildInfoRaw{Name: "okteto/api", WarmTarget: "deps"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(marshalled) != "name: okteto/api\nwarmTarget: deps\n" {
+		t.Errorf("didn't marshal the warm target: %s", marshalled)
+	}
+}
+
+func TestSyncSerialization(t *testing.T) {
+	manifest := []byte(`
+- .:/app
+- localPath: web
+  remotePath: /app/web
+  exclude:
+    - "**/node_modules"
+    - " "
+`)
+	var syncs []Sync
+	if err := yaml.Unmarshal(manifest, &syncs); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Sync{
+		{LocalPath: ".", RemotePath: "/app"},
+		{LocalPath: "web", RemotePath: "/app/web", Exclude: []string{"**/node_modules"}},
+	}
+	if !reflect.DeepEqual(syncs, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, syncs)
+	}
+
+	out, err := yaml.Marshal(syncs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var roundTrip []Sync
+	if err := yaml.Unmarshal(out, &roundTrip); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(roundTrip, expected) {
+		t.Errorf("expected %+v, got %+v", expected, roundTrip)
+	}
+
+	if err := yaml.Unmarshal([]byte("- exclude: [tmp]\n"), &syncs); err == nil {
+		t.Error("expected an error for a sync folder without paths")
+	}
+}
+
+func TestProbesSerialization(t *testing.T) {
+	manifest := []byte(`
+probes:
+  liveness: false
+  readiness: true
+  startup:
+    httpGet:
+      path: /healthz
+      port: 8080
+    failureThreshold: 30
+lifecycle:
+  preStop: false
+  postStart:
+    exec:
+      command: ["true"]
+`)
+	var dev Dev
+	if err := yaml.Unmarshal(manifest, &dev); err != nil {
+		t.Fatal(err)
+	}
+
+	if dev.Probes.Liveness.Enabled || dev.Probes.Liveness.Probe != nil {
+		t.Errorf("wrong liveness override: %+v", dev.Probes.Liveness)
+	}
+	if !dev.Probes.Readiness.Enabled || dev.Probes.Readiness.Probe != nil {
+		t.Errorf("wrong readiness override: %+v", dev.Probes.Readiness)
+	}
+	startup := dev.Probes.Startup.Probe
+	if !dev.Probes.Startup.Enabled || startup == nil || startup.HTTPGet.Path != "/healthz" || startup.HTTPGet.Port.IntValue() != 8080 || startup.FailureThreshold != 30 {
+		t.Errorf("wrong startup override: %+v", dev.Probes.Startup)
+	}
+	if dev.Lifecycle.PreStop.Enabled || !dev.Lifecycle.PostStart.Enabled || dev.Lifecycle.PostStart.Handler.Exec.Command[0] != "true" {
+		t.Errorf("wrong lifecycle overrides: %+v %+v", dev.Lifecycle.PreStop, dev.Lifecycle.PostStart)
+	}
+
+	out, err := yaml.Marshal(Dev{Probes: dev.Probes, Lifecycle: dev.Lifecycle})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var roundTrip Dev
+	if err := yaml.Unmarshal(out, &roundTrip); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(roundTrip.Probes, dev.Probes) || !reflect.DeepEqual(roundTrip.Lifecycle, dev.Lifecycle) {
+		t.Errorf("wrong round trip: %s", out)
+	}
+
+	if err := yaml.Unmarshal([]byte("probes:\n  liveness:\n    unknown: 1\n"), &dev); err == nil {
+		t.Error("expected an error for an unknown probe field")
+	}
+}
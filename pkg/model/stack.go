@@ -63,6 +63,10 @@ func GetStack(name, stackPath string) (*Stack, error) {
 		return nil, err
 	}
 
+	if err := loadDotEnv(filepath.Dir(stackPath)); err != nil {
+		return nil, err
+	}
+
 	s, err := ReadStack(b)
 	if err != nil {
 		return nil, err
@@ -90,8 +94,7 @@ func GetStack(name, stackPath string) (*Stack, error) {
 		if svc.Build == nil {
 			continue
 		}
-		svc.Build.Context = loadAbsPath(stackDir, svc.Build.Context)
-		svc.Build.Dockerfile = loadAbsPath(stackDir, svc.Build.Dockerfile)
+		svc.Build.loadAbsPaths(stackDir)
 		s.Services[name] = svc
 	}
 	return s, nil
@@ -120,7 +123,16 @@ func ReadStack(bytes []byte) (*Stack, error) {
 		return nil, errors.New(msg)
 	}
 	for i, svc := range s.Services {
+		image, err := ExpandEnv(svc.Image)
+		if err != nil {
+			return nil, err
+		}
+		svc.Image = image
+		s.Services[i] = svc
 		if svc.Build != nil {
+			if err := svc.Build.expandEnvVars(); err != nil {
+				return nil, err
+			}
 			if svc.Build.Name != "" {
 				svc.Build.Context = svc.Build.Name
 				svc.Build.Name = ""
@@ -150,6 +162,12 @@ func (s *Stack) validate() error {
 		if svc.Image == "" {
 			return fmt.Errorf(fmt.Sprintf("Invalid service '%s': image cannot be empty", name))
 		}
+		if err := svc.Build.validateCacheScope("build"); err != nil {
+			return fmt.Errorf("Invalid service '%s': %s", name, err)
+		}
+		if err := svc.Build.validateTagTemplate("build"); err != nil {
+			return fmt.Errorf("Invalid service '%s': %s", name, err)
+		}
 		for _, v := range svc.Volumes {
 			if !strings.HasPrefix(v, "/") {
 				return fmt.Errorf(fmt.Sprintf("Invalid volume '%s' in service '%s': must be an absolute path", v, name))
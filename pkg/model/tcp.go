@@ -0,0 +1,48 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// TCPOptions represents the socket options of TCP connections
+type TCPOptions struct {
+	NoDelay       *bool         `json:"noDelay,omitempty" yaml:"noDelay,omitempty"`
+	SendBuffer    int           `json:"sendBuffer,omitempty" yaml:"sendBuffer,omitempty"`
+	ReceiveBuffer int           `json:"receiveBuffer,omitempty" yaml:"receiveBuffer,omitempty"`
+	KeepAlive     time.Duration `json:"keepAlive,omitempty" yaml:"keepAlive,omitempty"`
+}
+
+// IsZero returns true if no option is set
+func (o TCPOptions) IsZero() bool {
+	return o.NoDelay == nil && o.SendBuffer == 0 && o.ReceiveBuffer == 0 && o.KeepAlive == 0
+}
+
+func (o TCPOptions) validate(field string) error {
+	if o.SendBuffer < 0 {
+		return fmt.Errorf("'%s.sendBuffer' must be >= 0", field)
+	}
+
+	if o.ReceiveBuffer < 0 {
+		return fmt.Errorf("'%s.receiveBuffer' must be >= 0", field)
+	}
+
+	if o.KeepAlive < 0 {
+		return fmt.Errorf("'%s.keepAlive' must be >= 0", field)
+	}
+
+	return nil
+}
@@ -20,14 +20,29 @@ import (
 
 //Translation represents the information for translating a deployment
 type Translation struct {
-	Interactive bool               `json:"interactive"`
-	Name        string             `json:"name"`
-	Version     string             `json:"version"`
-	Deployment  *appsv1.Deployment `json:"-"`
-	Annotations map[string]string  `json:"annotations,omitempty"`
-	Tolerations []apiv1.Toleration `json:"tolerations,omitempty"`
-	Replicas    int32              `json:"replicas"`
-	Rules       []*TranslationRule `json:"rules"`
+	Interactive       bool               `json:"interactive"`
+	Name              string             `json:"name"`
+	Version           string             `json:"version"`
+	Deployment        *appsv1.Deployment `json:"-"`
+	Annotations       map[string]string  `json:"annotations,omitempty"`
+	Tolerations       []apiv1.Toleration `json:"tolerations,omitempty"`
+	NodeSelector      map[string]string  `json:"nodeSelector,omitempty"`
+	Affinity          *apiv1.Affinity    `json:"affinity,omitempty"`
+	ExcludeContainers []string           `json:"excludeContainers,omitempty"`
+	PriorityClassName string             `json:"priorityClassName,omitempty"`
+	OriginalPriority  *OriginalPriority  `json:"originalPriority,omitempty"`
+	ServiceAccount    string             `json:"serviceAccount,omitempty"`
+	PodLabels         *PodLabels         `json:"podLabels,omitempty"`
+	SecurityPolicy    string             `json:"securityPolicy,omitempty"`
+	StrippedLabels    map[string]string  `json:"strippedLabels,omitempty"`
+	Replicas          int32              `json:"replicas"`
+	Rules             []*TranslationRule `json:"rules"`
+}
+
+//OriginalPriority represents the priority of the pod template before the dev mode translation
+type OriginalPriority struct {
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	Priority          *int32 `json:"priority,omitempty"`
 }
 
 //TranslationRule represents how to apply a container translation in a deployment
@@ -44,10 +59,15 @@ type TranslationRule struct {
 	Args              []string             `json:"args,omitempty"`
 	WorkDir           string               `json:"workdir"`
 	Healthchecks      bool                 `json:"healthchecks" yaml:"healthchecks"`
+	Probes            *Probes              `json:"probes,omitempty"`
+	Lifecycle         *Lifecycle           `json:"lifecycle,omitempty"`
 	PersistentVolume  bool                 `json:"persistentVolume" yaml:"persistentVolume"`
 	Volumes           []VolumeMount        `json:"volumes,omitempty"`
 	SecurityContext   *SecurityContext     `json:"securityContext,omitempty"`
 	Resources         ResourceRequirements `json:"resources,omitempty"`
+	Tolerations       []apiv1.Toleration   `json:"tolerations,omitempty"`
+	InitContainer     InitContainer        `json:"initContainer,omitempty"`
+	ImagePullSecret   string               `json:"imagePullSecret,omitempty"`
 }
 
 //VolumeMount represents a volume mount
@@ -57,6 +57,7 @@ services:
 	rule1OK := &TranslationRule{
 		Marker:            OktetoBinImageTag,
 		OktetoBinImageTag: OktetoBinImageTag,
+		InitContainer:     InitContainer{Image: OktetoBinImageTag},
 		Container:         "dev",
 		Image:             "web:latest",
 		ImagePullPolicy:   apiv1.PullNever,
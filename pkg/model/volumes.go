@@ -92,7 +92,7 @@ func (dev *Dev) translateDeprecatedVolumes() {
 			volumes = append(volumes, v)
 			continue
 		}
-		dev.Syncs = append(dev.Syncs, Sync(v))
+		dev.Syncs = append(dev.Syncs, Sync{LocalPath: v.LocalPath, RemotePath: v.RemotePath})
 	}
 	dev.Volumes = volumes
 }
@@ -120,6 +120,46 @@ func (dev *Dev) IsSubPathFolder(path string) (bool, error) {
 	return false, errors.ErrNotFound
 }
 
+//GetSyncIgnores returns the syncthing ignore patterns of the exclusions of the sync folder of localPath,
+//including the exclusions of the sync folders inside it, which are synchronized as part of it
+func (dev *Dev) GetSyncIgnores(localPath string) []string {
+	result := []string{}
+	for _, sync := range dev.Syncs {
+		rel, err := filepath.Rel(localPath, sync.LocalPath)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		for _, pattern := range sync.Exclude {
+			result = append(result, getSyncIgnores(filepath.ToSlash(rel), pattern)...)
+		}
+	}
+	return result
+}
+
+// getSyncIgnores returns the syncthing ignore patterns of an exclusion of the sync folder at rel, relative to its parent sync folder
+func getSyncIgnores(rel, pattern string) []string {
+	if rel == "." {
+		return []string{pattern}
+	}
+
+	negate := ""
+	if strings.HasPrefix(pattern, "!") {
+		negate = "!"
+		pattern = pattern[1:]
+	}
+
+	if strings.HasPrefix(pattern, "/") {
+		return []string{fmt.Sprintf("%s/%s%s", negate, rel, pattern)}
+	}
+
+	// unanchored patterns match at any depth of the sync folder
+	pattern = strings.TrimPrefix(pattern, "**/")
+	return []string{
+		fmt.Sprintf("%s/%s/%s", negate, rel, pattern),
+		fmt.Sprintf("%s/%s/**/%s", negate, rel, pattern),
+	}
+}
+
 func (dev *Dev) computeParentSyncFolder() {
 	pathSplits := map[int]string{}
 	maxIndex := -1
@@ -697,6 +697,37 @@ func Test_IsSubPathFolder(t *testing.T) {
 	}
 }
 
+func TestDev_GetSyncIgnores(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("this test is not supported on windows")
+	}
+
+	dev := &Dev{
+		Syncs: []Sync{
+			{LocalPath: "/src", RemotePath: "/app", Exclude: []string{"**/*.log"}},
+			{LocalPath: "/src/web", RemotePath: "/app/web", Exclude: []string{"**/node_modules", "/dist", "!important.log"}},
+			{LocalPath: "/other", RemotePath: "/other", Exclude: []string{"tmp"}},
+		},
+	}
+
+	expected := []string{
+		"**/*.log",
+		"/web/node_modules",
+		"/web/**/node_modules",
+		"/web/dist",
+		"!/web/important.log",
+		"!/web/**/important.log",
+	}
+	if ignores := dev.GetSyncIgnores("/src"); !reflect.DeepEqual(ignores, expected) {
+		t.Errorf("expected %v, got %v", expected, ignores)
+	}
+
+	expected = []string{"tmp"}
+	if ignores := dev.GetSyncIgnores("/other"); !reflect.DeepEqual(ignores, expected) {
+		t.Errorf("expected %v, got %v", expected, ignores)
+	}
+}
+
 func Test_computeParentSyncFolder(t *testing.T) {
 	var tests = []struct {
 		name   string
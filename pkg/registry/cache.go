@@ -0,0 +1,114 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/okteto/okteto/pkg/config"
+	"github.com/okteto/okteto/pkg/log"
+)
+
+// buildCacheState tracks the generation of the build cache mounts of the user.
+// Cache mount ids are namespaced with the generation, so a new generation starts from a clean cache
+// and the mounts of the previous ones are reclaimed by the garbage collector of BuildKit
+type buildCacheState struct {
+	Generation int64     `json:"generation"`
+	ResetAt    time.Time `json:"resetAt"`
+}
+
+func getBuildCacheStatePath() string {
+	return filepath.Join(config.GetOktetoHome(), ".buildcache")
+}
+
+func getBuildCacheState() (*buildCacheState, error) {
+	state := &buildCacheState{}
+	b, err := ioutil.ReadFile(getBuildCacheStatePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, state); err != nil {
+		return nil, fmt.Errorf("failed to read the build cache state: %s", err)
+	}
+	return state, nil
+}
+
+func saveBuildCacheState(state *buildCacheState) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(getBuildCacheStatePath(), b, 0600)
+}
+
+// ResetBuildCache starts a new generation of the build cache mounts of the user
+func ResetBuildCache() error {
+	state, err := getBuildCacheState()
+	if err != nil {
+		return err
+	}
+
+	state.Generation++
+	state.ResetAt = time.Now()
+	return saveBuildCacheState(state)
+}
+
+// getBuildCacheNamespace returns the namespace of the cache mount ids of the user,
+// resetting the build cache when it is older than the configured TTL
+func getBuildCacheNamespace(userID string) string {
+	state, err := getBuildCacheState()
+	if err != nil {
+		log.Infof("failed to get the build cache state: %s", err)
+		return userID
+	}
+
+	if ttl := config.GetBuildCacheTTL(); ttl > 0 {
+		state = expireBuildCache(state, ttl, time.Now())
+		if err := saveBuildCacheState(state); err != nil {
+			log.Infof("failed to save the build cache state: %s", err)
+		}
+	}
+
+	return getCacheNamespace(userID, state.Generation)
+}
+
+// expireBuildCache starts a new generation when the current one is older than ttl
+func expireBuildCache(state *buildCacheState, ttl time.Duration, now time.Time) *buildCacheState {
+	if state.ResetAt.IsZero() {
+		return &buildCacheState{Generation: state.Generation, ResetAt: now}
+	}
+
+	if now.Sub(state.ResetAt) < ttl {
+		return state
+	}
+
+	log.Infof("build cache generation %d expired", state.Generation)
+	return &buildCacheState{Generation: state.Generation + 1, ResetAt: now}
+}
+
+func getCacheNamespace(userID string, generation int64) string {
+	if generation == 0 {
+		return userID
+	}
+	return fmt.Sprintf("%s-%d", userID, generation)
+}
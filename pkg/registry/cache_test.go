@@ -0,0 +1,68 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_expireBuildCache(t *testing.T) {
+	now := time.Now()
+	var tests = []struct {
+		name               string
+		state              *buildCacheState
+		expectedGeneration int64
+		expectedResetAt    time.Time
+	}{
+		{
+			name:               "no-state",
+			state:              &buildCacheState{},
+			expectedGeneration: 0,
+			expectedResetAt:    now,
+		},
+		{
+			name:               "not-expired",
+			state:              &buildCacheState{Generation: 2, ResetAt: now.Add(-time.Hour)},
+			expectedGeneration: 2,
+			expectedResetAt:    now.Add(-time.Hour),
+		},
+		{
+			name:               "expired",
+			state:              &buildCacheState{Generation: 2, ResetAt: now.Add(-48 * time.Hour)},
+			expectedGeneration: 3,
+			expectedResetAt:    now,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := expireBuildCache(tt.state, 24*time.Hour, now)
+			if result.Generation != tt.expectedGeneration {
+				t.Errorf("got generation %d, expected %d", result.Generation, tt.expectedGeneration)
+			}
+			if !result.ResetAt.Equal(tt.expectedResetAt) {
+				t.Errorf("got reset time %s, expected %s", result.ResetAt, tt.expectedResetAt)
+			}
+		})
+	}
+}
+
+func Test_getCacheNamespace(t *testing.T) {
+	if ns := getCacheNamespace("userid", 0); ns != "userid" {
+		t.Errorf("got %s, expected userid", ns)
+	}
+	if ns := getCacheNamespace("userid", 3); ns != "userid-3" {
+		t.Errorf("got %s, expected userid-3", ns)
+	}
+}
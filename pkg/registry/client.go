@@ -15,6 +15,7 @@ package registry
 
 import (
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/heroku/docker-registry-client/registry"
@@ -24,7 +25,18 @@ import (
 // NewRegistryClient creates a new Registry with the given URL and credentials, then Ping()s it
 // before returning it to verify that the registry is available.
 func NewRegistryClient(registryURL, username, password string) (*registry.Registry, error) {
-	transport := http.DefaultTransport
+	u, err := url.Parse(registryURL)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := getTLSConfig(u.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
 	return newFromTransport(registryURL, username, password, transport)
 }
 
@@ -0,0 +1,170 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/okteto/okteto/pkg/log"
+)
+
+const (
+	dockerHubAuthServer = "https://index.docker.io/v1/"
+
+	// cloudTokenRefreshMargin is the time before their expiration when the short-lived tokens of cloud registries are refreshed
+	cloudTokenRefreshMargin = 5 * time.Minute
+
+	cloudCommandTimeout = 30 * time.Second
+)
+
+var (
+	ecrHostRegex = regexp.MustCompile(`^\d+\.dkr\.ecr(-fips)?\.([a-z0-9-]+)\.amazonaws\.com(\.cn)?$`)
+	gcrHostRegex = regexp.MustCompile(`^([a-z0-9-]+\.)?gcr\.io$|^[a-z0-9-]+-docker\.pkg\.dev$`)
+	acrHostRegex = regexp.MustCompile(`^([a-z0-9]+)\.azurecr\.(io|cn|us)$`)
+
+	cloudTokens   = map[string]*cloudToken{}
+	cloudTokensMu sync.Mutex
+)
+
+// cloudToken is a short-lived token of a cloud registry
+type cloudToken struct {
+	username  string
+	password  string
+	expiresAt time.Time
+}
+
+// cloudProvider gets short-lived tokens of a cloud registry with the CLI of the cloud
+type cloudProvider struct {
+	tool     string
+	username string
+	// commands are the arguments of the commands that print a token, tried in order
+	commands [][]string
+	ttl      time.Duration
+}
+
+//GetRegistryCredentials returns the username and password of a registry host, or an empty username and an identity token.
+//The docker config file is used first, including its credential helpers. Registries of AWS, GCP and Azure without docker credentials
+//use the short-lived tokens of the CLI of the cloud, which are refreshed when they are about to expire.
+//Registries without credentials are accessed anonymously
+func GetRegistryCredentials(host string) (string, string) {
+	configHost := host
+	if host == "registry-1.docker.io" || host == dockerHubHost {
+		configHost = dockerHubAuthServer
+	}
+
+	ac, err := config.LoadDefaultConfigFile(ioutil.Discard).GetAuthConfig(configHost)
+	if err != nil {
+		log.Infof("failed to read the docker credentials of '%s': %s", host, err)
+	} else if ac.IdentityToken != "" {
+		return "", ac.IdentityToken
+	} else if ac.Username != "" || ac.Password != "" {
+		return ac.Username, ac.Password
+	}
+
+	provider := getCloudProvider(host)
+	if provider == nil {
+		return "", ""
+	}
+
+	username, password, err := getCloudToken(host, provider)
+	if err != nil {
+		log.Infof("accessing '%s' anonymously: %s", host, err)
+		return "", ""
+	}
+	return username, password
+}
+
+// getCloudProvider returns the provider of the short-lived tokens of a registry host, or nil if it isn't a cloud registry
+func getCloudProvider(host string) *cloudProvider {
+	if m := ecrHostRegex.FindStringSubmatch(host); m != nil {
+		return &cloudProvider{
+			tool:     "aws",
+			username: "AWS",
+			commands: [][]string{{"ecr", "get-login-password", "--region", m[2]}},
+			ttl:      12 * time.Hour,
+		}
+	}
+
+	if gcrHostRegex.MatchString(host) {
+		return &cloudProvider{
+			tool:     "gcloud",
+			username: "oauth2accesstoken",
+			commands: [][]string{
+				{"auth", "application-default", "print-access-token"},
+				{"auth", "print-access-token"},
+			},
+			ttl: time.Hour,
+		}
+	}
+
+	if m := acrHostRegex.FindStringSubmatch(host); m != nil {
+		return &cloudProvider{
+			tool:     "az",
+			username: "00000000-0000-0000-0000-000000000000",
+			commands: [][]string{{"acr", "login", "--name", m[1], "--expose-token", "--output", "tsv", "--query", "accessToken"}},
+			ttl:      3 * time.Hour,
+		}
+	}
+	return nil
+}
+
+// getCloudToken returns the cached token of a cloud registry, getting a new one if it is about to expire
+func getCloudToken(host string, provider *cloudProvider) (string, string, error) {
+	cloudTokensMu.Lock()
+	defer cloudTokensMu.Unlock()
+
+	if t, ok := cloudTokens[host]; ok && time.Now().Add(cloudTokenRefreshMargin).Before(t.expiresAt) {
+		return t.username, t.password, nil
+	}
+
+	tool, err := exec.LookPath(provider.tool)
+	if err != nil {
+		return "", "", fmt.Errorf("the credentials of '%s' require '%s'", host, provider.tool)
+	}
+
+	var lastErr error
+	for _, args := range provider.commands {
+		ctx, cancel := context.WithTimeout(context.Background(), cloudCommandTimeout)
+		output, err := exec.CommandContext(ctx, tool, args...).Output()
+		cancel()
+		if err != nil {
+			lastErr = err
+			log.Infof("failed to run '%s %s': %s", provider.tool, strings.Join(args, " "), err)
+			continue
+		}
+
+		token := strings.TrimSpace(string(output))
+		if token == "" {
+			lastErr = fmt.Errorf("'%s %s' returned an empty token", provider.tool, strings.Join(args, " "))
+			continue
+		}
+
+		log.Infof("got a short-lived token of '%s' from '%s'", host, provider.tool)
+		cloudTokens[host] = &cloudToken{
+			username:  provider.username,
+			password:  token,
+			expiresAt: time.Now().Add(provider.ttl),
+		}
+		return provider.username, token, nil
+	}
+	return "", "", fmt.Errorf("failed to get the credentials of '%s' from '%s': %s", host, provider.tool, lastErr)
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_getCloudProvider(t *testing.T) {
+	var tests = []struct {
+		name     string
+		host     string
+		tool     string
+		commands [][]string
+	}{
+		{name: "ecr", host: "123456789012.dkr.ecr.eu-west-1.amazonaws.com", tool: "aws", commands: [][]string{{"ecr", "get-login-password", "--region", "eu-west-1"}}},
+		{name: "ecr-fips", host: "123456789012.dkr.ecr-fips.us-east-1.amazonaws.com", tool: "aws", commands: [][]string{{"ecr", "get-login-password", "--region", "us-east-1"}}},
+		{name: "gcr", host: "eu.gcr.io", tool: "gcloud", commands: [][]string{{"auth", "application-default", "print-access-token"}, {"auth", "print-access-token"}}},
+		{name: "artifact-registry", host: "europe-west1-docker.pkg.dev", tool: "gcloud", commands: [][]string{{"auth", "application-default", "print-access-token"}, {"auth", "print-access-token"}}},
+		{name: "acr", host: "okteto.azurecr.io", tool: "az", commands: [][]string{{"acr", "login", "--name", "okteto", "--expose-token", "--output", "tsv", "--query", "accessToken"}}},
+		{name: "docker-hub", host: "docker.io"},
+		{name: "fake-ecr", host: "dkr.ecr.eu-west-1.amazonaws.com.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := getCloudProvider(tt.host)
+			if tt.tool == "" {
+				if provider != nil {
+					t.Fatalf("expected no cloud provider, got %+v", provider)
+				}
+				return
+			}
+			if provider == nil {
+				t.Fatal("expected a cloud provider")
+			}
+			if provider.tool != tt.tool || !reflect.DeepEqual(provider.commands, tt.commands) {
+				t.Errorf("expected %s %v, got %s %v", tt.tool, tt.commands, provider.tool, provider.commands)
+			}
+		})
+	}
+}
+
+func Test_getCloudToken(t *testing.T) {
+	host := "okteto.azurecr.io"
+	provider := &cloudProvider{tool: "okteto-missing-cloud-cli", username: "user", commands: [][]string{{"token"}}, ttl: time.Hour}
+	defer delete(cloudTokens, host)
+
+	cloudTokens[host] = &cloudToken{username: "user", password: "cached", expiresAt: time.Now().Add(time.Hour)}
+	username, password, err := getCloudToken(host, provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if username != "user" || password != "cached" {
+		t.Errorf("expected the cached token, got %s %s", username, password)
+	}
+
+	cloudTokens[host] = &cloudToken{username: "user", password: "cached", expiresAt: time.Now().Add(time.Minute)}
+	if _, _, err := getCloudToken(host, provider); err == nil {
+		t.Error("expected the token about to expire to be refreshed")
+	}
+}
@@ -14,7 +14,6 @@
 package registry
 
 import (
-	"bufio"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -22,13 +21,22 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
 	"github.com/okteto/okteto/pkg/config"
+	"github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/okteto"
 	"github.com/pkg/errors"
 )
 
-//GetDockerfile returns the dockerfile with the cache translations
-func GetDockerfile(path, dockerFile string, isOktetoCluster bool) (string, error) {
+const (
+	mountFlagPrefix = "--mount="
+)
+
+// heredocRegex matches the heredocs of an instruction, like '<<EOF', '<<-EOF' or '<<"EOF"'
+var heredocRegex = regexp.MustCompile(`<<(-?)\s*["']?([A-Za-z_][A-Za-z0-9_]*)["']?`)
+
+//GetDockerfile returns the dockerfile with the cache translations, cacheScope is appended to the namespace of the cache mount ids
+func GetDockerfile(path, dockerFile, cacheScope string, isOktetoCluster bool) (string, error) {
 	if dockerFile == "" {
 		dockerFile = filepath.Join(path, "Dockerfile")
 	}
@@ -37,7 +45,7 @@ func GetDockerfile(path, dockerFile string, isOktetoCluster bool) (string, error
 		return dockerFile, nil
 	}
 
-	fileWithCacheHandler, err := getDockerfileWithCacheHandler(dockerFile)
+	fileWithCacheHandler, err := getDockerfileWithCacheHandler(dockerFile, cacheScope)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to create temporary build folder")
 	}
@@ -45,14 +53,11 @@ func GetDockerfile(path, dockerFile string, isOktetoCluster bool) (string, error
 	return fileWithCacheHandler, nil
 }
 
-func getDockerfileWithCacheHandler(filename string) (string, error) {
-	file, err := os.Open(filename)
+func getDockerfileWithCacheHandler(filename, cacheScope string) (string, error) {
+	data, err := ioutil.ReadFile(filename)
 	if err != nil {
 		return "", err
 	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
 
 	dockerfileTmpFolder := filepath.Join(config.GetOktetoHome(), ".dockerfile")
 	if err := os.MkdirAll(dockerfileTmpFolder, 0700); err != nil {
@@ -63,49 +68,118 @@ func getDockerfileWithCacheHandler(filename string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-
-	datawriter := bufio.NewWriter(tmpFile)
-	defer datawriter.Flush()
+	defer tmpFile.Close()
 
 	userID := okteto.GetUserID()
 	if userID == "" {
 		userID = "anonymous"
 	}
-	for scanner.Scan() {
-		line := scanner.Text()
-		translatedLine := translateCacheHandler(line, userID)
-		_, _ = datawriter.WriteString(translatedLine + "\n")
+
+	cacheNamespace := getBuildCacheNamespace(userID)
+	if cacheScope != "" {
+		cacheNamespace = fmt.Sprintf("%s-%s", cacheNamespace, cacheScope)
 	}
-	if err := scanner.Err(); err != nil {
+
+	if _, err := tmpFile.WriteString(translateCacheHandler(string(data), cacheNamespace)); err != nil {
 		return "", err
 	}
 
 	return tmpFile.Name(), nil
 }
 
-func translateCacheHandler(input, userID string) string {
-	matched, err := regexp.MatchString(`^RUN.*--mount=.*type=cache`, input)
+// translateCacheHandler namespaces the ids of the cache mounts of the RUN instructions of a Dockerfile with the user id.
+// Instructions continued with backslashes are rewritten as a whole, and the content of heredocs is left untouched.
+func translateCacheHandler(dockerfile, userID string) string {
+	result, err := parser.Parse(strings.NewReader(dockerfile))
 	if err != nil {
-		return input
+		log.Infof("failed to parse the Dockerfile, cache mounts won't be translated: %s", err)
+		return dockerfile
 	}
 
-	if matched {
-		matched, err = regexp.MatchString(`^RUN.*--mount=id=`, input)
-		if err != nil {
-			return input
+	lines := strings.Split(dockerfile, "\n")
+	heredocEnd := 0
+	for _, node := range result.AST.Children {
+		if node.StartLine <= heredocEnd {
+			continue
 		}
-		if matched {
-			return strings.ReplaceAll(input, "--mount=id=", fmt.Sprintf("--mount=id=%s-", userID))
+
+		if !strings.EqualFold(node.Value, "run") {
+			continue
 		}
-		matched, err = regexp.MatchString(`^RUN.*--mount=[^ ]+,id=`, input)
-		if err != nil {
-			return input
+
+		translateRunCacheMounts(lines[node.StartLine-1:node.EndLine], node.Flags, userID)
+		heredocEnd = getHeredocEnd(lines, node)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// translateRunCacheMounts replaces the cache mount flags of a RUN instruction in its lines
+func translateRunCacheMounts(lines, flags []string, userID string) {
+	line := 0
+	for _, flag := range flags {
+		translated := translateCacheMount(flag, userID)
+		if translated == flag {
+			continue
 		}
-		if matched {
-			return strings.ReplaceAll(input, ",id=", fmt.Sprintf(",id=%s-", userID))
+
+		for ; line < len(lines); line++ {
+			if i := strings.Index(lines[line], flag); i >= 0 {
+				lines[line] = lines[line][:i] + translated + lines[line][i+len(flag):]
+				break
+			}
 		}
-		return strings.ReplaceAll(input, "--mount=", fmt.Sprintf("--mount=id=%s,", userID))
+	}
+}
+
+// translateCacheMount returns a '--mount' flag with the id of a cache mount prefixed by the user id.
+// Cache mounts without id get the user id as id.
+func translateCacheMount(flag, userID string) string {
+	if !strings.HasPrefix(flag, mountFlagPrefix) {
+		return flag
 	}
 
-	return input
+	fields := strings.Split(strings.TrimPrefix(flag, mountFlagPrefix), ",")
+	isCache := false
+	idIndex := -1
+	for i, field := range fields {
+		switch {
+		case field == "type=cache":
+			isCache = true
+		case strings.HasPrefix(field, "id="):
+			idIndex = i
+		}
+	}
+
+	if !isCache {
+		return flag
+	}
+
+	if idIndex < 0 {
+		fields = append([]string{fmt.Sprintf("id=%s", userID)}, fields...)
+	} else {
+		fields[idIndex] = fmt.Sprintf("id=%s-%s", userID, strings.TrimPrefix(fields[idIndex], "id="))
+	}
+	return mountFlagPrefix + strings.Join(fields, ",")
+}
+
+// getHeredocEnd returns the last line of the heredocs of a RUN instruction, or 0 if it doesn't have heredocs.
+// The vendored Dockerfile parser doesn't support heredocs, so the lines of their content are parsed as instructions
+func getHeredocEnd(lines []string, node *parser.Node) int {
+	end := 0
+	line := node.EndLine
+	for _, m := range heredocRegex.FindAllStringSubmatch(node.Original, -1) {
+		for ; line < len(lines); line++ {
+			content := lines[line]
+			if m[1] == "-" {
+				content = strings.TrimLeft(content, "\t")
+			}
+			if strings.TrimRight(content, "\r") == m[2] {
+				line++
+				end = line
+				break
+			}
+		}
+	}
+	return end
 }
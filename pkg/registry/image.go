@@ -43,16 +43,30 @@ func GetRepoNameAndTag(name string) (string, string) {
 	return fmt.Sprintf("%s/%s", domain, remainder[:i]), remainder[i+1:]
 }
 
-//GetImageTag returns the image tag to build for a given services
-func GetImageTag(image, service, namespace, oktetoRegistryURL string) string {
+//GetImageTag returns the image tag to build for a given services, the tag is rendered from the tag template of the build
+func GetImageTag(image, service, namespace, oktetoRegistryURL string, b *model.BuildInfo) string {
+	tag := getDevTag(b, namespace, service)
 	if oktetoRegistryURL != "" {
 		if image == "" || image == model.DefaultImage {
-			return fmt.Sprintf("%s/%s/%s:okteto", oktetoRegistryURL, namespace, service)
+			return fmt.Sprintf("%s/%s/%s:%s", oktetoRegistryURL, namespace, service, tag)
+		}
+		if strings.Contains(image, "@") {
+			imageWithoutDigest, _ := GetRepoNameAndTag(image)
+			return fmt.Sprintf("%s:%s", imageWithoutDigest, tag)
 		}
 		return image
 	}
 	imageWithoutTag, _ := GetRepoNameAndTag(image)
-	return fmt.Sprintf("%s:okteto", imageWithoutTag)
+	return fmt.Sprintf("%s:%s", imageWithoutTag, tag)
+}
+
+//GetImageWithDigest returns the reference of a pushed image pinned by its digest, or the tag if the digest is unknown
+func GetImageWithDigest(tag, digest string) string {
+	if digest == "" {
+		return tag
+	}
+	repo, _ := GetRepoNameAndTag(tag)
+	return fmt.Sprintf("%s@%s", repo, digest)
 }
 
 //GetDevImageTag returns the image tag to build and push
@@ -60,5 +74,13 @@ func GetDevImageTag(dev *model.Dev, imageTag, imageFromDeployment, oktetoRegistr
 	if imageTag != "" && imageTag != model.DefaultImage {
 		return imageTag
 	}
-	return GetImageTag(imageFromDeployment, dev.Name, dev.Namespace, oktetoRegistryURL)
+	return GetImageTag(imageFromDeployment, dev.Name, dev.Namespace, oktetoRegistryURL, dev.Push)
+}
+
+//IsOktetoRegistryImage returns if an image is stored in the okteto registry
+func IsOktetoRegistryImage(image, oktetoRegistryURL string) bool {
+	if oktetoRegistryURL == "" {
+		return false
+	}
+	return strings.HasPrefix(image, fmt.Sprintf("%s/", oktetoRegistryURL))
 }
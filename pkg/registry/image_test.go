@@ -152,6 +152,14 @@ func Test_GetDevImageTag(t *testing.T) {
 			oktetoRegistryURL:   "",
 			expected:            "okteto/test:okteto",
 		},
+		{
+			name:                "pinned-image-from-deployment",
+			dev:                 &model.Dev{Name: "dev", Namespace: "ns"},
+			imageTag:            "",
+			imageFromDeployment: "registry.cloud.okteto.net/ns/dev@sha256:4a1c4b21597c1b4415bdbecb28a3296c6b5e23ca4f9feeb599860a1dac6a0108",
+			oktetoRegistryURL:   okteto.CloudRegistryURL,
+			expected:            "registry.cloud.okteto.net/ns/dev:okteto",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -163,6 +171,47 @@ func Test_GetDevImageTag(t *testing.T) {
 	}
 }
 
+func Test_IsOktetoRegistryImage(t *testing.T) {
+	var tests = []struct {
+		name              string
+		image             string
+		oktetoRegistryURL string
+		expected          bool
+	}{
+		{
+			name:              "okteto-registry",
+			image:             "registry.cloud.okteto.net/ns/api:dev",
+			oktetoRegistryURL: okteto.CloudRegistryURL,
+			expected:          true,
+		},
+		{
+			name:              "other-registry",
+			image:             "gcr.io/ns/api:dev",
+			oktetoRegistryURL: okteto.CloudRegistryURL,
+			expected:          false,
+		},
+		{
+			name:              "same-prefix",
+			image:             "registry.cloud.okteto.net.example.com/api:dev",
+			oktetoRegistryURL: okteto.CloudRegistryURL,
+			expected:          false,
+		},
+		{
+			name:              "no-registry",
+			image:             "registry.cloud.okteto.net/ns/api:dev",
+			oktetoRegistryURL: "",
+			expected:          false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := IsOktetoRegistryImage(tt.image, tt.oktetoRegistryURL); result != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, result)
+			}
+		})
+	}
+}
+
 func Test_translateCacheHandler(t *testing.T) {
 	var tests = []struct {
 		name     string
@@ -194,6 +243,24 @@ func Test_translateCacheHandler(t *testing.T) {
 			userID:   "userid",
 			expected: "RUN --mount=id=userid,type=cache,target=/root/.cache/go-build go build",
 		},
+		{
+			name:     "multi-line",
+			input:    "FROM golang\nRUN --mount=type=secret,id=npmrc \\\n  --mount=type=cache,id=go,target=/root/.cache/go-build \\\n  go build\n",
+			userID:   "userid",
+			expected: "FROM golang\nRUN --mount=type=secret,id=npmrc \\\n  --mount=type=cache,id=userid-go,target=/root/.cache/go-build \\\n  go build\n",
+		},
+		{
+			name:     "heredoc",
+			input:    "FROM golang\nRUN --mount=type=cache,target=/go <<EOF\nRUN --mount=type=cache,target=/tmp\nEOF\nRUN --mount=type=cache,target=/root go build\n",
+			userID:   "userid",
+			expected: "FROM golang\nRUN --mount=id=userid,type=cache,target=/go <<EOF\nRUN --mount=type=cache,target=/tmp\nEOF\nRUN --mount=id=userid,type=cache,target=/root go build\n",
+		},
+		{
+			name:     "not-run",
+			input:    "FROM golang\nCOPY --mount=type=cache,target=/go . .\n",
+			userID:   "userid",
+			expected: "FROM golang\nCOPY --mount=type=cache,target=/go . .\n",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -204,3 +271,39 @@ func Test_translateCacheHandler(t *testing.T) {
 		})
 	}
 }
+
+func Test_GetImageWithDigest(t *testing.T) {
+	digest := "sha256:4a1c4b21597c1b4415bdbecb28a3296c6b5e23ca4f9feeb599860a1dac6a0108"
+	var tests = []struct {
+		name     string
+		tag      string
+		digest   string
+		expected string
+	}{
+		{
+			name:     "okteto-registry",
+			tag:      "registry.cloud.okteto.net/ns/api:okteto",
+			digest:   digest,
+			expected: "registry.cloud.okteto.net/ns/api@" + digest,
+		},
+		{
+			name:     "registry-with-port",
+			tag:      "localhost:5000/api:dev",
+			digest:   digest,
+			expected: "localhost:5000/api@" + digest,
+		},
+		{
+			name:     "no-digest",
+			tag:      "okteto/api:dev",
+			digest:   "",
+			expected: "okteto/api:dev",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := GetImageWithDigest(tt.tag, tt.digest); result != tt.expected {
+				t.Errorf("expected %s got %s", tt.expected, result)
+			}
+		})
+	}
+}
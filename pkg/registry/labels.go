@@ -0,0 +1,112 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/heroku/docker-registry-client/registry"
+	"github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/okteto"
+)
+
+const (
+	dockerHubHost        = "docker.io"
+	dockerHubRegistryURL = "https://registry-1.docker.io"
+)
+
+// imageConfig is the subset of the image config read from the registry
+type imageConfig struct {
+	Config struct {
+		Labels map[string]string `json:"Labels"`
+	} `json:"config"`
+}
+
+//GetImageLabel returns the digest of an image and the value of one of its labels, read from the image config stored in the registry.
+//It returns errors.ErrNotFound if the image doesn't exist
+func GetImageLabel(image, label string) (string, string, error) {
+	c, repoName, tag, err := getImageRegistryClient(image)
+	if err != nil {
+		return "", "", err
+	}
+
+	manifest, err := c.ManifestV2(repoName, tag)
+	if err != nil {
+		if strings.Contains(err.Error(), "status=404") {
+			return "", "", errors.ErrNotFound
+		}
+		return "", "", fmt.Errorf("error getting the manifest of '%s': %s", image, err.Error())
+	}
+
+	blob, err := c.DownloadBlob(repoName, manifest.Config.Digest)
+	if err != nil {
+		return "", "", fmt.Errorf("error getting the config of '%s': %s", image, err.Error())
+	}
+	defer blob.Close()
+
+	var config imageConfig
+	if err := json.NewDecoder(blob).Decode(&config); err != nil {
+		return "", "", fmt.Errorf("error reading the config of '%s': %s", image, err.Error())
+	}
+
+	digest, err := c.ManifestDigest(repoName, tag)
+	if err != nil {
+		return "", "", fmt.Errorf("error getting the digest of '%s': %s", image, err.Error())
+	}
+	return digest.String(), config.Config.Labels[label], nil
+}
+
+// getImageRegistryClient returns a client of the registry of an image, the repository name and the tag of the image.
+// Images in the okteto registry are accessed with the okteto credentials, other registries with the credentials returned by GetRegistryCredentials
+func getImageRegistryClient(image string) (*registry.Registry, string, string, error) {
+	repoURL, tag := GetRepoNameAndTag(image)
+	host, repoName := splitRegistryHost(repoURL)
+
+	var username, password string
+	registryURL := fmt.Sprintf("https://%s", host)
+	if oktetoRegistryURL, err := okteto.GetRegistry(); err == nil && host == oktetoRegistryURL {
+		token, err := okteto.GetToken()
+		if err != nil {
+			return nil, "", "", fmt.Errorf("error getting token: %s", err.Error())
+		}
+		username = okteto.GetUserID()
+		password = token.Token
+	} else {
+		if host == dockerHubHost || host == "index.docker.io" {
+			host = dockerHubHost
+			registryURL = dockerHubRegistryURL
+		}
+		username, password = GetRegistryCredentials(host)
+	}
+
+	c, err := NewRegistryClient(registryURL, username, password)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("error creating registry client: %s", err.Error())
+	}
+	return c, repoName, tag, nil
+}
+
+// splitRegistryHost splits a repository in its registry host and its name, following the docker conventions for Docker Hub images
+func splitRegistryHost(repoURL string) (string, string) {
+	i := strings.IndexRune(repoURL, '/')
+	if i == -1 || (!strings.ContainsAny(repoURL[:i], ".:") && repoURL[:i] != "localhost") {
+		if !strings.Contains(repoURL, "/") {
+			repoURL = fmt.Sprintf("library/%s", repoURL)
+		}
+		return dockerHubHost, repoURL
+	}
+	return repoURL[:i], repoURL[i+1:]
+}
@@ -0,0 +1,38 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import "testing"
+
+func Test_splitRegistryHost(t *testing.T) {
+	var tests = []struct {
+		name     string
+		repo     string
+		host     string
+		repoName string
+	}{
+		{name: "official-image", repo: "alpine", host: "docker.io", repoName: "library/alpine"},
+		{name: "docker-hub", repo: "okteto/hello", host: "docker.io", repoName: "okteto/hello"},
+		{name: "okteto-registry", repo: "registry.cloud.okteto.net/cindy/api", host: "registry.cloud.okteto.net", repoName: "cindy/api"},
+		{name: "localhost", repo: "localhost:5000/api", host: "localhost:5000", repoName: "api"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, repoName := splitRegistryHost(tt.repo)
+			if host != tt.host || repoName != tt.repoName {
+				t.Errorf("expected %s %s, got %s %s", tt.host, tt.repoName, host, repoName)
+			}
+		})
+	}
+}
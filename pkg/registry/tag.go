@@ -0,0 +1,97 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"os/user"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/okteto"
+)
+
+const (
+	defaultDevTag = "okteto"
+	maxTagLength  = 128
+)
+
+// invalidTagCharsRegex matches the characters not allowed in image tags
+var invalidTagCharsRegex = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// devTag is the data available to the templates of the dev image tags
+type devTag struct {
+	Namespace string
+	Service   string
+	context   string
+}
+
+// User returns the id of the okteto user, or the local username when not logged into Okteto
+func (t devTag) User() string {
+	if id := okteto.GetUserID(); id != "" {
+		return id
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
+
+// GitSHA returns the short commit hash of the git repository of the build context
+func (t devTag) GitSHA() string {
+	repo, err := git.PlainOpenWithOptions(t.context, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		log.Infof("failed to open the git repository of '%s': %s", t.context, err)
+		return ""
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		log.Infof("failed to get the git commit of '%s': %s", t.context, err)
+		return ""
+	}
+	return head.Hash().String()[:7]
+}
+
+// getDevTag returns the tag of the dev images of a service, rendered from the tag template of its build
+func getDevTag(b *model.BuildInfo, namespace, service string) string {
+	if b == nil || b.TagTemplate == "" {
+		return defaultDevTag
+	}
+
+	tmpl, err := template.New("tag").Parse(strings.TrimPrefix(b.TagTemplate, ":"))
+	if err != nil {
+		log.Infof("invalid tag template '%s': %s", b.TagTemplate, err)
+		return defaultDevTag
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, devTag{Namespace: namespace, Service: service, context: b.Context}); err != nil {
+		log.Infof("failed to render the tag template '%s': %s", b.TagTemplate, err)
+		return defaultDevTag
+	}
+
+	tag := invalidTagCharsRegex.ReplaceAllString(sb.String(), "-")
+	tag = strings.TrimLeft(tag, ".-")
+	if len(tag) > maxTagLength {
+		tag = tag[:maxTagLength]
+	}
+	if tag == "" {
+		return defaultDevTag
+	}
+	return tag
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+)
+
+func Test_getDevTag(t *testing.T) {
+	var tests = []struct {
+		name        string
+		tagTemplate string
+		expected    string
+	}{
+		{name: "default", tagTemplate: "", expected: "okteto"},
+		{name: "namespace", tagTemplate: "{{.Namespace}}-{{.Service}}", expected: "cindy-api"},
+		{name: "leading-colon", tagTemplate: ":okteto-{{.Namespace}}", expected: "okteto-cindy"},
+		{name: "invalid-chars", tagTemplate: "okteto/{{.Namespace}}@dev", expected: "okteto-cindy-dev"},
+		{name: "invalid-template", tagTemplate: "{{.Namespace", expected: "okteto"},
+		{name: "unknown-field", tagTemplate: "{{.Branch}}", expected: "okteto"},
+		{name: "empty", tagTemplate: "{{if false}}x{{end}}", expected: "okteto"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &model.BuildInfo{BuildInfoRaw: model.BuildInfoRaw{TagTemplate: tt.tagTemplate}}
+			if result := getDevTag(b, "cindy", "api"); result != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func Test_GetImageTagWithTemplate(t *testing.T) {
+	b := &model.BuildInfo{BuildInfoRaw: model.BuildInfoRaw{TagTemplate: "{{.Namespace}}"}}
+	if result := GetImageTag("", "api", "cindy", "registry.okteto.dev", b); result != "registry.okteto.dev/cindy/api:cindy" {
+		t.Errorf("wrong okteto registry tag: %s", result)
+	}
+	if result := GetImageTag("okteto/api:1.0", "api", "cindy", "", b); result != "okteto/api:cindy" {
+		t.Errorf("wrong tag: %s", result)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/okteto/okteto/pkg/config"
+	"github.com/okteto/okteto/pkg/log"
+)
+
+const (
+	defaultRegistryHost = "docker.io"
+)
+
+//GetRegistryHost returns the host of the registry of an image
+func GetRegistryHost(image string) string {
+	i := strings.IndexRune(image, '/')
+	if i == -1 || (!strings.ContainsAny(image[:i], ".:") && image[:i] != "localhost") {
+		return defaultRegistryHost
+	}
+	return image[:i]
+}
+
+//IsInsecureRegistry returns if the TLS certificate of the registry isn't verified.
+//OKTETO_INSECURE_REGISTRY_ENABLED=true applies to every registry
+func IsInsecureRegistry(host string) bool {
+	if enabled, err := strconv.ParseBool(os.Getenv("OKTETO_INSECURE_REGISTRY_ENABLED")); err == nil && enabled {
+		return true
+	}
+
+	for _, r := range config.GetInsecureRegistries() {
+		if r == host {
+			return true
+		}
+	}
+	return false
+}
+
+//IsInsecureImage returns if the TLS certificate of the registry of an image isn't verified
+func IsInsecureImage(image string) bool {
+	return IsInsecureRegistry(GetRegistryHost(image))
+}
+
+// getTLSConfig returns the TLS configuration to access a registry.
+// The CA certificates of the registry are loaded from 'certs.d/<host>/*.crt', following the docker convention
+func getTLSConfig(host string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: IsInsecureRegistry(host),
+	}
+	if tlsConfig.InsecureSkipVerify {
+		log.Infof("skipping the TLS verification of the registry %s", host)
+		return tlsConfig, nil
+	}
+
+	certs := []string{}
+	for _, dir := range getCertsDirs(host) {
+		files, err := filepath.Glob(filepath.Join(dir, "*.crt"))
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, files...)
+	}
+
+	if len(certs) == 0 {
+		return tlsConfig, nil
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	for _, cert := range certs {
+		data, err := ioutil.ReadFile(cert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CA certificate of the registry %s: %s", host, err)
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("invalid CA certificate for the registry %s: %s", host, cert)
+		}
+		log.Infof("using the CA certificate %s for the registry %s", cert, host)
+	}
+
+	tlsConfig.RootCAs = pool
+	return tlsConfig, nil
+}
+
+func getCertsDirs(host string) []string {
+	dirs := []string{filepath.Join(config.GetUserHomeDir(), ".docker", "certs.d", host)}
+	if runtime.GOOS != "windows" {
+		dirs = append(dirs, filepath.Join("/etc", "docker", "certs.d", host))
+	}
+	return dirs
+}
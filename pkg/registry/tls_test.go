@@ -0,0 +1,67 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"os"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/config"
+)
+
+func Test_GetRegistryHost(t *testing.T) {
+	var tests = []struct {
+		image    string
+		expected string
+	}{
+		{image: "ubuntu", expected: "docker.io"},
+		{image: "okteto/api:dev", expected: "docker.io"},
+		{image: "registry.example.com/okteto/api:dev", expected: "registry.example.com"},
+		{image: "registry.example.com:5000/api", expected: "registry.example.com:5000"},
+		{image: "localhost/api", expected: "localhost"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			if result := GetRegistryHost(tt.image); result != tt.expected {
+				t.Errorf("expected %s got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func Test_IsInsecureImage(t *testing.T) {
+	config.SetInsecureRegistries([]string{"https://registry.example.com:5000/"})
+	defer config.SetInsecureRegistries(nil)
+
+	if !IsInsecureImage("registry.example.com:5000/api:dev") {
+		t.Error("registry.example.com:5000 should be insecure")
+	}
+	if IsInsecureImage("registry.example.com/api:dev") {
+		t.Error("registry.example.com shouldn't be insecure")
+	}
+
+	os.Setenv("OKTETO_INSECURE_REGISTRY_ENABLED", "true")
+	defer os.Unsetenv("OKTETO_INSECURE_REGISTRY_ENABLED")
+	if !IsInsecureImage("registry.example.com/api:dev") {
+		t.Error("every registry should be insecure with OKTETO_INSECURE_REGISTRY_ENABLED")
+	}
+
+	tlsConfig, err := getTLSConfig("registry.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tlsConfig.InsecureSkipVerify {
+		t.Error("the TLS certificate of an insecure registry shouldn't be verified")
+	}
+}
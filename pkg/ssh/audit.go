@@ -0,0 +1,126 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/okteto/okteto/pkg/config"
+	"github.com/okteto/okteto/pkg/log"
+)
+
+const auditFile = "ssh.audit.log"
+
+// AuditEntry is a connection accepted by a forward, a reverse forward or the SOCKS5 proxy
+type AuditEntry struct {
+	Time       time.Time `json:"time"`
+	Kind       string    `json:"kind"`
+	Listener   string    `json:"listener"`
+	Target     string    `json:"target"`
+	Peer       string    `json:"peer"`
+	BytesIn    uint64    `json:"bytesIn"`
+	BytesOut   uint64    `json:"bytesOut"`
+	DurationMs int64     `json:"durationMs"`
+}
+
+// auditLog writes one JSON entry per connection. A nil auditLog discards the entries
+type auditLog struct {
+	lock sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// EnableAudit logs every connection accepted by the forwards to $OKTETO_HOME/<namespace>/<name>/ssh.audit.log.
+// It must be called before Start
+func (fm *ForwardManager) EnableAudit(namespace, name string) error {
+	path := filepath.Join(config.GetDeploymentHome(namespace, name), auditFile)
+	a, err := newAuditLog(path)
+	if err != nil {
+		return err
+	}
+
+	fm.audit = a
+	go func() {
+		<-fm.ctx.Done()
+		a.close()
+	}()
+
+	log.Infof("audit log enabled at %s", path)
+	return nil
+}
+
+func newAuditLog(path string) (*auditLog, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auditLog{file: f, enc: json.NewEncoder(f)}, nil
+}
+
+// connection returns a function that records the connection when called, with the traffic counted in bytesIn and bytesOut
+func (a *auditLog) connection(kind, listener, target string, peer net.Addr, bytesIn, bytesOut *uint64) func() {
+	if a == nil {
+		return func() {}
+	}
+
+	start := time.Now()
+	return func() {
+		e := AuditEntry{
+			Time:       start,
+			Kind:       kind,
+			Listener:   listener,
+			Target:     target,
+			BytesIn:    atomic.LoadUint64(bytesIn),
+			BytesOut:   atomic.LoadUint64(bytesOut),
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+
+		if peer != nil {
+			e.Peer = peer.String()
+		}
+
+		a.write(e)
+	}
+}
+
+func (a *auditLog) write(e AuditEntry) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if a.enc == nil {
+		return
+	}
+
+	if err := a.enc.Encode(e); err != nil {
+		log.Infof("failed to write audit log: %s", err)
+	}
+}
+
+func (a *auditLog) close() {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if err := a.file.Close(); err != nil {
+		log.Infof("failed to close audit log: %s", err)
+	}
+
+	a.enc = nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_auditLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	var disabled *auditLog
+	var in, out uint64
+	disabled.connection("forward", "localhost:8080", "0.0.0.0:8080", nil, &in, &out)()
+
+	path := filepath.Join(dir, auditFile)
+	a, err := newAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	peer := &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 52000}
+	done := a.connection("forward", "localhost:8080", "0.0.0.0:8080", peer, &in, &out)
+	in, out = 10, 20
+	done()
+
+	a.close()
+	a.connection("forward", "localhost:8080", "0.0.0.0:8080", peer, &in, &out)()
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 entry, got %d: %s", len(lines), b)
+	}
+
+	var e AuditEntry
+	if err := json.Unmarshal([]byte(lines[0]), &e); err != nil {
+		t.Fatal(err)
+	}
+
+	if e.Peer != "127.0.0.1:52000" || e.BytesIn != 10 || e.BytesOut != 20 || e.Kind != "forward" {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+}
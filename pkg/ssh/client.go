@@ -14,13 +14,19 @@
 package ssh
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"io/ioutil"
+	"path/filepath"
 
+	"github.com/okteto/okteto/pkg/config"
 	"golang.org/x/crypto/ssh"
 )
 
-var clientConfig *ssh.ClientConfig
+var (
+	signer     ssh.Signer
+	identities []ssh.Signer
+)
 
 func getPrivateKey() (ssh.Signer, error) {
 	_, private := getKeyPaths()
@@ -37,25 +43,78 @@ func getPrivateKey() (ssh.Signer, error) {
 	return key, nil
 }
 
-func getSSHClientConfig() (*ssh.ClientConfig, error) {
-	if clientConfig != nil {
-		return clientConfig, nil
+// getSigner returns the okteto private key, loading it only once
+func getSigner() (ssh.Signer, error) {
+	if signer == nil {
+		key, err := getPrivateKey()
+		if err != nil {
+			return nil, err
+		}
+		signer = key
+	}
+
+	return signer, nil
+}
+
+// LoadIdentities loads the private keys of existing key pairs, offered in addition to the okteto key.
+// It returns the path of the file with the public keys to authorize in the development container
+func LoadIdentities(paths []string) (string, error) {
+	if len(paths) == 0 {
+		identities = nil
+		return GetPublicKey(), nil
 	}
 
-	keys, err := getPrivateKey()
+	authorized, err := ioutil.ReadFile(GetPublicKey())
+	if err != nil {
+		return "", fmt.Errorf("failed to load public key: %s", err)
+	}
+
+	loaded := make([]ssh.Signer, 0, len(paths))
+	for _, p := range paths {
+		buf, err := ioutil.ReadFile(p)
+		if err != nil {
+			return "", fmt.Errorf("failed to load private key %s: %s", p, err)
+		}
+
+		key, err := ssh.ParsePrivateKey(buf)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse private key %s: %s", p, err)
+		}
+
+		loaded = append(loaded, key)
+		authorized = append(authorized, ssh.MarshalAuthorizedKey(key.PublicKey())...)
+	}
+
+	identities = loaded
+
+	// the file name depends on its content, so manifests with different identities don't overwrite each other
+	sum := sha256.Sum256(authorized)
+	path := filepath.Join(config.GetOktetoHome(), fmt.Sprintf("authorized_keys_%x", sum[:6]))
+	if err := ioutil.WriteFile(path, authorized, 0600); err != nil {
+		return "", fmt.Errorf("failed to write authorized keys: %s", err)
+	}
+
+	return path, nil
+}
+
+func getSSHClientConfig(name string) (*ssh.ClientConfig, error) {
+	key, err := getSigner()
 	if err != nil {
 		return nil, err
 	}
 
-	clientConfig = &ssh.ClientConfig{
+	callback := hostKeyCallback(name)
+	if name == "" {
 		// skipcq GSC-G106
-		// Ignoring this issue since the remote server doesn't have a set identity, and it's already secured by the
+		// without a name there is no entry to pin the host key to, the connection is still secured by the
 		// port-forward tunnel to the kubernetes cluster.
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-		Auth: []ssh.AuthMethod{
-			ssh.PublicKeys(keys),
-		},
+		callback = ssh.InsecureIgnoreHostKey()
 	}
 
-	return clientConfig, nil
+	return &ssh.ClientConfig{
+		HostKeyCallback: callback,
+		Auth: []ssh.AuthMethod{
+			ssh.PublicKeys(append([]ssh.Signer{key}, identities...)...),
+		},
+	}, nil
 }
@@ -0,0 +1,177 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/okteto/okteto/pkg/config"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	controlSocketFile = "forward.sock"
+	forwardsPath      = "/forwards"
+)
+
+type controlRequest struct {
+	Forward string `json:"forward"`
+}
+
+func getControlSocket(namespace, name string) string {
+	return filepath.Join(config.GetDeploymentHome(namespace, name), controlSocketFile)
+}
+
+// ServeControl listens on a unix socket for requests to add or remove forwards while the forward manager is running
+func (fm *ForwardManager) ServeControl(ctx context.Context, namespace, name string) error {
+	socket := getControlSocket(namespace, name)
+	if err := os.Remove(socket); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stale control socket: %w", err)
+	}
+
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		return fmt.Errorf("failed to listen on control socket: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(forwardsPath, fm.handleAddForward)
+	mux.HandleFunc(forwardsPath+"/", fm.handleRemoveForward)
+	server := &http.Server{Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		if err := server.Close(); err != nil {
+			log.Infof("failed to close control server: %s", err)
+		}
+	}()
+
+	log.Infof("control server listening on %s", socket)
+	if err := server.Serve(l); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
+
+func (fm *ForwardManager) handleAddForward(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	req := controlRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	f := model.Forward{}
+	if err := yaml.Unmarshal([]byte(req.Forward), &f); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := fm.AddAndStart(f); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	log.Infof("added forward %s", f.String())
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (fm *ForwardManager) handleRemoveForward(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	port, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, forwardsPath+"/"))
+	if err != nil {
+		http.Error(w, "invalid port", http.StatusBadRequest)
+		return
+	}
+
+	if err := fm.Remove(port); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	log.Infof("removed forward on port %d", port)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddForward asks the running 'okteto up' of the development container to start a new forward
+func AddForward(namespace, name string, f model.Forward) error {
+	raw, err := yaml.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(controlRequest{Forward: string(raw)})
+	if err != nil {
+		return err
+	}
+
+	return callControl(namespace, name, http.MethodPost, forwardsPath, body)
+}
+
+// RemoveForward asks the running 'okteto up' of the development container to stop the forward listening on localPort
+func RemoveForward(namespace, name string, localPort int) error {
+	return callControl(namespace, name, http.MethodDelete, fmt.Sprintf("%s/%d", forwardsPath, localPort), nil)
+}
+
+func callControl(namespace, name, method, path string, body []byte) error {
+	socket := getControlSocket(namespace, name)
+	c := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				d := net.Dialer{}
+				return d.DialContext(ctx, "unix", socket)
+			},
+		},
+	}
+
+	req, err := http.NewRequest(method, "http://okteto"+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		log.Infof("failed to call control server: %s", err)
+		return fmt.Errorf("failed to connect to 'okteto up', make sure it's running")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("%s", strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
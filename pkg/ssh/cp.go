@@ -0,0 +1,211 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/pkg/sftp"
+)
+
+// Upload copies a local file or directory to the development container using SFTP
+func Upload(ctx context.Context, name, iface string, remotePort int, src, dst string) error {
+	client, closeFn, err := newSFTPClient(ctx, name, iface, remotePort)
+	if err != nil {
+		return err
+	}
+
+	defer closeFn()
+	return upload(client, src, dst)
+}
+
+// Download copies a file or directory from the development container to the local machine using SFTP
+func Download(ctx context.Context, name, iface string, remotePort int, src, dst string) error {
+	client, closeFn, err := newSFTPClient(ctx, name, iface, remotePort)
+	if err != nil {
+		return err
+	}
+
+	defer closeFn()
+	return download(client, src, dst)
+}
+
+func newSFTPClient(ctx context.Context, name, iface string, remotePort int) (*sftp.Client, func(), error) {
+	connection, err := connect(ctx, name, iface, remotePort)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client, err := sftp.NewClient(connection)
+	if err != nil {
+		connection.Close()
+		return nil, nil, fmt.Errorf("failed to start SFTP session: %s", err)
+	}
+
+	closeFn := func() {
+		if err := client.Close(); err != nil {
+			log.Infof("failed to close SFTP session: %s", err)
+		}
+
+		if err := connection.Close(); err != nil {
+			log.Infof("failed to close ssh client for cp: %s", err)
+		}
+	}
+
+	return client, closeFn, nil
+}
+
+func upload(client *sftp.Client, src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	// copy into the destination folder if it already exists, like cp does
+	if remote, err := client.Stat(dst); err == nil && remote.IsDir() {
+		dst = path.Join(dst, filepath.Base(src))
+	}
+
+	if !info.IsDir() {
+		return uploadFile(client, src, dst, info.Mode())
+	}
+
+	return filepath.Walk(src, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+
+		target := path.Join(dst, filepath.ToSlash(rel))
+		if info.IsDir() {
+			if err := client.MkdirAll(target); err != nil {
+				return fmt.Errorf("failed to create remote directory %s: %s", target, err)
+			}
+
+			return client.Chmod(target, info.Mode().Perm())
+		}
+
+		if !info.Mode().IsRegular() {
+			log.Infof("skipping %s: not a regular file", p)
+			return nil
+		}
+
+		return uploadFile(client, p, target, info.Mode())
+	})
+}
+
+func uploadFile(client *sftp.Client, src, dst string, mode os.FileMode) error {
+	log.Infof("uploading %s to %s", src, dst)
+	from, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+
+	defer from.Close()
+
+	to, err := client.Create(dst)
+	if err != nil {
+		return fmt.Errorf("failed to create remote file %s: %s", dst, err)
+	}
+
+	defer to.Close()
+
+	if _, err := io.Copy(to, from); err != nil {
+		return fmt.Errorf("failed to upload %s: %s", src, err)
+	}
+
+	return client.Chmod(dst, mode.Perm())
+}
+
+func download(client *sftp.Client, src, dst string) error {
+	info, err := client.Stat(src)
+	if err != nil {
+		return fmt.Errorf("failed to read remote path %s: %s", src, err)
+	}
+
+	// copy into the destination folder if it already exists, like cp does
+	if local, err := os.Stat(dst); err == nil && local.IsDir() {
+		dst = filepath.Join(dst, path.Base(src))
+	}
+
+	if !info.IsDir() {
+		return downloadFile(client, src, dst, info.Mode())
+	}
+
+	walker := client.Walk(src)
+	for walker.Step() {
+		if err := walker.Err(); err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, walker.Path())
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dst, rel)
+		info := walker.Stat()
+		if info.IsDir() {
+			if err := os.MkdirAll(target, info.Mode().Perm()); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !info.Mode().IsRegular() {
+			log.Infof("skipping %s: not a regular file", walker.Path())
+			continue
+		}
+
+		if err := downloadFile(client, walker.Path(), target, info.Mode()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func downloadFile(client *sftp.Client, src, dst string, mode os.FileMode) error {
+	log.Infof("downloading %s to %s", src, dst)
+	from, err := client.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open remote file %s: %s", src, err)
+	}
+
+	defer from.Close()
+
+	to, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return err
+	}
+
+	defer to.Close()
+
+	if _, err := io.Copy(to, from); err != nil {
+		return fmt.Errorf("failed to download %s: %s", src, err)
+	}
+
+	return nil
+}
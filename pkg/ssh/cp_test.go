@@ -0,0 +1,141 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gliderssh "github.com/gliderlabs/ssh"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+func startSFTPServer(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := &gliderssh.Server{
+		SubsystemHandlers: map[string]gliderssh.SubsystemHandler{
+			"sftp": func(s gliderssh.Session) {
+				server, err := sftp.NewServer(s)
+				if err != nil {
+					return
+				}
+				if err := server.Serve(); err != io.EOF {
+					t.Logf("sftp server failed: %s", err)
+				}
+			},
+		},
+	}
+
+	go server.Serve(l)
+	t.Cleanup(func() { server.Close() })
+	return l.Addr().String()
+}
+
+func newTestSFTPClient(t *testing.T, addr string) *sftp.Client {
+	conn, err := ssh.Dial("tcp", addr, &ssh.ClientConfig{HostKeyCallback: ssh.InsecureIgnoreHostKey()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		client.Close()
+		conn.Close()
+	})
+	return client
+}
+
+func Test_uploadAndDownload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "src")
+	if err := os.MkdirAll(filepath.Join(src, "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(src, "bin", "app"), []byte("binary"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(src, "README"), []byte("readme"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := newTestSFTPClient(t, startSFTPServer(t))
+
+	// the test server serves the local filesystem, so the remote side is another temporary folder
+	remote := filepath.Join(dir, "remote")
+	if err := upload(client, src, remote); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(filepath.Join(remote, "bin", "app"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("expected uploaded file mode to be 0755, got %s", info.Mode().Perm())
+	}
+
+	dst := filepath.Join(dir, "dst")
+	if err := os.Mkdir(dst, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := download(client, remote, dst); err != nil {
+		t.Fatal(err)
+	}
+
+	for file, expected := range map[string]string{
+		filepath.Join(dst, "remote", "bin", "app"): "binary",
+		filepath.Join(dst, "remote", "README"):     "readme",
+	} {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(content) != expected {
+			t.Errorf("expected %s to contain '%s', got '%s'", file, expected, content)
+		}
+	}
+
+	single := filepath.Join(dir, "core")
+	if err := download(client, filepath.Join(remote, "README"), single); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(single); err != nil {
+		t.Fatal(err)
+	}
+}
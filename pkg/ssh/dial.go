@@ -0,0 +1,141 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+const (
+	// dialAttemptTimeout is the maximum time spent on a single address
+	dialAttemptTimeout = 3 * time.Second
+
+	// dialFallbackDelay is the time to wait before racing the next address, as recommended by RFC 8305
+	dialFallbackDelay = 250 * time.Millisecond
+)
+
+type dialResult struct {
+	conn net.Conn
+	err  error
+}
+
+// dialHappyEyeballs resolves address and races connections to its IPv4 and IPv6 addresses.
+// Attempts are started dialFallbackDelay apart, or as soon as the previous one fails, and the first connection wins.
+// This avoids waiting for a full timeout on networks with broken IPv6 connectivity.
+func dialHappyEyeballs(ctx context.Context, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	d := net.Dialer{Timeout: dialAttemptTimeout}
+	if host == "" {
+		return d.DialContext(ctx, "tcp", address)
+	}
+
+	ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := interleaveFamilies(ips)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan dialResult, len(addrs))
+	timer := time.NewTimer(dialFallbackDelay)
+	defer timer.Stop()
+
+	var lastErr error
+	next, pending := 0, 0
+	for {
+		if next < len(addrs) {
+			target := net.JoinHostPort(addrs[next].String(), port)
+			go func() {
+				c, err := d.DialContext(ctx, "tcp", target)
+				results <- dialResult{conn: c, err: err}
+			}()
+
+			next++
+			pending++
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(dialFallbackDelay)
+		}
+
+		select {
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				go closePending(results, pending)
+				return r.conn, nil
+			}
+
+			lastErr = r.err
+			if pending == 0 && next == len(addrs) {
+				return nil, lastErr
+			}
+		case <-timer.C:
+		case <-ctx.Done():
+			go closePending(results, pending)
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// closePending closes the connections of the attempts that finished after the race was decided
+func closePending(results chan dialResult, pending int) {
+	for i := 0; i < pending; i++ {
+		r := <-results
+		if r.err == nil {
+			r.conn.Close()
+		}
+	}
+}
+
+// interleaveFamilies alternates the addresses of each family, starting with the family of the first address
+func interleaveFamilies(ips []net.IPAddr) []net.IPAddr {
+	if len(ips) == 0 {
+		return ips
+	}
+
+	primaryIsIPv4 := ips[0].IP.To4() != nil
+	var primaries, fallbacks []net.IPAddr
+	for _, ip := range ips {
+		if (ip.IP.To4() != nil) == primaryIsIPv4 {
+			primaries = append(primaries, ip)
+		} else {
+			fallbacks = append(fallbacks, ip)
+		}
+	}
+
+	result := make([]net.IPAddr, 0, len(ips))
+	for i := 0; i < len(primaries) || i < len(fallbacks); i++ {
+		if i < len(primaries) {
+			result = append(result, primaries[i])
+		}
+		if i < len(fallbacks) {
+			result = append(result, fallbacks[i])
+		}
+	}
+
+	return result
+}
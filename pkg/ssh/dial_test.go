@@ -0,0 +1,96 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func Test_interleaveFamilies(t *testing.T) {
+	v4a := net.IPAddr{IP: net.ParseIP("10.0.0.1")}
+	v4b := net.IPAddr{IP: net.ParseIP("10.0.0.2")}
+	v6a := net.IPAddr{IP: net.ParseIP("fd00::1")}
+	v6b := net.IPAddr{IP: net.ParseIP("fd00::2")}
+
+	tests := []struct {
+		name     string
+		ips      []net.IPAddr
+		expected []net.IPAddr
+	}{
+		{
+			name:     "empty",
+			ips:      []net.IPAddr{},
+			expected: []net.IPAddr{},
+		},
+		{
+			name:     "single-family",
+			ips:      []net.IPAddr{v4a, v4b},
+			expected: []net.IPAddr{v4a, v4b},
+		},
+		{
+			name:     "ipv6-first",
+			ips:      []net.IPAddr{v6a, v6b, v4a, v4b},
+			expected: []net.IPAddr{v6a, v4a, v6b, v4b},
+		},
+		{
+			name:     "ipv4-first",
+			ips:      []net.IPAddr{v4a, v6a, v6b},
+			expected: []net.IPAddr{v4a, v6a, v6b},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := interleaveFamilies(tt.ips)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("got %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_dialHappyEyeballs(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	port := l.Addr().(*net.TCPAddr).Port
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			c.Close()
+		}
+	}()
+
+	for _, address := range []string{fmt.Sprintf("localhost:%d", port), fmt.Sprintf("127.0.0.1:%d", port), fmt.Sprintf(":%d", port)} {
+		c, err := dialHappyEyeballs(context.Background(), address)
+		if err != nil {
+			t.Fatalf("failed to dial %s: %s", address, err)
+		}
+		c.Close()
+	}
+
+	if _, err := dialHappyEyeballs(context.Background(), "127.0.0.1:1"); err == nil {
+		t.Fatal("dialed a closed port")
+	}
+}
@@ -31,26 +31,10 @@ import (
 )
 
 // Exec executes the command over SSH
-func Exec(ctx context.Context, iface string, remotePort int, tty bool, inR io.Reader, outW, errW io.Writer, command []string) error {
-	log.Info("starting SSH connection")
-	sshConfig, err := getSSHClientConfig()
+func Exec(ctx context.Context, name, iface string, remotePort int, tty bool, inR io.Reader, outW, errW io.Writer, command []string) error {
+	connection, err := connect(ctx, name, iface, remotePort)
 	if err != nil {
-		return fmt.Errorf("failed to get SSH configuration: %s", err)
-	}
-
-	var connection *ssh.Client
-	t := time.NewTicker(100 * time.Millisecond)
-	for i := 0; i < 100; i++ {
-		connection, err = dial(ctx, "tcp", fmt.Sprintf("%s:%d", iface, remotePort), sshConfig)
-		if err == nil {
-			break
-		}
-
-		<-t.C
-	}
-
-	if err != nil {
-		return fmt.Errorf("failed to connect to SSH server: %s", err)
+		return err
 	}
 
 	defer connection.Close()
@@ -74,44 +58,38 @@ func Exec(ctx context.Context, iface string, remotePort int, tty bool, inR io.Re
 	defer session.Close()
 
 	if tty {
-		modes := ssh.TerminalModes{
-			ssh.ECHO:          0,      // Disable echoing
-			ssh.ECHOCTL:       0,      // Don't print control chars
-			ssh.IGNCR:         1,      // Ignore CR on input
-			ssh.TTY_OP_ISPEED: 115200, // baud in
-			ssh.TTY_OP_OSPEED: 115200, // baud out
-		}
+		termFD, interactive := isTerminal(inR)
+		width, height := defaultTerminalWidth, defaultTerminalHeight
+		if interactive {
+			width, height = terminalSize(termFD)
 
-		height, width := 80, 40
-		var termFD int
-		var ok bool
-		if termFD, ok = isTerminal(inR); ok {
-			width, height, err = terminal.GetSize(termFD)
+			state, err := terminal.MakeRaw(termFD)
 			if err != nil {
-				log.Infof("request for terminal size failed: %s", err)
+				log.Infof("request for raw terminal failed: %s", err)
 			}
-		}
 
-		state, err := terminal.MakeRaw(termFD)
-		if err != nil {
-			log.Infof("request for raw terminal failed: %s", err)
-		}
-
-		defer func() {
-			if state == nil {
-				return
-			}
+			defer func() {
+				if state == nil {
+					return
+				}
 
-			if err := terminal.Restore(termFD, state); err != nil {
-				log.Infof("failed to restore terminal: %s", err)
-			}
+				if err := terminal.Restore(termFD, state); err != nil {
+					log.Infof("failed to restore terminal: %s", err)
+				}
 
-			log.Infof("terminal restored")
-		}()
+				log.Infof("terminal restored")
+			}()
+		}
 
-		if err := session.RequestPty("xterm-256color", height, width, modes); err != nil {
+		if err := session.RequestPty(terminalType(), height, width, terminalModes(interactive)); err != nil {
 			return fmt.Errorf("request for pseudo terminal failed: %s", err)
 		}
+
+		if interactive {
+			resizeCtx, stopResize := context.WithCancel(ctx)
+			defer stopResize()
+			go watchTerminalSize(resizeCtx, session, termFD, width, height)
+		}
 	}
 
 	sockEnvVar, ok := os.LookupEnv("SSH_AUTH_SOCK")
@@ -165,6 +143,29 @@ func Exec(ctx context.Context, iface string, remotePort int, tty bool, inR io.Re
 	return session.Run(cmd)
 }
 
+// connect opens a new SSH connection to the development container, waiting for the SSH server to be ready
+func connect(ctx context.Context, name, iface string, remotePort int) (*ssh.Client, error) {
+	log.Info("starting SSH connection")
+	sshConfig, err := getSSHClientConfig(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SSH configuration: %s", err)
+	}
+
+	var connection *ssh.Client
+	t := time.NewTicker(100 * time.Millisecond)
+	defer t.Stop()
+	for i := 0; i < 100; i++ {
+		connection, err = dial(ctx, "tcp", fmt.Sprintf("%s:%d", iface, remotePort), sshConfig)
+		if err == nil {
+			return connection, nil
+		}
+
+		<-t.C
+	}
+
+	return nil, fmt.Errorf("failed to connect to SSH server: %s", err)
+}
+
 func isTerminal(r io.Reader) (int, bool) {
 	switch v := r.(type) {
 	case *os.File:
@@ -22,14 +22,36 @@ import (
 
 	"github.com/okteto/okteto/pkg/errors"
 	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"golang.org/x/time/rate"
 )
 
 type forward struct {
+	// traffic counters are accessed atomically and must stay 64-bit aligned
+	bytesIn  uint64
+	bytesOut uint64
+
 	localAddress  string
 	remoteAddress string
 	c             bool
 	lock          sync.Mutex
 	pool          *pool
+
+	// inLimiter and outLimiter throttle the traffic of all the connections of the forward, nil if unlimited
+	inLimiter  *rate.Limiter
+	outLimiter *rate.Limiter
+
+	// tcp are the socket options of the local connections
+	tcp model.TCPOptions
+
+	// audit records the accepted connections, nil if disabled
+	audit *auditLog
+
+	// cancel stops the forward
+	cancel context.CancelFunc
+
+	// listener accepts the local connections, nil if the forward isn't listening
+	listener net.Listener
 }
 
 func (f *forward) connected() bool {
@@ -50,23 +72,43 @@ func (f *forward) setDisconnected() {
 	f.c = false
 }
 
-func (f *forward) start(ctx context.Context) {
-	localListener, err := net.Listen("tcp", f.localAddress)
+// listen binds the local address of the forward
+func (f *forward) listen() error {
+	l, err := net.Listen("tcp", f.localAddress)
 	if err != nil {
-		log.Infof("%s -> failed to listen: %s", f.String(), err)
+		return err
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.listener = l
+	f.c = true
+	return nil
+}
+
+// close stops accepting connections and releases the local address
+func (f *forward) close() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.c = false
+	if f.listener == nil {
 		return
 	}
 
+	if err := f.listener.Close(); err != nil {
+		log.Infof("%s -> failed to close: %s", f.String(), err)
+	}
+	f.listener = nil
+	log.Infof("%s -> done", f.String())
+}
+
+// start accepts connections on the listener of the forward until ctx is done
+func (f *forward) start(ctx context.Context, localListener net.Listener) {
 	go func() {
 		<-ctx.Done()
-		f.setDisconnected()
-		if err := localListener.Close(); err != nil {
-			log.Infof("%s -> failed to close: %s", f.String(), err)
-		}
-		log.Infof("%s -> done", f.String())
+		f.close()
 	}()
 
-	f.setConnected()
 	log.Infof("%s -> started", f.String())
 
 	for {
@@ -87,6 +129,10 @@ func (f *forward) start(ctx context.Context) {
 func (f *forward) handle(local net.Conn) {
 	defer local.Close()
 
+	if err := setTCPOptions(local, f.tcp); err != nil {
+		log.Infof("%s -> failed to set tcp options: %s", f.String(), err)
+	}
+
 	remote, err := f.pool.get(f.remoteAddress)
 	if err != nil {
 		log.Infof("%s -> %s", f.String(), err)
@@ -95,10 +141,13 @@ func (f *forward) handle(local net.Conn) {
 
 	defer remote.Close()
 
+	var in, out uint64
+	defer f.audit.connection("forward", f.localAddress, f.remoteAddress, local.RemoteAddr(), &in, &out)()
+
 	quit := make(chan struct{}, 1)
 
-	go f.transfer(remote, local, quit)
-	go f.transfer(local, remote, quit)
+	go f.transfer(&countingWriter{w: throttle(remote, f.outLimiter), n: &out}, local, &f.bytesOut, quit)
+	go f.transfer(&countingWriter{w: throttle(local, f.inLimiter), n: &in}, remote, &f.bytesIn, quit)
 
 	<-quit
 }
@@ -107,8 +156,8 @@ func (f *forward) String() string {
 	return fmt.Sprintf("ssh forward %s->%s", f.localAddress, f.remoteAddress)
 }
 
-func (f *forward) transfer(from io.Writer, to io.Reader, quit chan struct{}) {
-	_, err := io.Copy(from, to)
+func (f *forward) transfer(from io.Writer, to io.Reader, counter *uint64, quit chan struct{}) {
+	_, err := io.Copy(&countingWriter{w: from, n: counter}, to)
 	if err != nil {
 		if !errors.IsClosedNetwork(err) {
 			log.Infof("%s -> data transfer failed: %v", f.String(), err)
@@ -0,0 +1,156 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	okErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/log"
+)
+
+type dialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// ServeHTTPProxy serves HTTP on localAddress and forwards every request to target through the SSH server of the
+// development container. Requests are sent with the given Host header, and the headers and cookies that reference
+// that host are rewritten back to localAddress, so applications that validate the Host header work over localhost
+func ServeHTTPProxy(ctx context.Context, name, iface string, remotePort int, localAddress, target, host string) error {
+	connection, err := connect(ctx, name, iface, remotePort)
+	if err != nil {
+		return err
+	}
+
+	defer connection.Close()
+
+	dial := func(_ context.Context, network, address string) (net.Conn, error) {
+		return connection.Dial(network, address)
+	}
+
+	server := &http.Server{
+		Addr:    localAddress,
+		Handler: newHTTPProxy(dial, target, host),
+	}
+
+	go func() {
+		<-ctx.Done()
+		if err := server.Close(); err != nil {
+			log.Infof("failed to close http proxy: %s", err)
+		}
+	}()
+
+	log.Infof("http proxy %s -> %s (%s) started", localAddress, target, host)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
+
+func newHTTPProxy(dial dialFunc, target, host string) *httputil.ReverseProxy {
+	return &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			// the original host is needed to rewrite the redirects in the response
+			req.Header.Set("X-Forwarded-Host", req.Host)
+			req.URL.Scheme = "http"
+			req.URL.Host = target
+			req.Host = host
+
+			if origin := req.Header.Get("Origin"); origin != "" {
+				req.Header.Set("Origin", replaceURLHost(origin, host))
+			}
+
+			if referer := req.Header.Get("Referer"); referer != "" {
+				req.Header.Set("Referer", replaceURLHost(referer, host))
+			}
+		},
+		Transport: &http.Transport{
+			DialContext: dial,
+		},
+		ModifyResponse: func(resp *http.Response) error {
+			if location := resp.Header.Get("Location"); location != "" {
+				if u, err := url.Parse(location); err == nil && strings.EqualFold(u.Host, host) {
+					resp.Header.Set("Location", replaceURLHost(location, resp.Request.Header.Get("X-Forwarded-Host")))
+				}
+			}
+
+			rewriteCookies(resp.Header, host)
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			if !okErrors.IsClosedNetwork(err) {
+				log.Infof("http proxy to %s failed: %s", target, err)
+			}
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	}
+}
+
+// replaceURLHost replaces the host of raw, which is always served over plain HTTP through the proxy
+func replaceURLHost(raw, host string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+
+	u.Scheme = "http"
+	u.Host = host
+	return u.String()
+}
+
+// rewriteCookies drops the domain and the secure attribute of the cookies set for host, so the browser keeps them for localhost
+func rewriteCookies(header http.Header, host string) {
+	values := header.Values("Set-Cookie")
+	if len(values) == 0 {
+		return
+	}
+
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+
+	header.Del("Set-Cookie")
+	for _, v := range values {
+		cookies := (&http.Response{Header: http.Header{"Set-Cookie": {v}}}).Cookies()
+		if len(cookies) != 1 {
+			header.Add("Set-Cookie", v)
+			continue
+		}
+
+		c := cookies[0]
+		if c.Domain != "" && !strings.HasSuffix(strings.ToLower(hostname), strings.ToLower(strings.TrimPrefix(c.Domain, "."))) {
+			header.Add("Set-Cookie", v)
+			continue
+		}
+
+		c.Domain = ""
+		c.Secure = false
+		header.Add("Set-Cookie", c.String())
+	}
+}
+
+// HTTPProxyTarget returns the address and the default Host header to reach a forward from the development container
+func HTTPProxyTarget(service string, port int) (string, string) {
+	if service == "" {
+		return fmt.Sprintf("localhost:%d", port), "localhost"
+	}
+
+	return fmt.Sprintf("%s:%d", service, port), service
+}
@@ -0,0 +1,87 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_newHTTPProxy(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Host != "app.example.com" {
+			w.WriteHeader(http.StatusMisdirectedRequest)
+			return
+		}
+
+		if origin := r.Header.Get("Origin"); origin != "" && origin != "http://app.example.com" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "1", Domain: "app.example.com", Secure: true})
+		http.SetCookie(w, &http.Cookie{Name: "other", Value: "2", Domain: "other.com"})
+		http.Redirect(w, r, "https://app.example.com/login", http.StatusFound)
+	}))
+	defer backend.Close()
+
+	dial := func(ctx context.Context, network, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, network, backend.Listener.Addr().String())
+	}
+
+	proxy := httptest.NewServer(newHTTPProxy(dial, "svc:8080", "app.example.com"))
+	defer proxy.Close()
+
+	req, err := http.NewRequest(http.MethodGet, proxy.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Origin", proxy.URL)
+
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("expected status %d, got %d", http.StatusFound, resp.StatusCode)
+	}
+
+	expectedLocation := proxy.URL + "/login"
+	if location := resp.Header.Get("Location"); location != expectedLocation {
+		t.Errorf("expected location '%s', got '%s'", expectedLocation, location)
+	}
+
+	for _, c := range resp.Cookies() {
+		switch c.Name {
+		case "session":
+			if c.Domain != "" || c.Secure {
+				t.Errorf("cookie for the proxied host wasn't rewritten: %s", c.String())
+			}
+		case "other":
+			if c.Domain != "other.com" {
+				t.Errorf("cookie for another domain was rewritten: %s", c.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"time"
+
+	"github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"golang.org/x/crypto/ssh"
+)
+
+// connectThroughJumpHost opens a connection to addr tunneled through the jump host.
+// The jump host client is shared by all the clients of the pool and re-established if it's lost
+func (p *pool) connectThroughJumpHost(ctx context.Context, addr string) (net.Conn, error) {
+	p.jumpLock.Lock()
+	defer p.jumpLock.Unlock()
+
+	if p.jump != nil {
+		c, err := p.jump.Dial("tcp", addr)
+		if err == nil {
+			return c, nil
+		}
+
+		log.Infof("failed to dial %s through jump host %s, reconnecting: %s", addr, p.jumpHost.Host, err)
+		p.closeJumpHost()
+	}
+
+	jump, err := dialJumpHost(ctx, p.jumpHost, p.ka, p.tcp)
+	if err != nil {
+		return nil, err
+	}
+
+	p.jump = jump
+	return jump.Dial("tcp", addr)
+}
+
+func (p *pool) closeJumpHost() {
+	if p.jump == nil {
+		return
+	}
+
+	if err := p.jump.Close(); err != nil {
+		if !errors.IsClosedNetwork(err) {
+			log.Infof("failed to close jump host client: %s", err)
+		}
+	}
+
+	p.jump = nil
+}
+
+func dialJumpHost(ctx context.Context, jh *model.JumpHost, keepAlive time.Duration, tcp model.TCPOptions) (*ssh.Client, error) {
+	signer, err := getJumpHostKey(jh)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &ssh.ClientConfig{
+		User:            jh.User,
+		HostKeyCallback: pinnedHostKeyCallback(jh.Host),
+		Auth: []ssh.AuthMethod{
+			ssh.PublicKeys(signer),
+		},
+	}
+
+	conn, err := getTCPConnection(ctx, jh.Host, keepAlive, tcp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to jump host %s: %w", jh.Host, err)
+	}
+
+	clientConn, chans, reqs, err := ssh.NewClientConn(conn, jh.Host, config)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect to jump host %s: %w", jh.Host, err)
+	}
+
+	log.Infof("connected to jump host %s", jh.Host)
+	return ssh.NewClient(clientConn, chans, reqs), nil
+}
+
+// getJumpHostKey returns the key used to authenticate with the jump host, the okteto key by default
+func getJumpHostKey(jh *model.JumpHost) (ssh.Signer, error) {
+	if jh.PrivateKey == "" {
+		return getSigner()
+	}
+
+	buf, err := ioutil.ReadFile(jh.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load jump host private key: %s", err)
+	}
+
+	key, err := ssh.ParsePrivateKey(buf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse jump host private key: %s", err)
+	}
+
+	return key, nil
+}
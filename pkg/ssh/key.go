@@ -14,13 +14,17 @@
 package ssh
 
 import (
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/binary"
 	"encoding/pem"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/okteto/okteto/pkg/config"
 	"github.com/okteto/okteto/pkg/log"
@@ -29,9 +33,16 @@ import (
 )
 
 const (
-	privateKeyFile = "id_rsa_okteto"
-	publicKeyFile  = "id_rsa_okteto.pub"
-	bitSize        = 4096
+	privateKeyFile        = "id_rsa_okteto"
+	publicKeyFile         = "id_rsa_okteto.pub"
+	ed25519PrivateKeyFile = "id_ed25519_okteto"
+	ed25519PublicKeyFile  = "id_ed25519_okteto.pub"
+	bitSize               = 4096
+
+	// keyTypeEnvVar selects the type of the key pair generated by okteto
+	keyTypeEnvVar  = "OKTETO_SSH_KEY_TYPE"
+	rsaKeyType     = "rsa"
+	ed25519KeyType = "ed25519"
 )
 
 // KeyExists returns true if the okteto key pair exists
@@ -56,6 +67,10 @@ func KeyExists() bool {
 // GenerateKeys generates a SSH key pair on path
 func GenerateKeys() error {
 	publicKeyPath, privateKeyPath := getKeyPaths()
+	if getKeyType() == ed25519KeyType {
+		return generateEd25519Keys(publicKeyPath, privateKeyPath)
+	}
+
 	return generateKeys(publicKeyPath, privateKeyPath, bitSize)
 }
 
@@ -125,8 +140,105 @@ func generatePublicKey(privatekey *rsa.PublicKey) ([]byte, error) {
 	return pubKeyBytes, nil
 }
 
+func generateEd25519Keys(public, private string) error {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return fmt.Errorf("failed to generate private SSH key: %s", err)
+	}
+
+	sshPublicKey, err := ssh.NewPublicKey(publicKey)
+	if err != nil {
+		return fmt.Errorf("failed to generate public SSH key: %s", err)
+	}
+
+	privateKeyBytes, err := encodeEd25519PrivateKeyToPEM(privateKey)
+	if err != nil {
+		return fmt.Errorf("failed to encode private SSH key: %s", err)
+	}
+
+	if err := ioutil.WriteFile(public, ssh.MarshalAuthorizedKey(sshPublicKey), 0600); err != nil {
+		return fmt.Errorf("failed to write public SSH key: %s", err)
+	}
+
+	if err := ioutil.WriteFile(private, privateKeyBytes, 0600); err != nil {
+		return fmt.Errorf("failed to write private SSH key: %s", err)
+	}
+
+	log.Infof("created ed25519 ssh keypair at  %s and %s", public, private)
+	return nil
+}
+
+// encodeEd25519PrivateKeyToPEM encodes the key in the OpenSSH format, the only one supported by ssh for ed25519 keys
+func encodeEd25519PrivateKeyToPEM(privateKey ed25519.PrivateKey) ([]byte, error) {
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+	sshPublicKey, err := ssh.NewPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	check := make([]byte, 4)
+	if _, err := rand.Read(check); err != nil {
+		return nil, err
+	}
+
+	private := struct {
+		Check1  uint32
+		Check2  uint32
+		Keytype string
+		Pub     []byte
+		Priv    []byte
+		Comment string
+		Rest    []byte `ssh:"rest"`
+	}{
+		Check1:  binary.BigEndian.Uint32(check),
+		Check2:  binary.BigEndian.Uint32(check),
+		Keytype: ssh.KeyAlgoED25519,
+		Pub:     publicKey,
+		Priv:    privateKey,
+	}
+
+	// the private section is padded to the cipher block size, 8 bytes for 'none'
+	for i := 1; (len(ssh.Marshal(private)))%8 != 0; i++ {
+		private.Rest = append(private.Rest, byte(i))
+	}
+
+	key := struct {
+		CipherName   string
+		KdfName      string
+		KdfOpts      string
+		NumKeys      uint32
+		PubKey       []byte
+		PrivKeyBlock []byte
+	}{
+		CipherName:   "none",
+		KdfName:      "none",
+		NumKeys:      1,
+		PubKey:       sshPublicKey.Marshal(),
+		PrivKeyBlock: ssh.Marshal(private),
+	}
+
+	block := pem.Block{
+		Type:  "OPENSSH PRIVATE KEY",
+		Bytes: append([]byte("openssh-key-v1\x00"), ssh.Marshal(key)...),
+	}
+
+	return pem.EncodeToMemory(&block), nil
+}
+
+func getKeyType() string {
+	if strings.EqualFold(os.Getenv(keyTypeEnvVar), ed25519KeyType) {
+		return ed25519KeyType
+	}
+
+	return rsaKeyType
+}
+
 func getKeyPaths() (string, string) {
 	dir := config.GetOktetoHome()
+	if getKeyType() == ed25519KeyType {
+		return filepath.Join(dir, ed25519PublicKeyFile), filepath.Join(dir, ed25519PrivateKeyFile)
+	}
+
 	public := filepath.Join(dir, publicKeyFile)
 	private := filepath.Join(dir, privateKeyFile)
 	return public, private
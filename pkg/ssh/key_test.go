@@ -17,7 +17,10 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"golang.org/x/crypto/ssh"
 )
 
 func TestKeyExists(t *testing.T) {
@@ -77,7 +80,64 @@ func TestGenerateKeys(t *testing.T) {
 		t.Error("keys don't exist after creation")
 	}
 
-	if _, err := getSSHClientConfig(); err != nil {
+	if _, err := getSSHClientConfig(""); err != nil {
 		t.Errorf("failed to get ssh client configuration: %s", err)
 	}
 }
+
+func TestGenerateEd25519Keys(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		os.RemoveAll(dir)
+		os.Unsetenv("OKTETO_FOLDER")
+		os.Unsetenv(keyTypeEnvVar)
+		identities = nil
+	}()
+
+	os.Setenv("OKTETO_FOLDER", dir)
+	os.Setenv(keyTypeEnvVar, ed25519KeyType)
+	if err := GenerateKeys(); err != nil {
+		t.Fatal(err)
+	}
+
+	public, private := getKeyPaths()
+	if filepath.Base(private) != ed25519PrivateKeyFile || filepath.Base(public) != ed25519PublicKeyFile {
+		t.Fatalf("unexpected key paths %s and %s", public, private)
+	}
+
+	key, err := getPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key.PublicKey().Type() != ssh.KeyAlgoED25519 {
+		t.Fatalf("expected an ed25519 key, got %s", key.PublicKey().Type())
+	}
+
+	identity := filepath.Join(dir, "id_rsa")
+	if err := generateKeys(identity+".pub", identity, 1024); err != nil {
+		t.Fatal(err)
+	}
+
+	authorizedKeys, err := LoadIdentities([]string{identity})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(identities) != 1 {
+		t.Fatalf("expected 1 identity, got %d", len(identities))
+	}
+
+	b, err := ioutil.ReadFile(authorizedKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := strings.Count(string(b), "\n"); n != 2 {
+		t.Fatalf("expected 2 authorized keys, got %d:\n%s", n, b)
+	}
+}
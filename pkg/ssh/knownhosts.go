@@ -0,0 +1,177 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/okteto/okteto/pkg/config"
+	okErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const knownHostsFile = "known_hosts"
+
+var (
+	knownHostsLock sync.Mutex
+
+	// devPods are the UIDs of the pods of the development containers, by name
+	devPods     = map[string]string{}
+	devPodsLock sync.Mutex
+)
+
+func getKnownHostsPath() string {
+	return filepath.Join(config.GetOktetoHome(), knownHostsFile)
+}
+
+// SetDevPod sets the pod of a development container. The development container generates a new host key every time
+// it starts, so its host key is pinned per pod, and the keys pinned for its previous pods are removed
+func SetDevPod(name, podUID string) {
+	devPodsLock.Lock()
+	defer devPodsLock.Unlock()
+	devPods[name] = podUID
+}
+
+func getDevPod(name string) string {
+	devPodsLock.Lock()
+	defer devPodsLock.Unlock()
+	return devPods[name]
+}
+
+// hostKeyCallback pins the host key of the pod of the development container the first time it's seen,
+// and verifies it on every subsequent connection to the same pod
+func hostKeyCallback(name string) ssh.HostKeyCallback {
+	return func(_ string, remote net.Addr, key ssh.PublicKey) error {
+		path := getKnownHostsPath()
+		host := buildHostname(name)
+		if uid := getDevPod(name); uid != "" {
+			podHost := fmt.Sprintf("%s.%s", uid, host)
+			if err := removeStaleHostKeys(path, host, podHost); err != nil {
+				log.Infof("failed to remove the host keys of previous pods of %s: %s", host, err)
+			}
+			host = podHost
+		}
+		return checkHostKey(path, net.JoinHostPort(host, "22"), remote, key)
+	}
+}
+
+// pinnedHostKeyCallback pins the host key presented for address in the managed known_hosts file
+func pinnedHostKeyCallback(address string) ssh.HostKeyCallback {
+	return func(_ string, remote net.Addr, key ssh.PublicKey) error {
+		return checkHostKey(getKnownHostsPath(), address, remote, key)
+	}
+}
+
+func checkHostKey(path, address string, remote net.Addr, key ssh.PublicKey) error {
+	knownHostsLock.Lock()
+	defer knownHostsLock.Unlock()
+
+	if !model.FileExists(path) {
+		if err := ioutil.WriteFile(path, nil, 0600); err != nil {
+			return fmt.Errorf("failed to create %s: %w", path, err)
+		}
+	}
+
+	callback, err := knownhosts.New(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	err = callback(address, remote, key)
+	if err == nil {
+		return nil
+	}
+
+	var keyErr *knownhosts.KeyError
+	if !errors.As(err, &keyErr) {
+		return err
+	}
+
+	if len(keyErr.Want) > 0 {
+		log.Infof("host key of %s changed, got %s", address, ssh.FingerprintSHA256(key))
+		return okErrors.ErrHostKeyMismatch
+	}
+
+	log.Infof("pinning host key of %s: %s", address, ssh.FingerprintSHA256(key))
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(knownhosts.Line([]string{address}, key) + "\n"); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// ResetHostKey removes the pinned host keys of a development container
+func ResetHostKey(name string) error {
+	return removeStaleHostKeys(getKnownHostsPath(), buildHostname(name), "")
+}
+
+// removeStaleHostKeys removes the host keys pinned for host and for the pods of host, except the ones of keep
+func removeStaleHostKeys(path, host, keep string) error {
+	knownHostsLock.Lock()
+	defer knownHostsLock.Unlock()
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	removed := false
+	lines := []string{}
+	for _, line := range strings.Split(string(b), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && hostMatches(fields[0], host) && !hostMatches(fields[0], keep) {
+			removed = true
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	if !removed {
+		return nil
+	}
+	return ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")), 0600)
+}
+
+// hostMatches returns true if hosts includes host or a pod of host
+func hostMatches(hosts, host string) bool {
+	if host == "" {
+		return false
+	}
+	for _, h := range strings.Split(hosts, ",") {
+		if h == host || strings.HasSuffix(h, "."+host) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,132 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+func newTestHostKey(t *testing.T) ssh.PublicKey {
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := ssh.NewPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func Test_checkHostKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, knownHostsFile)
+	remote := &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 2222}
+	key := newTestHostKey(t)
+	other := newTestHostKey(t)
+
+	if err := checkHostKey(path, "test.okteto:22", remote, key); err != nil {
+		t.Fatalf("failed to pin host key: %s", err)
+	}
+
+	if err := checkHostKey(path, "test.okteto:22", remote, key); err != nil {
+		t.Fatalf("pinned host key was rejected: %s", err)
+	}
+
+	if err := checkHostKey(path, "other.okteto:22", remote, other); err != nil {
+		t.Fatalf("failed to pin host key of a different host: %s", err)
+	}
+
+	if err := checkHostKey(path, "test.okteto:22", remote, other); err != errors.ErrHostKeyMismatch {
+		t.Fatalf("got %v, expected %s", err, errors.ErrHostKeyMismatch)
+	}
+
+	if err := removeStaleHostKeys(path, "test.okteto", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkHostKey(path, "test.okteto:22", remote, other); err != nil {
+		t.Fatalf("failed to pin host key after reset: %s", err)
+	}
+
+	if err := checkHostKey(path, "other.okteto:22", remote, other); err != nil {
+		t.Fatalf("host key of a different host was reset: %s", err)
+	}
+}
+
+func Test_hostKeyCallbackPerPod(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	os.Setenv("OKTETO_FOLDER", dir)
+	defer os.Unsetenv("OKTETO_FOLDER")
+
+	remote := &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 2222}
+	key := newTestHostKey(t)
+	other := newTestHostKey(t)
+	callback := hostKeyCallback("test")
+
+	SetDevPod("test", "pod-1")
+	if err := callback("", remote, key); err != nil {
+		t.Fatalf("failed to pin host key: %s", err)
+	}
+	if err := callback("", remote, other); err != errors.ErrHostKeyMismatch {
+		t.Fatalf("got %v, expected %s", err, errors.ErrHostKeyMismatch)
+	}
+
+	SetDevPod("test", "pod-2")
+	if err := callback("", remote, other); err != nil {
+		t.Fatalf("host key of a new pod was rejected: %s", err)
+	}
+
+	b, err := ioutil.ReadFile(getKnownHostsPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "pod-1") || !strings.Contains(string(b), "pod-2.test.okteto") {
+		t.Errorf("host keys of previous pods weren't removed: %s", b)
+	}
+
+	if err := ResetHostKey("test"); err != nil {
+		t.Fatal(err)
+	}
+	b, err = ioutil.ReadFile(getKnownHostsPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "test.okteto") {
+		t.Errorf("host keys weren't reset: %s", b)
+	}
+}
@@ -16,8 +16,10 @@ package ssh
 import (
 	"context"
 	"fmt"
-	"runtime"
+	"sort"
+	"sync"
 
+	"github.com/okteto/okteto/pkg/errors"
 	k8sforward "github.com/okteto/okteto/pkg/k8s/forward"
 	"github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/model"
@@ -29,22 +31,32 @@ type ForwardManager struct {
 	remoteInterface string
 	forwards        map[int]*forward
 	reverses        map[int]*reverse
+	remapped        map[int]int
+	denied          []int
+	auto            map[int]model.Forward
+	socks           *socks
 	ctx             context.Context
 	sshAddr         string
 	pf              *k8sforward.PortForwardManager
 	pool            *pool
+	poolOptions     PoolOptions
+	audit           *auditLog
+	lock            sync.Mutex
 }
 
 // NewForwardManager returns a newly initialized instance of ForwardManager
-func NewForwardManager(ctx context.Context, sshAddr, localInterface, remoteInterface string, pf *k8sforward.PortForwardManager) *ForwardManager {
+func NewForwardManager(ctx context.Context, sshAddr, localInterface, remoteInterface string, pf *k8sforward.PortForwardManager, poolOptions PoolOptions) *ForwardManager {
 	return &ForwardManager{
 		ctx:             ctx,
 		localInterface:  localInterface,
 		remoteInterface: remoteInterface,
 		forwards:        make(map[int]*forward),
 		reverses:        make(map[int]*reverse),
+		remapped:        make(map[int]int),
+		auto:            make(map[int]model.Forward),
 		sshAddr:         sshAddr,
 		pf:              pf,
+		poolOptions:     poolOptions,
 	}
 }
 
@@ -57,20 +69,20 @@ func (fm *ForwardManager) canAdd(localPort int, checkAvailable bool) error {
 		return fmt.Errorf("port %d is listed multiple times, please check your forwards configuration", localPort)
 	}
 
+	for privileged, port := range fm.remapped {
+		if port == localPort {
+			return fmt.Errorf("port %d is already used to forward the privileged port %d", localPort, privileged)
+		}
+	}
+
 	if !checkAvailable {
 		return nil
 	}
 
 	if !model.IsPortAvailable(fm.localInterface, localPort) {
-		if localPort <= 1024 {
-			os := runtime.GOOS
-			switch os {
-			case "darwin":
-				if fm.localInterface == model.Localhost {
-					return fmt.Errorf("local port %d is privileged. Define 'interface: 0.0.0.0' in your okteto manifest and try again", localPort)
-				}
-			case "linux":
-				return fmt.Errorf("local port %d is privileged. Try running \"sudo setcap 'cap_net_bind_service=+ep' /usr/local/bin/okteto\" and try again", localPort)
+		if localPort <= maxPrivilegedPort {
+			if hint := PrivilegedPortHint(fm.localInterface); hint != "" {
+				return fmt.Errorf("local port %d is privileged. %s and try again", localPort, hint)
 			}
 		}
 		return fmt.Errorf("local port %d is already in-use in your local machine", localPort)
@@ -81,20 +93,137 @@ func (fm *ForwardManager) canAdd(localPort int, checkAvailable bool) error {
 
 // Add initializes a remote forward
 func (fm *ForwardManager) Add(f model.Forward) error {
+	fm.lock.Lock()
+	defer fm.lock.Unlock()
+
+	if f.Auto {
+		port, err := fm.freeLocalPort()
+		if err != nil {
+			return fmt.Errorf("failed to select a local port for %s: %w", f.String(), err)
+		}
+
+		log.Infof("selected local port %d for %s", port, f.String())
+		f.Local = port
+		fm.auto[port] = f
+		fm.forwards[port] = fm.newForward(f, port)
+		return nil
+	}
+
+	localPort, err := fm.resolveLocalPort(f.Local)
+	if err != nil {
+		return err
+	}
+
+	fm.forwards[f.Local] = fm.newForward(f, localPort)
+	return nil
+}
+
+// AddAndStart adds a remote forward to a running forward manager
+func (fm *ForwardManager) AddAndStart(f model.Forward) error {
+	fm.lock.Lock()
+	defer fm.lock.Unlock()
+
+	if fm.pool == nil {
+		return fmt.Errorf("the SSH forward manager is not running")
+	}
+
+	if f.Auto {
+		return fmt.Errorf("'auto' local ports are only supported in the okteto manifest")
+	}
 
 	if err := fm.canAdd(f.Local, true); err != nil {
 		return err
 	}
 
-	fm.forwards[f.Local] = &forward{
-		localAddress:  fmt.Sprintf("%s:%d", fm.localInterface, f.Local),
+	ff := fm.newForward(f, f.Local)
+	if err := fm.startForward(ff); err != nil {
+		return fmt.Errorf("failed to listen on local port %d: %w", f.Local, err)
+	}
+
+	fm.forwards[f.Local] = ff
+	return nil
+}
+
+// Remove stops and removes the remote forward listening on localPort
+func (fm *ForwardManager) Remove(localPort int) error {
+	fm.lock.Lock()
+	defer fm.lock.Unlock()
+
+	ff, ok := fm.forwards[localPort]
+	if !ok {
+		return fmt.Errorf("port %d is not forwarded", localPort)
+	}
+
+	if ff.cancel != nil {
+		ff.cancel()
+	}
+	ff.close()
+
+	delete(fm.forwards, localPort)
+	delete(fm.remapped, localPort)
+	delete(fm.auto, localPort)
+	return nil
+}
+
+// freeLocalPort returns a local port that is available and not used by any other forward
+func (fm *ForwardManager) freeLocalPort() (int, error) {
+	var err error
+	for i := 0; i < 10; i++ {
+		var port int
+		port, err = model.GetAvailablePort(fm.localInterface)
+		if err != nil {
+			continue
+		}
+
+		if err = fm.canAdd(port, false); err == nil {
+			return port, nil
+		}
+	}
+
+	return 0, err
+}
+
+// AutoForwards returns the forwards defined as 'auto', with the local port selected for each of them
+func (fm *ForwardManager) AutoForwards() []model.Forward {
+	fm.lock.Lock()
+	defer fm.lock.Unlock()
+
+	result := make([]model.Forward, 0, len(fm.auto))
+	for _, f := range fm.auto {
+		result = append(result, f)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Local < result[j].Local })
+	return result
+}
+
+func (fm *ForwardManager) newForward(f model.Forward, localPort int) *forward {
+	ff := &forward{
+		localAddress:  fmt.Sprintf("%s:%d", fm.localInterface, localPort),
 		remoteAddress: fmt.Sprintf("%s:%d", fm.remoteInterface, f.Remote),
+		inLimiter:     newLimiter(f.MaxBandwidth.BytesPerSecond()),
+		outLimiter:    newLimiter(f.MaxBandwidth.BytesPerSecond()),
+		tcp:           f.TCP,
 	}
 
 	if f.Service {
-		fm.forwards[f.Local].remoteAddress = fmt.Sprintf("%s:%d", f.ServiceName, f.Remote)
+		ff.remoteAddress = fmt.Sprintf("%s:%d", f.ServiceName, f.Remote)
+	}
+
+	return ff
+}
+
+// startForward binds the local port of the forward and accepts its connections in a goroutine
+func (fm *ForwardManager) startForward(ff *forward) error {
+	if err := ff.listen(); err != nil {
+		return err
 	}
 
+	ctx, cancel := context.WithCancel(fm.ctx)
+	ff.pool = fm.pool
+	ff.audit = fm.audit
+	ff.cancel = cancel
+	go ff.start(ctx, ff.listener)
 	return nil
 }
 
@@ -109,37 +238,63 @@ func (fm *ForwardManager) Start(devPod, namespace string) error {
 		log.Info("port forward to dev pod connected")
 	}
 
-	c, err := getSSHClientConfig()
+	c, err := getSSHClientConfig(fm.poolOptions.Name)
 	if err != nil {
 		return fmt.Errorf("failed to get SSH configuration: %s", err)
 	}
 
 	log.Infof("starting SSH connection pool on %s", fm.sshAddr)
-	pool, err := startPool(fm.ctx, fm.sshAddr, c)
+	pool, err := startPool(fm.ctx, fm.sshAddr, c, fm.poolOptions)
 	if err != nil {
 		return err
 	}
 
+	fm.lock.Lock()
+	defer fm.lock.Unlock()
 	fm.pool = pool
 
-	for _, ff := range fm.forwards {
-		ff.pool = pool
-		go ff.start(fm.ctx)
+	if err := fm.remapDeniedPorts(); err != nil {
+		return err
+	}
 
+	for _, ff := range fm.forwards {
+		if err := fm.startForward(ff); err != nil {
+			log.Infof("%s -> failed to listen: %s", ff.String(), err)
+		}
 	}
 
 	for _, rt := range fm.reverses {
 		rt.pool = pool
+		rt.audit = fm.audit
 		go rt.start(fm.ctx)
 	}
 
+	if fm.socks != nil {
+		fm.socks.pool = pool
+		fm.socks.audit = fm.audit
+		go fm.socks.start(fm.ctx)
+	}
+
 	return nil
 }
 
+// Monitor sends a disconnect signal if the SSH pool loses its connection to the development container
+func (fm *ForwardManager) Monitor(ctx context.Context, disconnect chan error) {
+	select {
+	case err := <-fm.pool.errors:
+		log.Infof("ssh pool error, sending disconnect signal: %s", err)
+		disconnect <- errors.ErrSSHConnectionLost
+	case <-ctx.Done():
+	}
+}
+
 // Stop sends a stop signal to all the connections
 func (fm *ForwardManager) Stop() {
 
 	if fm.pool != nil {
+		if fm.poolOptions.DrainTimeout > 0 {
+			fm.pool.drain(fm.poolOptions.DrainTimeout)
+		}
 		fm.pool.stop()
 	}
 
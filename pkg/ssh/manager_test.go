@@ -3,8 +3,12 @@ package ssh
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -12,6 +16,8 @@ import (
 	"github.com/gliderlabs/ssh"
 	"github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/model"
+	"golang.org/x/net/proxy"
+	"golang.org/x/net/websocket"
 )
 
 type testHTTPHandler struct {
@@ -61,7 +67,7 @@ func TestForward(t *testing.T) {
 	sshAddr := fmt.Sprintf("localhost:%d", sshPort)
 	ssh := testSSHHandler{}
 	go ssh.listenAndServe(sshAddr)
-	fm := NewForwardManager(ctx, sshAddr, model.Localhost, "0.0.0.0", nil)
+	fm := NewForwardManager(ctx, sshAddr, model.Localhost, "0.0.0.0", nil, PoolOptions{Size: 2})
 
 	if err := startServers(fm); err != nil {
 		t.Fatal(err)
@@ -81,6 +87,30 @@ func TestForward(t *testing.T) {
 		t.Error(err)
 	}
 
+	stats := fm.Stats()
+	if stats.Clients != 2 {
+		t.Errorf("got %d clients, expected 2", stats.Clients)
+	}
+
+	if len(stats.Forwards) != len(fm.forwards) {
+		t.Errorf("got stats for %d forwards, expected %d", len(stats.Forwards), len(fm.forwards))
+	}
+
+	for _, f := range stats.Forwards {
+		if f.BytesIn == 0 || f.BytesOut == 0 {
+			t.Errorf("%s didn't record its traffic: %+v", f.Name, f)
+		}
+	}
+
+	if err := fm.pool.sendKeepAlive(0); err != nil {
+		t.Fatal(err)
+	}
+
+	health := fm.HealthCheck()
+	if health.Latency <= 0 || !health.Healthy {
+		t.Errorf("unexpected health: %+v", health)
+	}
+
 	cancel()
 	fm.Stop()
 	if err := fm.waitForwardsDisconnected(); err != nil {
@@ -102,7 +132,7 @@ func TestReverse(t *testing.T) {
 	sshAddr := fmt.Sprintf("localhost:%d", sshPort)
 	ssh := testSSHHandler{}
 	go ssh.listenAndServe(sshAddr)
-	fm := NewForwardManager(ctx, sshAddr, model.Localhost, "0.0.0.0", nil)
+	fm := NewForwardManager(ctx, sshAddr, model.Localhost, "0.0.0.0", nil, PoolOptions{})
 
 	if err := connectReverseForwards(fm); err != nil {
 		t.Fatal(err)
@@ -122,6 +152,343 @@ func TestReverse(t *testing.T) {
 
 }
 
+func TestReverseReconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sshPort, err := model.GetAvailablePort(model.Localhost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sshAddr := fmt.Sprintf("localhost:%d", sshPort)
+	ssh := testSSHHandler{}
+	go ssh.listenAndServe(sshAddr)
+	fm := NewForwardManager(ctx, sshAddr, model.Localhost, "0.0.0.0", nil, PoolOptions{})
+
+	if err := connectReverseForwards(fm); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fm.Start("", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkReverseForwardsConnected(fm); err != nil {
+		t.Fatal(err)
+	}
+
+	old := fm.pool.getClient(0)
+	if err := fm.pool.reconnect(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if fm.pool.getClient(0) == old {
+		t.Fatal("ssh client was not swapped")
+	}
+
+	tk := time.NewTicker(100 * time.Millisecond)
+	defer tk.Stop()
+	for i := 0; ; i++ {
+		err := callReverseForwards(fm)
+		if err == nil {
+			break
+		}
+
+		if i == 50 {
+			t.Fatalf("reverse forwards were not re-established: %s", err)
+		}
+		<-tk.C
+	}
+}
+
+func TestWebSocketFallback(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sshPort, err := model.GetAvailablePort(model.Localhost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sshAddr := fmt.Sprintf("localhost:%d", sshPort)
+	ssh := testSSHHandler{}
+	go ssh.listenAndServe(sshAddr)
+
+	wsServer := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		ws.PayloadType = websocket.BinaryFrame
+		c, err := net.Dial("tcp", sshAddr)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer c.Close()
+		go func() {
+			_, _ = io.Copy(c, ws)
+		}()
+		_, _ = io.Copy(ws, c)
+	}))
+	defer wsServer.Close()
+
+	unreachablePort, err := model.GetAvailablePort(model.Localhost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wsURL := "ws" + strings.TrimPrefix(wsServer.URL, "http")
+	fm := NewForwardManager(ctx, fmt.Sprintf("localhost:%d", unreachablePort), model.Localhost, "0.0.0.0", nil, PoolOptions{WebSocketURL: wsURL})
+
+	if err := startServers(fm); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fm.Start("", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fm.waitForwardsConnected(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := callForwards(fm); err != nil {
+		t.Error(err)
+	}
+
+	fm.Stop()
+}
+
+func TestJumpHost(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		os.RemoveAll(dir)
+		os.Unsetenv("OKTETO_FOLDER")
+	}()
+
+	os.Setenv("OKTETO_FOLDER", dir)
+
+	jumpPort, err := model.GetAvailablePort(model.Localhost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jumpAddr := fmt.Sprintf("localhost:%d", jumpPort)
+	jump := testSSHHandler{}
+	go jump.listenAndServe(jumpAddr)
+
+	sshPort, err := model.GetAvailablePort(model.Localhost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sshAddr := fmt.Sprintf("localhost:%d", sshPort)
+	ssh := testSSHHandler{}
+	go ssh.listenAndServe(sshAddr)
+
+	fm := NewForwardManager(ctx, sshAddr, model.Localhost, "0.0.0.0", nil, PoolOptions{
+		JumpHost: &model.JumpHost{Host: jumpAddr, User: "okteto"},
+	})
+
+	if err := startServers(fm); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fm.Start("", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if fm.pool.jump == nil {
+		t.Fatal("pool didn't connect to the jump host")
+	}
+
+	if err := fm.waitForwardsConnected(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := callForwards(fm); err != nil {
+		t.Error(err)
+	}
+
+	fm.Stop()
+	if fm.pool.jump != nil {
+		t.Error("jump host client was not closed")
+	}
+}
+
+func TestControl(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		os.RemoveAll(dir)
+		os.Unsetenv("OKTETO_FOLDER")
+	}()
+
+	os.Setenv("OKTETO_FOLDER", dir)
+
+	sshPort, err := model.GetAvailablePort(model.Localhost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sshAddr := fmt.Sprintf("localhost:%d", sshPort)
+	ssh := testSSHHandler{}
+	go ssh.listenAndServe(sshAddr)
+	fm := NewForwardManager(ctx, sshAddr, model.Localhost, "0.0.0.0", nil, PoolOptions{})
+	if err := fm.Start("", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		if err := fm.ServeControl(ctx, "test", "control"); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	local, err := model.GetAvailablePort(model.Localhost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remote, err := model.GetAvailablePort(model.Localhost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		handler := &testHTTPHandler{message: fmt.Sprintf("%d", remote)}
+		_ = http.ListenAndServe(fmt.Sprintf(":%d", remote), handler)
+	}()
+
+	tk := time.NewTicker(100 * time.Millisecond)
+	defer tk.Stop()
+	for i := 0; ; i++ {
+		err := AddForward("test", "control", model.Forward{Local: local, Remote: remote})
+		if err == nil {
+			break
+		}
+
+		if i == 50 {
+			t.Fatal(err)
+		}
+		<-tk.C
+	}
+
+	if err := AddForward("test", "control", model.Forward{Local: local, Remote: remote}); err == nil {
+		t.Fatal("duplicated forward was added")
+	}
+
+	if err := fm.waitForwardsConnected(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := callForwards(fm); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RemoveForward("test", "control", local); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fm.forwards) != 0 {
+		t.Fatal("forward was not removed")
+	}
+
+	if err := RemoveForward("test", "control", local); err == nil {
+		t.Fatal("removed a forward that doesn't exist")
+	}
+
+	if !model.IsPortAvailable(model.Localhost, local) {
+		t.Fatalf("port %d is still in use after removing its forward", local)
+	}
+
+	if err := AddForward("test", "control", model.Forward{Local: local, Remote: remote}); err != nil {
+		t.Fatalf("failed to add the removed forward again: %s", err)
+	}
+
+	if err := fm.waitForwardsConnected(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSocks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sshPort, err := model.GetAvailablePort(model.Localhost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sshAddr := fmt.Sprintf("localhost:%d", sshPort)
+	ssh := testSSHHandler{}
+	go ssh.listenAndServe(sshAddr)
+	fm := NewForwardManager(ctx, sshAddr, model.Localhost, "0.0.0.0", nil, PoolOptions{})
+
+	socksPort, err := model.GetAvailablePort(model.Localhost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	socksAddr := fmt.Sprintf("localhost:%d", socksPort)
+	if err := fm.AddSocks(socksAddr); err != nil {
+		t.Fatal(err)
+	}
+
+	remote, err := model.GetAvailablePort(model.Localhost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		handler := &testHTTPHandler{message: fmt.Sprintf("%d", remote)}
+		_ = http.ListenAndServe(fmt.Sprintf(":%d", remote), handler)
+	}()
+
+	if err := fm.Start("", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	dialer, err := proxy.SOCKS5("tcp", socksAddr, nil, proxy.Direct)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &http.Client{Transport: &http.Transport{Dial: dialer.Dial}}
+	tk := time.NewTicker(100 * time.Millisecond)
+	defer tk.Stop()
+	for i := 0; ; i++ {
+		r, err := c.Get(fmt.Sprintf("http://localhost:%d", remote))
+		if err != nil {
+			if i == 50 {
+				t.Fatal(err)
+			}
+			<-tk.C
+			continue
+		}
+
+		defer r.Body.Close()
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(body) != fmt.Sprintf("%d", remote) {
+			t.Fatalf("got: %s, expected: %d", string(body), remote)
+		}
+
+		return
+	}
+}
+
 func startServers(fm *ForwardManager) error {
 	for i := 0; i < 1; i++ {
 		local, err := model.GetAvailablePort(model.Localhost)
@@ -309,7 +676,7 @@ func (fm *ForwardManager) waitForwardsDisconnected() error {
 
 func TestAdd(t *testing.T) {
 
-	pf := NewForwardManager(context.Background(), "0.0.0.0:22000", "0.0.0.0", "0.0.0.0", nil)
+	pf := NewForwardManager(context.Background(), "0.0.0.0:22000", "0.0.0.0", "0.0.0.0", nil, PoolOptions{})
 	if err := pf.Add(model.Forward{Local: 10010, Remote: 1010}); err != nil {
 		t.Fatal(err)
 	}
@@ -330,3 +697,41 @@ func TestAdd(t *testing.T) {
 		t.Fatalf("expected 'svc:15123', got '%s'", pf.forwards[1012].remoteAddress)
 	}
 }
+
+func TestAddAuto(t *testing.T) {
+	pf := NewForwardManager(context.Background(), "0.0.0.0:22000", "0.0.0.0", "0.0.0.0", nil, PoolOptions{})
+	if err := pf.Add(model.Forward{Auto: true, Remote: 8080}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := pf.Add(model.Forward{Auto: true, Remote: 5432, Service: true, ServiceName: "db"}); err != nil {
+		t.Fatal(err)
+	}
+
+	auto := pf.AutoForwards()
+	if len(auto) != 2 {
+		t.Fatalf("expected 2 auto forwards, got %d", len(auto))
+	}
+
+	if auto[0].Local == auto[1].Local {
+		t.Fatalf("the same local port %d was selected twice", auto[0].Local)
+	}
+
+	for _, f := range auto {
+		if f.Local == 0 {
+			t.Fatalf("local port wasn't selected for %s", f.String())
+		}
+
+		if _, ok := pf.forwards[f.Local]; !ok {
+			t.Fatalf("forward for %s wasn't added on port %d", f.String(), f.Local)
+		}
+	}
+
+	if err := pf.Remove(auto[0].Local); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pf.AutoForwards()) != 1 {
+		t.Fatal("auto forward wasn't removed")
+	}
+}
@@ -16,66 +16,189 @@ package ssh
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"net"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/okteto/okteto/pkg/config"
 	"github.com/okteto/okteto/pkg/errors"
 	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
 	"golang.org/x/crypto/ssh"
 )
 
+const (
+	defaultPoolSize            = 1
+	defaultKeepAlive           = 30 * time.Second
+	defaultMaxMissedKeepAlives = 3
+
+	sshBannerPrefix = "SSH-"
+
+	initialRetryInterval = 100 * time.Millisecond
+	maxRetryInterval     = 2 * time.Second
+
+	drainPollInterval = 100 * time.Millisecond
+)
+
+var errPoolDraining = fmt.Errorf("ssh pool is shutting down")
+
+// PoolOptions configures the SSH connection pool
+type PoolOptions struct {
+	// Size is the number of SSH clients kept open by the pool
+	Size int
+
+	// KeepAlive is the interval between keepalive requests
+	KeepAlive time.Duration
+
+	// MaxMissedKeepAlives is the number of consecutive keepalive failures before reconnecting a client
+	MaxMissedKeepAlives int
+
+	// WebSocketURL is the websocket endpoint used to tunnel SSH when the TCP port is not reachable
+	WebSocketURL string
+
+	// Name is the name of the development container, used to pin its host key
+	Name string
+
+	// JumpHost is the bastion used to reach the SSH server, if any
+	JumpHost *model.JumpHost
+
+	// ConnectTimeout is the overall deadline to establish each SSH connection
+	ConnectTimeout time.Duration
+
+	// TCP are the socket options of the connections to the SSH server
+	TCP model.TCPOptions
+
+	// DrainTimeout is the time to wait for the active channels to finish before closing the pool, 0 to close it immediately
+	DrainTimeout time.Duration
+}
+
 type pool struct {
-	ka      time.Duration
-	client  *ssh.Client
-	stopped bool
+	// counters are accessed atomically and must stay 64-bit aligned
+	activeChannels   int64
+	reconnects       int64
+	missedKeepAlives int64
+	dialLatency      int64
+	latency          int64
+
+	ka             time.Duration
+	maxMissed      int
+	connectTimeout time.Duration
+	tcp            model.TCPOptions
+	errors         chan error
+	serverAddr     string
+	wsURL          string
+	jumpHost       *model.JumpHost
+	jumpLock       sync.Mutex
+	jump           *ssh.Client
+	config         *ssh.ClientConfig
+	lock           sync.RWMutex
+	clients        []*ssh.Client
+	next           uint32
+	stopped        bool
+	draining       bool
 }
 
-func startPool(ctx context.Context, serverAddr string, config *ssh.ClientConfig) (*pool, error) {
-	p := &pool{
-		ka:      30 * time.Second,
-		stopped: false,
+func startPool(ctx context.Context, serverAddr string, config *ssh.ClientConfig, opts PoolOptions) (*pool, error) {
+	size := opts.Size
+	if size <= 0 {
+		size = defaultPoolSize
 	}
 
-	clientConn, chans, reqs, err := retryNewClientConn(ctx, serverAddr, config, p)
-	if err != nil {
-		log.Infof("failed to create ssh connection for %s: %s", serverAddr, err.Error())
-		return nil, errors.ErrSSHConnectError
+	ka := opts.KeepAlive
+	if ka <= 0 {
+		ka = defaultKeepAlive
+	}
+
+	maxMissed := opts.MaxMissedKeepAlives
+	if maxMissed <= 0 {
+		maxMissed = defaultMaxMissedKeepAlives
+	}
+
+	connectTimeout := opts.ConnectTimeout
+	if connectTimeout <= 0 {
+		connectTimeout = defaultConnectTimeout()
 	}
 
-	client := ssh.NewClient(clientConn, chans, reqs)
+	p := &pool{
+		ka:             ka,
+		maxMissed:      maxMissed,
+		connectTimeout: connectTimeout,
+		tcp:            opts.TCP,
+		errors:         make(chan error, size),
+		serverAddr:     serverAddr,
+		wsURL:          opts.WebSocketURL,
+		jumpHost:       opts.JumpHost,
+		config:         config,
+		clients:        make([]*ssh.Client, 0, size),
+		stopped:        false,
+	}
 
-	p.client = client
-	go p.keepAlive(ctx)
+	for i := 0; i < size; i++ {
+		client, err := p.dial(ctx)
+		if err != nil {
+			log.Infof("failed to create ssh connection %d for %s: %s", i, serverAddr, err.Error())
+			p.stop()
+			if errors.IsHostKeyMismatch(err) {
+				return nil, errors.ErrHostKeyMismatch
+			}
+			return nil, err
+		}
+
+		p.lock.Lock()
+		p.clients = append(p.clients, client)
+		p.lock.Unlock()
+		go p.keepAlive(ctx, i)
+	}
 
+	log.Infof("ssh pool started with %d clients", len(p.clients))
 	return p, nil
 }
 
+func (p *pool) dial(ctx context.Context) (*ssh.Client, error) {
+	clientConn, chans, reqs, err := retryNewClientConn(ctx, p.serverAddr, p.config, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return ssh.NewClient(clientConn, chans, reqs), nil
+}
+
 func retryNewClientConn(ctx context.Context, addr string, conf *ssh.ClientConfig, p *pool) (ssh.Conn, <-chan ssh.NewChannel, <-chan *ssh.Request, error) {
-	ticker := time.NewTicker(300 * time.Millisecond)
-	to := config.GetTimeout() / 10 // 3 seconds
-	timeout := time.Now().Add(to)
+	deadline := time.Now().Add(p.connectTimeout)
+	interval := initialRetryInterval
+	reachable := false
 
 	log.Infof("waiting for ssh to be ready %s", addr)
-	for i := 0; ; i++ {
-		conn, err := getTCPConnection(ctx, addr, p.ka)
+	for i := 1; ; i++ {
+		conn, err := p.connect(ctx, addr)
 		if err == nil {
-			clientConn, chans, reqs, errConn := ssh.NewClientConn(conn, addr, conf)
+			bc := &bannerConn{Conn: conn}
+			clientConn, chans, reqs, errConn := ssh.NewClientConn(bc, addr, conf)
 			if errConn == nil {
 				return clientConn, chans, reqs, nil
 			}
 			err = errConn
+			// port forwards accept connections even if nothing listens in the development container,
+			// the ssh server is only reachable if it sent its banner
+			reachable = reachable || bc.sawBanner()
+		}
+
+		if errors.IsHostKeyMismatch(err) {
+			return nil, nil, nil, err
 		}
 
 		log.Infof("ssh is not ready yet: %s", err)
 
-		if time.Now().After(timeout) {
-			return nil, nil, nil, err
+		wait := withJitter(interval)
+		if time.Now().Add(wait).After(deadline) {
+			return nil, nil, nil, &errors.SSHConnectError{Reachable: reachable, Attempts: i, Err: err}
 		}
 
 		select {
-		case <-ticker.C:
-			continue
+		case <-time.After(wait):
+			interval = nextRetryInterval(interval)
 		case <-ctx.Done():
 			log.Infof("ssh.retryNewClientConn cancelled")
 			return nil, nil, nil, fmt.Errorf("ssh.retryNewClientConn cancelled")
@@ -83,9 +206,64 @@ func retryNewClientConn(ctx context.Context, addr string, conf *ssh.ClientConfig
 	}
 }
 
-func (p *pool) keepAlive(ctx context.Context) {
+// bannerConn records if the first bytes read from the connection are the banner of an ssh server
+type bannerConn struct {
+	net.Conn
+	prefix []byte
+}
+
+func (c *bannerConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if missing := len(sshBannerPrefix) - len(c.prefix); missing > 0 && n > 0 {
+		if n < missing {
+			missing = n
+		}
+		c.prefix = append(c.prefix, b[:missing]...)
+	}
+	return n, err
+}
+
+func (c *bannerConn) sawBanner() bool {
+	return string(c.prefix) == sshBannerPrefix
+}
+
+func defaultConnectTimeout() time.Duration {
+	return config.GetTimeout() / 10 // 3 seconds
+}
+
+// nextRetryInterval doubles the interval between connection attempts, up to maxRetryInterval
+func nextRetryInterval(interval time.Duration) time.Duration {
+	next := interval * 2
+	if next > maxRetryInterval {
+		return maxRetryInterval
+	}
+
+	return next
+}
+
+// withJitter returns a random duration between 0.5 and 1.5 times d, so clients don't retry in lockstep
+func withJitter(d time.Duration) time.Duration {
+	return d/2 + time.Duration(rand.Int63n(int64(d)))
+}
+
+func (p *pool) connect(ctx context.Context, addr string) (net.Conn, error) {
+	if p.jumpHost != nil {
+		return p.connectThroughJumpHost(ctx, addr)
+	}
+
+	conn, err := getTCPConnection(ctx, addr, p.ka, p.tcp)
+	if err == nil || p.wsURL == "" {
+		return conn, err
+	}
+
+	log.Infof("failed to connect to %s, falling back to websocket transport: %s", addr, err)
+	return getWebSocketConnection(p.wsURL, p.ka)
+}
+
+func (p *pool) keepAlive(ctx context.Context, i int) {
 	t := time.NewTicker(p.ka)
 	defer t.Stop()
+	missed := 0
 	for {
 		select {
 		case <-ctx.Done():
@@ -98,32 +276,152 @@ func (p *pool) keepAlive(ctx context.Context) {
 
 			return
 		case <-t.C:
-			if p.stopped {
+			if p.isStopped() {
 				return
 			}
 
-			if _, _, err := p.client.SendRequest("dev.okteto.com/keepalive", true, nil); err != nil {
-				log.Infof("failed to send SSH keepalive: %s", err)
+			if err := p.sendKeepAlive(i); err != nil {
+				missed++
+				atomic.AddInt64(&p.missedKeepAlives, 1)
+				log.Infof("failed to send SSH keepalive (%d/%d): %s", missed, p.maxMissed, err)
+				if missed < p.maxMissed {
+					continue
+				}
+
+				if err := p.reconnect(ctx, i); err != nil {
+					log.Infof("failed to reconnect ssh client %d: %s", i, err)
+					p.errors <- fmt.Errorf("ssh client %d missed %d keepalives and couldn't reconnect: %w", i, missed, err)
+					return
+				}
 			}
+
+			missed = 0
 		}
 	}
 }
 
+// sendKeepAlive sends a keepalive request and records its round-trip latency.
+// The latency is measured with the local clock only, the reply payload is ignored
+func (p *pool) sendKeepAlive(i int) error {
+	sent := time.Now()
+	if _, _, err := p.getClient(i).SendRequest("dev.okteto.com/keepalive", true, nil); err != nil {
+		return err
+	}
+
+	atomic.StoreInt64(&p.latency, int64(time.Since(sent)))
+	return nil
+}
+
+// reconnect dials a new connection and swaps it with the client in position i.
+// Closing the old client closes its reverse listeners, which are then re-established by their owners.
+func (p *pool) reconnect(ctx context.Context, i int) error {
+	log.Infof("reconnecting ssh client %d to %s", i, p.serverAddr)
+	client, err := p.dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	p.lock.Lock()
+	if p.stopped {
+		p.lock.Unlock()
+		return client.Close()
+	}
+	old := p.clients[i]
+	p.clients[i] = client
+	p.lock.Unlock()
+
+	if err := old.Close(); err != nil {
+		if !errors.IsClosedNetwork(err) {
+			log.Infof("failed to close stale ssh client %d: %s", i, err)
+		}
+	}
+
+	atomic.AddInt64(&p.reconnects, 1)
+	log.Infof("ssh client %d reconnected", i)
+	return nil
+}
+
+func (p *pool) getClient(i int) *ssh.Client {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.clients[i]
+}
+
+// client returns the next client of the pool in a round-robin fashion
+func (p *pool) client() *ssh.Client {
+	n := atomic.AddUint32(&p.next, 1)
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.clients[(int(n)-1)%len(p.clients)]
+}
+
+func (p *pool) isStopped() bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.stopped
+}
+
+func (p *pool) isDraining() bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.draining
+}
+
+// drain stops opening new channels and waits until the active ones are finished or the timeout expires
+func (p *pool) drain(timeout time.Duration) {
+	p.lock.Lock()
+	p.draining = true
+	p.lock.Unlock()
+
+	deadline := time.Now().Add(timeout)
+	t := time.NewTicker(drainPollInterval)
+	defer t.Stop()
+
+	for {
+		active := atomic.LoadInt64(&p.activeChannels)
+		if active <= 0 {
+			log.Infof("ssh pool drained")
+			return
+		}
+
+		if time.Now().After(deadline) {
+			log.Infof("%d ssh channels still active after %s, closing them", active, timeout)
+			return
+		}
+
+		<-t.C
+	}
+}
+
 func (p *pool) get(address string) (net.Conn, error) {
-	c, err := p.client.Dial("tcp", address)
-	return c, err
+	if p.isDraining() {
+		return nil, errPoolDraining
+	}
+
+	start := time.Now()
+	c, err := p.client().Dial("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	atomic.StoreInt64(&p.dialLatency, int64(time.Since(start)))
+	return p.track(c), nil
 }
 
 func (p *pool) getListener(address string) (net.Listener, error) {
-	l, err := p.client.Listen("tcp", address)
+	if p.isDraining() {
+		return nil, errPoolDraining
+	}
+
+	l, err := p.client().Listen("tcp", address)
 	if err != nil {
 		return nil, fmt.Errorf("failed to start ssh listener on %s: %w", address, err)
 	}
 
-	return l, nil
+	return &trackedListener{Listener: l, p: p}, nil
 }
 
-func getTCPConnection(ctx context.Context, serverAddr string, keepAlive time.Duration) (net.Conn, error) {
+func getTCPConnection(ctx context.Context, serverAddr string, keepAlive time.Duration, opts model.TCPOptions) (net.Conn, error) {
 	c, err := getConn(ctx, serverAddr, 3)
 	if err != nil {
 		return nil, err
@@ -137,6 +435,11 @@ func getTCPConnection(ctx context.Context, serverAddr string, keepAlive time.Dur
 		return nil, err
 	}
 
+	if err := setTCPOptions(c, opts); err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to set tcp options: %w", err)
+	}
+
 	return c, nil
 }
 
@@ -144,8 +447,7 @@ func getConn(ctx context.Context, serverAddr string, maxRetries int) (net.Conn,
 	var lastErr error
 	t := time.NewTicker(100 * time.Millisecond)
 	for i := 0; i < 5; i++ {
-		d := net.Dialer{}
-		c, err := d.DialContext(ctx, "tcp", serverAddr)
+		c, err := dialHappyEyeballs(ctx, serverAddr)
 		if err == nil {
 			return c, nil
 		}
@@ -158,10 +460,18 @@ func getConn(ctx context.Context, serverAddr string, maxRetries int) (net.Conn,
 }
 
 func (p *pool) stop() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
 	p.stopped = true
-	if err := p.client.Close(); err != nil {
-		if !errors.IsClosedNetwork(err) {
-			log.Infof("failed to close SSH pool: %s", err)
+	for _, client := range p.clients {
+		if err := client.Close(); err != nil {
+			if !errors.IsClosedNetwork(err) {
+				log.Infof("failed to close SSH pool: %s", err)
+			}
 		}
 	}
+
+	p.jumpLock.Lock()
+	defer p.jumpLock.Unlock()
+	p.closeJumpHost()
 }
@@ -0,0 +1,166 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/okteto/okteto/pkg/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+func Test_nextRetryInterval(t *testing.T) {
+	interval := initialRetryInterval
+	for i := 0; i < 10; i++ {
+		next := nextRetryInterval(interval)
+		if next > maxRetryInterval {
+			t.Fatalf("interval %s is over the maximum of %s", next, maxRetryInterval)
+		}
+		if next < interval {
+			t.Fatalf("interval decreased from %s to %s", interval, next)
+		}
+		interval = next
+	}
+
+	if interval != maxRetryInterval {
+		t.Fatalf("expected interval to be capped at %s, got %s", maxRetryInterval, interval)
+	}
+}
+
+func Test_withJitter(t *testing.T) {
+	d := time.Second
+	for i := 0; i < 100; i++ {
+		j := withJitter(d)
+		if j < d/2 || j >= d+d/2 {
+			t.Fatalf("jitter %s out of range for %s", j, d)
+		}
+	}
+}
+
+func Test_startPoolErrors(t *testing.T) {
+	unused, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unreachable := unused.Addr().String()
+	unused.Close()
+
+	rejecting, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rejecting.Close()
+
+	go func() {
+		for {
+			c, err := rejecting.Accept()
+			if err != nil {
+				return
+			}
+			c.Close()
+		}
+	}()
+
+	banner, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer banner.Close()
+
+	go func() {
+		for {
+			c, err := banner.Accept()
+			if err != nil {
+				return
+			}
+			c.Write([]byte("SSH-2.0-test\r\n"))
+			c.Close()
+		}
+	}()
+
+	tests := []struct {
+		name      string
+		addr      string
+		reachable bool
+	}{
+		{
+			name:      "not-reachable",
+			addr:      unreachable,
+			reachable: false,
+		},
+		{
+			name:      "closed-without-banner",
+			addr:      rejecting.Addr().String(),
+			reachable: false,
+		},
+		{
+			name:      "handshake-rejected",
+			addr:      banner.Addr().String(),
+			reachable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &ssh.ClientConfig{HostKeyCallback: ssh.InsecureIgnoreHostKey()}
+			_, err := startPool(context.Background(), tt.addr, conf, PoolOptions{ConnectTimeout: time.Second})
+			connectErr, ok := err.(*errors.SSHConnectError)
+			if !ok {
+				t.Fatalf("expected a connection error, got %v", err)
+			}
+
+			if connectErr.Reachable != tt.reachable {
+				t.Errorf("expected reachable to be %t, got %t: %s", tt.reachable, connectErr.Reachable, connectErr)
+			}
+
+			if connectErr.Attempts < 2 {
+				t.Errorf("expected the connection to be retried, got %d attempts", connectErr.Attempts)
+			}
+		})
+	}
+}
+
+func Test_drain(t *testing.T) {
+	p := &pool{activeChannels: 1}
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		atomic.AddInt64(&p.activeChannels, -1)
+	}()
+
+	start := time.Now()
+	p.drain(5 * time.Second)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("drain didn't finish when the active channels were done, took %s", elapsed)
+	}
+
+	if _, err := p.get("localhost:8080"); err != errPoolDraining {
+		t.Fatalf("expected new channels to be rejected, got %v", err)
+	}
+
+	if _, err := p.getListener("localhost:8080"); err != errPoolDraining {
+		t.Fatalf("expected new listeners to be rejected, got %v", err)
+	}
+
+	p = &pool{activeChannels: 1}
+	start = time.Now()
+	p.drain(300 * time.Millisecond)
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Fatalf("drain returned before the timeout with active channels, took %s", elapsed)
+	}
+}
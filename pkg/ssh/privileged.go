@@ -0,0 +1,123 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+)
+
+const (
+	maxPrivilegedPort = 1023
+
+	// privilegedPortOffset maps privileged ports to their usual unprivileged alternative (80 -> 8080, 443 -> 8443)
+	privilegedPortOffset = 8000
+)
+
+// PrivilegedPortHint returns how to allow okteto to bind privileged ports on the local interface
+func PrivilegedPortHint(iface string) string {
+	switch runtime.GOOS {
+	case "darwin":
+		if iface == model.Localhost {
+			return "Define 'interface: 0.0.0.0' in your okteto manifest"
+		}
+	case "linux":
+		return "Run \"sudo setcap 'cap_net_bind_service=+ep' /usr/local/bin/okteto\""
+	}
+
+	return ""
+}
+
+// isPrivilegedPortDenied returns true if binding the port fails because it's privileged
+func isPrivilegedPortDenied(iface string, port int) bool {
+	if port > maxPrivilegedPort {
+		return false
+	}
+
+	l, err := net.Listen("tcp", fmt.Sprintf("%s:%d", iface, port))
+	if err != nil {
+		return os.IsPermission(err)
+	}
+
+	l.Close()
+	return false
+}
+
+// resolveLocalPort returns the local port used to forward localPort. Privileged ports that the user is not allowed to bind
+// are replaced by an unprivileged port when the forward manager starts, once all the declared ports are registered
+func (fm *ForwardManager) resolveLocalPort(localPort int) (int, error) {
+	if !isPrivilegedPortDenied(fm.localInterface, localPort) {
+		return localPort, fm.canAdd(localPort, true)
+	}
+
+	if err := fm.canAdd(localPort, false); err != nil {
+		return 0, err
+	}
+
+	fm.denied = append(fm.denied, localPort)
+	return localPort, nil
+}
+
+// remapDeniedPorts replaces the privileged ports that the user is not allowed to bind by unprivileged ports
+// that are available and not used by any other forward
+func (fm *ForwardManager) remapDeniedPorts() error {
+	for _, localPort := range fm.denied {
+		ff, ok := fm.forwards[localPort]
+		if !ok {
+			continue
+		}
+
+		port := localPort + privilegedPortOffset
+		if !fm.isFallbackAvailable(port) {
+			var err error
+			port, err = fm.freeLocalPort()
+			if err != nil {
+				return fmt.Errorf("local port %d is privileged and there isn't a port available to replace it: %w", localPort, err)
+			}
+		}
+
+		log.Infof("local port %d is privileged, using %d instead", localPort, port)
+		fm.remapped[localPort] = port
+		ff.localAddress = fmt.Sprintf("%s:%d", fm.localInterface, port)
+	}
+
+	fm.denied = nil
+	return nil
+}
+
+func (fm *ForwardManager) isFallbackAvailable(port int) bool {
+	if fm.canAdd(port, false) != nil {
+		return false
+	}
+
+	return model.IsPortAvailable(fm.localInterface, port)
+}
+
+// RemappedPorts returns the privileged local ports that were replaced by an unprivileged one
+func (fm *ForwardManager) RemappedPorts() map[int]int {
+	fm.lock.Lock()
+	defer fm.lock.Unlock()
+
+	result := make(map[int]int, len(fm.remapped))
+	for k, v := range fm.remapped {
+		result[k] = v
+	}
+
+	return result
+}
@@ -0,0 +1,97 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+)
+
+func Test_isPrivilegedPortDenied(t *testing.T) {
+	p, err := model.GetAvailablePort(model.Localhost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if isPrivilegedPortDenied(model.Localhost, p) {
+		t.Fatalf("port %d is not privileged", p)
+	}
+}
+
+func TestRemappedPorts(t *testing.T) {
+	fm := NewForwardManager(context.Background(), ":8022", model.Localhost, "0.0.0.0", nil, PoolOptions{})
+	fm.remapped[80] = 8080
+
+	if err := fm.canAdd(8080, false); err == nil {
+		t.Fatal("the fallback of a privileged port was accepted as a local port")
+	}
+
+	if fm.isFallbackAvailable(8080) {
+		t.Fatal("the fallback of a privileged port was reused")
+	}
+
+	remapped := fm.RemappedPorts()
+	if remapped[80] != 8080 {
+		t.Fatalf("expected port 80 to be remapped to 8080, got %d", remapped[80])
+	}
+
+	delete(remapped, 80)
+	if _, ok := fm.remapped[80]; !ok {
+		t.Fatal("RemappedPorts didn't return a copy")
+	}
+
+	fm.forwards[80] = fm.newForward(model.Forward{Local: 80, Remote: 8080}, 8080)
+	if fm.forwards[80].localAddress != "localhost:8080" {
+		t.Fatalf("expected the forward to listen on the fallback port, got %s", fm.forwards[80].localAddress)
+	}
+
+	if err := fm.Remove(80); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fm.RemappedPorts()) != 0 {
+		t.Fatal("the fallback port wasn't released")
+	}
+}
+
+func Test_remapDeniedPorts(t *testing.T) {
+	fm := NewForwardManager(context.Background(), ":8022", model.Localhost, "0.0.0.0", nil, PoolOptions{})
+	fm.forwards[80] = fm.newForward(model.Forward{Local: 80, Remote: 80}, 80)
+	fm.forwards[443] = fm.newForward(model.Forward{Local: 443, Remote: 443}, 443)
+	fm.denied = []int{80, 443}
+
+	// 8080 is declared after the privileged port 80 is registered
+	fm.forwards[8080] = fm.newForward(model.Forward{Local: 8080, Remote: 8080}, 8080)
+
+	if err := fm.remapDeniedPorts(); err != nil {
+		t.Fatal(err)
+	}
+
+	remapped := fm.RemappedPorts()
+	if remapped[80] == 0 || remapped[80] == 8080 {
+		t.Errorf("port 80 was remapped to a declared port: %d", remapped[80])
+	}
+	if fm.forwards[80].localAddress != fmt.Sprintf("localhost:%d", remapped[80]) {
+		t.Errorf("the forward of port 80 doesn't listen on its fallback port: %s", fm.forwards[80].localAddress)
+	}
+	if fm.forwards[8080].localAddress != "localhost:8080" {
+		t.Errorf("the declared port 8080 was changed: %s", fm.forwards[8080].localAddress)
+	}
+	if len(fm.denied) != 0 {
+		t.Errorf("denied ports weren't cleared: %v", fm.denied)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const resizePollInterval = 250 * time.Millisecond
+
+// watchTerminalSize propagates the size of the local terminal to the session. Windows doesn't have SIGWINCH, so the size is polled
+func watchTerminalSize(ctx context.Context, session *ssh.Session, fd, width, height int) {
+	t := time.NewTicker(resizePollInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			width, height = resizeTerminal(session, fd, width, height)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -18,33 +18,61 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"time"
 
 	"github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/model"
 )
 
+// reverseRetryPeriod is the time to wait before re-establishing a lost remote listener
+const reverseRetryPeriod = 2 * time.Second
+
 type reverse struct {
 	forward
 }
 
-// AddReverse adds a reverse forward
+// AddReverse adds a reverse forward. Port ranges are expanded into one reverse forward per port
 func (fm *ForwardManager) AddReverse(f model.Reverse) error {
+	fm.lock.Lock()
+	defer fm.lock.Unlock()
 
-	if err := fm.canAdd(f.Local, false); err != nil {
-		return err
-	}
+	for _, r := range f.Expand() {
+		if err := fm.canAdd(r.Local, false); err != nil {
+			return err
+		}
 
-	fm.reverses[f.Local] = &reverse{
-		forward: forward{
-			localAddress:  fmt.Sprintf("%s:%d", fm.localInterface, f.Local),
-			remoteAddress: fmt.Sprintf("%s:%d", fm.remoteInterface, f.Remote),
-		},
+		fm.reverses[r.Local] = &reverse{
+			forward: forward{
+				localAddress:  fmt.Sprintf("%s:%d", fm.localInterface, r.Local),
+				remoteAddress: fmt.Sprintf("%s:%d", fm.remoteInterface, r.Remote),
+			},
+		}
 	}
 
 	return nil
 }
 
 func (r *reverse) start(ctx context.Context) {
+	t := time.NewTicker(reverseRetryPeriod)
+	defer t.Stop()
+	for {
+		r.listen(ctx)
+		if r.pool.isStopped() {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Infof("%s -> done", r.String())
+			return
+		case <-t.C:
+			log.Infof("%s -> re-establishing remote listener", r.String())
+		}
+	}
+}
+
+// listen accepts remote connections until the remote listener is closed or the context is cancelled
+func (r *reverse) listen(ctx context.Context) {
 	remoteListener, err := r.pool.getListener(r.remoteAddress)
 	if err != nil {
 		log.Infof("%s -> failed to listen on remote address: %v", r.String(), err)
@@ -52,26 +80,31 @@ func (r *reverse) start(ctx context.Context) {
 	}
 
 	defer remoteListener.Close()
+
+	done := make(chan struct{})
+	defer close(done)
 	go func() {
-		<-ctx.Done()
-		r.setDisconnected()
-		if err := remoteListener.Close(); err != nil {
-			log.Infof("%s -> failed to close: %s", r.String(), err)
+		select {
+		case <-ctx.Done():
+			r.setDisconnected()
+			if err := remoteListener.Close(); err != nil {
+				log.Infof("%s -> failed to close: %s", r.String(), err)
+			}
+		case <-done:
 		}
-
-		log.Infof("%s -> done", r.String())
 	}()
 
+	r.setConnected()
 	for {
-		r.setConnected()
 		remoteConn, err := remoteListener.Accept()
 		if err != nil {
-			if !r.connected() {
+			if ctx.Err() != nil {
 				return
 			}
 
 			log.Infof("%s -> failed to accept connection: %v", r.String(), err)
-			continue
+			r.setDisconnected()
+			return
 		}
 
 		go r.handle(ctx, remoteConn)
@@ -91,8 +124,11 @@ func (r *reverse) handle(ctx context.Context, remote net.Conn) {
 
 	defer local.Close()
 
-	go r.transfer(remote, local, quit)
-	go r.transfer(local, remote, quit)
+	var in, out uint64
+	defer r.audit.connection("reverse", r.remoteAddress, r.localAddress, remote.RemoteAddr(), &in, &out)()
+
+	go r.transfer(&countingWriter{w: remote, n: &out}, local, &r.bytesOut, quit)
+	go r.transfer(&countingWriter{w: local, n: &in}, remote, &r.bytesIn, quit)
 
 	<-quit
 }
@@ -101,8 +137,8 @@ func (r *reverse) String() string {
 	return fmt.Sprintf("ssh reverse forward %s<-%s", r.localAddress, r.remoteAddress)
 }
 
-func (r *reverse) transfer(from io.Writer, to io.Reader, quit chan struct{}) {
-	_, err := io.Copy(from, to)
+func (r *reverse) transfer(from io.Writer, to io.Reader, counter *uint64, quit chan struct{}) {
+	_, err := io.Copy(&countingWriter{w: from, n: counter}, to)
 	if err != nil {
 		log.Infof("%s -> data transfer failed: %v", r.String(), err)
 	}
@@ -15,6 +15,7 @@ package ssh
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/okteto/okteto/pkg/model"
@@ -63,3 +64,35 @@ func TestReverseManager_Add(t *testing.T) {
 		})
 	}
 }
+
+func TestReverseManager_AddRange(t *testing.T) {
+	r := &ForwardManager{
+		reverses: map[int]*reverse{},
+		forwards: map[int]*forward{},
+		ctx:      context.TODO(),
+		sshAddr:  "localhost:22",
+	}
+
+	if err := r.AddReverse(model.Reverse{Local: 8000, Remote: 9000, Count: 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.reverses) != 3 {
+		t.Fatalf("expected 3 reverse forwards, got %d", len(r.reverses))
+	}
+
+	for i := 0; i < 3; i++ {
+		f, ok := r.reverses[8000+i]
+		if !ok {
+			t.Fatalf("reverse forward for local port %d wasn't added", 8000+i)
+		}
+
+		if expected := fmt.Sprintf(":%d", 9000+i); f.remoteAddress != expected {
+			t.Fatalf("remote address is not %s, it is: %s", expected, f.remoteAddress)
+		}
+	}
+
+	if err := r.AddReverse(model.Reverse{Local: 8002, Remote: 9100}); err == nil {
+		t.Fatal("overlapping range didn't return an error")
+	}
+}
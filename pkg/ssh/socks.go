@@ -0,0 +1,221 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+
+	"github.com/okteto/okteto/pkg/log"
+)
+
+// SOCKS5 protocol constants, as defined in RFC 1928
+const (
+	socksVersion = 0x05
+
+	socksNoAuth       = 0x00
+	socksNoAcceptable = 0xff
+
+	socksConnect = 0x01
+
+	socksIPv4   = 0x01
+	socksDomain = 0x03
+	socksIPv6   = 0x04
+
+	socksSucceeded           = 0x00
+	socksGeneralFailure      = 0x01
+	socksCommandNotSupported = 0x07
+	socksAddressNotSupported = 0x08
+)
+
+type socks struct {
+	forward
+}
+
+// AddSocks starts a SOCKS5 proxy on the given local address that dials its targets through the SSH pool
+func (fm *ForwardManager) AddSocks(address string) error {
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return fmt.Errorf("invalid SOCKS5 proxy address '%s': %w", address, err)
+	}
+
+	fm.socks = &socks{
+		forward: forward{
+			localAddress: address,
+		},
+	}
+
+	return nil
+}
+
+func (s *socks) start(ctx context.Context) {
+	localListener, err := net.Listen("tcp", s.localAddress)
+	if err != nil {
+		log.Infof("%s -> failed to listen: %s", s.String(), err)
+		return
+	}
+
+	go func() {
+		<-ctx.Done()
+		s.setDisconnected()
+		if err := localListener.Close(); err != nil {
+			log.Infof("%s -> failed to close: %s", s.String(), err)
+		}
+		log.Infof("%s -> done", s.String())
+	}()
+
+	s.setConnected()
+	log.Infof("%s -> started", s.String())
+
+	for {
+		localConn, err := localListener.Accept()
+		if err != nil {
+			if !s.connected() {
+				return
+			}
+
+			log.Infof("%s -> failed to accept connection: %v", s.String(), err)
+			continue
+		}
+		go s.handle(localConn)
+	}
+}
+
+func (s *socks) handle(local net.Conn) {
+	defer local.Close()
+
+	target, err := s.handshake(local)
+	if err != nil {
+		log.Infof("%s -> handshake failed: %s", s.String(), err)
+		return
+	}
+
+	remote, err := s.pool.get(target)
+	if err != nil {
+		log.Infof("%s -> failed to dial %s: %s", s.String(), target, err)
+		_ = writeSocksReply(local, socksGeneralFailure)
+		return
+	}
+
+	defer remote.Close()
+
+	if err := writeSocksReply(local, socksSucceeded); err != nil {
+		log.Infof("%s -> failed to reply: %s", s.String(), err)
+		return
+	}
+
+	var in, out uint64
+	defer s.audit.connection("socks", s.localAddress, target, local.RemoteAddr(), &in, &out)()
+
+	quit := make(chan struct{}, 1)
+
+	go s.transfer(&countingWriter{w: remote, n: &out}, local, &s.bytesOut, quit)
+	go s.transfer(&countingWriter{w: local, n: &in}, remote, &s.bytesIn, quit)
+
+	<-quit
+}
+
+// handshake negotiates the authentication method and returns the address requested by the client
+func (s *socks) handshake(conn net.Conn) (string, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return "", err
+	}
+
+	if header[0] != socksVersion {
+		return "", fmt.Errorf("unsupported SOCKS version %d", header[0])
+	}
+
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return "", err
+	}
+
+	method := byte(socksNoAcceptable)
+	for _, m := range methods {
+		if m == socksNoAuth {
+			method = socksNoAuth
+			break
+		}
+	}
+
+	if _, err := conn.Write([]byte{socksVersion, method}); err != nil {
+		return "", err
+	}
+
+	if method == socksNoAcceptable {
+		return "", fmt.Errorf("client doesn't support unauthenticated connections")
+	}
+
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		return "", err
+	}
+
+	if request[1] != socksConnect {
+		_ = writeSocksReply(conn, socksCommandNotSupported)
+		return "", fmt.Errorf("unsupported SOCKS command %d", request[1])
+	}
+
+	var host string
+	switch request[3] {
+	case socksIPv4, socksIPv6:
+		size := net.IPv4len
+		if request[3] == socksIPv6 {
+			size = net.IPv6len
+		}
+
+		ip := make([]byte, size)
+		if _, err := io.ReadFull(conn, ip); err != nil {
+			return "", err
+		}
+
+		host = net.IP(ip).String()
+	case socksDomain:
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return "", err
+		}
+
+		domain := make([]byte, length[0])
+		if _, err := io.ReadFull(conn, domain); err != nil {
+			return "", err
+		}
+
+		host = string(domain)
+	default:
+		_ = writeSocksReply(conn, socksAddressNotSupported)
+		return "", fmt.Errorf("unsupported SOCKS address type %d", request[3])
+	}
+
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return "", err
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))), nil
+}
+
+func writeSocksReply(conn net.Conn, reply byte) error {
+	// the bound address is not meaningful for the client, so it's always reported as 0.0.0.0:0
+	_, err := conn.Write([]byte{socksVersion, reply, 0x00, socksIPv4, 0, 0, 0, 0, 0, 0})
+	return err
+}
+
+func (s *socks) String() string {
+	return fmt.Sprintf("ssh socks5 proxy %s", s.localAddress)
+}
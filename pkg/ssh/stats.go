@@ -0,0 +1,232 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/okteto/okteto/pkg/config"
+	"github.com/okteto/okteto/pkg/log"
+)
+
+const (
+	// statsReportPeriod is the frequency used to persist the stats of a running pool
+	statsReportPeriod = 5 * time.Second
+
+	// highLatencyThreshold is the round-trip latency above which the tunnel is reported as unhealthy
+	highLatencyThreshold = 500 * time.Millisecond
+)
+
+// Health is the health of the tunnel to the development container, as measured by the keepalives
+type Health struct {
+	Latency time.Duration `json:"latency"`
+	Healthy bool          `json:"healthy"`
+}
+
+// Stats is a snapshot of the activity of the SSH pool
+type Stats struct {
+	Clients          int            `json:"clients"`
+	ActiveChannels   int64          `json:"activeChannels"`
+	Reconnects       int64          `json:"reconnects"`
+	MissedKeepAlives int64          `json:"missedKeepAlives"`
+	DialLatency      time.Duration  `json:"dialLatency"`
+	Health           Health         `json:"health"`
+	Forwards         []ForwardStats `json:"forwards,omitempty"`
+	Timestamp        time.Time      `json:"timestamp"`
+}
+
+// ForwardStats is the traffic of a single forward. BytesIn is the traffic received from the development container and BytesOut the traffic sent to it
+type ForwardStats struct {
+	Name     string `json:"name"`
+	BytesIn  uint64 `json:"bytesIn"`
+	BytesOut uint64 `json:"bytesOut"`
+}
+
+// Stats returns a snapshot of the activity of the SSH pool and its forwards
+func (fm *ForwardManager) Stats() Stats {
+	fm.lock.Lock()
+	defer fm.lock.Unlock()
+
+	s := Stats{Timestamp: time.Now()}
+	if fm.pool != nil {
+		s = fm.pool.stats()
+	}
+
+	for _, f := range fm.forwards {
+		s.Forwards = append(s.Forwards, f.stats(f.String()))
+	}
+
+	for _, r := range fm.reverses {
+		s.Forwards = append(s.Forwards, r.stats(r.String()))
+	}
+
+	if fm.socks != nil {
+		s.Forwards = append(s.Forwards, fm.socks.stats(fm.socks.String()))
+	}
+
+	sort.Slice(s.Forwards, func(i, j int) bool {
+		return s.Forwards[i].Name < s.Forwards[j].Name
+	})
+
+	return s
+}
+
+// HealthCheck returns the round-trip latency of the last keepalive and whether it's below the warning threshold
+func (fm *ForwardManager) HealthCheck() Health {
+	fm.lock.Lock()
+	defer fm.lock.Unlock()
+
+	if fm.pool == nil {
+		return Health{}
+	}
+
+	return fm.pool.health()
+}
+
+// ReportStats periodically saves the stats of the pool so they can be read by other okteto commands
+func (fm *ForwardManager) ReportStats(ctx context.Context, namespace, name string) {
+	t := time.NewTicker(statsReportPeriod)
+	defer t.Stop()
+	for {
+		if err := SaveStats(namespace, name, fm.Stats()); err != nil {
+			log.Infof("failed to save ssh stats: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// SaveStats persists the stats of the SSH pool of a development container
+func SaveStats(namespace, name string, s Stats) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(getStatsFile(namespace, name), b, 0600)
+}
+
+// LoadStats returns the last stats saved by the SSH pool of a development container
+func LoadStats(namespace, name string) (*Stats, error) {
+	b, err := ioutil.ReadFile(getStatsFile(namespace, name))
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Stats{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// IsStale returns if the stats weren't saved by a running pool in the last two report periods
+func (s *Stats) IsStale() bool {
+	return time.Since(s.Timestamp) > 2*statsReportPeriod
+}
+
+func getStatsFile(namespace, name string) string {
+	return filepath.Join(config.GetDeploymentHome(namespace, name), "ssh.stats")
+}
+
+func (p *pool) stats() Stats {
+	p.lock.RLock()
+	clients := len(p.clients)
+	p.lock.RUnlock()
+
+	return Stats{
+		Clients:          clients,
+		ActiveChannels:   atomic.LoadInt64(&p.activeChannels),
+		Reconnects:       atomic.LoadInt64(&p.reconnects),
+		MissedKeepAlives: atomic.LoadInt64(&p.missedKeepAlives),
+		DialLatency:      time.Duration(atomic.LoadInt64(&p.dialLatency)),
+		Health:           p.health(),
+		Timestamp:        time.Now(),
+	}
+}
+
+func (p *pool) health() Health {
+	latency := time.Duration(atomic.LoadInt64(&p.latency))
+	return Health{
+		Latency: latency,
+		Healthy: latency < highLatencyThreshold,
+	}
+}
+
+func (p *pool) track(c net.Conn) net.Conn {
+	atomic.AddInt64(&p.activeChannels, 1)
+	return &trackedConn{Conn: c, p: p}
+}
+
+// trackedConn keeps the count of active channels of the pool up to date
+type trackedConn struct {
+	net.Conn
+	p    *pool
+	once sync.Once
+}
+
+func (c *trackedConn) Close() error {
+	c.once.Do(func() {
+		atomic.AddInt64(&c.p.activeChannels, -1)
+	})
+	return c.Conn.Close()
+}
+
+type trackedListener struct {
+	net.Listener
+	p *pool
+}
+
+func (l *trackedListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	return l.p.track(c), nil
+}
+
+func (f *forward) stats(name string) ForwardStats {
+	return ForwardStats{
+		Name:     name,
+		BytesIn:  atomic.LoadUint64(&f.bytesIn),
+		BytesOut: atomic.LoadUint64(&f.bytesOut),
+	}
+}
+
+// countingWriter adds the number of bytes written to n
+type countingWriter struct {
+	w io.Writer
+	n *uint64
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	atomic.AddUint64(c.n, uint64(n))
+	return n, err
+}
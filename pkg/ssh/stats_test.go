@@ -0,0 +1,47 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatsIsStale(t *testing.T) {
+	tests := []struct {
+		name      string
+		timestamp time.Time
+		expected  bool
+	}{
+		{
+			name:      "recent",
+			timestamp: time.Now().Add(-statsReportPeriod),
+			expected:  false,
+		},
+		{
+			name:      "stale",
+			timestamp: time.Now().Add(-3 * statsReportPeriod),
+			expected:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Stats{Timestamp: tt.timestamp}
+			if s.IsStale() != tt.expected {
+				t.Errorf("expected stale to be %t for stats saved %s ago", tt.expected, time.Since(tt.timestamp))
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"net"
+
+	"github.com/okteto/okteto/pkg/model"
+)
+
+// setTCPOptions applies the socket options to c. Connections that are not TCP, like websockets, are left untouched
+func setTCPOptions(c net.Conn, opts model.TCPOptions) error {
+	tc, ok := c.(*net.TCPConn)
+	if !ok {
+		return nil
+	}
+
+	if opts.NoDelay != nil {
+		if err := tc.SetNoDelay(*opts.NoDelay); err != nil {
+			return err
+		}
+	}
+
+	if opts.SendBuffer > 0 {
+		if err := tc.SetWriteBuffer(opts.SendBuffer); err != nil {
+			return err
+		}
+	}
+
+	if opts.ReceiveBuffer > 0 {
+		if err := tc.SetReadBuffer(opts.ReceiveBuffer); err != nil {
+			return err
+		}
+	}
+
+	if opts.KeepAlive > 0 {
+		if err := tc.SetKeepAlive(true); err != nil {
+			return err
+		}
+
+		if err := tc.SetKeepAlivePeriod(opts.KeepAlive); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/okteto/okteto/pkg/model"
+)
+
+func Test_setTCPOptions(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	go func() {
+		c, err := l.Accept()
+		if err == nil {
+			c.Close()
+		}
+	}()
+
+	c, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	noDelay := false
+	opts := model.TCPOptions{
+		NoDelay:       &noDelay,
+		SendBuffer:    64 * 1024,
+		ReceiveBuffer: 64 * 1024,
+		KeepAlive:     10 * time.Second,
+	}
+
+	if err := setTCPOptions(c, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	local, remote := net.Pipe()
+	defer local.Close()
+	defer remote.Close()
+
+	if err := setTCPOptions(local, opts); err != nil {
+		t.Fatalf("options were applied to a connection that is not tcp: %s", err)
+	}
+}
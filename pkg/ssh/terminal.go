@@ -0,0 +1,115 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"os"
+
+	"github.com/okteto/okteto/pkg/log"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const (
+	defaultTerminalType   = "xterm-256color"
+	defaultTerminalWidth  = 80
+	defaultTerminalHeight = 24
+	terminalSpeed         = 115200
+)
+
+// knownTerminalTypes are the terminal types included in the terminfo database of most images
+var knownTerminalTypes = map[string]bool{
+	"ansi":            true,
+	"dumb":            true,
+	"linux":           true,
+	"rxvt":            true,
+	"rxvt-unicode":    true,
+	"screen":          true,
+	"screen-256color": true,
+	"vt100":           true,
+	"vt220":           true,
+	"xterm":           true,
+	"xterm-256color":  true,
+	"xterm-color":     true,
+}
+
+// terminalType returns the local terminal type, so the remote programs use the same capabilities.
+// Terminal types that the development container may not know, like 'xterm-kitty' or 'alacritty', fall back to xterm-256color
+func terminalType() string {
+	t := os.Getenv("TERM")
+	if knownTerminalTypes[t] {
+		return t
+	}
+
+	if t != "" {
+		log.Infof("unknown terminal type '%s', using '%s'", t, defaultTerminalType)
+	}
+	return defaultTerminalType
+}
+
+// terminalModes returns the modes of the remote pseudo terminal. The local terminal is in raw mode while the
+// session runs, so the remote terminal is in charge of echoing and of the line discipline. Echo is disabled when
+// the input is not a terminal, to avoid printing it back
+func terminalModes(interactive bool) ssh.TerminalModes {
+	var echo uint32
+	if interactive {
+		echo = 1
+	}
+
+	return ssh.TerminalModes{
+		ssh.ECHO:          echo,
+		ssh.ECHOCTL:       echo,
+		ssh.ECHOE:         1,
+		ssh.ECHOK:         1,
+		ssh.ICANON:        1,
+		ssh.ISIG:          1,
+		ssh.IEXTEN:        1,
+		ssh.ICRNL:         1,
+		ssh.OPOST:         1,
+		ssh.ONLCR:         1,
+		ssh.VINTR:         3,   // ctrl+c
+		ssh.VQUIT:         28,  // ctrl+\
+		ssh.VERASE:        127, // backspace
+		ssh.VKILL:         21,  // ctrl+u
+		ssh.VEOF:          4,   // ctrl+d
+		ssh.VSUSP:         26,  // ctrl+z
+		ssh.TTY_OP_ISPEED: terminalSpeed,
+		ssh.TTY_OP_OSPEED: terminalSpeed,
+	}
+}
+
+// terminalSize returns the size of the local terminal, or the default size if it's not available
+func terminalSize(fd int) (int, int) {
+	width, height, err := terminal.GetSize(fd)
+	if err != nil || width <= 0 || height <= 0 {
+		log.Infof("request for terminal size failed: %v", err)
+		return defaultTerminalWidth, defaultTerminalHeight
+	}
+
+	return width, height
+}
+
+// resizeTerminal sends a window-change request if the size of the local terminal changed
+func resizeTerminal(session *ssh.Session, fd, width, height int) (int, int) {
+	w, h := terminalSize(fd)
+	if w == width && h == height {
+		return width, height
+	}
+
+	if err := session.WindowChange(h, w); err != nil {
+		log.Infof("failed to send window-change request: %s", err)
+	}
+
+	return w, h
+}
@@ -0,0 +1,82 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"os"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func Test_terminalModes(t *testing.T) {
+	tests := []struct {
+		name        string
+		interactive bool
+		echo        uint32
+	}{
+		{
+			name:        "interactive",
+			interactive: true,
+			echo:        1,
+		},
+		{
+			name:        "piped",
+			interactive: false,
+			echo:        0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modes := terminalModes(tt.interactive)
+			if modes[ssh.ECHO] != tt.echo {
+				t.Errorf("expected ECHO to be %d, got %d", tt.echo, modes[ssh.ECHO])
+			}
+
+			if modes[ssh.ICRNL] != 1 {
+				t.Error("carriage returns are not translated to new lines")
+			}
+
+			if _, ok := modes[ssh.IGNCR]; ok {
+				t.Error("carriage returns are ignored")
+			}
+		})
+	}
+}
+
+func Test_terminalType(t *testing.T) {
+	tests := []struct {
+		term     string
+		expected string
+	}{
+		{term: "", expected: "xterm-256color"},
+		{term: "xterm", expected: "xterm"},
+		{term: "screen-256color", expected: "screen-256color"},
+		{term: "xterm-kitty", expected: "xterm-256color"},
+		{term: "alacritty", expected: "xterm-256color"},
+	}
+
+	term := os.Getenv("TERM")
+	defer os.Setenv("TERM", term)
+
+	for _, tt := range tests {
+		t.Run(tt.term, func(t *testing.T) {
+			os.Setenv("TERM", tt.term)
+			if got := terminalType(); got != tt.expected {
+				t.Errorf("expected '%s' for TERM='%s', got '%s'", tt.expected, tt.term, got)
+			}
+		})
+	}
+}
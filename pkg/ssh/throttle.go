@@ -0,0 +1,76 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"context"
+	"io"
+
+	"golang.org/x/time/rate"
+)
+
+// maxThrottleBurst is the largest chunk of data written at once by a throttled forward
+const maxThrottleBurst = 32 * 1024
+
+// newLimiter returns a token bucket that allows bytesPerSecond, or nil if the traffic is not limited
+func newLimiter(bytesPerSecond int64) *rate.Limiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+
+	burst := int(bytesPerSecond)
+	if bytesPerSecond > maxThrottleBurst {
+		burst = maxThrottleBurst
+	}
+
+	return rate.NewLimiter(rate.Limit(bytesPerSecond), burst)
+}
+
+// throttle limits the writes to w to the rate allowed by l
+func throttle(w io.Writer, l *rate.Limiter) io.Writer {
+	if l == nil {
+		return w
+	}
+
+	return &throttledWriter{w: w, l: l}
+}
+
+type throttledWriter struct {
+	w io.Writer
+	l *rate.Limiter
+}
+
+func (t *throttledWriter) Write(b []byte) (int, error) {
+	written := 0
+	for len(b) > 0 {
+		n := len(b)
+		if n > t.l.Burst() {
+			n = t.l.Burst()
+		}
+
+		if err := t.l.WaitN(context.Background(), n); err != nil {
+			return written, err
+		}
+
+		w, err := t.w.Write(b[:n])
+		written += w
+		if err != nil {
+			return written, err
+		}
+
+		b = b[n:]
+	}
+
+	return written, nil
+}
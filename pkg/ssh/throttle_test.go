@@ -0,0 +1,44 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func Test_throttle(t *testing.T) {
+	var buf bytes.Buffer
+	if w := throttle(&buf, nil); w != &buf {
+		t.Fatal("unlimited writer was throttled")
+	}
+
+	// the first burst is written right away, the rest at 64KB per second
+	w := throttle(&buf, newLimiter(64*1024))
+	data := make([]byte, maxThrottleBurst+64*1024)
+	start := time.Now()
+	n, err := w.Write(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != len(data) || buf.Len() != len(data) {
+		t.Fatalf("wrote %d bytes, expected %d", n, len(data))
+	}
+
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("write wasn't throttled, took %s", elapsed)
+	}
+}
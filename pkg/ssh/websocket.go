@@ -0,0 +1,52 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// getWebSocketConnection returns a connection that tunnels the SSH stream over a websocket.
+// It's used when the raw TCP port can't be reached, e.g. behind a corporate proxy that only allows HTTPS traffic
+func getWebSocketConnection(wsURL string, keepAlive time.Duration) (net.Conn, error) {
+	u, err := url.Parse(wsURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid websocket url '%s': %w", wsURL, err)
+	}
+
+	origin := &url.URL{Scheme: "http", Host: u.Host}
+	if u.Scheme == "wss" {
+		origin.Scheme = "https"
+	}
+
+	c, err := websocket.NewConfig(wsURL, origin.String())
+	if err != nil {
+		return nil, err
+	}
+
+	c.Dialer = &net.Dialer{Timeout: 10 * time.Second, KeepAlive: keepAlive}
+	ws, err := websocket.DialConfig(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// the ssh stream is binary, text frames would be rejected by the server
+	ws.PayloadType = websocket.BinaryFrame
+	return ws, nil
+}
@@ -99,6 +99,8 @@ type Folder struct {
 	Name       string `yaml:"name"`
 	LocalPath  string `yaml:"localPath"`
 	RemotePath string `yaml:"remotePath"`
+	// Ignores are the ignore patterns of the exclusions of the sync folder in the manifest, added to its '.stignore' file
+	Ignores []string `yaml:"ignores,omitempty"`
 }
 
 //Ignores represents the .stignore file
@@ -206,6 +208,7 @@ func New(dev *model.Dev) (*Syncthing, error) {
 					Name:       strconv.Itoa(index),
 					LocalPath:  sync.LocalPath,
 					RemotePath: sync.RemotePath,
+					Ignores:    dev.GetSyncIgnores(sync.LocalPath),
 				},
 			)
 			index++
@@ -344,7 +347,7 @@ func (s *Syncthing) Ping(ctx context.Context, local bool) bool {
 	return err == nil
 }
 
-//SendStignoreFile sends .stignore from local to remote
+//SendStignoreFile sends .stignore from local to remote, with the exclusions of the sync folders of the manifest
 func (s *Syncthing) SendStignoreFile(ctx context.Context, dev *model.Dev) error {
 	for _, folder := range s.Folders {
 		log.Infof("sending '.stignore' file %s to the remote syncthing", folder.Name)
@@ -360,6 +363,7 @@ func (s *Syncthing) SendStignoreFile(ctx context.Context, dev *model.Dev) error
 			log.Infof("error unmarshalling ignore files: %s", err.Error())
 			continue
 		}
+		ignores.Ignore = append(ignores.Ignore, folder.Ignores...)
 		for i, line := range ignores.Ignore {
 			line := strings.TrimSpace(line)
 			if line == "" {